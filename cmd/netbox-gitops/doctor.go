@@ -0,0 +1,199 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/loader"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// newDoctorCmd creates the doctor subcommand
+func newDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the environment, the NetBox connection and the data directory without changing anything",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if failed := runDoctor(cmd.OutOrStdout()); failed > 0 {
+				return fmt.Errorf("%d critical checks failed", failed)
+			}
+			return nil
+		},
+	}
+	cmd.SilenceUsage = true
+
+	// The same settings a sync reads its environment and data from
+	cmd.Flags().StringVar(&dataDir, "data-dir", ".", "Base directory for definitions and inventory, or a .tar/.tar.gz archive of it")
+	cmd.Flags().StringVar(&layoutFile, "layout", "", "YAML file mapping resource types to folders (default: layout.yaml in --data-dir if present)")
+	cmd.Flags().StringVar(&tokenFile, "token-file", "", "Read the NetBox token from a file; NETBOX_TOKEN takes precedence")
+	cmd.Flags().StringVar(&tokenSource, "token-source", client.TokenSourceAuto, "Where to read the NetBox token from: auto, env, file or vault")
+	cmd.Flags().StringVar(&tokenPath, "token-path", "", "Token location for --token-source")
+	return cmd
+}
+
+// doctorReport prints the checklist lines of the doctor command and counts failed critical checks
+type doctorReport struct {
+	out    io.Writer
+	failed int
+}
+
+func (r *doctorReport) pass(check, detail string) {
+	fmt.Fprintf(r.out, "[PASS] %s: %s\n", check, detail)
+}
+
+// fail records a failed check; only critical failures make the doctor command fail
+func (r *doctorReport) fail(check string, critical bool, err error) {
+	if critical {
+		r.failed++
+		fmt.Fprintf(r.out, "[FAIL] %s: %v\n", check, err)
+		return
+	}
+	fmt.Fprintf(r.out, "[WARN] %s: %v\n", check, err)
+}
+
+func (r *doctorReport) skip(check, reason string) {
+	fmt.Fprintf(r.out, "[SKIP] %s: %s\n", check, reason)
+}
+
+// runDoctor runs all checks, prints the checklist and returns the number of failed critical checks
+func runDoctor(out io.Writer) int {
+	report := &doctorReport{out: out}
+	// The helpers shared with the sync log what they do; the checklist says it instead
+	logger := utils.NewLogger(true)
+	logger.SetOutput(io.Discard, io.Discard)
+
+	checkNetBox(report)
+	checkData(report, logger)
+
+	if report.failed > 0 {
+		fmt.Fprintf(out, "\n%d critical checks failed\n", report.failed)
+	} else {
+		fmt.Fprintln(out, "\nAll critical checks passed")
+	}
+	return report.failed
+}
+
+// checkNetBox checks NETBOX_URL, the token, TLS and that NetBox accepts the token
+func checkNetBox(report *doctorReport) {
+	netboxURL := os.Getenv("NETBOX_URL")
+	if netboxURL == "" {
+		report.fail("NETBOX_URL", true, fmt.Errorf("environment variable is not set"))
+	} else {
+		report.pass("NETBOX_URL", netboxURL)
+	}
+
+	var netboxToken string
+	tokenProvider, err := newTokenProvider()
+	if err == nil {
+		netboxToken, err = tokenProvider.Token()
+	}
+	if err != nil {
+		report.fail("NetBox token", true, err)
+	} else {
+		report.pass("NetBox token", fmt.Sprintf("found (source: %s)", tokenSource))
+	}
+
+	if netboxURL == "" {
+		report.skip("TLS", "no NETBOX_URL")
+		report.skip("NetBox API", "no NETBOX_URL")
+		return
+	}
+	checkTLS(report, netboxURL)
+
+	if netboxToken == "" {
+		report.skip("NetBox API", "no token")
+		return
+	}
+	// Dry-run, so connecting never creates the managed tag
+	c, err := client.NewClient(netboxURL, netboxToken, true)
+	if err == nil {
+		c.Logger().SetOutput(io.Discard, io.Discard)
+		err = c.Ping()
+	}
+	if err != nil {
+		report.fail("NetBox API", true, err)
+		return
+	}
+	netboxVersion, err := c.ServerVersion()
+	if err != nil {
+		netboxVersion = "unknown version"
+	}
+	report.pass("NetBox API", fmt.Sprintf("reachable, token accepted (NetBox %s)", netboxVersion))
+}
+
+// checkTLS verifies the certificate of an HTTPS NetBox. The controller does not verify
+// certificates, so problems are warnings.
+func checkTLS(report *doctorReport, netboxURL string) {
+	parsed, err := url.Parse(netboxURL)
+	if err != nil {
+		report.fail("TLS", true, fmt.Errorf("invalid NETBOX_URL: %w", err))
+		return
+	}
+	if parsed.Scheme != "https" {
+		report.fail("TLS", false, fmt.Errorf("%s is not HTTPS, the token is sent unencrypted", netboxURL))
+		return
+	}
+
+	host := parsed.Host
+	if parsed.Port() == "" {
+		host = net.JoinHostPort(parsed.Hostname(), "443")
+	}
+	dialer := &net.Dialer{Timeout: constants.DoctorDialTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", host, &tls.Config{ServerName: parsed.Hostname()})
+	if err != nil {
+		report.fail("TLS", false, fmt.Errorf("certificate not verified (the controller skips verification): %w", err))
+		return
+	}
+	conn.Close()
+	report.pass("TLS", "certificate verified")
+}
+
+// checkData checks the data directory, the layout, the definition folders and that every YAML file parses
+func checkData(report *doctorReport, logger *utils.Logger) {
+	dir, cleanup, err := prepareDataDir(logger)
+	if err != nil {
+		report.fail("Data directory", true, err)
+		report.skip("Definition folders", "no data directory")
+		return
+	}
+	defer cleanup()
+	report.pass("Data directory", dir)
+
+	layout, err := resolveLayout(dir, logger)
+	if err != nil {
+		report.fail("Layout", true, err)
+		report.skip("Definition folders", "no layout")
+		return
+	}
+	report.pass("Layout", fmt.Sprintf("%d resource types", len(layout)))
+
+	dataLoader := loader.NewDataLoader(dir, logger)
+	for _, resource := range layout.Resources() {
+		for _, folder := range layout.Folders(resource) {
+			check := fmt.Sprintf("%s (%s)", resource, folder)
+			if _, err := os.Stat(filepath.Join(dir, folder)); err != nil {
+				report.fail(check, false, fmt.Errorf("folder not found, skipped during sync"))
+				continue
+			}
+
+			files, errs := dataLoader.CheckFolder(folder)
+			if len(errs) > 0 {
+				for _, err := range errs {
+					report.fail(check, true, err)
+				}
+				continue
+			}
+			report.pass(check, fmt.Sprintf("%d files parsed", files))
+		}
+	}
+}
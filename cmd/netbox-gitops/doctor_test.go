@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+)
+
+// setDoctorFlags sets the flags the doctor command reads and restores them after the test
+func setDoctorFlags(t *testing.T, dir string) {
+	oldDataDir, oldLayoutFile := dataDir, layoutFile
+	oldTokenFile, oldTokenSource, oldTokenPath := tokenFile, tokenSource, tokenPath
+	dataDir, layoutFile = dir, ""
+	tokenFile, tokenSource, tokenPath = "", client.TokenSourceAuto, ""
+	t.Cleanup(func() {
+		dataDir, layoutFile = oldDataDir, oldLayoutFile
+		tokenFile, tokenSource, tokenPath = oldTokenFile, oldTokenSource, oldTokenPath
+	})
+}
+
+func TestDoctorMissingToken(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "definitions"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	setDoctorFlags(t, dir)
+	t.Setenv("NETBOX_URL", srv.URL)
+	t.Setenv("NETBOX_TOKEN", "")
+	t.Setenv("NETBOX_TOKEN_FILE", "")
+
+	var out bytes.Buffer
+	failed := runDoctor(&out)
+	if failed != 1 {
+		t.Errorf("runDoctor() = %d failed checks, expected 1\n%s", failed, out.String())
+	}
+	for _, want := range []string{"[FAIL] NetBox token", "[SKIP] NetBox API: no token", "[WARN] TLS", "[PASS] Data directory"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Output is missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestDoctorUnreadableDataDir(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+	srv.Intercept("GET", "/api/status/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"netbox-version": "4.1.3"}`)
+	})
+	setDoctorFlags(t, filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("NETBOX_URL", srv.URL)
+	t.Setenv("NETBOX_TOKEN", "test-token")
+
+	var out bytes.Buffer
+	failed := runDoctor(&out)
+	if failed != 1 {
+		t.Errorf("runDoctor() = %d failed checks, expected 1\n%s", failed, out.String())
+	}
+	for _, want := range []string{"[PASS] NetBox API: reachable, token accepted (NetBox 4.1.3)", "[FAIL] Data directory", "[SKIP] Definition folders"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Output is missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestDoctorInvalidYAML(t *testing.T) {
+	dir := t.TempDir()
+	sites := filepath.Join(dir, "definitions", "sites")
+	if err := os.MkdirAll(sites, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sites, "sites.yaml"), []byte("- name: [unclosed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	setDoctorFlags(t, dir)
+	t.Setenv("NETBOX_URL", "")
+
+	var out bytes.Buffer
+	runDoctor(&out)
+	if !strings.Contains(out.String(), "[FAIL] sites (definitions/sites)") {
+		t.Errorf("Output does not fail the sites folder:\n%s", out.String())
+	}
+}
@@ -1,13 +1,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
+	"github.com/braunma/netbox-gitops-controller/internal/version"
 	"github.com/braunma/netbox-gitops-controller/pkg/client"
 	"github.com/braunma/netbox-gitops-controller/pkg/loader"
+	"github.com/braunma/netbox-gitops-controller/pkg/metrics"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
 	"github.com/braunma/netbox-gitops-controller/pkg/reconciler"
 	"github.com/braunma/netbox-gitops-controller/pkg/utils"
 )
@@ -16,6 +27,60 @@ var (
 	dryRun     bool
 	configFile string
 	dataDir    string
+	tokenFile  string
+	repoURL    string
+	repoRef    string
+	prune      bool
+	pruneScope []string
+	assumeYes  bool
+	maxDeletes int
+	excludes   []string
+	overrides  []string
+	layoutFile string
+	lookupFile string
+	orderFile  string
+	siteSlugs  []string
+
+	reportOrphans bool
+
+	dumpCache          bool
+	dumpCacheResources []string
+	dumpPlanFile       string
+
+	watch         bool
+	watchInterval time.Duration
+	metricsAddr   string
+
+	allowMassStatusChange     bool
+	massStatusChangeThreshold int
+	allowInterfaceTypeChange  bool
+	interfacesFromTemplate    bool
+
+	tokenSource string
+	tokenPath   string
+
+	noColor         bool
+	summaryOnly     bool
+	diffOnlyChanged bool
+	showProgress    bool
+	quietNoChange   bool
+	quiet           bool
+
+	concurrency int
+
+	objectTimeout time.Duration
+	failFast      bool
+
+	httpMaxIdleConns        int
+	httpMaxIdleConnsPerHost int
+	httpIdleConnTimeout     time.Duration
+
+	warnOnExternalChange bool
+	lastRunFile          string
+
+	traceHTTP bool
+	journal   bool
+	simulate  bool
 )
 
 func main() {
@@ -25,10 +90,57 @@ func main() {
 		Long:  `Declarative infrastructure management for NetBox using YAML definitions`,
 		RunE:  runSync,
 	}
+	rootCmd.Version = version.String()
+	rootCmd.SetVersionTemplate("netbox-gitops {{.Version}}\n")
+	rootCmd.AddCommand(newVersionCmd())
+	rootCmd.AddCommand(newDoctorCmd())
 
 	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Simulate changes without applying them")
 	rootCmd.Flags().StringVar(&configFile, "config", ".env", "Configuration file path")
-	rootCmd.Flags().StringVar(&dataDir, "data-dir", ".", "Base directory for definitions and inventory (e.g., 'example' for test data)")
+	rootCmd.Flags().StringVar(&dataDir, "data-dir", ".", "Base directory for definitions and inventory (e.g., 'example' for test data), or a .tar/.tar.gz archive of it")
+	rootCmd.Flags().StringVar(&repoURL, "repo", "", fmt.Sprintf("Git URL to shallow-clone the data from; --data-dir is then relative to the clone (HTTPS token from %s)", loader.RepoTokenEnv))
+	rootCmd.Flags().StringVar(&repoRef, "ref", "", "Branch or tag of --repo to clone (default: the repository's default branch)")
+	rootCmd.Flags().StringVar(&tokenFile, "token-file", "", "Read the NetBox token from a file (e.g., a secret mount); NETBOX_TOKEN takes precedence")
+	rootCmd.Flags().StringVar(&tokenSource, "token-source", client.TokenSourceAuto, "Where to read the NetBox token from: auto, env, file or vault (vault uses VAULT_ADDR and VAULT_TOKEN)")
+	rootCmd.Flags().StringVar(&tokenPath, "token-path", "", "Token location for --token-source: environment variable, file, or vault secret path (e.g., 'secret/data/netbox#token')")
+	rootCmd.Flags().BoolVar(&showProgress, "progress", true, "Show a progress line with the running phases, counts and ETA (only on a terminal)")
+	rootCmd.Flags().BoolVar(&summaryOnly, "summary-only", false, "Only print the final summary table, warnings and errors")
+	rootCmd.Flags().BoolVar(&diffOnlyChanged, "diff-only-changed", false, "Print one line per changed field after the run instead of a diff box per object")
+	rootCmd.Flags().BoolVar(&quietNoChange, "quiet-no-change", false, "Hold back all output and, if the run created, updated and deleted nothing, only print a single \"No changes\" line")
+	rootCmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "With --quiet-no-change, print nothing at all when nothing changed")
+	rootCmd.Flags().StringArrayVar(&siteSlugs, "site", nil, "Only reconcile objects of this site (slug), repeatable")
+	rootCmd.Flags().BoolVar(&dumpCache, "dump-cache", false, "Load the global and site caches, print their slug/name→ID mappings and exit")
+	rootCmd.Flags().StringArrayVar(&dumpCacheResources, "dump-cache-resource", nil, "Limit --dump-cache to this resource type, repeatable (e.g., 'sites', 'vlans')")
+	rootCmd.Flags().StringVar(&dumpPlanFile, "dump-plan-file", "", "Write the compiled desired state (after includes, --set and --site) to this file, JSON if it ends in .json else YAML, and exit without contacting NetBox")
+	rootCmd.Flags().StringVar(&layoutFile, "layout", "", "YAML file mapping resource types to folders (default: layout.yaml in --data-dir if present)")
+	rootCmd.Flags().StringVar(&lookupFile, "lookups", "", "YAML file setting how objects are matched to existing NetBox objects per resource type (default: lookups.yaml in --data-dir if present)")
+	rootCmd.Flags().StringVar(&orderFile, "order", "", "YAML list of the resource types to reconcile, in order; unlisted types are skipped (default: order.yaml in --data-dir if present, else dependency order)")
+	rootCmd.Flags().StringArrayVar(&overrides, "set", nil, "Override a field of a loaded object, repeatable (e.g., 'device.web-01.status=offline')")
+	rootCmd.Flags().StringArrayVar(&excludes, "exclude", nil, "Glob of definition files or directories to skip, relative to --data-dir, repeatable (e.g., '**/_drafts/*')")
+	rootCmd.Flags().BoolVar(&prune, "prune", false, "Delete managed objects that are no longer defined in YAML (requires --prune-scope)")
+	rootCmd.Flags().StringArrayVar(&pruneScope, "prune-scope", nil, fmt.Sprintf("Resource type that --prune may delete, repeatable (%s)", strings.Join(reconciler.PruneResourceTypes(), ", ")))
+	rootCmd.Flags().BoolVar(&reportOrphans, "report-orphans", false, "After the sync, list managed objects that are no longer declared in YAML (never deletes)")
+	rootCmd.Flags().IntVar(&maxDeletes, "max-deletes", constants.DefaultMaxDeletes, "Abort --prune without deleting anything if it would delete more than this many objects")
+	rootCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Delete --prune candidates without asking for confirmation (required when stdin is not a terminal)")
+	rootCmd.Flags().BoolVar(&watch, "watch", false, "Keep running and re-sync every --interval")
+	rootCmd.Flags().DurationVar(&watchInterval, "interval", 5*time.Minute, "Time between syncs in --watch mode")
+	rootCmd.Flags().StringVar(&metricsAddr, "metrics-addr", ":9090", "Address for the Prometheus /metrics endpoint in --watch mode (empty to disable)")
+	rootCmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output (also honors NO_COLOR and non-terminal stdout)")
+	rootCmd.Flags().BoolVar(&warnOnExternalChange, "warn-on-external-change", false, "Warn when updating objects that were modified in NetBox since the last run")
+	rootCmd.Flags().StringVar(&lastRunFile, "last-run-file", ".netbox-gitops-last-run", "File recording the time of the last successful run (used by --warn-on-external-change)")
+	rootCmd.Flags().BoolVar(&traceHTTP, "trace-http", false, "Log every NetBox API request and response with its JSON body (the token is redacted)")
+	rootCmd.Flags().BoolVar(&simulate, "simulate", false, "With --dry-run, give would-be-created objects placeholder IDs so their interfaces, ports and modules are planned too")
+	rootCmd.Flags().BoolVar(&journal, "journal", false, "Record a journal entry in NetBox for every object the run creates or updates")
+	rootCmd.Flags().IntVar(&concurrency, "concurrency", 1, "Maximum number of independent resource types reconciled in parallel")
+	rootCmd.Flags().DurationVar(&objectTimeout, "reconcile-timeout-per-object", 0, "Maximum time spent creating or updating one object, e.g. '30s' (0 disables the limit)")
+	rootCmd.Flags().BoolVar(&failFast, "fail-fast", true, "Abort the run when an object exceeds --reconcile-timeout-per-object; with --fail-fast=false it is skipped and the run fails at the end")
+	rootCmd.Flags().IntVar(&httpMaxIdleConns, "http-max-idle-conns", constants.DefaultMaxIdleConns, "Maximum number of idle keep-alive connections kept open")
+	rootCmd.Flags().IntVar(&httpMaxIdleConnsPerHost, "http-max-idle-conns-per-host", constants.DefaultMaxIdleConnsPerHost, "Maximum number of idle keep-alive connections to NetBox (keep at or above --concurrency)")
+	rootCmd.Flags().DurationVar(&httpIdleConnTimeout, "http-idle-conn-timeout", constants.DefaultIdleConnTimeout, "How long an idle keep-alive connection is kept open")
+	rootCmd.Flags().BoolVar(&allowMassStatusChange, "allow-mass-status-change", false, "Allow a run to change the status of more than --mass-status-change-threshold percent of managed devices")
+	rootCmd.Flags().BoolVar(&allowInterfaceTypeChange, "allow-interface-type-change", false, "Allow changing the type of module-backed and breakout interfaces")
+	rootCmd.Flags().BoolVar(&interfacesFromTemplate, "interfaces-from-template", false, "Only update the device-specific settings of interfaces the device type's templates provide, never create them")
+	rootCmd.Flags().IntVar(&massStatusChangeThreshold, "mass-status-change-threshold", constants.DefaultMassStatusChangePercent, "Maximum percentage of managed devices whose status may change in one run")
 
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
@@ -36,219 +148,633 @@ func main() {
 }
 
 func runSync(cmd *cobra.Command, args []string) error {
+	utils.ConfigureColor(noColor)
+	utils.ConfigureSummaryOnly(summaryOnly)
 	logger := utils.NewLogger(dryRun)
 
-	// Auto-detect and validate data directory
-	dataDir, err := resolveDataDir(dataDir, logger)
-	if err != nil {
-		logger.Error("Failed to resolve data directory", err)
+	// A partial run must not prune: objects of other sites would look undeclared
+	if prune && len(siteSlugs) > 0 {
+		err := fmt.Errorf("--prune cannot be combined with --site")
+		logger.Error("Invalid flags", err)
 		return err
 	}
-
-	// Load environment variables
-	netboxURL := os.Getenv("NETBOX_URL")
-	netboxToken := os.Getenv("NETBOX_TOKEN")
-
-	if netboxURL == "" || netboxToken == "" {
-		logger.Error("NETBOX_URL and NETBOX_TOKEN environment variables must be set", nil)
-		return fmt.Errorf("missing required environment variables")
-	}
-
-	// Initialize NetBox client
-	logger.Info("Initializing NetBox client...")
-	c, err := client.NewClient(netboxURL, netboxToken, dryRun)
-	if err != nil {
-		logger.Error("Failed to initialize NetBox client", err)
+	if reportOrphans && len(siteSlugs) > 0 {
+		err := fmt.Errorf("--report-orphans cannot be combined with --site")
+		logger.Error("Invalid flags", err)
 		return err
 	}
-
-	// Initialize data loader
-	dataLoader := loader.NewDataLoader(dataDir, logger)
-
-	// =========================================================================
-	// LOAD GLOBAL CACHES (MUST BE BEFORE PHASE 1)
-	// =========================================================================
-	// Python does this in main.py lines 154-156 BEFORE device reconciliation
-	// We need to load it even earlier because foundation reconciler needs it
-	logger.Info("Loading global caches...")
-	if err := c.Cache().LoadGlobal(); err != nil {
-		logger.Error("Failed to load global caches", err)
+	if quiet && !quietNoChange {
+		err := fmt.Errorf("--quiet requires --quiet-no-change")
+		logger.Error("Invalid flags", err)
 		return err
 	}
-
-	// =========================================================================
-	// PHASE 1: FOUNDATION
-	// =========================================================================
-	logger.Info("═══════════════════════════════════════════════════════")
-	logger.Info("Phase 1: Foundation")
-	logger.Info("═══════════════════════════════════════════════════════")
-
-	foundationReconciler := reconciler.NewFoundationReconciler(c)
-
-	// Load and reconcile tags
-	tags, err := dataLoader.LoadTags(buildPath(dataDir, "definitions/extras"))
-	if err != nil {
-		logger.Error("Failed to load tags", err)
+	// The prune confirmation prompt would be shown without the candidates it asks about
+	if quietNoChange && prune && !assumeYes {
+		err := fmt.Errorf("--quiet-no-change with --prune requires --yes")
+		logger.Error("Invalid flags", err)
 		return err
 	}
-	if err := foundationReconciler.ReconcileTags(tags); err != nil {
-		logger.Error("Failed to reconcile tags", err)
+	if simulate && !dryRun {
+		err := fmt.Errorf("--simulate requires --dry-run")
+		logger.Error("Invalid flags", err)
 		return err
 	}
 
-	// Load and reconcile roles
-	roles, err := dataLoader.LoadRoles(buildPath(dataDir, "definitions/roles"))
+	// Auto-detect and validate data directory, or clone it
+	dataDir, cleanup, err := prepareDataDir(logger)
 	if err != nil {
-		logger.Error("Failed to load roles", err)
+		logger.Error("Failed to resolve data directory", err)
 		return err
 	}
-	if err := foundationReconciler.ReconcileRoles(roles); err != nil {
-		logger.Error("Failed to reconcile roles", err)
-		return err
+	defer cleanup()
+
+	if dumpPlanFile != "" {
+		if err := dumpPlan(logger, dataDir); err != nil {
+			logger.Error("Failed to dump plan", err)
+			return err
+		}
+		return nil
 	}
 
-	// Load and reconcile sites
-	sites, err := dataLoader.LoadSites(buildPath(dataDir, "definitions/sites"))
+	netboxURL, netboxToken, err := netboxCredentials(logger)
 	if err != nil {
-		logger.Error("Failed to load sites", err)
 		return err
 	}
-	if err := foundationReconciler.ReconcileSites(sites); err != nil {
-		logger.Error("Failed to reconcile sites", err)
+
+	if !watch {
+		_, err := syncReported(context.Background(), logger, dataDir, netboxURL, netboxToken)
 		return err
 	}
 
-	// Load and reconcile racks
-	racks, err := dataLoader.LoadRacks(buildPath(dataDir, "definitions/racks"))
+	return watchLoop(logger, dataDir, netboxURL, netboxToken)
+}
+
+// netboxCredentials reads NETBOX_URL and resolves the token from the configured source
+func netboxCredentials(logger *utils.Logger) (netboxURL, netboxToken string, err error) {
+	netboxURL = os.Getenv("NETBOX_URL")
+	if netboxURL == "" {
+		logger.Error("NETBOX_URL environment variable must be set", nil)
+		return "", "", fmt.Errorf("missing required environment variables")
+	}
+
+	tokenProvider, err := newTokenProvider()
 	if err != nil {
-		logger.Error("Failed to load racks", err)
-		return err
+		logger.Error("Invalid token source", err)
+		return "", "", err
 	}
-	if err := foundationReconciler.ReconcileRacks(racks); err != nil {
-		logger.Error("Failed to reconcile racks", err)
-		return err
+	netboxToken, err = tokenProvider.Token()
+	if err != nil {
+		logger.Error("Failed to resolve NetBox token", err)
+		return "", "", err
 	}
 
-	// =========================================================================
-	// PHASE 2: NETWORK & TYPES
-	// =========================================================================
-	logger.Info("═══════════════════════════════════════════════════════")
-	logger.Info("Phase 2: Network & Types")
-	logger.Info("═══════════════════════════════════════════════════════")
+	return netboxURL, netboxToken, nil
+}
 
-	networkReconciler := reconciler.NewNetworkReconciler(c)
+// newTokenProvider returns the token provider selected by --token-source, --token-path and --token-file
+func newTokenProvider() (client.TokenProvider, error) {
+	path := tokenPath
+	if path == "" && (tokenSource == client.TokenSourceAuto || tokenSource == client.TokenSourceFile) {
+		path = tokenFile
+	}
+	return client.NewTokenProvider(tokenSource, path)
+}
 
-	// Load and reconcile VRFs
-	vrfs, err := dataLoader.LoadVRFs(buildPath(dataDir, "definitions/vrfs"))
-	if err != nil {
-		logger.Error("Failed to load VRFs", err)
-		return err
+// watchLoop re-runs the sync every interval until interrupted, exposing metrics if configured
+func watchLoop(logger *utils.Logger, dataDir, netboxURL, netboxToken string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	registry := metrics.NewRegistry()
+	if metricsAddr != "" {
+		metricsServer := metrics.NewServer(metricsAddr, registry, logger)
+		if err := metricsServer.Start(); err != nil {
+			logger.Error("Failed to start metrics server", err)
+			return err
+		}
+		logger.Info("Serving metrics on http://%s/metrics", metricsServer.Addr())
+
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+				logger.Error("Failed to shut down metrics server", err)
+			}
+		}()
 	}
-	if err := networkReconciler.ReconcileVRFs(vrfs); err != nil {
-		logger.Error("Failed to reconcile VRFs", err)
-		return err
+
+	logger.Info("Watching for changes every %s (Ctrl-C to stop)", watchInterval)
+	for cycle := 1; ; cycle++ {
+		start := time.Now()
+		stats, err := watchCycle(ctx, logger, dataDir, netboxURL, netboxToken, cycle > 1)
+		registry.ObserveSync(stats, time.Since(start), err)
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Stopping watch")
+			return nil
+		case <-time.After(watchInterval):
+		}
 	}
+}
 
-	// Load and reconcile VLAN groups
-	vlanGroups, err := dataLoader.LoadVLANGroups(buildPath(dataDir, "definitions/vlan_groups"))
-	if err != nil {
-		logger.Error("Failed to load VLAN groups", err)
-		return err
+// watchCycle runs one sync of --watch. With --repo, every cycle after the first syncs a fresh
+// clone, so commits pushed since the previous cycle are picked up.
+func watchCycle(ctx context.Context, logger *utils.Logger, dataDir, netboxURL, netboxToken string, reclone bool) (*client.Stats, error) {
+	if repoURL != "" && reclone {
+		dir, cleanup, err := prepareDataDir(logger)
+		if err != nil {
+			logger.Error("Failed to refresh data directory", err)
+			return nil, err
+		}
+		defer cleanup()
+		dataDir = dir
 	}
-	if err := networkReconciler.ReconcileVLANGroups(vlanGroups); err != nil {
-		logger.Error("Failed to reconcile VLAN groups", err)
-		return err
+	return syncReported(ctx, logger, dataDir, netboxURL, netboxToken)
+}
+
+// syncReported runs syncOnce. With --quiet-no-change its output is held back and only
+// printed if the run changed something or failed; otherwise a single line (or, with --quiet,
+// nothing) is printed instead.
+func syncReported(ctx context.Context, logger *utils.Logger, dataDir, netboxURL, netboxToken string) (*client.Stats, error) {
+	if !quietNoChange {
+		return syncOnce(ctx, logger, dataDir, netboxURL, netboxToken)
 	}
 
-	// Load and reconcile VLANs
-	vlans, err := dataLoader.LoadVLANs(buildPath(dataDir, "definitions/vlans"))
-	if err != nil {
-		logger.Error("Failed to load VLANs", err)
-		return err
+	utils.BufferOutput()
+	stats, err := syncOnce(ctx, logger, dataDir, netboxURL, netboxToken)
+	if err != nil || stats.Changes() > 0 {
+		utils.FlushOutput()
+		return stats, err
 	}
-	if err := networkReconciler.ReconcileVLANs(vlans); err != nil {
-		logger.Error("Failed to reconcile VLANs", err)
-		return err
+
+	utils.DiscardOutput()
+	if !quiet {
+		logger.Summary("No changes")
 	}
+	return stats, nil
+}
 
-	// Load and reconcile prefixes
-	prefixes, err := dataLoader.LoadPrefixes(buildPath(dataDir, "definitions/prefixes"))
+// syncOnce runs one full reconciliation and returns the collected stats.
+// Canceling ctx aborts the requests still in flight.
+func syncOnce(ctx context.Context, logger *utils.Logger, dataDir, netboxURL, netboxToken string) (*client.Stats, error) {
+	// Initialize NetBox client
+	logger.Info("Initializing NetBox client...")
+	c, err := client.NewClient(netboxURL, netboxToken, dryRun)
 	if err != nil {
-		logger.Error("Failed to load prefixes", err)
-		return err
+		logger.Error("Failed to initialize NetBox client", err)
+		return nil, err
 	}
-	if err := networkReconciler.ReconcilePrefixes(prefixes); err != nil {
-		logger.Error("Failed to reconcile prefixes", err)
-		return err
+
+	c.SetContext(ctx)
+	c.SetConnectionPool(httpMaxIdleConns, httpMaxIdleConnsPerHost, httpIdleConnTimeout)
+	if httpMaxIdleConnsPerHost < concurrency {
+		logger.Warning("--http-max-idle-conns-per-host %d is below --concurrency %d, parallel requests will open new connections", httpMaxIdleConnsPerHost, concurrency)
+	}
+	if traceHTTP {
+		c.SetTraceHTTP(true)
 	}
+	if journal {
+		c.EnableJournal()
+	}
+	c.SetSimulate(simulate)
+	c.SetCompactDiff(diffOnlyChanged)
+	c.SetObjectTimeout(objectTimeout, failFast)
 
-	// Device types
-	deviceTypeReconciler := reconciler.NewDeviceTypeReconciler(c)
+	// Detect edits made in NetBox since our last successful run
+	if warnOnExternalChange {
+		lastRun, err := client.ReadLastRun(lastRunFile)
+		if err != nil {
+			logger.Error("Failed to read last run time", err)
+			return c.Stats(), err
+		}
+		if lastRun.IsZero() {
+			logger.Info("No previous run recorded in %s, skipping external change detection", lastRunFile)
+		}
+		c.SetExternalChangeCheck(lastRun)
+	}
+
+	// Validate prune scope before touching anything
+	var pruner *reconciler.Pruner
+	if prune {
+		pruner, err = reconciler.NewPruner(c, pruneScope)
+		if err != nil {
+			logger.Error("Invalid prune scope", err)
+			return c.Stats(), err
+		}
+		pruner.SetMaxDeletes(maxDeletes)
+		if !assumeYes {
+			pruner.SetConfirm(reconciler.PromptPruneConfirm(os.Stdin, os.Stdout, utils.IsTerminal(os.Stdin)))
+		}
+	}
 
-	// Load and reconcile module types
-	moduleTypes, err := dataLoader.LoadModuleTypes(buildPath(dataDir, "definitions/module_types"))
+	// Initialize data loader and the folder layout
+	dataLoader := loader.NewDataLoader(dataDir, logger)
+	layout, err := resolveLayout(dataDir, logger)
 	if err != nil {
-		logger.Error("Failed to load module types", err)
-		return err
+		logger.Error("Failed to load layout", err)
+		return c.Stats(), err
 	}
-	if err := deviceTypeReconciler.ReconcileModuleTypes(moduleTypes); err != nil {
-		logger.Error("Failed to reconcile module types", err)
-		return err
+	lookups, err := resolveLookupStrategies(dataDir, logger)
+	if err != nil {
+		logger.Error("Failed to load lookup strategies", err)
+		return c.Stats(), err
 	}
-
-	// Load and reconcile device types
-	deviceTypes, err := dataLoader.LoadDeviceTypes(buildPath(dataDir, "definitions/device_types"))
+	explicitOrder, err := resolveOrder(dataDir, logger)
 	if err != nil {
-		logger.Error("Failed to load device types", err)
-		return err
+		logger.Error("Failed to load reconcile order", err)
+		return c.Stats(), err
 	}
-	if err := deviceTypeReconciler.ReconcileDeviceTypes(deviceTypes); err != nil {
-		logger.Error("Failed to reconcile device types", err)
-		return err
+	siteFilter := loader.NewSiteFilter(siteSlugs)
+	if len(siteFilter) > 0 {
+		logger.Info("Limiting run to sites: %v", siteSlugs)
 	}
+	dataLoader.SetExcludes(excludes)
+	if err := dataLoader.SetOverrides(overrides); err != nil {
+		logger.Error("Invalid --set", err)
+		return c.Stats(), err
+	}
+	// Each step loads its definitions, which are kept for building the desired state when pruning
+	definitions := loader.NewDefinitions(dataLoader, layout, siteFilter)
 
 	// =========================================================================
-	// PHASE 3: DEVICES
+	// LOAD GLOBAL CACHES (MUST BE BEFORE RECONCILIATION)
 	// =========================================================================
-	logger.Info("═══════════════════════════════════════════════════════")
-	logger.Info("Phase 3: Devices")
-	logger.Info("═══════════════════════════════════════════════════════")
+	// Python does this in main.py lines 154-156 BEFORE device reconciliation
+	// We need to load it even earlier because foundation reconciler needs it
+	logger.Info("Loading global caches...")
+	if err := c.Cache().LoadGlobal(); err != nil {
+		logger.Error("Failed to load global caches", err)
+		return c.Stats(), err
+	}
+
+	if dumpCache {
+		return c.Stats(), dumpCaches(logger, c, definitions)
+	}
+
+	// =========================================================================
+	// RECONCILE (in dependency order, see reconciler.ResourceDependencies)
+	// =========================================================================
+	foundationReconciler := reconciler.NewFoundationReconciler(c)
+	tenancyReconciler := reconciler.NewTenancyReconciler(c)
+	extrasReconciler := reconciler.NewExtrasReconciler(c)
+	networkReconciler := reconciler.NewNetworkReconciler(c)
+	deviceTypeReconciler := reconciler.NewDeviceTypeReconciler(c)
+	deviceReconciler := reconciler.NewDeviceReconciler(c)
+	deviceReconciler.SetStatusChangeGuard(allowMassStatusChange, massStatusChangeThreshold)
+	deviceReconciler.AllowInterfaceTypeChange(allowInterfaceTypeChange)
+	deviceReconciler.InterfacesFromTemplate(interfacesFromTemplate)
+	deviceReconciler.DeferCables()
+	if err := deviceReconciler.SetLookupStrategy(lookups["devices"]); err != nil {
+		return c.Stats(), err
+	}
+	virtualChassisReconciler := reconciler.NewVirtualChassisReconciler(c)
+	fhrpReconciler := reconciler.NewFHRPReconciler(c)
+	circuitReconciler := reconciler.NewCircuitReconciler(c)
 
-	// Load devices from inventory
-	activeDevices, err := dataLoader.LoadDevices(buildPath(dataDir, "inventory/hardware/active"))
+	// Resource types whose global caches failed to load are skipped, the rest still run.
+	cacheGuard := reconciler.NewCacheGuard(c)
+	graph, err := reconciler.NewResourceGraph(cacheGuard.Wrap(map[string]func() error{
+		"tags": func() error {
+			tags, err := loader.Load[*models.Tag](definitions, "tags")
+			if err != nil {
+				return err
+			}
+			return foundationReconciler.ReconcileTags(tags)
+		},
+		"contact_groups": func() error {
+			groups, err := loader.Load[*models.ContactGroup](definitions, "contact_groups")
+			if err != nil {
+				return err
+			}
+			return tenancyReconciler.ReconcileContactGroups(groups)
+		},
+		"contact_roles": func() error {
+			contactRoles, err := loader.Load[*models.ContactRole](definitions, "contact_roles")
+			if err != nil {
+				return err
+			}
+			return tenancyReconciler.ReconcileContactRoles(contactRoles)
+		},
+		"tenant_groups": func() error {
+			groups, err := loader.Load[*models.TenantGroup](definitions, "tenant_groups")
+			if err != nil {
+				return err
+			}
+			return tenancyReconciler.ReconcileTenantGroups(groups)
+		},
+		"tenants": func() error {
+			tenants, err := loader.Load[*models.Tenant](definitions, "tenants")
+			if err != nil {
+				return err
+			}
+			return tenancyReconciler.ReconcileTenants(tenants)
+		},
+		"contacts": func() error {
+			contacts, err := loader.Load[*models.Contact](definitions, "contacts")
+			if err != nil {
+				return err
+			}
+			return tenancyReconciler.ReconcileContacts(contacts)
+		},
+		"role_groups": func() error {
+			roleGroups, err := loader.Load[*models.RoleGroup](definitions, "role_groups")
+			if err != nil {
+				return err
+			}
+			return foundationReconciler.ReconcileRoleGroups(roleGroups)
+		},
+		"roles": func() error {
+			roles, err := loader.Load[*models.Role](definitions, "roles")
+			if err != nil {
+				return err
+			}
+			return foundationReconciler.ReconcileRoles(roles)
+		},
+		"inventory_item_roles": func() error {
+			inventoryItemRoles, err := loader.Load[*models.InventoryItemRole](definitions, "inventory_item_roles")
+			if err != nil {
+				return err
+			}
+			return foundationReconciler.ReconcileInventoryItemRoles(inventoryItemRoles)
+		},
+		"sites": func() error {
+			sites, err := loader.Load[*models.Site](definitions, "sites")
+			if err != nil {
+				return err
+			}
+			return foundationReconciler.ReconcileSites(sites)
+		},
+		"racks": func() error {
+			racks, err := loader.Load[*models.Rack](definitions, "racks")
+			if err != nil {
+				return err
+			}
+			return foundationReconciler.ReconcileRacks(racks)
+		},
+		"config_contexts": func() error {
+			configContexts, err := loader.Load[*models.ConfigContext](definitions, "config_contexts")
+			if err != nil {
+				return err
+			}
+			return foundationReconciler.ReconcileConfigContexts(configContexts)
+		},
+		"custom_field_choice_sets": func() error {
+			choiceSets, err := loader.Load[*models.CustomFieldChoiceSet](definitions, "custom_field_choice_sets")
+			if err != nil {
+				return err
+			}
+			return extrasReconciler.ReconcileCustomFieldChoiceSets(choiceSets)
+		},
+		"custom_fields": func() error {
+			customFields, err := loader.Load[*models.CustomFieldDef](definitions, "custom_fields")
+			if err != nil {
+				return err
+			}
+			return extrasReconciler.ReconcileCustomFields(customFields)
+		},
+		"webhooks": func() error {
+			webhooks, err := loader.Load[*models.Webhook](definitions, "webhooks")
+			if err != nil {
+				return err
+			}
+			return extrasReconciler.ReconcileWebhooks(webhooks)
+		},
+		"export_templates": func() error {
+			exportTemplates, err := loader.Load[*models.ExportTemplate](definitions, "export_templates")
+			if err != nil {
+				return err
+			}
+			return extrasReconciler.ReconcileExportTemplates(exportTemplates)
+		},
+		"config_templates": func() error {
+			configTemplates, err := loader.Load[*models.ConfigTemplate](definitions, "config_templates")
+			if err != nil {
+				return err
+			}
+			return extrasReconciler.ReconcileConfigTemplates(configTemplates)
+		},
+		"vrfs": func() error {
+			vrfs, err := loader.Load[*models.VRF](definitions, "vrfs")
+			if err != nil {
+				return err
+			}
+			return networkReconciler.ReconcileVRFs(vrfs)
+		},
+		"ipam_roles": func() error {
+			ipamRoles, err := loader.Load[*models.IPAMRole](definitions, "ipam_roles")
+			if err != nil {
+				return err
+			}
+			return networkReconciler.ReconcileIPAMRoles(ipamRoles)
+		},
+		"vlan_groups": func() error {
+			vlanGroups, err := loader.Load[*models.VLANGroup](definitions, "vlan_groups")
+			if err != nil {
+				return err
+			}
+			return networkReconciler.ReconcileVLANGroups(vlanGroups)
+		},
+		"vlans": func() error {
+			vlans, err := loader.Load[*models.VLAN](definitions, "vlans")
+			if err != nil {
+				return err
+			}
+			return networkReconciler.ReconcileVLANs(vlans)
+		},
+		"prefixes": func() error {
+			prefixes, err := loader.Load[*models.Prefix](definitions, "prefixes")
+			if err != nil {
+				return err
+			}
+			return networkReconciler.ReconcilePrefixes(prefixes)
+		},
+		"vlan_translation_policies": func() error {
+			policies, err := loader.Load[*models.VLANTranslationPolicy](definitions, "vlan_translation_policies")
+			if err != nil {
+				return err
+			}
+			return networkReconciler.ReconcileVLANTranslationPolicies(policies)
+		},
+		"module_types": func() error {
+			moduleTypes, err := loader.Load[*models.ModuleType](definitions, "module_types")
+			if err != nil {
+				return err
+			}
+			return deviceTypeReconciler.ReconcileModuleTypes(moduleTypes)
+		},
+		"device_types": func() error {
+			deviceTypes, err := loader.Load[*models.DeviceType](definitions, "device_types")
+			if err != nil {
+				return err
+			}
+			return deviceTypeReconciler.ReconcileDeviceTypes(deviceTypes)
+		},
+		"devices": func() error {
+			devices, err := loader.Load[*models.DeviceConfig](definitions, "devices")
+			if err != nil {
+				return err
+			}
+
+			logger.Info("Loaded %d devices from inventory", len(devices))
+
+			// Load site-specific caches
+			uniqueSites := make(map[string]bool)
+			for _, device := range devices {
+				uniqueSites[device.SiteSlug] = true
+			}
+
+			logger.Info("Loading site caches for: %v", getKeys(uniqueSites))
+			if err := c.Cache().LoadSites(getKeys(uniqueSites), constants.SiteCacheConcurrency); err != nil {
+				return fmt.Errorf("failed to load site caches: %w", err)
+			}
+
+			return deviceReconciler.ReconcileDevices(devices)
+		},
+		"virtual_chassis": func() error {
+			chassis, err := loader.Load[*models.VirtualChassis](definitions, "virtual_chassis")
+			if err != nil {
+				return err
+			}
+			return virtualChassisReconciler.ReconcileVirtualChassis(chassis, loader.Loaded[*models.DeviceConfig](definitions, "devices"))
+		},
+		"fhrp_groups": func() error {
+			groups, err := loader.Load[*models.FHRPGroup](definitions, "fhrp_groups")
+			if err != nil {
+				return err
+			}
+			return fhrpReconciler.ReconcileFHRPGroups(groups, loader.Loaded[*models.DeviceConfig](definitions, "devices"))
+		},
+		"cables": deviceReconciler.ReconcileCables,
+		"providers": func() error {
+			providers, err := loader.Load[*models.Provider](definitions, "providers")
+			if err != nil {
+				return err
+			}
+			return circuitReconciler.ReconcileProviders(providers)
+		},
+		"circuit_types": func() error {
+			circuitTypes, err := loader.Load[*models.CircuitType](definitions, "circuit_types")
+			if err != nil {
+				return err
+			}
+			return circuitReconciler.ReconcileCircuitTypes(circuitTypes)
+		},
+		"circuits": func() error {
+			circuits, err := loader.Load[*models.Circuit](definitions, "circuits")
+			if err != nil {
+				return err
+			}
+			return circuitReconciler.ReconcileCircuits(circuits)
+		},
+	}))
 	if err != nil {
-		logger.Error("Failed to load active devices", err)
-		return err
+		logger.Error("Failed to build reconciliation graph", err)
+		return c.Stats(), err
 	}
 
-	passiveDevices, err := dataLoader.LoadDevices(buildPath(dataDir, "inventory/hardware/passive"))
+	// Steps left out of an explicit order, whose types are kept out of --prune and --report-orphans
+	var skippedSteps []string
+	order, err := graph.Order()
 	if err != nil {
-		logger.Error("Failed to load passive devices", err)
-		return err
+		logger.Error("Invalid reconciliation graph", err)
+		return c.Stats(), err
+	}
+	if explicitOrder != nil {
+		warnings, err := graph.CheckOrder(explicitOrder)
+		if err != nil {
+			logger.Error("Invalid reconcile order", err)
+			return c.Stats(), err
+		}
+		for _, warning := range warnings {
+			logger.Warning("Reconcile order: %s", warning)
+		}
+		skippedSteps = graph.Omitted(explicitOrder)
+		if len(skippedSteps) > 0 {
+			logger.Info("Reconcile order disables: %s", strings.Join(skippedSteps, ", "))
+		}
+		// Disabled steps load no definitions, so pruning their types would delete everything
+		if pruner != nil {
+			if err := pruner.CheckSkipped(skippedSteps); err != nil {
+				logger.Error("Invalid prune scope", err)
+				return c.Stats(), err
+			}
+		}
+		if skipped := reconciler.SkippedPruneTypes(skippedSteps); reportOrphans && len(skipped) > 0 {
+			logger.Info("Orphan report leaves out %s (disabled in the reconcile order)", strings.Join(skipped, ", "))
+		}
+		order = explicitOrder
 	}
+	logger.Debug("Reconciliation order: %s", strings.Join(order, " → "))
 
-	allDevices := append(activeDevices, passiveDevices...)
-	logger.Info("Loaded %d devices from inventory", len(allDevices))
+	// Progress line below the log output, only on an interactive terminal
+	stopProgress := func() {}
+	if showProgress && !quietNoChange && utils.IsTerminal(os.Stdout) {
+		progress := utils.NewProgress(os.Stdout, len(order))
+		graph.SetStepHooks(progress.StartPhase, progress.FinishPhase)
+		c.Stats().SetObserver(progress.Record)
+		utils.SetActiveProgress(progress)
+		stopProgress = func() {
+			utils.SetActiveProgress(nil)
+			progress.Stop()
+		}
+	}
 
-	// Load site-specific caches
-	uniqueSites := make(map[string]bool)
-	for _, device := range allDevices {
-		uniqueSites[device.SiteSlug] = true
+	if explicitOrder != nil {
+		err = graph.ExecuteOrder(explicitOrder)
+	} else {
+		err = graph.Execute(concurrency)
 	}
+	stopProgress()
 
-	logger.Info("Loading site caches for: %v", getKeys(uniqueSites))
-	for siteSlug := range uniqueSites {
-		if err := c.Cache().LoadSite(siteSlug); err != nil {
-			logger.Error("Failed to load site cache for "+siteSlug, err)
-			return err
+	for _, override := range dataLoader.UnusedOverrides() {
+		logger.Warning("Override --set %s matched no loaded object", override.Raw)
+	}
+
+	// Journal what was changed, even if the run failed part-way
+	if journalErr := c.FlushJournal(); journalErr != nil {
+		logger.Warning("Failed to record journal entries: %v", journalErr)
+	}
+	if timedOut := c.TimedOutObjects(); err == nil && len(timedOut) > 0 {
+		for _, object := range timedOut {
+			logger.Warning("Timed out: %s", object)
 		}
+		err = fmt.Errorf("%d objects exceeded --reconcile-timeout-per-object %s and were skipped", len(timedOut), objectTimeout)
+	}
+	if err != nil {
+		logger.Error("Failed to reconcile", err)
+		return c.Stats(), err
+	}
+	// Don't prune or record the run when resource types were skipped
+	if err := cacheGuard.Err(); err != nil {
+		logger.Error("Some resource types were not reconciled", err)
+		return c.Stats(), err
 	}
 
-	// Reconcile devices
-	deviceReconciler := reconciler.NewDeviceReconciler(c)
-	if err := deviceReconciler.ReconcileDevices(allDevices); err != nil {
-		logger.Error("Failed to reconcile devices", err)
-		return err
+	// =========================================================================
+	// PRUNE
+	// =========================================================================
+	var desired *reconciler.DesiredState
+	if pruner != nil || reportOrphans {
+		desired = buildDesiredState(definitions)
+	}
+
+	if pruner != nil {
+		logger.Info("═══════════════════════════════════════════════════════")
+		logger.Info("Prune: %v", pruneScope)
+		logger.Info("═══════════════════════════════════════════════════════")
+
+		if err := pruner.Prune(desired); err != nil {
+			logger.Error("Failed to prune", err)
+			return c.Stats(), err
+		}
+	}
+
+	// Record the successful run (after our own updates, so they are not flagged next time)
+	if warnOnExternalChange && !dryRun {
+		if err := client.WriteLastRun(lastRunFile, time.Now()); err != nil {
+			logger.Warning("Failed to record last run time: %v", err)
+		}
 	}
 
 	// =========================================================================
@@ -261,8 +787,88 @@ func runSync(cmd *cobra.Command, args []string) error {
 		logger.Success("SYNC COMPLETE: Changes applied successfully")
 	}
 	logger.Info("═══════════════════════════════════════════════════════")
+	if diffOnlyChanged {
+		c.Stats().PrintCompactDiff(logger)
+	}
+	c.Stats().PrintSummary(logger)
 
-	return nil
+	// Read-only: what --prune would delete with every scope
+	if reportOrphans {
+		orphans, err := reconciler.FindOrphans(c, desired, skippedSteps)
+		if err != nil {
+			logger.Error("Failed to find orphans", err)
+			return c.Stats(), err
+		}
+		reconciler.PrintOrphanReport(logger, orphans)
+	}
+
+	return c.Stats(), nil
+}
+
+// buildDesiredState collects the identities of all objects declared in YAML for pruning,
+// from the definitions the steps of the run loaded
+func buildDesiredState(definitions *loader.Definitions) *reconciler.DesiredState {
+	desired := reconciler.NewDesiredState()
+
+	for _, site := range loader.Loaded[*models.Site](definitions, "sites") {
+		desired.Add("sites", site.Slug)
+	}
+	for _, rack := range loader.Loaded[*models.Rack](definitions, "racks") {
+		desired.Add("racks", rack.SiteSlug+"/"+rack.Name)
+	}
+	for _, group := range loader.Loaded[*models.RoleGroup](definitions, "role_groups") {
+		desired.Add("roles", group.Slug)
+	}
+	for _, role := range loader.Loaded[*models.Role](definitions, "roles") {
+		desired.Add("roles", role.Slug)
+	}
+	for _, contact := range loader.Loaded[*models.Contact](definitions, "contacts") {
+		desired.Add("contacts", contact.Name)
+	}
+	for _, vrf := range loader.Loaded[*models.VRF](definitions, "vrfs") {
+		desired.Add("vrfs", vrf.Name)
+	}
+	for _, role := range loader.Loaded[*models.IPAMRole](definitions, "ipam_roles") {
+		desired.Add("ipam_roles", role.Slug)
+	}
+	for _, group := range loader.Loaded[*models.VLANGroup](definitions, "vlan_groups") {
+		desired.Add("vlan_groups", group.Slug)
+	}
+	for _, vlan := range loader.Loaded[*models.VLAN](definitions, "vlans") {
+		desired.Add("vlans", reconciler.VLANKey(vlan.SiteSlug, vlan.GroupSlug, vlan.VID))
+	}
+	for _, prefix := range loader.Loaded[*models.Prefix](definitions, "prefixes") {
+		desired.Add("prefixes", reconciler.PrefixKey(prefix.Prefix, prefix.VRFName))
+	}
+	for _, mt := range loader.Loaded[*models.ModuleType](definitions, "module_types") {
+		desired.Add("module_types", mt.Slug)
+	}
+	for _, dt := range loader.Loaded[*models.DeviceType](definitions, "device_types") {
+		desired.Add("device_types", dt.Slug)
+	}
+	for _, device := range loader.Loaded[*models.DeviceConfig](definitions, "devices") {
+		desired.Add("devices", device.Name)
+		for _, iface := range device.Interfaces {
+			desired.Add("interfaces", reconciler.ComponentKey(device.Name, iface.Name))
+			if iface.IP != nil {
+				desired.Add("ip_addresses", iface.IP.Address)
+			}
+		}
+		if len(device.ManagementIPs) > 0 {
+			desired.Add("interfaces", reconciler.ComponentKey(device.Name, constants.ManagementInterfaceName))
+		}
+		for _, ip := range device.ManagementIPs {
+			desired.Add("ip_addresses", ip.Address)
+		}
+		for _, port := range device.FrontPorts {
+			desired.Add("front_ports", reconciler.ComponentKey(device.Name, port.Name))
+		}
+		for _, port := range device.RearPorts {
+			desired.Add("rear_ports", reconciler.ComponentKey(device.Name, port.Name))
+		}
+	}
+
+	return desired
 }
 
 // getKeys returns the keys of a map as a slice
@@ -274,16 +880,53 @@ func getKeys(m map[string]bool) []string {
 	return keys
 }
 
+// prepareDataDir returns the data directory, cloning --repo first if set or extracting
+// --data-dir if it is an archive. The returned cleanup removes the clone or extraction.
+func prepareDataDir(logger *utils.Logger) (string, func(), error) {
+	if repoURL == "" && loader.IsArchive(dataDir) {
+		logger.Info("Extracting %s...", dataDir)
+		dir, cleanup, err := loader.ExtractArchive(dataDir)
+		if err != nil {
+			return "", nil, err
+		}
+		logger.Info("Using data directory: %s (from %s)", dir, dataDir)
+		return dir, cleanup, nil
+	}
+	if repoURL == "" {
+		dir, err := resolveDataDir(dataDir, logger)
+		return dir, func() {}, err
+	}
+
+	logger.Info("Cloning %s...", repoURL)
+	cloneDir, cleanup, err := loader.CloneRepo(repoURL, repoRef, os.Getenv(loader.RepoTokenEnv))
+	if err != nil {
+		return "", nil, err
+	}
+
+	// No fallback to example/ here: a typo must not sync the example data
+	dir := filepath.Join(cloneDir, dataDir)
+	if _, err := os.Stat(dir); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("data directory %s not found in %s", dataDir, repoURL)
+	}
+	logger.Info("Using data directory: %s (from %s)", dir, repoURL)
+	return dir, cleanup, nil
+}
+
 // resolveDataDir determines the correct data directory to use
 // It implements auto-detection: if definitions/ doesn't exist in the specified directory,
 // it falls back to the example/ directory
 func resolveDataDir(dir string, logger *utils.Logger) (string, error) {
-	// Check if definitions directory exists in the specified directory
+	// Check if definitions directory or a custom layout exists in the specified directory
 	definitionsPath := fmt.Sprintf("%s/definitions", dir)
 	if _, err := os.Stat(definitionsPath); err == nil {
 		logger.Info("Using data directory: %s", dir)
 		return dir, nil
 	}
+	if _, err := os.Stat(filepath.Join(dir, loader.LayoutFile)); err == nil || layoutFile != "" {
+		logger.Info("Using data directory: %s", dir)
+		return dir, nil
+	}
 
 	// If not in current directory, check if example/ directory exists
 	examplePath := "example"
@@ -296,10 +939,122 @@ func resolveDataDir(dir string, logger *utils.Logger) (string, error) {
 	return "", fmt.Errorf("no valid data directory found: checked '%s' and '%s'", dir, examplePath)
 }
 
-// buildPath constructs a path relative to the data directory
-func buildPath(dataDir, subPath string) string {
-	if dataDir == "." {
-		return subPath
+// dumpCaches loads the site caches of all inventory sites and prints every cached
+// identifier→ID mapping (limited to --dump-cache-resource), without reconciling anything
+func dumpCaches(logger *utils.Logger, c *client.NetBoxClient, definitions *loader.Definitions) error {
+	devices, err := loader.Load[*models.DeviceConfig](definitions, "devices")
+	if err != nil {
+		return err
+	}
+
+	uniqueSites := make(map[string]bool)
+	for _, device := range devices {
+		uniqueSites[device.SiteSlug] = true
+	}
+	if err := c.Cache().LoadSites(getKeys(uniqueSites), constants.SiteCacheConcurrency); err != nil {
+		return fmt.Errorf("failed to load site caches: %w", err)
+	}
+
+	dump := c.Cache().Dump(dumpCacheResources...)
+	resources := make([]string, 0, len(dump))
+	for resource := range dump {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+
+	for _, resource := range resources {
+		entries := dump[resource]
+		logger.Summary("%s (%d entries)", resource, len(entries))
+
+		identifiers := make([]string, 0, len(entries))
+		for identifier := range entries {
+			identifiers = append(identifiers, identifier)
+		}
+		sort.Strings(identifiers)
+		for _, identifier := range identifiers {
+			logger.Summary("  %s → %d", identifier, entries[identifier])
+		}
 	}
-	return fmt.Sprintf("%s/%s", dataDir, subPath)
+	return nil
+}
+
+// dumpPlan loads every definition the way a sync would and writes the result to --dump-plan-file.
+// An explicit reconcile order limits the plan to the steps it runs.
+func dumpPlan(logger *utils.Logger, dataDir string) error {
+	dataLoader := loader.NewDataLoader(dataDir, logger)
+	layout, err := resolveLayout(dataDir, logger)
+	if err != nil {
+		return err
+	}
+	explicitOrder, err := resolveOrder(dataDir, logger)
+	if err != nil {
+		return err
+	}
+	dataLoader.SetExcludes(excludes)
+	if err := dataLoader.SetOverrides(overrides); err != nil {
+		return err
+	}
+
+	resources := layout.Resources()
+	if explicitOrder != nil {
+		resources = explicitOrder
+	}
+	plan, err := loader.NewDefinitions(dataLoader, layout, loader.NewSiteFilter(siteSlugs)).Plan(resources)
+	if err != nil {
+		return err
+	}
+	for _, override := range dataLoader.UnusedOverrides() {
+		logger.Warning("Override --set %s matched no loaded object", override.Raw)
+	}
+
+	if err := loader.WritePlan(dumpPlanFile, plan); err != nil {
+		return err
+	}
+	logger.Success("Wrote plan to %s", dumpPlanFile)
+	return nil
+}
+
+// resolveLayout returns the folder layout: --layout if given, else layout.yaml in the
+// data directory if present, else the default definitions/ and inventory/ layout
+func resolveLayout(dataDir string, logger *utils.Logger) (loader.Layout, error) {
+	path := layoutFile
+	if path == "" {
+		path = filepath.Join(dataDir, loader.LayoutFile)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return loader.DefaultLayout(), nil
+		}
+	}
+
+	logger.Info("Using layout file: %s", path)
+	return loader.LoadLayout(path)
+}
+
+// resolveLookupStrategies returns the lookup strategies: --lookups if given, else lookups.yaml
+// in the data directory if present, else the defaults
+func resolveLookupStrategies(dataDir string, logger *utils.Logger) (reconciler.LookupStrategies, error) {
+	path := lookupFile
+	if path == "" {
+		path = filepath.Join(dataDir, reconciler.LookupStrategiesFile)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return reconciler.DefaultLookupStrategies(), nil
+		}
+	}
+
+	logger.Info("Using lookup strategy file: %s", path)
+	return reconciler.LoadLookupStrategies(path)
+}
+
+// resolveOrder returns the explicit reconcile order: --order if given, else order.yaml in the
+// data directory if present. Nil means the steps run in dependency order.
+func resolveOrder(dataDir string, logger *utils.Logger) ([]string, error) {
+	path := orderFile
+	if path == "" {
+		path = filepath.Join(dataDir, reconciler.OrderFile)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil, nil
+		}
+	}
+
+	logger.Info("Using reconcile order file: %s", path)
+	return reconciler.LoadOrder(path)
 }
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+func TestSyncReportedQuietNoChange(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+	dataDir := t.TempDir()
+	tags := filepath.Join(dataDir, "definitions", "extras")
+	if err := os.MkdirAll(tags, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tags, "tags.yaml"), []byte("- name: Production\n  slug: production\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	oldQuietNoChange, oldQuiet := quietNoChange, quiet
+	quietNoChange = true
+	t.Cleanup(func() { quietNoChange, quiet = oldQuietNoChange, oldQuiet })
+
+	var out, errOut bytes.Buffer
+	logger := utils.NewLogger(false)
+	logger.SetOutput(&out, &errOut)
+
+	// The first run creates the tag, so its output is shown in full
+	stats, err := syncReported(context.Background(), logger, dataDir, srv.URL, "test-token")
+	if err != nil {
+		t.Fatalf("syncReported() error = %v", err)
+	}
+	if stats.Changes() == 0 {
+		t.Fatal("First run changed nothing, expected the tag to be created")
+	}
+	if !strings.Contains(out.String(), "SYNC COMPLETE") {
+		t.Errorf("Run with changes printed:\n%s\nexpected the full output", out.String())
+	}
+
+	out.Reset()
+	if _, err := syncReported(context.Background(), logger, dataDir, srv.URL, "test-token"); err != nil {
+		t.Fatalf("syncReported() error = %v", err)
+	}
+	if got := strings.TrimSpace(out.String()); got != "No changes" || errOut.Len() != 0 {
+		t.Errorf("No-op run printed %q (stderr %q), expected only \"No changes\"", got, errOut.String())
+	}
+
+	quiet = true
+	out.Reset()
+	if _, err := syncReported(context.Background(), logger, dataDir, srv.URL, "test-token"); err != nil {
+		t.Fatalf("syncReported() error = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("No-op run with --quiet printed %q, expected nothing", out.String())
+	}
+}
+
+// TestSyncOnceCanceled tests that a canceled context (Ctrl-C during --watch) aborts the sync
+// before anything is written
+func TestSyncOnceCanceled(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+	dataDir := t.TempDir()
+	writeTestFile(t, dataDir, "definitions/extras/tags.yaml", "- name: Production\n  slug: production\n")
+
+	var out bytes.Buffer
+	logger := utils.NewLogger(false)
+	logger.SetOutput(&out, &out)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := syncOnce(ctx, logger, dataDir, srv.URL, "test-token")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("syncOnce() error = %v, expected context.Canceled", err)
+	}
+	if srv.Find("extras", "tags", "slug", "production") != nil {
+		t.Error("A canceled sync created the production tag")
+	}
+}
+
+// TestSyncPruneWithOrderOmittingType tests that a resource type left out of order.yaml is
+// neither pruned nor reported as orphaned, since its definitions are never loaded
+func TestSyncPruneWithOrderOmittingType(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+	managed := []interface{}{map[string]interface{}{"slug": "gitops"}}
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc", "tags": managed})
+	srv.Add("ipam", "vrfs", map[string]interface{}{"name": "legacy", "tags": managed})
+
+	dataDir := t.TempDir()
+	writeTestFile(t, dataDir, "definitions/sites/sites.yaml", "- name: Berlin DC\n  slug: berlin-dc\n")
+	writeTestFile(t, dataDir, "order.yaml", "- tags\n- vrfs\n")
+
+	oldPrune, oldScope, oldYes, oldOrphans := prune, pruneScope, assumeYes, reportOrphans
+	t.Cleanup(func() { prune, pruneScope, assumeYes, reportOrphans = oldPrune, oldScope, oldYes, oldOrphans })
+	prune, pruneScope, assumeYes = true, []string{"sites"}, true
+
+	var out bytes.Buffer
+	logger := utils.NewLogger(false)
+	logger.SetOutput(&out, &out)
+
+	_, err := syncOnce(context.Background(), logger, dataDir, srv.URL, "test-token")
+	if err == nil || !strings.Contains(err.Error(), "cannot prune sites") {
+		t.Errorf("syncOnce() error = %v, expected pruning the disabled sites step to be rejected", err)
+	}
+	if got := srv.CountRequests("DELETE", "/api/dcim/sites/"); got != 0 {
+		t.Errorf("Sites skipped by order.yaml were deleted %d times", got)
+	}
+
+	prune, pruneScope, reportOrphans = false, nil, true
+	out.Reset()
+	if _, err := syncOnce(context.Background(), logger, dataDir, srv.URL, "test-token"); err != nil {
+		t.Fatalf("syncOnce() error = %v", err)
+	}
+	if strings.Contains(out.String(), "berlin-dc") {
+		t.Errorf("Orphan report lists a site of the disabled sites step:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "legacy") {
+		t.Errorf("Orphan report does not list the undeclared VRF legacy:\n%s", out.String())
+	}
+}
+
+// TestWatchCycleReclonesRepo tests that each --watch cycle with --repo syncs the latest commit
+func TestWatchCycleReclonesRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v: %s", args, err, output)
+		}
+	}
+
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+	work, bare := t.TempDir(), t.TempDir()
+	git(bare, "init", "--quiet", "--bare")
+	git(work, "init", "--quiet")
+	push := func(tags string) {
+		writeTestFile(t, work, "definitions/extras/tags.yaml", tags)
+		git(work, "add", ".")
+		git(work, "commit", "--quiet", "-m", "Update tags")
+		git(work, "push", "--quiet", bare, "HEAD:refs/heads/main")
+	}
+
+	oldRepoURL, oldRepoRef, oldDataDir := repoURL, repoRef, dataDir
+	repoURL, repoRef, dataDir = "file://"+bare, "main", "."
+	t.Cleanup(func() { repoURL, repoRef, dataDir = oldRepoURL, oldRepoRef, oldDataDir })
+
+	logger := utils.NewLogger(false)
+	logger.SetOutput(&bytes.Buffer{}, &bytes.Buffer{})
+
+	push("- name: Production\n  slug: production\n")
+	if _, err := watchCycle(context.Background(), logger, "unused", srv.URL, "test-token", true); err != nil {
+		t.Fatalf("watchCycle() error = %v", err)
+	}
+	push("- name: Production\n  slug: production\n- name: Staging\n  slug: staging\n")
+	if _, err := watchCycle(context.Background(), logger, "unused", srv.URL, "test-token", true); err != nil {
+		t.Fatalf("watchCycle() error = %v", err)
+	}
+	if srv.Find("extras", "tags", "slug", "staging") == nil {
+		t.Error("Tag staging pushed between cycles was not synced")
+	}
+}
+
+func writeTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/braunma/netbox-gitops-controller/internal/version"
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+)
+
+// newVersionCmd creates the version subcommand
+func newVersionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print the controller's version and, if NETBOX_URL and a token are set, the NetBox version",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printVersion(cmd.OutOrStdout(), netboxServerVersion)
+			return nil
+		},
+	}
+}
+
+// printVersion writes the build information and the NetBox version from serverVersion
+// (an empty version means no NetBox is configured)
+func printVersion(out io.Writer, serverVersion func() (string, error)) {
+	fmt.Fprintf(out, "Version:    %s\n", version.Version)
+	fmt.Fprintf(out, "Commit:     %s\n", version.Commit)
+	fmt.Fprintf(out, "Build date: %s\n", version.BuildDate)
+
+	netboxVersion, err := serverVersion()
+	switch {
+	case err != nil:
+		fmt.Fprintf(out, "NetBox:     unavailable (%v)\n", err)
+	case netboxVersion != "":
+		fmt.Fprintf(out, "NetBox:     %s\n", netboxVersion)
+	}
+}
+
+// netboxServerVersion asks the configured NetBox for its version, read-only ("" without NETBOX_URL or token)
+func netboxServerVersion() (string, error) {
+	netboxURL := os.Getenv("NETBOX_URL")
+	if netboxURL == "" {
+		return "", nil
+	}
+	tokenProvider, err := client.NewTokenProvider(client.TokenSourceAuto, "")
+	if err != nil {
+		return "", err
+	}
+	netboxToken, err := tokenProvider.Token()
+	if err != nil || netboxToken == "" {
+		return "", nil
+	}
+
+	// Dry-run, so looking up the version never creates the managed tag
+	c, err := client.NewClient(netboxURL, netboxToken, true)
+	if err != nil {
+		return "", err
+	}
+	c.Logger().SetOutput(io.Discard, io.Discard)
+	return c.ServerVersion()
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/version"
+)
+
+// setBuildInfo sets the -ldflags injected build information for one test
+func setBuildInfo(t *testing.T, v, commit, date string) {
+	t.Helper()
+	oldVersion, oldCommit, oldDate := version.Version, version.Commit, version.BuildDate
+	version.Version, version.Commit, version.BuildDate = v, commit, date
+	t.Cleanup(func() { version.Version, version.Commit, version.BuildDate = oldVersion, oldCommit, oldDate })
+}
+
+func TestVersionCommand(t *testing.T) {
+	setBuildInfo(t, "1.4.2", "abc1234", "2025-03-01T12:00:00Z")
+	t.Setenv("NETBOX_URL", "")
+
+	cmd := newVersionCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs(nil)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("version error = %v", err)
+	}
+
+	for _, expected := range []string{"1.4.2", "abc1234", "2025-03-01T12:00:00Z"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("version output is missing %q:\n%s", expected, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "NetBox") {
+		t.Errorf("version output without NETBOX_URL should not mention NetBox:\n%s", out.String())
+	}
+}
+
+func TestPrintVersionNetBox(t *testing.T) {
+	setBuildInfo(t, "1.4.2", "abc1234", "2025-03-01T12:00:00Z")
+
+	var out bytes.Buffer
+	printVersion(&out, func() (string, error) { return "4.1.3", nil })
+	if !strings.Contains(out.String(), "NetBox:     4.1.3") {
+		t.Errorf("printVersion() output is missing the NetBox version:\n%s", out.String())
+	}
+
+	out.Reset()
+	printVersion(&out, func() (string, error) { return "", fmt.Errorf("connection refused") })
+	if !strings.Contains(out.String(), "unavailable (connection refused)") {
+		t.Errorf("printVersion() output is missing the NetBox error:\n%s", out.String())
+	}
+}
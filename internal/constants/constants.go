@@ -1,5 +1,7 @@
 package constants
 
+import "time"
+
 // Managed tag constants
 const (
 	ManagedTagSlug        = "gitops"
@@ -15,11 +17,42 @@ const (
 	DefaultLengthUnit  = "m"
 )
 
+// Mass status change safety: a run may not change the status of more than
+// this percentage of existing managed devices without --allow-mass-status-change
+const (
+	DefaultMassStatusChangePercent = 50
+	MassStatusChangeMinDevices     = 3
+)
+
+// DefaultMaxDeletes is the largest number of objects --prune may delete in one run
+// without raising --max-deletes
+const DefaultMaxDeletes = 10
+
+// Device-level management IPs (DeviceConfig.ManagementIPs) are assigned to this
+// interface, created on the device if it does not exist
+const (
+	ManagementInterfaceName = "mgmt0"
+	ManagementInterfaceType = "virtual"
+)
+
+// Fields a cable's peer ports are matched by (LinkConfig.PeerPortBy)
+const (
+	PeerPortByName  = "name"
+	PeerPortByLabel = "label"
+)
+
+// NetBox field length limits
+const (
+	MaxSerialLength   = 50
+	MaxAssetTagLength = 50
+)
+
 // Termination types
 const (
-	TerminationInterface = "dcim.interface"
-	TerminationFrontPort = "dcim.frontport"
-	TerminationRearPort  = "dcim.rearport"
+	TerminationInterface          = "dcim.interface"
+	TerminationFrontPort          = "dcim.frontport"
+	TerminationRearPort           = "dcim.rearport"
+	TerminationCircuitTermination = "circuits.circuittermination"
 )
 
 // Endpoints
@@ -37,6 +70,37 @@ const (
 	WaitAfterModuleDelete = 200
 )
 
+// APIPageSize is the page size used when listing all objects (NetBox MAX_PAGE_SIZE default)
+const APIPageSize = 1000
+
+// Asset tags are looked up with a repeated asset_tag filter, AssetTagBatchSize per request,
+// so the query string stays within URL length limits
+const AssetTagBatchSize = 100
+
+// Journal entries (--journal) are posted in bulk batches of JournalBatchSize,
+// at most MaxJournalEntries per run
+const (
+	JournalBatchSize  = 50
+	MaxJournalEntries = 500
+)
+
+// HTTP keep-alive pool defaults, above Go's (2 idle connections per host) so parallel
+// requests (--concurrency, site caches) reuse connections instead of new TLS handshakes
+const (
+	DefaultMaxIdleConns        = 100
+	DefaultMaxIdleConnsPerHost = 16
+	DefaultIdleConnTimeout     = 90 * time.Second
+)
+
+// DefaultFHRPPriority is the priority of FHRP group assignments that don't set one (the VRRP default)
+const DefaultFHRPPriority = 100
+
+// DoctorDialTimeout bounds the TLS handshake of the doctor command's certificate check
+const DoctorDialTimeout = 10 * time.Second
+
+// SiteCacheConcurrency is the maximum number of site caches loaded in parallel
+const SiteCacheConcurrency = 4
+
 // Template endpoints (don't support tags)
 var TemplateEndpoints = []string{
 	"interface_templates",
@@ -46,10 +110,70 @@ var TemplateEndpoints = []string{
 	"module_bay_templates",
 }
 
-// Field transforms for API calls
+// Endpoints the managed tag must never be injected for: config contexts, whose "tags"
+// field selects which objects they apply to rather than marking ownership, and objects
+// that have no tags
+var UntaggedEndpoints = []string{
+	"config-contexts",
+	"custom-fields",
+	"custom-field-choice-sets",
+	"fhrp-group-assignments",
+}
+
+// Wireless interface types (the only types that accept tx_power and rf_channel)
+var WirelessInterfaceTypes = []string{
+	"ieee802.11a",
+	"ieee802.11g",
+	"ieee802.11n",
+	"ieee802.11ac",
+	"ieee802.11ad",
+	"ieee802.11ax",
+	"ieee802.11ay",
+	"ieee802.11be",
+	"ieee802.15.1",
+	"other-wireless",
+}
+
+// Device type airflow directions NetBox accepts
+var AirflowChoices = []string{
+	"front-to-rear",
+	"rear-to-front",
+	"left-to-right",
+	"right-to-left",
+	"side-to-rear",
+	"rear-to-side",
+	"bottom-to-top",
+	"top-to-bottom",
+	"passive",
+	"mixed",
+}
+
+// Weight units NetBox accepts
+var WeightUnitChoices = []string{"kg", "g", "lb", "oz"}
+
+// DefaultMTU is the MTU an interface without one uses on most platforms; larger MTUs are jumbo frames
+const DefaultMTU = 1500
+
+// MaxJumboMTU is the largest interface MTU most platforms support; larger MTUs are warned about
+const MaxJumboMTU = 9216
+
+// MaxTxPower is the highest transmit power (dBm) NetBox accepts
+const MaxTxPower = 127
+
+// Webhook event names mapped to NetBox event rule event types
+var WebhookEventTypes = map[string]string{
+	"create": "object_created",
+	"update": "object_updated",
+	"delete": "object_deleted",
+}
+
+// Field transforms for API calls: filter (lookup) key → payload field, applied by
+// client.PayloadKey/LookupKey
 var FieldTransforms = map[string]string{
 	"device_type_id": "device_type",
 	"module_type_id": "module_type",
+	"device_id":      "device",
+	"module_bay_id":  "module_bay",
 }
 
 // Cache resource types
@@ -61,6 +185,11 @@ var CacheResourceTypes = []string{
 	"racks",
 	"vlans",
 	"vrfs",
+	"ipam_roles",
+	"contact_groups",
+	"contact_roles",
+	"contacts",
+	"clusters",
 	"tags",
 	"manufacturers",
 }
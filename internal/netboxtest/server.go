@@ -0,0 +1,399 @@
+// Package netboxtest provides an in-memory NetBox API used by tests
+package netboxtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Request records a single request received by the fake server
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   map[string]interface{}
+}
+
+// Server is a minimal in-memory NetBox REST API
+// Objects are stored per "app/endpoint" and filtered using NetBox-style query parameters
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	objects    map[string][]map[string]interface{}
+	nextID     int
+	requests   []Request
+	intercepts map[string]http.HandlerFunc
+}
+
+// NewServer starts a new fake NetBox server
+func NewServer() *Server {
+	s := &Server{
+		objects:    make(map[string][]map[string]interface{}),
+		nextID:     1,
+		intercepts: make(map[string]http.HandlerFunc),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Add stores an object under app/endpoint and returns it with an assigned ID
+func (s *Server) Add(app, endpoint string, obj map[string]interface{}) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.add(app+"/"+endpoint, obj)
+}
+
+func (s *Server) add(key string, obj map[string]interface{}) map[string]interface{} {
+	if _, ok := obj["id"]; !ok {
+		obj["id"] = s.nextID
+		s.nextID++
+	} else if id := ID(obj["id"]); id >= s.nextID {
+		s.nextID = id + 1
+	}
+	s.objects[key] = append(s.objects[key], obj)
+	return obj
+}
+
+// Objects returns all stored objects of app/endpoint
+func (s *Server) Objects(app, endpoint string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]map[string]interface{}(nil), s.objects[app+"/"+endpoint]...)
+}
+
+// Find returns the first stored object of app/endpoint whose field equals value
+func (s *Server) Find(app, endpoint, field string, value interface{}) map[string]interface{} {
+	for _, obj := range s.Objects(app, endpoint) {
+		if fmt.Sprint(obj[field]) == fmt.Sprint(value) {
+			return obj
+		}
+	}
+	return nil
+}
+
+// Requests returns all recorded requests
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// CountRequests counts recorded requests matching method and path prefix
+func (s *Server) CountRequests(method, pathPrefix string) int {
+	count := 0
+	for _, r := range s.Requests() {
+		if r.Method == method && strings.HasPrefix(r.Path, pathPrefix) {
+			count++
+		}
+	}
+	return count
+}
+
+// ResetRequests clears the recorded requests
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = nil
+}
+
+// Intercept overrides the handling of requests with the given method and exact path
+func (s *Server) Intercept(method, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.intercepts[method+" "+path] = handler
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	raw, _ := io.ReadAll(r.Body)
+	var body map[string]interface{}
+	var bulk []map[string]interface{} // bulk creates send a list of objects
+	if len(raw) > 0 {
+		if raw[0] == '[' {
+			_ = json.Unmarshal(raw, &bulk)
+		} else {
+			_ = json.Unmarshal(raw, &body)
+		}
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Header: r.Header.Clone(), Body: body})
+	intercept := s.intercepts[r.Method+" "+r.URL.Path]
+	s.mu.Unlock()
+
+	if intercept != nil {
+		r.Body = io.NopCloser(strings.NewReader(string(raw)))
+		intercept(w, r)
+		return
+	}
+
+	// Paths look like /api/{app}/{endpoint}/ or /api/{app}/{endpoint}/{id}/
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || parts[0] != "api" {
+		http.NotFound(w, r)
+		return
+	}
+	key := parts[1] + "/" + parts[2]
+
+	id := 0
+	if len(parts) >= 4 {
+		id, _ = strconv.Atoi(parts[3])
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodGet && id == 0:
+		results := []map[string]interface{}{}
+		for _, obj := range s.objects[key] {
+			if s.matches(obj, r.URL.Query()) {
+				results = append(results, obj)
+			}
+		}
+		writeJSON(w, http.StatusOK, paginate(r, results))
+	case r.Method == http.MethodGet:
+		if obj := s.get(key, id); obj != nil {
+			writeJSON(w, http.StatusOK, obj)
+			return
+		}
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"detail": "Not found."})
+	case r.Method == http.MethodPost && bulk != nil:
+		created := make([]map[string]interface{}, len(bulk))
+		for i, obj := range bulk {
+			delete(obj, "id")
+			created[i] = s.add(key, obj)
+		}
+		writeJSON(w, http.StatusCreated, created)
+	case r.Method == http.MethodPost:
+		if body == nil {
+			body = map[string]interface{}{}
+		}
+		delete(body, "id")
+		writeJSON(w, http.StatusCreated, s.add(key, body))
+	case r.Method == http.MethodPatch || r.Method == http.MethodPut:
+		obj := s.get(key, id)
+		if obj == nil {
+			writeJSON(w, http.StatusNotFound, map[string]interface{}{"detail": "Not found."})
+			return
+		}
+		for k, v := range body {
+			obj[k] = v
+		}
+		writeJSON(w, http.StatusOK, obj)
+	case r.Method == http.MethodDelete:
+		objs := s.objects[key]
+		for i, obj := range objs {
+			if ID(obj["id"]) == id {
+				s.objects[key] = append(objs[:i], objs[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"detail": "Not found."})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) get(key string, id int) map[string]interface{} {
+	for _, obj := range s.objects[key] {
+		if ID(obj["id"]) == id {
+			return obj
+		}
+	}
+	return nil
+}
+
+// paginate applies NetBox limit/offset pagination (default page size 50) to a result list
+func paginate(r *http.Request, results []map[string]interface{}) map[string]interface{} {
+	query := r.URL.Query()
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultPageSize
+	}
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	if offset > len(results) {
+		offset = len(results)
+	}
+	end := offset + limit
+	if end > len(results) {
+		end = len(results)
+	}
+
+	var next interface{}
+	if end < len(results) {
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("offset", strconv.Itoa(end))
+		next = "http://" + r.Host + r.URL.Path + "?" + query.Encode()
+	}
+
+	return map[string]interface{}{
+		"count":   len(results),
+		"next":    next,
+		"results": results[offset:end],
+	}
+}
+
+// defaultPageSize matches NetBox's default PAGINATE_COUNT
+const defaultPageSize = 50
+
+// matches reports whether obj satisfies all NetBox-style query filters
+func (s *Server) matches(obj map[string]interface{}, query url.Values) bool {
+	for key, values := range query {
+		switch key {
+		case "limit", "offset", "brief":
+			continue
+		}
+		want := values[0]
+
+		if key == "tag" {
+			if !s.hasTag(obj["tags"], want) {
+				return false
+			}
+			continue
+		}
+		if key == "tag__n" {
+			if s.hasTag(obj["tags"], want) {
+				return false
+			}
+			continue
+		}
+
+		// within=... matches prefixes strictly contained in the given prefix
+		if key == "within" {
+			if !prefixWithin(obj["prefix"], want) {
+				return false
+			}
+			continue
+		}
+
+		// name__ie=... is NetBox's case-insensitive exact match
+		if strings.HasSuffix(key, "__ie") {
+			value, exists := obj[strings.TrimSuffix(key, "__ie")]
+			if !exists || !strings.EqualFold(fmt.Sprint(value), want) {
+				return false
+			}
+			continue
+		}
+
+		// cf_<name>=... matches a custom field
+		if name, ok := strings.CutPrefix(key, "cf_"); ok {
+			fields, _ := obj["custom_fields"].(map[string]interface{})
+			if value, exists := fields[name]; !exists || !valueMatches(value, want) {
+				return false
+			}
+			continue
+		}
+
+		field := key
+		value, exists := obj[field]
+		if !exists && strings.HasSuffix(key, "_id") {
+			field = strings.TrimSuffix(key, "_id")
+			value, exists = obj[field]
+		}
+		// IP addresses are filtered by device through their assigned interface
+		if !exists {
+			if assigned, ok := obj["assigned_object"].(map[string]interface{}); ok {
+				value, exists = assigned[field]
+			}
+		}
+
+		if want == "null" {
+			if exists && value != nil {
+				return false
+			}
+			continue
+		}
+		// A repeated filter (asset_tag=a&asset_tag=b) matches any of its values
+		if !exists || !slices.ContainsFunc(values, func(want string) bool { return valueMatches(value, want) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// prefixWithin reports whether value is a prefix strictly inside parent
+func prefixWithin(value interface{}, parent string) bool {
+	child, err := netip.ParsePrefix(fmt.Sprint(value))
+	if err != nil {
+		return false
+	}
+	outer, err := netip.ParsePrefix(parent)
+	if err != nil {
+		return false
+	}
+	return child.Bits() > outer.Bits() && outer.Contains(child.Addr())
+}
+
+func valueMatches(value interface{}, want string) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, k := range []string{"id", "value", "slug", "name"} {
+			if inner, ok := v[k]; ok && valueMatches(inner, want) {
+				return true
+			}
+		}
+		return false
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64) == want
+	case nil:
+		return false
+	default:
+		return fmt.Sprint(v) == want
+	}
+}
+
+// hasTag reports whether a tag list contains slug, either as nested tag objects
+// or as tag IDs (as sent in POST/PATCH bodies) resolved through extras/tags
+func (s *Server) hasTag(tags interface{}, slug string) bool {
+	list, ok := tags.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, tag := range list {
+		if m, ok := tag.(map[string]interface{}); ok && m["slug"] == slug {
+			return true
+		}
+		if id := ID(tag); id > 0 {
+			if stored := s.get("extras/tags", id); stored != nil && stored["slug"] == slug {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ID returns the integer ID of an object or a reference value (int, float64 or nested object)
+func ID(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case float64:
+		return int(n)
+	case map[string]interface{}:
+		return ID(n["id"])
+	}
+	return 0
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,20 @@
+// Package version holds the build information of the controller, injected at build time:
+//
+//	go build -ldflags "-X github.com/braunma/netbox-gitops-controller/internal/version.Version=1.2.0 \
+//	  -X github.com/braunma/netbox-gitops-controller/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/braunma/netbox-gitops-controller/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package version
+
+import "fmt"
+
+// Build information, overridden with -ldflags "-X ..."
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
+
+// String returns the build information on one line, e.g. "1.2.0 (commit abc1234, built 2025-01-01T00:00:00Z)"
+func String() string {
+	return fmt.Sprintf("%s (commit %s, built %s)", Version, Commit, BuildDate)
+}
@@ -11,42 +11,94 @@ import (
 type CacheManager struct {
 	client *NetBoxClient
 	cache  map[string]map[string]int
-	mu     sync.RWMutex
+	// slugs maps the IDs of loaded global objects to their slugs, see GetSlug
+	slugs map[string]map[int]string
+	mu    sync.RWMutex
+	// reloaded records "resource:identifier" misses that already triggered a reload
+	reloaded map[string]bool
+	// unavailable records global resources that failed to load, see Unavailable
+	unavailable map[string]error
 }
 
 // NewCacheManager creates a new cache manager
 func NewCacheManager(client *NetBoxClient) *CacheManager {
 	return &CacheManager{
-		client: client,
-		cache:  make(map[string]map[string]int),
+		client:      client,
+		cache:       make(map[string]map[string]int),
+		slugs:       make(map[string]map[int]string),
+		reloaded:    make(map[string]bool),
+		unavailable: make(map[string]error),
 	}
 }
 
+// globalResources maps global (not site-specific) cache resources to their API paths
+var globalResources = map[string]string{
+	"device_types":             "dcim/device-types",
+	"module_types":             "dcim/module-types",
+	"roles":                    "dcim/device-roles",
+	"manufacturers":            "dcim/manufacturers",
+	"inventory_item_roles":     "dcim/inventory-item-roles",
+	"sites":                    "dcim/sites",
+	"vrfs":                     "ipam/vrfs",
+	"providers":                "circuits/providers",
+	"circuit_types":            "circuits/circuit-types",
+	"ipam_roles":               "ipam/roles",
+	"contact_groups":           "tenancy/contact-groups",
+	"contact_roles":            "tenancy/contact-roles",
+	"contacts":                 "tenancy/contacts",
+	"tenant_groups":            "tenancy/tenant-groups",
+	"tenants":                  "tenancy/tenants",
+	"clusters":                 "virtualization/clusters",
+	"custom_field_choice_sets": "extras/custom-field-choice-sets",
+	"config_templates":         "extras/config-templates",
+}
+
 // LoadGlobal loads global resources (not site-specific)
+// A resource that fails to load (e.g. a 403 on one endpoint) is recorded and the others are
+// still loaded; see Unavailable. An error is only returned if no resource could be loaded.
 func (cm *CacheManager) LoadGlobal() error {
 	cm.client.logger.Info("Loading global caches...")
 
-	resources := map[string]string{
-		"device_types":  "dcim/device-types",
-		"module_types":  "dcim/module-types",
-		"roles":         "dcim/device-roles",
-		"manufacturers": "dcim/manufacturers",
-		"sites":         "dcim/sites",
-		"vrfs":          "ipam/vrfs",
-	}
-
-	for resource, path := range resources {
+	var lastErr error
+	failed := 0
+	for resource, path := range globalResources {
 		cm.client.logger.Debug("→ %s", resource)
 		// Pass siteID=0 for global resources (no site prefix)
 		if err := cm.loadResource(resource, path, nil, 0); err != nil {
-			return fmt.Errorf("failed to load %s: %w", resource, err)
+			unavailable := &CacheUnavailableError{Resource: resource, Err: err}
+			cm.mu.Lock()
+			cm.unavailable[resource] = unavailable
+			cm.mu.Unlock()
+			cm.client.logger.Warning("Failed to load %s cache, resource types that need it are skipped: %v", resource, err)
+			lastErr = fmt.Errorf("failed to load %s: %w", resource, err)
+			failed++
 		}
 	}
 
+	if failed == len(globalResources) {
+		return lastErr
+	}
+	if failed > 0 {
+		cm.client.logger.Warning("Global caches loaded, %d of %d unavailable", failed, len(globalResources))
+		return nil
+	}
+
 	cm.client.logger.Success("Global caches loaded")
 	return nil
 }
 
+// Unavailable returns a *CacheUnavailableError if the global resource failed to load,
+// or nil if it is available
+func (cm *CacheManager) Unavailable(resource string) error {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	if err, ok := cm.unavailable[resource]; ok {
+		return err
+	}
+	return nil
+}
+
 // LoadSite loads site-specific resources with composite keys
 func (cm *CacheManager) LoadSite(siteSlug string) error {
 	cm.client.logger.Info("Reloading cache for site: %s", siteSlug)
@@ -92,16 +144,56 @@ func (cm *CacheManager) LoadSite(siteSlug string) error {
 	return nil
 }
 
-// loadResource loads a specific resource into cache
-// If siteID > 0, creates composite keys: "site-{siteID}:{identifier}"
-func (cm *CacheManager) loadResource(resource, path string, filters map[string]interface{}, siteID int) error {
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
+// LoadSites loads the caches of several sites in parallel, at most concurrency at a time
+// After the first failure no further site loads are started and that error is returned
+func (cm *CacheManager) LoadSites(siteSlugs []string, concurrency int) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
 
-	if cm.cache[resource] == nil {
-		cm.cache[resource] = make(map[string]int)
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		failed   = make(chan struct{})
+		sem      = make(chan struct{}, concurrency)
+	)
+
+	for _, siteSlug := range siteSlugs {
+		// Wait for a free slot, unless a load already failed
+		select {
+		case sem <- struct{}{}:
+		case <-failed:
+		}
+
+		select {
+		case <-failed:
+			wg.Wait()
+			return firstErr
+		default:
+		}
+
+		wg.Add(1)
+		go func(siteSlug string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := cm.LoadSite(siteSlug); err != nil {
+				once.Do(func() {
+					firstErr = fmt.Errorf("failed to load site cache for %s: %w", siteSlug, err)
+					close(failed)
+				})
+			}
+		}(siteSlug)
 	}
 
+	wg.Wait()
+	return firstErr
+}
+
+// loadResource loads a specific resource into cache
+// If siteID > 0, creates composite keys: "site-{siteID}:{identifier}"
+func (cm *CacheManager) loadResource(resource, path string, filters map[string]interface{}, siteID int) error {
 	// Parse app and endpoint from path
 	app := ""
 	endpoint := ""
@@ -117,11 +209,29 @@ func (cm *CacheManager) loadResource(resource, path string, filters map[string]i
 		return fmt.Errorf("invalid path: %s", path)
 	}
 
-	objects, err := cm.client.Filter(app, endpoint, filters)
+	// Bypass the response memo: (re)loads must see objects created since the last load
+	objects, err := cm.client.List(fmt.Sprintf("/api/%s/%s/", app, endpoint), filters)
 	if err != nil {
 		return fmt.Errorf("failed to filter %s: %w", resource, err)
 	}
 
+	// Only hold the lock while storing, so sites can be loaded in parallel
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if cm.cache[resource] == nil {
+		cm.cache[resource] = make(map[string]int)
+	}
+	if siteID == 0 {
+		delete(cm.unavailable, resource)
+		cm.slugs[resource] = make(map[int]string)
+	}
+
+	// Models are only unique per manufacturer: a model shared by several types is not cached,
+	// so a lookup by it misses and the caller can report the ambiguity
+	slugs := make(map[string]bool)
+	modelIDs := make(map[string]map[int]bool)
+
 	for _, obj := range objects {
 		id := utils.GetIDFromObject(obj)
 		if id == 0 {
@@ -143,6 +253,10 @@ func (cm *CacheManager) loadResource(resource, path string, filters map[string]i
 		// Index by slug
 		if slug, ok := obj["slug"].(string); ok {
 			storeKey(slug)
+			slugs[slug] = true
+			if siteID == 0 {
+				cm.slugs[resource][id] = slug
+			}
 		}
 
 		// Index by name/model
@@ -150,11 +264,21 @@ func (cm *CacheManager) loadResource(resource, path string, filters map[string]i
 			storeKey(name)
 		} else if model, ok := obj["model"].(string); ok {
 			storeKey(model)
+			if modelIDs[model] == nil {
+				modelIDs[model] = make(map[int]bool)
+			}
+			modelIDs[model][id] = true
 		} else if label, ok := obj["label"].(string); ok {
 			storeKey(label)
 		}
 	}
 
+	for model, ids := range modelIDs {
+		if len(ids) > 1 && !slugs[model] && siteID == 0 {
+			delete(cm.cache[resource], model)
+		}
+	}
+
 	return nil
 }
 
@@ -172,11 +296,42 @@ func (cm *CacheManager) GetID(resource, identifier string) (int, bool) {
 }
 
 // GetGlobalID retrieves an ID for a global resource (not site-specific)
-// Use this for: device_types, module_types, roles, manufacturers, sites, vrfs
+// Use this for: device_types, module_types, roles, manufacturers, sites, vrfs, ipam_roles, clusters
 func (cm *CacheManager) GetGlobalID(resource, identifier string) (int, bool) {
 	return cm.GetID(resource, identifier)
 }
 
+// GetGlobalIDOrReload retrieves an ID for a global resource and, on a cache miss, reloads
+// that resource once from NetBox so objects created earlier in the same run are found.
+// Each missing identifier triggers at most one reload.
+func (cm *CacheManager) GetGlobalIDOrReload(resource, identifier string) (int, bool) {
+	if id, ok := cm.GetGlobalID(resource, identifier); ok {
+		return id, true
+	}
+
+	path, ok := globalResources[resource]
+	if !ok {
+		return 0, false
+	}
+
+	missKey := resource + ":" + identifier
+	cm.mu.Lock()
+	if cm.reloaded[missKey] {
+		cm.mu.Unlock()
+		return 0, false
+	}
+	cm.reloaded[missKey] = true
+	cm.mu.Unlock()
+
+	cm.client.logger.Debug("Cache miss for %s %s, reloading %s", resource, identifier, resource)
+	if err := cm.loadResource(resource, path, nil, 0); err != nil {
+		cm.client.logger.Warning("Failed to reload %s cache: %v", resource, err)
+		return 0, false
+	}
+
+	return cm.GetGlobalID(resource, identifier)
+}
+
 // GetSiteID retrieves an ID for a site-specific resource using composite key
 // Use this for: vlans, racks, and other site-scoped resources
 // Key format: "site-{siteID}:{identifier}"
@@ -199,12 +354,34 @@ func (cm *CacheManager) GetSiteID(resource string, siteID int, identifier string
 	return id, ok
 }
 
+// GetSlug returns the slug of a loaded global object, e.g. to tell whether a cache hit
+// matched the slug or the name/model of the object
+func (cm *CacheManager) GetSlug(resource string, id int) (string, bool) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	slug, ok := cm.slugs[resource][id]
+	return slug, ok
+}
+
+// Set stores an ID for a global resource (e.g. an object created during this run)
+func (cm *CacheManager) Set(resource, identifier string, id int) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if cm.cache[resource] == nil {
+		cm.cache[resource] = make(map[string]int)
+	}
+	cm.cache[resource][identifier] = id
+}
+
 // Invalidate clears the cache for a specific resource
 func (cm *CacheManager) Invalidate(resource string) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
 	delete(cm.cache, resource)
+	delete(cm.slugs, resource)
 }
 
 // InvalidateAll clears all caches
@@ -213,6 +390,7 @@ func (cm *CacheManager) InvalidateAll() {
 	defer cm.mu.Unlock()
 
 	cm.cache = make(map[string]map[string]int)
+	cm.slugs = make(map[string]map[int]string)
 }
 
 // Resources returns a list of cached resources
@@ -239,6 +417,30 @@ func (cm *CacheManager) Size(resource string) int {
 	return len(cm.cache[resource])
 }
 
+// Dump returns a copy of the cached identifier→ID mappings per resource type,
+// limited to the given resource types if any are given
+func (cm *CacheManager) Dump(resources ...string) map[string]map[string]int {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	selected := make(map[string]bool, len(resources))
+	for _, resource := range resources {
+		selected[resource] = true
+	}
+
+	dump := make(map[string]map[string]int, len(cm.cache))
+	for resource, entries := range cm.cache {
+		if len(selected) > 0 && !selected[resource] {
+			continue
+		}
+		dump[resource] = make(map[string]int, len(entries))
+		for identifier, id := range entries {
+			dump[resource][identifier] = id
+		}
+	}
+	return dump
+}
+
 // splitPath splits a path like "dcim/sites" into ["dcim", "sites"]
 func splitPath(path string) []string {
 	var parts []string
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+)
+
+func TestLoadSitesConcurrently(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	slugs := []string{"berlin-dc", "munich-dc", "hamburg-dc"}
+	siteIDs := make(map[string]int)
+	for _, slug := range slugs {
+		site := srv.Add("dcim", "sites", map[string]interface{}{"name": slug, "slug": slug})
+		siteIDs[slug] = netboxtest.ID(site)
+		srv.Add("ipam", "vlans", map[string]interface{}{"name": "Mgmt", "vid": 100, "site": site["id"]})
+		srv.Add("dcim", "racks", map[string]interface{}{"name": "R01", "site": site["id"]})
+	}
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	if err := c.Cache().LoadSites(slugs, 3); err != nil {
+		t.Fatalf("LoadSites() error = %v", err)
+	}
+
+	for _, slug := range slugs {
+		for _, resource := range []string{"vlans", "racks"} {
+			name := map[string]string{"vlans": "Mgmt", "racks": "R01"}[resource]
+			if _, ok := c.Cache().GetSiteID(resource, siteIDs[slug], name); !ok {
+				t.Errorf("%s %s not cached for site %s", resource, name, slug)
+			}
+		}
+	}
+	// VLANs of different sites must not collide
+	if got := c.Cache().Size("vlans"); got != 3 {
+		t.Errorf("Cached %d VLANs, expected 3", got)
+	}
+}
+
+func TestLoadSitesReportsFailingSite(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin", "slug": "berlin-dc"})
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	var slugs []string
+	for i := 0; i < 10; i++ {
+		slugs = append(slugs, fmt.Sprintf("missing-%d", i))
+	}
+	err = c.Cache().LoadSites(append([]string{"berlin-dc"}, slugs...), 1)
+	if err == nil || !strings.Contains(err.Error(), "missing-0") {
+		t.Fatalf("LoadSites() error = %v, expected failure for missing-0", err)
+	}
+}
+
+func TestGetGlobalIDOrReloadReloadsOncePerMiss(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	role := srv.Add("dcim", "device-roles", map[string]interface{}{"name": "Server", "slug": "server"})
+	srv.ResetRequests()
+
+	if id, ok := c.Cache().GetGlobalIDOrReload("roles", "server"); !ok || id != netboxtest.ID(role["id"]) {
+		t.Errorf("GetGlobalIDOrReload(roles, server) = %d, %v, expected %v", id, ok, role["id"])
+	}
+
+	// A missing identifier reloads only once
+	for i := 0; i < 3; i++ {
+		if _, ok := c.Cache().GetGlobalIDOrReload("roles", "missing"); ok {
+			t.Error("GetGlobalIDOrReload(roles, missing) found a role")
+		}
+	}
+	if got := srv.CountRequests("GET", "/api/dcim/device-roles/"); got != 2 {
+		t.Errorf("Expected 2 role reloads (server, missing), got %d", got)
+	}
+}
+
+func TestCacheDump(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	role := srv.Add("dcim", "device-roles", map[string]interface{}{"name": "Server", "slug": "server"})
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	dump := c.Cache().Dump()
+	if got := dump["sites"]["berlin-dc"]; got != netboxtest.ID(site) {
+		t.Errorf("Dump()[sites][berlin-dc] = %d, expected %d", got, netboxtest.ID(site))
+	}
+	if got := dump["roles"]["server"]; got != netboxtest.ID(role) {
+		t.Errorf("Dump()[roles][server] = %d, expected %d", got, netboxtest.ID(role))
+	}
+
+	// Filtered by resource, and a copy that doesn't alias the cache
+	filtered := c.Cache().Dump("roles")
+	if len(filtered) != 1 || filtered["roles"] == nil {
+		t.Errorf("Dump(roles) = %v, expected only roles", filtered)
+	}
+	filtered["roles"]["server"] = 999
+	if id, _ := c.Cache().GetGlobalID("roles", "server"); id != netboxtest.ID(role) {
+		t.Errorf("Modifying the dump changed the cache: roles/server = %d", id)
+	}
+}
@@ -2,12 +2,17 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/braunma/netbox-gitops-controller/internal/constants"
@@ -20,10 +25,24 @@ type NetBoxClient struct {
 	token         string
 	httpClient    *http.Client
 	cache         *CacheManager
+	memo          *ResponseMemo
 	tagManager    *TagManager
 	logger        *utils.Logger
 	dryRun        bool
 	managedTagID  int
+	stats         *Stats
+	lastRun       time.Time
+	journal       *journal
+	compactDiff   bool
+	timeouts      *objectTimeouts
+	ctx           context.Context
+
+	simulate        bool
+	lastSyntheticID int64
+
+	versionOnce  sync.Once
+	versionMajor int
+	versionMinor int
 }
 
 // NewClient creates a new NetBox API client
@@ -33,7 +52,10 @@ func NewClient(baseURL, token string, dryRun bool) (*NetBoxClient, error) {
 	httpClient := &http.Client{
 		Timeout: 30 * time.Second,
 		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+			MaxIdleConns:        constants.DefaultMaxIdleConns,
+			MaxIdleConnsPerHost: constants.DefaultMaxIdleConnsPerHost,
+			IdleConnTimeout:     constants.DefaultIdleConnTimeout,
 		},
 	}
 
@@ -43,6 +65,8 @@ func NewClient(baseURL, token string, dryRun bool) (*NetBoxClient, error) {
 		httpClient: httpClient,
 		logger:     logger,
 		dryRun:     dryRun,
+		stats:      NewStats(),
+		memo:       NewResponseMemo(),
 	}
 
 	client.cache = NewCacheManager(client)
@@ -63,6 +87,11 @@ type Object map[string]interface{}
 
 // Request makes an HTTP request to the NetBox API
 func (c *NetBoxClient) Request(method, path string, body interface{}) (Object, error) {
+	return c.requestContext(c.baseContext(), method, path, body)
+}
+
+// requestContext makes an HTTP request that is canceled with ctx (see SetObjectTimeout)
+func (c *NetBoxClient) requestContext(ctx context.Context, method, path string, body interface{}) (Object, error) {
 	url := c.baseURL + path
 
 	var bodyReader io.Reader
@@ -74,7 +103,7 @@ func (c *NetBoxClient) Request(method, path string, body interface{}) (Object, e
 		bodyReader = bytes.NewReader(jsonBody)
 	}
 
-	req, err := http.NewRequest(method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -85,8 +114,14 @@ func (c *NetBoxClient) Request(method, path string, body interface{}) (Object, e
 
 	if c.dryRun && (method == "POST" || method == "PATCH" || method == "PUT" || method == "DELETE") {
 		c.logger.DryRun(method, path)
+		if c.simulate && method == "POST" {
+			return Object{"id": c.nextSyntheticID()}, nil
+		}
 		return Object{"id": 0}, nil
 	}
+	if method != "GET" {
+		c.memo.Invalidate(path)
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -100,10 +135,11 @@ func (c *NetBoxClient) Request(method, path string, body interface{}) (Object, e
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
-	if len(respBody) == 0 {
+	// Bulk creates answer with a list of objects, which no caller needs
+	if len(respBody) == 0 || respBody[0] == '[' {
 		return nil, nil
 	}
 
@@ -117,19 +153,21 @@ func (c *NetBoxClient) Request(method, path string, body interface{}) (Object, e
 
 // List makes a GET request and returns a list of objects
 func (c *NetBoxClient) List(path string, filters map[string]interface{}) ([]Object, error) {
+	results, _, err := c.listPage(c.baseContext(), path, filters)
+	return results, err
+}
+
+// listPage fetches one page of a list endpoint and reports whether more pages follow
+func (c *NetBoxClient) listPage(ctx context.Context, path string, filters map[string]interface{}) ([]Object, bool, error) {
 	requestURL := c.baseURL + path
 
 	if len(filters) > 0 {
-		queryParams := url.Values{}
-		for k, v := range filters {
-			queryParams.Add(k, fmt.Sprintf("%v", v))
-		}
-		requestURL += "?" + queryParams.Encode()
+		requestURL += "?" + encodeFilters(filters)
 	}
 
-	req, err := http.NewRequest("GET", requestURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Token "+c.token)
@@ -137,20 +175,21 @@ func (c *NetBoxClient) List(path string, filters map[string]interface{}) ([]Obje
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, false, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, false, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(respBody))
+		return nil, false, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var result struct {
+		Next    *string  `json:"next"`
 		Results []Object `json:"results"`
 	}
 
@@ -158,24 +197,147 @@ func (c *NetBoxClient) List(path string, filters map[string]interface{}) ([]Obje
 		// Try unmarshaling as direct array
 		var directResults []Object
 		if err2 := json.Unmarshal(respBody, &directResults); err2 == nil {
-			return directResults, nil
+			return directResults, false, nil
 		}
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		return nil, false, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
-	return result.Results, nil
+	return result.Results, result.Next != nil, nil
 }
 
-// Get retrieves a single object by ID
+// encodeFilters builds a query string with keys in sorted order, so identical filters
+// always produce identical URLs. Slice values become repeated parameters (e.g. tag=a&tag=b).
+func encodeFilters(filters map[string]interface{}) string {
+	keys := make([]string, 0, len(filters))
+	for k := range filters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		for _, v := range filterValues(filters[k]) {
+			parts = append(parts, url.QueryEscape(k)+"="+url.QueryEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// filterValues converts a filter value to its query parameter values
+func filterValues(v interface{}) []string {
+	if !isList(v) {
+		return []string{fmt.Sprintf("%v", v)}
+	}
+
+	rv := reflect.ValueOf(v)
+	values := make([]string, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		values = append(values, fmt.Sprintf("%v", rv.Index(i).Interface()))
+	}
+	return values
+}
+
+// Get retrieves a single object by ID.
+// Responses are memoized until an object of the same endpoint is written.
 func (c *NetBoxClient) Get(app, endpoint string, id int) (Object, error) {
+	// A simulated object exists only in this dry-run
+	if IsSyntheticID(id) {
+		return Object{"id": id}, nil
+	}
+
 	path := fmt.Sprintf("/api/%s/%s/%d/", app, endpoint, id)
-	return c.Request("GET", path, nil)
+	if cached, ok := c.memo.Get(path); ok {
+		return cached[0], nil
+	}
+
+	obj, err := c.Request("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.memo.Set(path, []Object{obj})
+	return obj, nil
 }
 
-// Filter retrieves objects matching the given filters
+// Filter retrieves objects matching the given filters.
+// Responses are memoized until an object of the same endpoint is written.
 func (c *NetBoxClient) Filter(app, endpoint string, filters map[string]interface{}) ([]Object, error) {
+	return c.filterContext(c.baseContext(), app, endpoint, filters)
+}
+
+// filterContext is Filter with a context, used by Apply
+func (c *NetBoxClient) filterContext(ctx context.Context, app, endpoint string, filters map[string]interface{}) ([]Object, error) {
+	if hasSyntheticID(filters) {
+		return nil, nil
+	}
+
+	path := fmt.Sprintf("/api/%s/%s/", app, endpoint)
+	key := path + "?" + encodeFilters(filters)
+	if cached, ok := c.memo.Get(key); ok {
+		return cached, nil
+	}
+
+	results, _, err := c.listPage(ctx, path, filters)
+	if err != nil {
+		return nil, err
+	}
+	c.memo.Set(key, results)
+	return results, nil
+}
+
+// FilterAll retrieves all objects matching the given filters, following pagination
+func (c *NetBoxClient) FilterAll(app, endpoint string, filters map[string]interface{}) ([]Object, error) {
+	if hasSyntheticID(filters) {
+		return nil, nil
+	}
+
 	path := fmt.Sprintf("/api/%s/%s/", app, endpoint)
-	return c.List(path, filters)
+
+	pageFilters := make(map[string]interface{}, len(filters)+2)
+	for k, v := range filters {
+		pageFilters[k] = v
+	}
+	pageFilters["limit"] = constants.APIPageSize
+
+	var all []Object
+	for {
+		pageFilters["offset"] = len(all)
+		page, hasNext, err := c.listPage(c.baseContext(), path, pageFilters)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if !hasNext || len(page) == 0 {
+			return all, nil
+		}
+	}
+}
+
+// Count returns how many objects match the given filters, fetching a single object at most
+func (c *NetBoxClient) Count(app, endpoint string, filters map[string]interface{}) (int, error) {
+	if hasSyntheticID(filters) {
+		return 0, nil
+	}
+
+	countFilters := make(map[string]interface{}, len(filters)+1)
+	for k, v := range filters {
+		countFilters[k] = v
+	}
+	countFilters["limit"] = 1
+
+	result, err := c.Request("GET", fmt.Sprintf("/api/%s/%s/?%s", app, endpoint, encodeFilters(countFilters)), nil)
+	if err != nil {
+		return 0, err
+	}
+	count, ok := result["count"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("response of %s/%s has no count", app, endpoint)
+	}
+	return int(count), nil
+}
+
+// FilterManaged retrieves all objects of a type that carry the managed tag
+func (c *NetBoxClient) FilterManaged(app, endpoint string) ([]Object, error) {
+	return c.FilterAll(app, endpoint, map[string]interface{}{"tag": constants.ManagedTagSlug})
 }
 
 // Create creates a new object
@@ -193,20 +355,50 @@ func (c *NetBoxClient) Update(app, endpoint string, id int, data map[string]inte
 
 // Delete deletes an object
 func (c *NetBoxClient) Delete(app, endpoint string, id int) error {
+	// Label the object for the compact diff while it still exists
+	var label string
+	if c.compactDiff {
+		label = fmt.Sprintf("#%d", id)
+		if existing, err := c.Get(app, endpoint, id); err == nil {
+			label = c.objectLabel(existing, nil)
+		}
+	}
+
 	path := fmt.Sprintf("/api/%s/%s/%d/", app, endpoint, id)
-	_, err := c.Request("DELETE", path, nil)
-	return err
+	if _, err := c.Request("DELETE", path, nil); err != nil {
+		return err
+	}
+	c.stats.Record(endpoint, ActionDeleted)
+	if c.compactDiff {
+		c.stats.RecordFieldChange(FieldChange{Resource: endpoint, Object: label, Deleted: true})
+	}
+	return nil
 }
 
-// Apply creates or updates an object (idempotent)
+// Apply creates or updates an object (idempotent). With an object timeout (SetObjectTimeout)
+// all requests for the object share its deadline.
 func (c *NetBoxClient) Apply(app, endpoint string, lookup, payload map[string]interface{}) (Object, error) {
+	ctx, cancel := c.objectContext()
+	defer cancel()
+
+	obj, err := c.apply(ctx, app, endpoint, lookup, payload)
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		return nil, c.objectTimedOut(endpoint, lookup, err)
+	}
+	return obj, err
+}
+
+// apply creates or updates an object, canceled with ctx
+func (c *NetBoxClient) apply(ctx context.Context, app, endpoint string, lookup, payload map[string]interface{}) (Object, error) {
 	// Inject managed tag
-	payload = c.tagManager.InjectTag(payload, c.managedTagID)
+	if !isUntaggedEndpoint(endpoint) {
+		payload = c.tagManager.InjectTag(payload, c.managedTagID)
+	}
 
 	c.logger.Debug("  → Applying %s with lookup: %v", endpoint, lookup)
 
 	// Try to find existing object
-	existing, err := c.Filter(app, endpoint, lookup)
+	existing, err := c.filterContext(ctx, app, endpoint, lookup)
 	if err != nil {
 		return nil, fmt.Errorf("failed to filter objects: %w", err)
 	}
@@ -215,11 +407,88 @@ func (c *NetBoxClient) Apply(app, endpoint string, lookup, payload map[string]in
 		// Create new object
 		c.logger.Success("  ✓ Creating %s: %v", endpoint, c.formatLookup(lookup))
 		c.printDiff("CREATE", nil, payload)
-		return c.Create(app, endpoint, payload)
+		created, err := c.requestContext(ctx, "POST", fmt.Sprintf("/api/%s/%s/", app, endpoint), payload)
+		if err != nil {
+			if fields, ok := uniqueViolationFields(err); ok {
+				return c.recoverUniqueViolation(ctx, app, endpoint, lookup, payload, fields, err)
+			}
+			return nil, err
+		}
+		c.stats.Record(endpoint, ActionCreated)
+		if c.compactDiff {
+			c.stats.RecordFieldChange(FieldChange{Resource: endpoint, Object: c.objectLabel(nil, lookup)})
+		}
+
+		// ID 0 must only ever mean "created in dry-run mode". If NetBox answered
+		// with an empty body (204 or empty 200), fetch the object we just created.
+		if !c.dryRun && utils.GetIDFromObject(created) == 0 {
+			c.logger.Debug("  Empty create response for %s, re-reading object", endpoint)
+			refetched, err := c.filterContext(ctx, app, endpoint, lookup)
+			if err != nil {
+				return nil, fmt.Errorf("failed to re-read created object: %w", err)
+			}
+			if len(refetched) == 0 {
+				return nil, fmt.Errorf("created %s %s but NetBox returned no object", endpoint, c.formatLookup(lookup))
+			}
+			created = refetched[0]
+		}
+		c.recordJournal(app, endpoint, utils.GetIDFromObject(created), "Created by the GitOps controller")
+		return created, nil
+	}
+
+	return c.updateExisting(ctx, app, endpoint, lookup, existing[0], payload)
+}
+
+// recoverUniqueViolation handles a create rejected because the object already exists:
+// the lookup missed it (a concurrent create, or a case difference such as "Berlin-DC"
+// vs "berlin-dc"). The existing object is fetched and updated instead.
+func (c *NetBoxClient) recoverUniqueViolation(ctx context.Context, app, endpoint string, lookup, payload map[string]interface{}, fields []string, createErr error) (Object, error) {
+	c.logger.Warning("  %s %s already exists (%s), updating the existing object", endpoint, c.formatLookup(lookup), strings.Join(fields, ", "))
+
+	existing, err := c.filterContext(ctx, app, endpoint, lookup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-read existing object: %w", err)
+	}
+
+	// Retry with a case-insensitive lookup on the conflicting fields, within the scope of the
+	// lookup (its relation filters such as device_id), so an object of another device or
+	// site with the same name is never taken over
+	for _, field := range fields {
+		if len(existing) > 0 {
+			break
+		}
+		value, ok := payload[field]
+		if !ok {
+			continue
+		}
+		filter := make(map[string]interface{}, len(lookup)+1)
+		for key, scope := range lookup {
+			if strings.HasSuffix(key, "_id") && key != field+"_id" {
+				filter[key] = scope
+			}
+		}
+		if _, isString := value.(string); isString {
+			filter[field+"__ie"] = value
+		} else {
+			filter[field] = value
+		}
+		if existing, err = c.filterContext(ctx, app, endpoint, filter); err != nil {
+			return nil, fmt.Errorf("failed to re-read existing object: %w", err)
+		}
 	}
 
-	// Update existing object
-	obj := existing[0]
+	if len(existing) == 0 {
+		return nil, createErr
+	}
+	if !isUntaggedEndpoint(endpoint) && !utils.IsManaged(existing[0], c.managedTagID) {
+		return nil, fmt.Errorf("%s %s conflicts with an object not managed by gitops (ID: %d), tag it %s to take it over: %w",
+			endpoint, c.formatLookup(lookup), utils.GetIDFromObject(existing[0]), constants.ManagedTagSlug, createErr)
+	}
+	return c.updateExisting(ctx, app, endpoint, lookup, existing[0], payload)
+}
+
+// updateExisting updates an existing object with the fields of payload that differ
+func (c *NetBoxClient) updateExisting(ctx context.Context, app, endpoint string, lookup map[string]interface{}, obj Object, payload map[string]interface{}) (Object, error) {
 	objID := utils.GetIDFromObject(obj)
 	if objID == 0 {
 		// Enhanced error message with object details for debugging
@@ -236,13 +505,19 @@ func (c *NetBoxClient) Apply(app, endpoint string, lookup, payload map[string]in
 	changes := c.calculateDiff(obj, payload)
 	if len(changes) > 0 {
 		c.logger.Info("  ⟳ Updating %s (ID: %d): %v", endpoint, objID, c.formatLookup(lookup))
+		c.warnOnExternalChange(endpoint, obj, lookup, changes)
 		c.printDiff("UPDATE", obj, changes)
-		if err := c.Update(app, endpoint, objID, changes); err != nil {
+		path := fmt.Sprintf("/api/%s/%s/%d/", app, endpoint, objID)
+		if _, err := c.requestContext(ctx, "PATCH", path, changes); err != nil {
 			return nil, fmt.Errorf("failed to update object: %w", err)
 		}
 		c.logger.Success("  ✓ Update complete")
+		c.stats.Record(endpoint, ActionUpdated)
+		c.recordFieldChanges(endpoint, lookup, obj, changes)
+		c.recordJournal(app, endpoint, objID, journalComment(changes))
 	} else {
 		c.logger.Debug("  = No changes for %s (ID: %d)", endpoint, objID)
+		c.stats.Record(endpoint, ActionUnchanged)
 	}
 
 	return obj, nil
@@ -263,10 +538,117 @@ func (c *NetBoxClient) formatLookup(lookup map[string]interface{}) string {
 	return "{}"
 }
 
+// SetCompactDiff replaces the per-object diff boxes with one line per changed field,
+// printed after the run (--diff-only-changed)
+func (c *NetBoxClient) SetCompactDiff(enabled bool) {
+	c.compactDiff = enabled
+}
+
+// recordFieldChanges records the changed fields of an updated object for the compact diff
+func (c *NetBoxClient) recordFieldChanges(endpoint string, lookup map[string]interface{}, existing Object, changes map[string]interface{}) {
+	if !c.compactDiff {
+		return
+	}
+	object := c.objectLabel(existing, lookup)
+	for key, newVal := range changes {
+		if key == "tags" {
+			continue
+		}
+		c.stats.RecordFieldChange(FieldChange{
+			Resource: endpoint,
+			Object:   object,
+			Field:    key,
+			Old:      compactValue(existing[key]),
+			New:      compactValue(newVal),
+		})
+	}
+}
+
+// objectLabel names an object in the compact diff: its name or slug, else its display name.
+// Device components are qualified with their device, e.g. "leaf-01/eth0".
+func (c *NetBoxClient) objectLabel(existing Object, lookup map[string]interface{}) string {
+	label := plainLabel(existing, lookup)
+	if device := c.deviceName(existing, lookup); device != "" {
+		return device + "/" + label
+	}
+	return label
+}
+
+// plainLabel names an object by its lookup's name or slug, else its own, else its display name
+func plainLabel(existing Object, lookup map[string]interface{}) string {
+	for _, key := range []string{"name", "slug"} {
+		if value, ok := lookup[key]; ok {
+			return fmt.Sprintf("%v", value)
+		}
+	}
+	for _, key := range []string{"name", "slug", "display"} {
+		if value, ok := existing[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	keys := make([]string, 0, len(lookup))
+	for key, value := range lookup {
+		keys = append(keys, fmt.Sprintf("%s=%v", key, value))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// deviceName returns the name of the device a component belongs to, from the object or by
+// the lookup's device_id, and "" for objects that are not device components
+func (c *NetBoxClient) deviceName(existing Object, lookup map[string]interface{}) string {
+	device, _ := existing["device"].(map[string]interface{})
+	if name, ok := device["name"].(string); ok && name != "" {
+		return name
+	}
+
+	id := utils.GetIDFromObject(lookup["device_id"])
+	if id == 0 {
+		id = utils.GetIDFromObject(device["id"])
+	}
+	if id == 0 {
+		return ""
+	}
+	if obj, err := c.Get("dcim", "devices", id); err == nil {
+		if name, ok := obj["name"].(string); ok && name != "" {
+			return name
+		}
+	}
+	return fmt.Sprintf("device %d", id)
+}
+
+// compactValue formats a value for the compact diff: choice fields and nested objects are
+// shown by their value or ID, strings without quotes
+func compactValue(val interface{}) string {
+	switch v := val.(type) {
+	case nil:
+		return "<nil>"
+	case nullValue:
+		return "<nil>"
+	case string:
+		if v == "" {
+			return `""`
+		}
+		return v
+	case map[string]interface{}:
+		if value, ok := v["value"]; ok {
+			return compactValue(value)
+		}
+		if id, ok := v["id"]; ok {
+			return fmt.Sprintf("%v", id)
+		}
+		return "{...}"
+	case []interface{}:
+		return fmt.Sprintf("[%d items]", len(v))
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
 // printDiff prints a visual diff for pipeline console visibility
 func (c *NetBoxClient) printDiff(action string, existing Object, changes map[string]interface{}) {
-	if c.dryRun {
-		return // Dry run already shows the action
+	if c.dryRun || utils.SummaryOnly() || c.compactDiff {
+		return // Dry run already shows the action; summary-only and the compact diff show no per-object output
 	}
 
 	if action == "CREATE" {
@@ -336,21 +718,60 @@ func (c *NetBoxClient) calculateDiff(existing Object, desired map[string]interfa
 		if desiredValue == nil {
 			continue
 		}
+		if desiredValue == Null {
+			if existing[key] != nil {
+				changes[key] = Null
+			}
+			continue
+		}
+
+		// Multi-line strings (comments, descriptions) from a Windows-edited YAML file
+		// would otherwise differ from NetBox's copy in their line endings on every run
+		if text, ok := desiredValue.(string); ok {
+			desiredValue = normalizeLineEndings(text)
+		}
 
 		existingValue, exists := existing[key]
 		if !exists {
 			changes[key] = desiredValue
 			continue
 		}
+		if text, ok := existingValue.(string); ok {
+			existingValue = normalizeLineEndings(text)
+		}
 
-		// Handle tags specially
-		if key == "tags" {
+		// Handle tags specially (slug lists, e.g. config context assignments, are compared below)
+		if _, slugs := desiredValue.([]string); key == "tags" && !slugs {
 			if !c.tagsEqual(existingValue, desiredValue) {
 				changes[key] = desiredValue
 			}
 			continue
 		}
 
+		// NetBox merges custom fields on update, so only the declared ones are compared
+		if key == "custom_fields" {
+			if !customFieldsEqual(existingValue, desiredValue) {
+				changes[key] = desiredValue
+			}
+			continue
+		}
+
+		// Free-form JSON (e.g. config context data) is compared deeply
+		if _, ok := desiredValue.(map[string]interface{}); ok {
+			if !jsonEqual(existingValue, desiredValue) {
+				changes[key] = desiredValue
+			}
+			continue
+		}
+
+		// Lists of references are compared as sets, ignoring order
+		if isList(desiredValue) {
+			if !listsEqual(existingValue, desiredValue) {
+				changes[key] = desiredValue
+			}
+			continue
+		}
+
 		// Handle nested objects (extract ID)
 		if existingMap, ok := existingValue.(map[string]interface{}); ok {
 			existingValue = utils.GetIDFromObject(existingMap)
@@ -406,6 +827,14 @@ func (c *NetBoxClient) extractTagIDs(tags interface{}) []int {
 	return ids
 }
 
+// normalizeLineEndings converts CRLF and CR line endings to LF
+func normalizeLineEndings(s string) string {
+	if !strings.Contains(s, "\r") {
+		return s
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\r", "\n")
+}
+
 // valuesEqual compares two values for equality
 func valuesEqual(a, b interface{}) bool {
 	// Handle type conversions
@@ -427,11 +856,107 @@ func valuesEqual(a, b interface{}) bool {
 	return a == b
 }
 
+// jsonEqual compares two values by their JSON representation, so that e.g.
+// YAML ints and JSON float64s or differently ordered map keys compare equal
+func jsonEqual(a, b interface{}) bool {
+	var an, bn interface{}
+	if err := roundTripJSON(a, &an); err != nil {
+		return false
+	}
+	if err := roundTripJSON(b, &bn); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(an, bn)
+}
+
+// customFieldsEqual reports whether the existing custom fields hold every desired value
+func customFieldsEqual(existing, desired interface{}) bool {
+	existingFields, _ := existing.(map[string]interface{})
+	desiredFields, ok := desired.(map[string]interface{})
+	if !ok {
+		return jsonEqual(existing, desired)
+	}
+	for name, value := range desiredFields {
+		if !jsonEqual(existingFields[name], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// roundTripJSON marshals a value and decodes it into target
+func roundTripJSON(v interface{}, target *interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+// isList reports whether a value is a slice
+func isList(v interface{}) bool {
+	return v != nil && reflect.TypeOf(v).Kind() == reflect.Slice
+}
+
+// listsEqual compares two lists of IDs, nested objects or strings as sets
+func listsEqual(existing, desired interface{}) bool {
+	existingKeys := listKeys(existing)
+	desiredKeys := listKeys(desired)
+	if len(existingKeys) != len(desiredKeys) {
+		return false
+	}
+
+	sort.Strings(existingKeys)
+	sort.Strings(desiredKeys)
+	for i := range existingKeys {
+		if existingKeys[i] != desiredKeys[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// listKeys converts list items to comparable keys (nested objects by ID)
+func listKeys(list interface{}) []string {
+	if !isList(list) {
+		return nil
+	}
+
+	rv := reflect.ValueOf(list)
+	keys := make([]string, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		switch item := rv.Index(i).Interface().(type) {
+		case string:
+			keys = append(keys, item)
+		case map[string]interface{}, int, float64:
+			keys = append(keys, fmt.Sprintf("%d", utils.GetIDFromObject(item)))
+		default:
+			keys = append(keys, fmt.Sprintf("%v", item))
+		}
+	}
+	return keys
+}
+
+// isUntaggedEndpoint reports whether the managed tag must not be injected for an endpoint
+func isUntaggedEndpoint(endpoint string) bool {
+	for _, e := range constants.UntaggedEndpoints {
+		if e == endpoint {
+			return true
+		}
+	}
+	return false
+}
+
 // Cache returns the cache manager
 func (c *NetBoxClient) Cache() *CacheManager {
 	return c.cache
 }
 
+// Stats returns the stats collector
+func (c *NetBoxClient) Stats() *Stats {
+	return c.stats
+}
+
 // Tags returns the tag manager
 func (c *NetBoxClient) Tags() *TagManager {
 	return c.tagManager
@@ -1,8 +1,15 @@
 package client
 
 import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
 	"github.com/braunma/netbox-gitops-controller/pkg/utils"
 )
 
@@ -223,6 +230,50 @@ func TestCalculateDiff(t *testing.T) {
 			},
 			expected: map[string]interface{}{},
 		},
+		{
+			name: "Null clears value",
+			existing: Object{
+				"asset_tag": "INV-1000",
+			},
+			desired: map[string]interface{}{
+				"asset_tag": Null,
+			},
+			expected: map[string]interface{}{
+				"asset_tag": Null,
+			},
+		},
+		{
+			name: "Null on null value unchanged",
+			existing: Object{
+				"asset_tag": nil,
+			},
+			desired: map[string]interface{}{
+				"asset_tag": Null,
+			},
+			expected: map[string]interface{}{},
+		},
+		{
+			name: "CRLF comments equal LF comments",
+			existing: Object{
+				"comments": "Line one\nLine two\n",
+			},
+			desired: map[string]interface{}{
+				"comments": "Line one\r\nLine two\r\n",
+			},
+			expected: map[string]interface{}{},
+		},
+		{
+			name: "changed CRLF comments are sent with LF",
+			existing: Object{
+				"comments": "Line one\n",
+			},
+			desired: map[string]interface{}{
+				"comments": "Line one\r\nLine two\r\n",
+			},
+			expected: map[string]interface{}{
+				"comments": "Line one\nLine two\n",
+			},
+		},
 		{
 			name: "int to float conversion",
 			existing: Object{
@@ -389,6 +440,16 @@ func TestExtractTagIDs(t *testing.T) {
 	}
 }
 
+func TestInjectTagKeepsIntTags(t *testing.T) {
+	tm := &TagManager{}
+	payload := tm.InjectTag(map[string]interface{}{"tags": []int{7}}, 1)
+
+	tags, ok := payload["tags"].([]int)
+	if !ok || len(tags) != 2 || tags[0] != 7 || tags[1] != 1 {
+		t.Errorf("InjectTag() tags = %v, expected [7 1]", payload["tags"])
+	}
+}
+
 func TestTagsEqual(t *testing.T) {
 	logger := utils.NewLogger(true)
 	client := &NetBoxClient{
@@ -444,3 +505,684 @@ func TestTagsEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyEmptyResponses(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		status int
+	}{
+		{"204 No Content", http.StatusNoContent},
+		{"empty 200", http.StatusOK},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := netboxtest.NewServer()
+			defer srv.Close()
+
+			c, err := NewClient(srv.URL, "test-token", false)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+
+			// NetBox stores the object but answers without a body
+			srv.Intercept("POST", "/api/dcim/sites/", func(w http.ResponseWriter, r *http.Request) {
+				srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+				w.WriteHeader(tt.status)
+			})
+			srv.Intercept("PATCH", "/api/dcim/sites/2/", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			})
+
+			lookup := map[string]interface{}{"slug": "berlin-dc"}
+			created, err := c.Apply("dcim", "sites", lookup, map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+			if err != nil {
+				t.Fatalf("Apply() create error = %v", err)
+			}
+			if id := utils.GetIDFromObject(created); id != 2 {
+				t.Errorf("Apply() create returned ID %d, expected 2 (not the dry-run ID 0)", id)
+			}
+
+			updated, err := c.Apply("dcim", "sites", lookup, map[string]interface{}{"name": "Berlin", "slug": "berlin-dc"})
+			if err != nil {
+				t.Fatalf("Apply() update error = %v", err)
+			}
+			if id := utils.GetIDFromObject(updated); id != 2 {
+				t.Errorf("Apply() update returned ID %d, expected 2", id)
+			}
+
+			if err := c.Delete("dcim", "sites", 2); err != nil {
+				t.Errorf("Delete() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestApplyEmptyCreateResponseWithoutObject(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	srv.Intercept("POST", "/api/dcim/sites/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	_, err = c.Apply("dcim", "sites", map[string]interface{}{"slug": "ghost"}, map[string]interface{}{"name": "Ghost", "slug": "ghost"})
+	if err == nil {
+		t.Error("Apply() expected error when the created object cannot be found")
+	}
+}
+
+func TestFilterManaged(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	// More managed objects than fit in one page, tagged the way Apply tags them (by ID)
+	for i := 0; i < 1200; i++ {
+		srv.Add("ipam", "ip-addresses", map[string]interface{}{
+			"address": fmt.Sprintf("10.%d.%d.1/24", i/256, i%256),
+			"tags":    []interface{}{c.ManagedTagID()},
+		})
+	}
+	for i := 0; i < 5; i++ {
+		srv.Add("ipam", "ip-addresses", map[string]interface{}{"address": fmt.Sprintf("192.168.0.%d/24", i)})
+	}
+
+	srv.ResetRequests()
+	objects, err := c.FilterManaged("ipam", "ip-addresses")
+	if err != nil {
+		t.Fatalf("FilterManaged() error = %v", err)
+	}
+	if len(objects) != 1200 {
+		t.Errorf("FilterManaged() returned %d objects, expected 1200", len(objects))
+	}
+
+	requests := srv.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("FilterManaged() sent %d requests, expected 2 pages", len(requests))
+	}
+	for _, r := range requests {
+		if got := r.Query.Get("tag"); got != "gitops" {
+			t.Errorf("Request %s tag filter = %q, expected gitops", r.Query.Encode(), got)
+		}
+	}
+}
+
+func TestEncodeFilters(t *testing.T) {
+	filters := map[string]interface{}{
+		"site_id": 3,
+		"name":    "eth 0/1",
+		"vid":     100,
+		"tag":     []string{"gitops", "production"},
+		"limit":   1000,
+		"offset":  0,
+	}
+
+	expected := "limit=1000&name=eth+0%2F1&offset=0&site_id=3&tag=gitops&tag=production&vid=100"
+	for i := 0; i < 20; i++ {
+		if got := encodeFilters(filters); got != expected {
+			t.Fatalf("encodeFilters() = %q, expected %q", got, expected)
+		}
+	}
+}
+
+func TestFilterMemoizesUntilWrite(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	template := srv.Add("dcim", "rear-port-templates", map[string]interface{}{"name": "rear1", "device_type_id": 7})
+	srv.Add("dcim", "interfaces", map[string]interface{}{"name": "eth0"})
+
+	srv.ResetRequests()
+	filters := map[string]interface{}{"device_type_id": 7, "name": "rear1"}
+	for i := 0; i < 3; i++ {
+		results, err := c.Filter("dcim", "rear-port-templates", filters)
+		if err != nil {
+			t.Fatalf("Filter() error = %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("Filter() returned %d results, expected 1", len(results))
+		}
+		// Modifying a result must not leak into the memoized response
+		results[0]["name"] = "modified"
+	}
+	if got := srv.CountRequests("GET", "/api/dcim/rear-port-templates/"); got != 1 {
+		t.Errorf("Repeated identical Filter() sent %d requests, expected 1", got)
+	}
+
+	// A write to another endpoint keeps the memo, a write to the same endpoint drops it
+	if err := c.Update("dcim", "interfaces", netboxtest.ID(srv.Find("dcim", "interfaces", "name", "eth0")), map[string]interface{}{"mtu": 9000}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := c.Filter("dcim", "rear-port-templates", filters); err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if got := srv.CountRequests("GET", "/api/dcim/rear-port-templates/"); got != 1 {
+		t.Errorf("Filter() after unrelated write sent %d requests, expected 1", got)
+	}
+
+	if err := c.Update("dcim", "rear-port-templates", netboxtest.ID(template), map[string]interface{}{"positions": 2}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	results, err := c.Filter("dcim", "rear-port-templates", filters)
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if got := srv.CountRequests("GET", "/api/dcim/rear-port-templates/"); got != 2 {
+		t.Errorf("Filter() after write sent %d requests in total, expected 2", got)
+	}
+	if len(results) != 1 || results[0]["name"] != "rear1" {
+		t.Errorf("Filter() after write = %v, expected the unmodified template", results)
+	}
+
+	// A cable write drops the ports it may have connected or disconnected
+	portFilters := map[string]interface{}{"name": "eth0"}
+	if _, err := c.Filter("dcim", "interfaces", portFilters); err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if _, err := c.Create("dcim", "cables", map[string]interface{}{"type": "cat6"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	srv.ResetRequests()
+	if _, err := c.Filter("dcim", "interfaces", portFilters); err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if got := srv.CountRequests("GET", "/api/dcim/interfaces/"); got != 1 {
+		t.Errorf("Filter() after a cable write sent %d requests, expected 1", got)
+	}
+}
+
+func TestSummaryOnlySuppressesObjectLogs(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	c.Logger().SetOutput(&out, &out)
+	utils.ConfigureSummaryOnly(true)
+	t.Cleanup(func() { utils.ConfigureSummaryOnly(false) })
+
+	if _, err := c.Apply("dcim", "sites", map[string]interface{}{"slug": "berlin"}, map[string]interface{}{"name": "Berlin", "slug": "berlin"}); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	c.Logger().Warning("site berlin has no region")
+	c.Stats().PrintSummary(c.Logger())
+
+	logs := out.String()
+	if strings.Contains(logs, "Creating sites") || strings.Contains(logs, "name: \"Berlin\"") {
+		t.Errorf("Summary-only output contains per-object lines:\n%s", logs)
+	}
+	if !strings.Contains(logs, "site berlin has no region") {
+		t.Errorf("Summary-only output is missing the warning:\n%s", logs)
+	}
+	if !strings.Contains(logs, "Summary:") || !strings.Contains(logs, "sites") {
+		t.Errorf("Summary-only output is missing the summary table:\n%s", logs)
+	}
+}
+
+func TestNewClientDryRunReadsExistingManagedTag(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	tag := srv.Add("extras", "tags", map[string]interface{}{"name": "GitOps Managed", "slug": "gitops", "id": 42})
+
+	c, err := NewClient(srv.URL, "test-token", true)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if got := c.ManagedTagID(); got != netboxtest.ID(tag) {
+		t.Errorf("ManagedTagID() in dry-run = %d, expected %d", got, netboxtest.ID(tag))
+	}
+	if got := srv.CountRequests("POST", "/api/extras/tags/"); got != 0 {
+		t.Errorf("Dry-run sent %d tag POST requests, expected 0", got)
+	}
+}
+
+func TestNewClientDryRunDoesNotCreateManagedTag(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "test-token", true)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	if got := c.ManagedTagID(); got != 0 {
+		t.Errorf("ManagedTagID() in dry-run without tag = %d, expected 0", got)
+	}
+	if got := srv.CountRequests("POST", "/api/extras/tags/"); got != 0 {
+		t.Errorf("Dry-run sent %d tag POST requests, expected 0", got)
+	}
+}
+
+func TestApplyRecoversFromUniqueViolation(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	// Created with a differently cased slug, so the lookup misses it
+	managed := []interface{}{map[string]interface{}{"id": c.ManagedTagID(), "slug": constants.ManagedTagSlug}}
+	existing := srv.Add("dcim", "manufacturers", map[string]interface{}{"name": "Juniper", "slug": "Juniper", "tags": managed})
+	srv.Intercept("POST", "/api/dcim/manufacturers/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"slug": ["manufacturer with this slug already exists."]}`))
+	})
+
+	obj, err := c.Apply("dcim", "manufacturers", map[string]interface{}{"slug": "juniper"}, map[string]interface{}{"name": "Juniper", "slug": "juniper"})
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if netboxtest.ID(obj["id"]) != netboxtest.ID(existing) {
+		t.Errorf("Apply() returned ID %d, expected the existing object %d", netboxtest.ID(obj["id"]), netboxtest.ID(existing))
+	}
+	if got := srv.CountRequests("PATCH", "/api/dcim/manufacturers/"); got != 1 {
+		t.Errorf("Apply() sent %d PATCH requests, expected 1", got)
+	}
+	if existing["slug"] != "juniper" {
+		t.Errorf("Existing object slug = %v, expected it to be updated to juniper", existing["slug"])
+	}
+
+	// An object created outside of gitops is not taken over
+	manual := srv.Add("dcim", "manufacturers", map[string]interface{}{"name": "Arista", "slug": "Arista"})
+	srv.ResetRequests()
+	_, err = c.Apply("dcim", "manufacturers", map[string]interface{}{"slug": "arista"}, map[string]interface{}{"name": "Arista", "slug": "arista"})
+	if err == nil || !strings.Contains(err.Error(), "not managed by gitops") {
+		t.Errorf("Apply() error = %v, expected the unmanaged object to be refused", err)
+	}
+	if got := srv.CountRequests("PATCH", "/api/dcim/manufacturers/"); got != 0 || manual["slug"] != "Arista" {
+		t.Errorf("Apply() sent %d PATCH requests and left slug %v, expected the unmanaged object untouched", got, manual["slug"])
+	}
+}
+
+// TestApplyUniqueViolationKeepsLookupScope tests that the case-insensitive retry stays on the
+// lookup's device, so an interface of another device with the same name is not updated
+func TestApplyUniqueViolationKeepsLookupScope(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	managed := []interface{}{map[string]interface{}{"id": c.ManagedTagID(), "slug": constants.ManagedTagSlug}}
+	leaf1 := srv.Add("dcim", "devices", map[string]interface{}{"name": "leaf-01"})
+	leaf2 := srv.Add("dcim", "devices", map[string]interface{}{"name": "leaf-02"})
+	other := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "ETH0", "device": leaf2["id"], "tags": managed})
+	own := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "Eth0", "device": leaf1["id"], "tags": managed})
+	srv.Intercept("POST", "/api/dcim/interfaces/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"name": ["interface with this name already exists."]}`))
+	})
+
+	lookup := map[string]interface{}{"device_id": leaf1["id"], "name": "eth0"}
+	payload := map[string]interface{}{"device": leaf1["id"], "name": "eth0", "description": "uplink"}
+	if _, err := c.Apply("dcim", "interfaces", lookup, payload); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if own["description"] != "uplink" {
+		t.Errorf("leaf-01 Eth0 description = %v, expected it to be updated", own["description"])
+	}
+	if other["description"] != nil {
+		t.Errorf("leaf-02 ETH0 description = %v, expected the other device's interface untouched", other["description"])
+	}
+}
+
+func TestApplyReturnsOtherCreateErrors(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	srv.Intercept("POST", "/api/dcim/manufacturers/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"name": ["This field is required."]}`))
+	})
+
+	if _, err := c.Apply("dcim", "manufacturers", map[string]interface{}{"slug": "juniper"}, map[string]interface{}{"slug": "juniper"}); err == nil {
+		t.Error("Apply() expected error for a non-uniqueness 400")
+	}
+	if got := srv.CountRequests("PATCH", "/api/dcim/manufacturers/"); got != 0 {
+		t.Errorf("Apply() sent %d PATCH requests, expected 0", got)
+	}
+}
+
+func TestTraceHTTP(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "secret-token-123", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	var out bytes.Buffer
+	c.Logger().SetOutput(&out, &out)
+	c.SetTraceHTTP(true)
+
+	if _, err := c.Create("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	logs := out.String()
+	for _, expected := range []string{"→ POST " + srv.URL + "/api/dcim/sites/", `"slug":"berlin-dc"`, "← 201", "Authorization: <redacted>"} {
+		if !strings.Contains(logs, expected) {
+			t.Errorf("Trace output is missing %q:\n%s", expected, logs)
+		}
+	}
+	if strings.Contains(logs, "secret-token-123") {
+		t.Errorf("Trace output contains the token:\n%s", logs)
+	}
+
+	out.Reset()
+	c.SetTraceHTTP(false)
+	if _, err := c.Filter("dcim", "sites", map[string]interface{}{"slug": "berlin-dc"}); err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if strings.Contains(out.String(), "→") {
+		t.Errorf("Trace output after disabling tracing:\n%s", out.String())
+	}
+}
+
+func TestSetConnectionPool(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	transport := c.transport()
+	if transport.MaxIdleConns != constants.DefaultMaxIdleConns || transport.MaxIdleConnsPerHost != constants.DefaultMaxIdleConnsPerHost ||
+		transport.IdleConnTimeout != constants.DefaultIdleConnTimeout {
+		t.Errorf("Default pool = %d/%d/%v, expected %d/%d/%v", transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.IdleConnTimeout,
+			constants.DefaultMaxIdleConns, constants.DefaultMaxIdleConnsPerHost, constants.DefaultIdleConnTimeout)
+	}
+
+	// The pool is tuned below the --trace-http wrapper
+	c.SetTraceHTTP(true)
+	c.SetConnectionPool(200, 64, 30*time.Second)
+	transport = c.transport()
+	if transport.MaxIdleConns != 200 || transport.MaxIdleConnsPerHost != 64 || transport.IdleConnTimeout != 30*time.Second {
+		t.Errorf("Pool = %d/%d/%v, expected 200/64/30s", transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+
+	// Zero values keep the current settings
+	c.SetConnectionPool(0, 0, 0)
+	if transport.MaxIdleConns != 200 || transport.MaxIdleConnsPerHost != 64 || transport.IdleConnTimeout != 30*time.Second {
+		t.Errorf("Pool after zero values = %d/%d/%v, expected 200/64/30s", transport.MaxIdleConns, transport.MaxIdleConnsPerHost, transport.IdleConnTimeout)
+	}
+}
+
+func TestJournalOnlyForChangedObjects(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	c.Logger().SetOutput(&bytes.Buffer{}, &bytes.Buffer{})
+	c.EnableJournal()
+
+	tagged := []interface{}{map[string]interface{}{"id": c.ManagedTagID()}}
+	unchanged := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc", "tags": tagged})
+	updated := srv.Add("dcim", "sites", map[string]interface{}{"name": "Munich DC", "slug": "munich-dc", "status": "planned", "tags": tagged})
+
+	sites := []map[string]interface{}{
+		{"name": "Berlin DC", "slug": "berlin-dc"},
+		{"name": "Munich DC", "slug": "munich-dc", "status": "active"},
+		{"name": "Hamburg DC", "slug": "hamburg-dc"},
+	}
+	for _, site := range sites {
+		if _, err := c.Apply("dcim", "sites", map[string]interface{}{"slug": site["slug"]}, site); err != nil {
+			t.Fatalf("Apply(%s) error = %v", site["slug"], err)
+		}
+	}
+
+	if got := srv.CountRequests("POST", "/api/extras/journal-entries/"); got != 0 {
+		t.Fatalf("Journal entries posted before FlushJournal: %d requests", got)
+	}
+	if err := c.FlushJournal(); err != nil {
+		t.Fatalf("FlushJournal() error = %v", err)
+	}
+	if got := srv.CountRequests("POST", "/api/extras/journal-entries/"); got != 1 {
+		t.Errorf("FlushJournal() sent %d requests, expected 1 bulk request", got)
+	}
+
+	created := srv.Find("dcim", "sites", "slug", "hamburg-dc")
+	entries := srv.Objects("extras", "journal-entries")
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 journal entries (updated and created site), got %d: %v", len(entries), entries)
+	}
+	for _, entry := range entries {
+		if entry["assigned_object_type"] != "dcim.site" {
+			t.Errorf("assigned_object_type = %v, expected dcim.site", entry["assigned_object_type"])
+		}
+		if netboxtest.ID(entry["assigned_object_id"]) == netboxtest.ID(unchanged) {
+			t.Errorf("Journal entry posted for unchanged site: %v", entry)
+		}
+	}
+	if netboxtest.ID(entries[0]["assigned_object_id"]) != netboxtest.ID(updated) || entries[0]["comments"] != "Updated by the GitOps controller: status" {
+		t.Errorf("First entry = %v, expected update of munich-dc listing status", entries[0])
+	}
+	if netboxtest.ID(entries[1]["assigned_object_id"]) != netboxtest.ID(created) {
+		t.Errorf("Second entry = %v, expected creation of hamburg-dc", entries[1])
+	}
+
+	// The queue is empty after flushing
+	if err := c.FlushJournal(); err != nil {
+		t.Fatalf("FlushJournal() error = %v", err)
+	}
+	if got := srv.CountRequests("POST", "/api/extras/journal-entries/"); got != 1 {
+		t.Errorf("Second FlushJournal() posted again: %d requests", got)
+	}
+}
+
+// TestCompactDiff tests that --diff-only-changed lists one line per changed field, grouped by
+// resource type and object, instead of a diff box per object
+func TestCompactDiff(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	var out bytes.Buffer
+	c.Logger().SetOutput(&out, &out)
+	c.SetCompactDiff(true)
+
+	srv.Add("dcim", "devices", map[string]interface{}{"name": "web-02", "serial": "A1", "status": "active"})
+	web01 := srv.Add("dcim", "devices", map[string]interface{}{"name": "web-01", "position": 10, "status": "active"})
+	srv.Add("dcim", "interfaces", map[string]interface{}{"name": "eth0", "device": map[string]interface{}{"id": web01["id"]}, "description": "old"})
+	oldSite := srv.Add("dcim", "sites", map[string]interface{}{"name": "Old DC", "slug": "old-dc"})
+
+	applies := []struct {
+		endpoint string
+		payload  map[string]interface{}
+	}{
+		{"devices", map[string]interface{}{"name": "web-02", "serial": "B2", "status": "active"}},
+		{"devices", map[string]interface{}{"name": "web-01", "position": 12, "status": "offline"}},
+		{"sites", map[string]interface{}{"name": "Hamburg DC", "slug": "hamburg-dc"}},
+	}
+	for _, apply := range applies {
+		lookup := map[string]interface{}{"name": apply.payload["name"]}
+		if _, err := c.Apply("dcim", apply.endpoint, lookup, apply.payload); err != nil {
+			t.Fatalf("Apply(%v) error = %v", lookup, err)
+		}
+	}
+
+	// Device components are named with their device, deletes are listed too
+	ifaceLookup := map[string]interface{}{"device_id": web01["id"], "name": "eth0"}
+	ifacePayload := map[string]interface{}{"device": web01["id"], "name": "eth0", "description": "uplink"}
+	if _, err := c.Apply("dcim", "interfaces", ifaceLookup, ifacePayload); err != nil {
+		t.Fatalf("Apply(%v) error = %v", ifaceLookup, err)
+	}
+	if err := c.Delete("dcim", "sites", oldSite["id"].(int)); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	expected := []string{
+		"devices/web-01: position 10→12",
+		"devices/web-01: status active→offline",
+		"devices/web-02: serial A1→B2",
+		"interfaces/web-01/eth0: description old→uplink",
+		"sites/Hamburg DC: created",
+		"sites/Old DC: deleted",
+	}
+	if got := c.Stats().CompactDiff(); strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("CompactDiff() = %q, expected %q", got, expected)
+	}
+	if strings.Contains(out.String(), "┌─ Changes") {
+		t.Errorf("Diff boxes printed with the compact diff enabled:\n%s", out.String())
+	}
+
+	out.Reset()
+	c.Stats().PrintCompactDiff(c.Logger())
+	if !strings.Contains(out.String(), "  devices/web-01: status active→offline") {
+		t.Errorf("PrintCompactDiff() output = %q, expected the status change", out.String())
+	}
+}
+
+// TestJournalContentTypes tests that only journaling models are journaled, with their content type
+func TestJournalContentTypes(t *testing.T) {
+	c := &NetBoxClient{journal: &journal{}}
+	journaled := map[string]string{
+		"dcim/devices":              "dcim.device",
+		"dcim/device-roles":         "dcim.devicerole",
+		"dcim/virtual-chassis":      "dcim.virtualchassis",
+		"ipam/ip-addresses":         "ipam.ipaddress",
+		"ipam/prefixes":             "ipam.prefix",
+		"virtualization/interfaces": "virtualization.vminterface",
+	}
+	notJournaled := []string{
+		"dcim/interface-templates",
+		"extras/tags",
+		"extras/custom-fields",
+		"extras/journal-entries",
+		"tenancy/contact-assignments",
+		"ipam/fhrp-group-assignments",
+	}
+
+	for path := range journaled {
+		parts := strings.SplitN(path, "/", 2)
+		c.recordJournal(parts[0], parts[1], 1, "test")
+	}
+	for _, path := range notJournaled {
+		parts := strings.SplitN(path, "/", 2)
+		c.recordJournal(parts[0], parts[1], 1, "test")
+	}
+
+	if len(c.journal.entries) != len(journaled) {
+		t.Fatalf("Queued %d entries, expected %d for the journaling models only: %v", len(c.journal.entries), len(journaled), c.journal.entries)
+	}
+	got := make(map[string]bool)
+	for _, entry := range c.journal.entries {
+		got[entry["assigned_object_type"].(string)] = true
+	}
+	for path, expected := range journaled {
+		if !got[expected] {
+			t.Errorf("No entry of type %s queued for %s", expected, path)
+		}
+	}
+}
+
+// TestFlushJournalContinuesAfterFailedBatch tests that a rejected batch doesn't drop later batches
+func TestFlushJournalContinuesAfterFailedBatch(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	c.Logger().SetOutput(&bytes.Buffer{}, &bytes.Buffer{})
+	c.EnableJournal()
+
+	posts := 0
+	srv.Intercept("POST", "/api/extras/journal-entries/", func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		if posts == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"detail": "rejected"}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `[]`)
+	})
+
+	for id := 1; id <= 2*constants.JournalBatchSize+1; id++ {
+		c.recordJournal("dcim", "devices", id, "test")
+	}
+	err = c.FlushJournal()
+	if posts != 3 {
+		t.Errorf("FlushJournal() posted %d batches, expected all 3", posts)
+	}
+	if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("1-%d", constants.JournalBatchSize)) {
+		t.Errorf("FlushJournal() error = %v, expected the first batch to be reported", err)
+	}
+}
+
+func TestServerVersion(t *testing.T) {
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+	srv.Intercept("GET", "/api/status/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"netbox-version": "4.1.3", "python-version": "3.12.3"}`)
+	})
+
+	c, err := NewClient(srv.URL, "test-token", true)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	got, err := c.ServerVersion()
+	if err != nil {
+		t.Fatalf("ServerVersion() error = %v", err)
+	}
+	if got != "4.1.3" {
+		t.Errorf("ServerVersion() = %q, expected 4.1.3", got)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version      string
+		major, minor int
+		wantErr      bool
+	}{
+		{"4.1.3", 4, 1, false},
+		{"v4.2-beta1", 4, 2, false},
+		{"3.7", 3, 7, false},
+		{"4", 0, 0, true},
+		{"unknown", 0, 0, true},
+	}
+	for _, tt := range tests {
+		major, minor, err := parseVersion(tt.version)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseVersion(%q) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+			continue
+		}
+		if major != tt.major || minor != tt.minor {
+			t.Errorf("parseVersion(%q) = %d.%d, expected %d.%d", tt.version, major, minor, tt.major, tt.minor)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SetExternalChangeCheck enables warnings for objects modified in NetBox after lastRun
+// A zero time disables the check
+func (c *NetBoxClient) SetExternalChangeCheck(lastRun time.Time) {
+	c.lastRun = lastRun
+}
+
+// warnOnExternalChange logs a warning if an object about to be updated was
+// changed in NetBox (by someone else) since the last controller run
+func (c *NetBoxClient) warnOnExternalChange(endpoint string, obj Object, lookup map[string]interface{}, changes map[string]interface{}) {
+	if c.lastRun.IsZero() {
+		return
+	}
+
+	raw, ok := obj["last_updated"].(string)
+	if !ok {
+		return
+	}
+	lastUpdated, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		c.logger.Debug("  Cannot parse last_updated %q: %v", raw, err)
+		return
+	}
+	if !lastUpdated.After(c.lastRun) {
+		return
+	}
+
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	c.logger.Warning("  EXTERNAL CHANGE: %s %s was modified in NetBox at %s (after last run at %s), overwriting: %s",
+		endpoint, c.formatLookup(lookup), lastUpdated.Format(time.RFC3339), c.lastRun.Format(time.RFC3339), strings.Join(fields, ", "))
+}
+
+// ReadLastRun reads the timestamp of the last successful run from path
+// A missing file returns the zero time (first run)
+func ReadLastRun(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read last run file: %w", err)
+	}
+
+	lastRun, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(data)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid last run timestamp in %s: %w", path, err)
+	}
+	return lastRun, nil
+}
+
+// WriteLastRun records the timestamp of a successful run to path
+func WriteLastRun(path string, t time.Time) error {
+	if err := os.WriteFile(path, []byte(t.UTC().Format(time.RFC3339Nano)+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write last run file: %w", err)
+	}
+	return nil
+}
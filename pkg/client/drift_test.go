@@ -0,0 +1,77 @@
+package client
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+)
+
+func TestWarnOnExternalChange(t *testing.T) {
+	lastRun := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		lastUpdated string
+		expectWarn  bool
+	}{
+		{"edited after last run", "2024-03-02T08:15:30.123456Z", true},
+		{"unchanged since last run", "2024-02-28T08:15:30.123456Z", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := netboxtest.NewServer()
+			defer srv.Close()
+
+			c, err := NewClient(srv.URL, "test-token", false)
+			if err != nil {
+				t.Fatalf("NewClient() error = %v", err)
+			}
+			srv.Add("dcim", "sites", map[string]interface{}{
+				"name":         "Berlin DC",
+				"slug":         "berlin-dc",
+				"description":  "edited by hand",
+				"last_updated": tt.lastUpdated,
+			})
+
+			var out bytes.Buffer
+			c.Logger().SetOutput(&out, &out)
+			c.SetExternalChangeCheck(lastRun)
+
+			lookup := map[string]interface{}{"slug": "berlin-dc"}
+			if _, err := c.Apply("dcim", "sites", lookup, map[string]interface{}{"slug": "berlin-dc", "description": "from git"}); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+
+			warned := strings.Contains(out.String(), "EXTERNAL CHANGE: sites slug=berlin-dc")
+			if warned != tt.expectWarn {
+				t.Errorf("External change warning = %v, expected %v; output:\n%s", warned, tt.expectWarn, out.String())
+			}
+		})
+	}
+}
+
+func TestLastRunRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "last-run")
+
+	lastRun, err := ReadLastRun(path)
+	if err != nil || !lastRun.IsZero() {
+		t.Fatalf("ReadLastRun() on missing file = %v, %v; expected zero time", lastRun, err)
+	}
+
+	now := time.Now()
+	if err := WriteLastRun(path, now); err != nil {
+		t.Fatalf("WriteLastRun() error = %v", err)
+	}
+	lastRun, err = ReadLastRun(path)
+	if err != nil {
+		t.Fatalf("ReadLastRun() error = %v", err)
+	}
+	if !lastRun.Equal(now) {
+		t.Errorf("ReadLastRun() = %v, expected %v", lastRun, now)
+	}
+}
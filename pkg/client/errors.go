@@ -0,0 +1,67 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// APIError is an error response (HTTP status >= 400) from the NetBox API
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error %d: %s", e.StatusCode, e.Body)
+}
+
+// CacheUnavailableError reports a global cache resource that could not be loaded from NetBox
+type CacheUnavailableError struct {
+	Resource string
+	Err      error
+}
+
+func (e *CacheUnavailableError) Error() string {
+	var apiErr *APIError
+	if errors.As(e.Err, &apiErr) {
+		return fmt.Sprintf("%s cache unavailable (%d)", e.Resource, apiErr.StatusCode)
+	}
+	return fmt.Sprintf("%s cache unavailable (%v)", e.Resource, e.Err)
+}
+
+func (e *CacheUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// uniqueViolationFields returns the fields of a NetBox uniqueness 400, which looks like
+// {"slug": ["site with this slug already exists."]}. ok is false for any other error.
+func uniqueViolationFields(err error) (fields []string, ok bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 400 {
+		return nil, false
+	}
+
+	var body map[string]interface{}
+	if json.Unmarshal([]byte(apiErr.Body), &body) != nil {
+		return nil, false
+	}
+
+	for field, messages := range body {
+		list, isList := messages.([]interface{})
+		if !isList {
+			continue
+		}
+		for _, msg := range list {
+			if s, isString := msg.(string); isString && strings.Contains(s, "already exists") {
+				fields = append(fields, field)
+				break
+			}
+		}
+	}
+	sort.Strings(fields)
+
+	return fields, len(fields) > 0
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
+)
+
+// journalContentTypes maps the endpoints of models that support journal entries to their
+// content type. Other models (templates, tags, custom fields, assignments, ...) have no
+// journal, and a single entry for one of them makes NetBox reject the whole bulk request.
+var journalContentTypes = map[string]string{
+	"circuits/circuit-types":          "circuits.circuittype",
+	"circuits/circuits":               "circuits.circuit",
+	"circuits/providers":              "circuits.provider",
+	"dcim/cables":                     "dcim.cable",
+	"dcim/console-ports":              "dcim.consoleport",
+	"dcim/console-server-ports":       "dcim.consoleserverport",
+	"dcim/device-bays":                "dcim.devicebay",
+	"dcim/device-roles":               "dcim.devicerole",
+	"dcim/device-types":               "dcim.devicetype",
+	"dcim/devices":                    "dcim.device",
+	"dcim/front-ports":                "dcim.frontport",
+	"dcim/interfaces":                 "dcim.interface",
+	"dcim/inventory-item-roles":       "dcim.inventoryitemrole",
+	"dcim/inventory-items":            "dcim.inventoryitem",
+	"dcim/locations":                  "dcim.location",
+	"dcim/manufacturers":              "dcim.manufacturer",
+	"dcim/module-bays":                "dcim.modulebay",
+	"dcim/module-types":               "dcim.moduletype",
+	"dcim/modules":                    "dcim.module",
+	"dcim/platforms":                  "dcim.platform",
+	"dcim/power-outlets":              "dcim.poweroutlet",
+	"dcim/power-ports":                "dcim.powerport",
+	"dcim/racks":                      "dcim.rack",
+	"dcim/rear-ports":                 "dcim.rearport",
+	"dcim/regions":                    "dcim.region",
+	"dcim/site-groups":                "dcim.sitegroup",
+	"dcim/sites":                      "dcim.site",
+	"dcim/virtual-chassis":            "dcim.virtualchassis",
+	"ipam/fhrp-groups":                "ipam.fhrpgroup",
+	"ipam/ip-addresses":               "ipam.ipaddress",
+	"ipam/prefixes":                   "ipam.prefix",
+	"ipam/roles":                      "ipam.role",
+	"ipam/services":                   "ipam.service",
+	"ipam/vlan-groups":                "ipam.vlangroup",
+	"ipam/vlan-translation-policies":  "ipam.vlantranslationpolicy",
+	"ipam/vlan-translation-rules":     "ipam.vlantranslationrule",
+	"ipam/vlans":                      "ipam.vlan",
+	"ipam/vrfs":                       "ipam.vrf",
+	"tenancy/contact-groups":          "tenancy.contactgroup",
+	"tenancy/contact-roles":           "tenancy.contactrole",
+	"tenancy/contacts":                "tenancy.contact",
+	"tenancy/tenant-groups":           "tenancy.tenantgroup",
+	"tenancy/tenants":                 "tenancy.tenant",
+	"virtualization/cluster-groups":   "virtualization.clustergroup",
+	"virtualization/cluster-types":    "virtualization.clustertype",
+	"virtualization/clusters":         "virtualization.cluster",
+	"virtualization/interfaces":       "virtualization.vminterface",
+	"virtualization/virtual-machines": "virtualization.virtualmachine",
+}
+
+// journal queues journal entries for objects changed during a run until FlushJournal
+type journal struct {
+	mu      sync.Mutex
+	entries []map[string]interface{}
+	dropped int
+}
+
+// EnableJournal records a journal entry in NetBox for every object the run creates or
+// updates (--journal). Entries are queued and posted in batches by FlushJournal.
+func (c *NetBoxClient) EnableJournal() {
+	c.journal = &journal{}
+}
+
+// recordJournal queues a journal entry for a created or updated object of a journaling model
+func (c *NetBoxClient) recordJournal(app, endpoint string, id int, comments string) {
+	if c.journal == nil || c.dryRun || id <= 0 {
+		return
+	}
+	objectType, ok := journalContentTypes[app+"/"+endpoint]
+	if !ok {
+		return
+	}
+
+	c.journal.mu.Lock()
+	defer c.journal.mu.Unlock()
+
+	if len(c.journal.entries) >= constants.MaxJournalEntries {
+		c.journal.dropped++
+		return
+	}
+	c.journal.entries = append(c.journal.entries, map[string]interface{}{
+		"assigned_object_type": objectType,
+		"assigned_object_id":   id,
+		"kind":                 "info",
+		"comments":             comments,
+	})
+}
+
+// FlushJournal posts the queued journal entries in bulk and clears the queue
+func (c *NetBoxClient) FlushJournal() error {
+	if c.journal == nil {
+		return nil
+	}
+
+	c.journal.mu.Lock()
+	entries, dropped := c.journal.entries, c.journal.dropped
+	c.journal.entries, c.journal.dropped = nil, 0
+	c.journal.mu.Unlock()
+
+	if dropped > 0 {
+		c.logger.Warning("Journal: %d changed objects over the limit of %d entries per run were not journaled", dropped, constants.MaxJournalEntries)
+	}
+
+	// A rejected batch doesn't stop the others from being recorded
+	var errs []error
+	recorded := 0
+	for start := 0; start < len(entries); start += constants.JournalBatchSize {
+		end := start + constants.JournalBatchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		if _, err := c.Request("POST", "/api/extras/journal-entries/", entries[start:end]); err != nil {
+			errs = append(errs, fmt.Errorf("failed to post journal entries %d-%d: %w", start+1, end, err))
+			continue
+		}
+		recorded += end - start
+	}
+
+	if recorded > 0 {
+		c.logger.Info("Journal: recorded %d entries in NetBox", recorded)
+	}
+	return errors.Join(errs...)
+}
+
+// journalComment summarizes the changed fields of an update for a journal entry
+func journalComment(changes map[string]interface{}) string {
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return "Updated by the GitOps controller: " + strings.Join(fields, ", ")
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"strings"
+	"sync"
+)
+
+// ResponseMemo caches Get and Filter responses for the lifetime of a client (one sync run).
+// Entries are keyed on the request path plus the sorted query string and are dropped
+// whenever an object of the same app/endpoint (or a cable attached to it) is written.
+// Unlike CacheManager, which maps slugs to IDs, it covers arbitrary filtered lookups.
+type ResponseMemo struct {
+	mu      sync.Mutex
+	entries map[string][]Object
+}
+
+// NewResponseMemo creates an empty response memo
+func NewResponseMemo() *ResponseMemo {
+	return &ResponseMemo{entries: make(map[string][]Object)}
+}
+
+// Get returns a copy of the cached response for key.
+// A nil memo never has entries, so clients built without NewClient don't memoize.
+func (m *ResponseMemo) Get(key string) ([]Object, bool) {
+	if m == nil {
+		return nil, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	objects, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	return copyObjects(objects), true
+}
+
+// Set caches a copy of a response under key
+func (m *ResponseMemo) Set(key string, objects []Object) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = copyObjects(objects)
+}
+
+// cabledEndpoints are the endpoints whose objects show the cable attached to them, so a
+// cable write changes their responses too
+var cabledEndpoints = []string{
+	"/api/dcim/interfaces/",
+	"/api/dcim/front-ports/",
+	"/api/dcim/rear-ports/",
+	"/api/dcim/console-ports/",
+	"/api/dcim/console-server-ports/",
+	"/api/dcim/power-ports/",
+	"/api/dcim/power-outlets/",
+	"/api/dcim/power-feeds/",
+	"/api/circuits/circuit-terminations/",
+}
+
+// Invalidate drops all cached responses of the app/endpoint a request path belongs to.
+// A cable write also drops the responses of the endpoints it can terminate on.
+func (m *ResponseMemo) Invalidate(path string) {
+	if m == nil {
+		return
+	}
+	prefixes := []string{endpointPrefix(path)}
+	if prefixes[0] == "/api/dcim/cables/" {
+		prefixes = append(prefixes, cabledEndpoints...)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key := range m.entries {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				delete(m.entries, key)
+				break
+			}
+		}
+	}
+}
+
+// endpointPrefix reduces an API path like /api/dcim/interfaces/12/ to /api/dcim/interfaces/
+func endpointPrefix(path string) string {
+	parts := strings.SplitN(strings.Trim(path, "/"), "/", 4)
+	if len(parts) < 3 {
+		return path
+	}
+	return "/" + strings.Join(parts[:3], "/") + "/"
+}
+
+// copyObjects copies the result list and the top-level fields of each object,
+// so callers modifying a returned object don't change the cached response
+func copyObjects(objects []Object) []Object {
+	if objects == nil {
+		return nil
+	}
+
+	copied := make([]Object, len(objects))
+	for i, obj := range objects {
+		if obj == nil {
+			continue
+		}
+		copied[i] = make(Object, len(obj))
+		for k, v := range obj {
+			copied[i][k] = v
+		}
+	}
+	return copied
+}
@@ -0,0 +1,16 @@
+package client
+
+// Null clears a nullable field when used as a payload value.
+// A plain nil is ignored by Apply, so it never clears anything by accident.
+var Null = nullValue{}
+
+type nullValue struct{}
+
+// MarshalJSON encodes Null as JSON null
+func (nullValue) MarshalJSON() ([]byte, error) {
+	return []byte("null"), nil
+}
+
+func (nullValue) String() string {
+	return "null"
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// SetConnectionPool tunes the keep-alive pool of the NetBox transport: at most maxIdle idle
+// connections in total and maxIdlePerHost to NetBox, each closed after idleTimeout unused.
+// Values below 1 keep the current setting.
+func (c *NetBoxClient) SetConnectionPool(maxIdle, maxIdlePerHost int, idleTimeout time.Duration) {
+	transport := c.transport()
+	if transport == nil {
+		return
+	}
+	if maxIdle > 0 {
+		transport.MaxIdleConns = maxIdle
+	}
+	if maxIdlePerHost > 0 {
+		transport.MaxIdleConnsPerHost = maxIdlePerHost
+	}
+	if idleTimeout > 0 {
+		transport.IdleConnTimeout = idleTimeout
+	}
+}
+
+// transport returns the HTTP transport below the --trace-http wrapper
+func (c *NetBoxClient) transport() *http.Transport {
+	rt := c.httpClient.Transport
+	if tt, ok := rt.(*traceTransport); ok {
+		rt = tt.next
+	}
+	transport, _ := rt.(*http.Transport)
+	return transport
+}
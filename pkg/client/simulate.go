@@ -0,0 +1,40 @@
+package client
+
+import "sync/atomic"
+
+// SetSimulate makes dry-run creates return synthetic negative IDs instead of ID 0, so
+// objects depending on a would-be-created object (e.g. the interfaces of a new device)
+// are still reconciled and show up in the plan. Nothing is written either way.
+func (c *NetBoxClient) SetSimulate(enabled bool) {
+	c.simulate = enabled
+}
+
+// IsSyntheticID reports whether id was assigned to an object simulated in dry-run
+func IsSyntheticID(id int) bool {
+	return id < 0
+}
+
+// nextSyntheticID returns a new synthetic ID for an object created in a simulated dry-run
+func (c *NetBoxClient) nextSyntheticID() int {
+	return int(atomic.AddInt64(&c.lastSyntheticID, -1))
+}
+
+// hasSyntheticID reports whether a filter references a simulated object. No NetBox object
+// can match it, and NetBox would reject the unknown ID, so such filters are not sent.
+func hasSyntheticID(filters map[string]interface{}) bool {
+	for _, value := range filters {
+		switch v := value.(type) {
+		case int:
+			if IsSyntheticID(v) {
+				return true
+			}
+		case []int:
+			for _, id := range v {
+				if IsSyntheticID(id) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// Sync actions recorded by the stats collector
+const (
+	ActionCreated   = "created"
+	ActionUpdated   = "updated"
+	ActionDeleted   = "deleted"
+	ActionUnchanged = "unchanged"
+)
+
+// FieldChange is one changed field of one object, listed by the compact diff
+// (--diff-only-changed). A created object is recorded once, without a field.
+type FieldChange struct {
+	Resource string // Endpoint, e.g. "devices"
+	Object   string // Name, slug or display name of the object
+	Field    string // Empty for a created or deleted object
+	Old, New string // Formatted values
+	Deleted  bool   // The object was deleted
+}
+
+// Stats collects per-resource-type counts of the actions taken during a sync
+type Stats struct {
+	mu       sync.Mutex
+	counts   map[string]map[string]int
+	fields   []FieldChange
+	observer func(resource, action string)
+}
+
+// NewStats creates an empty stats collector
+func NewStats() *Stats {
+	return &Stats{counts: make(map[string]map[string]int)}
+}
+
+// SetObserver sets a function called for every recorded action (e.g., a progress line)
+func (s *Stats) SetObserver(observer func(resource, action string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.observer = observer
+}
+
+// Record counts one action on a resource type (e.g., "vlans", "created")
+func (s *Stats) Record(resource, action string) {
+	s.mu.Lock()
+	if s.counts[resource] == nil {
+		s.counts[resource] = make(map[string]int)
+	}
+	s.counts[resource][action]++
+	observer := s.observer
+	s.mu.Unlock()
+
+	if observer != nil {
+		observer(resource, action)
+	}
+}
+
+// RecordFieldChange records a changed field (or a created object) for the compact diff
+func (s *Stats) RecordFieldChange(change FieldChange) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fields = append(s.fields, change)
+}
+
+// CompactDiff returns one line per changed field, grouped by resource type and object,
+// e.g. "devices/web-01: status active→offline"
+func (s *Stats) CompactDiff() []string {
+	s.mu.Lock()
+	fields := append([]FieldChange(nil), s.fields...)
+	s.mu.Unlock()
+
+	sort.SliceStable(fields, func(i, j int) bool {
+		if fields[i].Resource != fields[j].Resource {
+			return fields[i].Resource < fields[j].Resource
+		}
+		if fields[i].Object != fields[j].Object {
+			return fields[i].Object < fields[j].Object
+		}
+		return fields[i].Field < fields[j].Field
+	})
+
+	lines := make([]string, 0, len(fields))
+	for _, change := range fields {
+		if change.Deleted {
+			lines = append(lines, fmt.Sprintf("%s/%s: deleted", change.Resource, change.Object))
+			continue
+		}
+		if change.Field == "" {
+			lines = append(lines, fmt.Sprintf("%s/%s: created", change.Resource, change.Object))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s/%s: %s %s→%s", change.Resource, change.Object, change.Field, change.Old, change.New))
+	}
+	return lines
+}
+
+// PrintCompactDiff logs the compact diff, even in summary-only mode
+func (s *Stats) PrintCompactDiff(logger *utils.Logger) {
+	lines := s.CompactDiff()
+	if len(lines) == 0 {
+		logger.Summary("Changes: none")
+		return
+	}
+	logger.Summary("Changes:")
+	for _, line := range lines {
+		logger.Summary("  %s", line)
+	}
+}
+
+// Count returns the number of recorded actions for a resource type
+func (s *Stats) Count(resource, action string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.counts[resource][action]
+}
+
+// Changes returns the number of recorded creates, updates and deletes across all resource types
+func (s *Stats) Changes() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changes := 0
+	for _, actions := range s.counts {
+		changes += actions[ActionCreated] + actions[ActionUpdated] + actions[ActionDeleted]
+	}
+	return changes
+}
+
+// Snapshot returns a copy of all counts, keyed by resource type and action
+func (s *Stats) Snapshot() map[string]map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := make(map[string]map[string]int, len(s.counts))
+	for resource, actions := range s.counts {
+		snapshot[resource] = make(map[string]int, len(actions))
+		for action, n := range actions {
+			snapshot[resource][action] = n
+		}
+	}
+	return snapshot
+}
+
+// Resources returns the recorded resource types in sorted order
+func (s *Stats) Resources() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resources := make([]string, 0, len(s.counts))
+	for resource := range s.counts {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// Reset clears all counts
+func (s *Stats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.counts = make(map[string]map[string]int)
+	s.fields = nil
+}
+
+// PrintSummary logs a per-resource table of the recorded actions
+func (s *Stats) PrintSummary(logger *utils.Logger) {
+	resources := s.Resources()
+	if len(resources) == 0 {
+		logger.Summary("Summary: no objects processed")
+		return
+	}
+
+	snapshot := s.Snapshot()
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Resource\tCreated\tUpdated\tDeleted\tUnchanged\t")
+	for _, resource := range resources {
+		counts := snapshot[resource]
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t\n", resource,
+			counts[ActionCreated], counts[ActionUpdated], counts[ActionDeleted], counts[ActionUnchanged])
+	}
+	w.Flush()
+
+	logger.Summary("Summary:")
+	for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+		logger.Summary("  %s", line)
+	}
+}
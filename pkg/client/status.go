@@ -0,0 +1,70 @@
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ServerVersion returns the NetBox version reported by /api/status/ (e.g. "4.1.3")
+func (c *NetBoxClient) ServerVersion() (string, error) {
+	status, err := c.Request("GET", "/api/status/", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to read NetBox status: %w", err)
+	}
+	version, ok := status["netbox-version"].(string)
+	if !ok || version == "" {
+		return "", fmt.Errorf("NetBox status has no netbox-version")
+	}
+	return version, nil
+}
+
+// Ping checks that NetBox is reachable and accepts the token: NetBox rejects an invalid
+// token on every endpoint, including /api/status/
+func (c *NetBoxClient) Ping() error {
+	if _, err := c.Request("GET", "/api/status/", nil); err != nil {
+		return fmt.Errorf("failed to reach NetBox: %w", err)
+	}
+	return nil
+}
+
+// AtLeastVersion reports whether NetBox is at least major.minor, for fields that older
+// versions reject. The version is read once per client; if it cannot be determined,
+// AtLeastVersion reports false so that version-gated fields are left out.
+func (c *NetBoxClient) AtLeastVersion(major, minor int) bool {
+	c.versionOnce.Do(func() {
+		version, err := c.ServerVersion()
+		if err != nil {
+			c.logger.Warning("Cannot determine the NetBox version, leaving out version-dependent fields: %v", err)
+			return
+		}
+		c.versionMajor, c.versionMinor, err = parseVersion(version)
+		if err != nil {
+			c.logger.Warning("Cannot parse NetBox version %q, leaving out version-dependent fields", version)
+		}
+	})
+
+	if c.versionMajor != major {
+		return c.versionMajor > major
+	}
+	return c.versionMinor >= minor
+}
+
+// parseVersion returns the major and minor number of a version such as "4.1.3" or "v4.2-beta1"
+func parseVersion(version string) (major, minor int, err error) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("invalid version %q", version)
+	}
+	if major, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, fmt.Errorf("invalid version %q", version)
+	}
+	minorDigits := parts[1]
+	if i := strings.IndexFunc(minorDigits, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		minorDigits = minorDigits[:i]
+	}
+	if minor, err = strconv.Atoi(minorDigits); err != nil {
+		return 0, 0, fmt.Errorf("invalid version %q", version)
+	}
+	return major, minor, nil
+}
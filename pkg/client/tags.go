@@ -17,12 +17,10 @@ func NewTagManager(client *NetBoxClient) *TagManager {
 	return &TagManager{client: client}
 }
 
-// Ensure ensures a tag exists, creating it if necessary
+// Ensure ensures a tag exists, creating it if necessary.
+// In dry-run mode the existing tag is still looked up, so its real ID is returned;
+// only the create is skipped (returning 0 when the tag does not exist yet).
 func (tm *TagManager) Ensure(slug string) (int, error) {
-	if tm.client.dryRun {
-		return 0, nil
-	}
-
 	// Try to find existing tag
 	tags, err := tm.client.Filter("extras", "tags", map[string]interface{}{"slug": slug})
 	if err != nil {
@@ -33,6 +31,11 @@ func (tm *TagManager) Ensure(slug string) (int, error) {
 		return utils.GetIDFromObject(tags[0]), nil
 	}
 
+	if tm.client.dryRun {
+		tm.client.logger.Debug("Tag %s does not exist yet, would be created", slug)
+		return 0, nil
+	}
+
 	// Create the tag
 	tagData := map[string]interface{}{
 		"slug":  slug,
@@ -59,7 +62,12 @@ func (tm *TagManager) Ensure(slug string) (int, error) {
 	}
 
 	tm.client.logger.Success("Created system tag: %s", slug)
-	return utils.GetIDFromObject(tag), nil
+	if tagID := utils.GetIDFromObject(tag); tagID > 0 {
+		return tagID, nil
+	}
+
+	// Empty create response, a tag ID of 0 would silently disable tagging
+	return tm.GetID(slug)
 }
 
 // GetID retrieves the ID of a tag by slug
@@ -110,10 +118,15 @@ func (tm *TagManager) InjectTag(payload map[string]interface{}, tagID int) map[s
 		result[k] = v
 	}
 
-	// Get existing tags
-	existingTags, ok := result["tags"].([]interface{})
-	if !ok {
-		existingTags = []interface{}{}
+	// Get existing tags (objects or IDs)
+	var existingTags []interface{}
+	switch tags := result["tags"].(type) {
+	case []interface{}:
+		existingTags = tags
+	case []int:
+		for _, id := range tags {
+			existingTags = append(existingTags, id)
+		}
 	}
 
 	// Convert to IDs and check if managed tag is present
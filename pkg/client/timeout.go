@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// objectTimeouts holds the per-object timeout (--reconcile-timeout-per-object) and the
+// objects that exceeded it
+type objectTimeouts struct {
+	timeout  time.Duration
+	failFast bool
+
+	mu       sync.Mutex
+	timedOut []string
+}
+
+// ObjectTimeoutError is returned by Apply when the requests for one object take longer
+// than the per-object timeout
+type ObjectTimeoutError struct {
+	Endpoint string
+	Lookup   string
+	Timeout  time.Duration
+	Err      error
+}
+
+func (e *ObjectTimeoutError) Error() string {
+	return fmt.Sprintf("%s %s timed out after %s: %v", e.Endpoint, e.Lookup, e.Timeout, e.Err)
+}
+
+func (e *ObjectTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// SetObjectTimeout limits the time Apply may spend on one object, so a pathological object
+// (a huge config context, a slow endpoint) fails fast instead of stalling the run. Apply then
+// returns an *ObjectTimeoutError. Without failFast, callers skip the object with SkipTimeout
+// and carry on; the skipped objects are listed by TimedOutObjects. A zero timeout disables the limit.
+func (c *NetBoxClient) SetObjectTimeout(timeout time.Duration, failFast bool) {
+	c.timeouts = &objectTimeouts{timeout: timeout, failFast: failFast}
+}
+
+// SkipTimeout reports whether err is an object timeout the run should skip instead of
+// failing on (--fail-fast=false), and records the object for TimedOutObjects if so
+func (c *NetBoxClient) SkipTimeout(err error) bool {
+	var timeoutErr *ObjectTimeoutError
+	if c.timeouts == nil || c.timeouts.failFast || !errors.As(err, &timeoutErr) {
+		return false
+	}
+
+	c.logger.Error("Skipping object", timeoutErr)
+	c.timeouts.mu.Lock()
+	c.timeouts.timedOut = append(c.timeouts.timedOut, fmt.Sprintf("%s %s", timeoutErr.Endpoint, timeoutErr.Lookup))
+	c.timeouts.mu.Unlock()
+	return true
+}
+
+// TimedOutObjects returns the objects skipped because they exceeded the per-object timeout
+func (c *NetBoxClient) TimedOutObjects() []string {
+	if c.timeouts == nil {
+		return nil
+	}
+	c.timeouts.mu.Lock()
+	defer c.timeouts.mu.Unlock()
+
+	return append([]string(nil), c.timeouts.timedOut...)
+}
+
+// SetContext cancels all requests of the client with ctx, so an interrupted --watch stops
+// the sync in flight instead of waiting for it to finish
+func (c *NetBoxClient) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// baseContext returns the context set by SetContext, or context.Background()
+func (c *NetBoxClient) baseContext() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// objectContext returns the context for the requests of one object
+func (c *NetBoxClient) objectContext() (context.Context, context.CancelFunc) {
+	if c.timeouts == nil || c.timeouts.timeout <= 0 {
+		return c.baseContext(), func() {}
+	}
+	return context.WithTimeout(c.baseContext(), c.timeouts.timeout)
+}
+
+// objectTimedOut wraps the error of an object that exceeded the per-object timeout
+func (c *NetBoxClient) objectTimedOut(endpoint string, lookup map[string]interface{}, err error) error {
+	return &ObjectTimeoutError{
+		Endpoint: endpoint,
+		Lookup:   c.formatLookup(lookup),
+		Timeout:  c.timeouts.timeout,
+		Err:      err,
+	}
+}
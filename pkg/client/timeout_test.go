@@ -0,0 +1,82 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+)
+
+// TestObjectTimeout tests that an object whose lookup sleeps past the per-object timeout
+// fails fast, or with failFast off is skipped and reported while the next object is applied
+func TestObjectTimeout(t *testing.T) {
+	newClient := func(t *testing.T, failFast bool) (*NetBoxClient, *netboxtest.Server) {
+		srv := netboxtest.NewServer()
+		t.Cleanup(srv.Close)
+		srv.Intercept("GET", "/api/extras/config-contexts/", func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(2 * time.Second):
+			case <-r.Context().Done():
+			}
+		})
+
+		c, err := NewClient(srv.URL, "test-token", false)
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		c.Logger().SetOutput(&bytes.Buffer{}, &bytes.Buffer{})
+		c.SetObjectTimeout(50*time.Millisecond, failFast)
+		return c, srv
+	}
+	slow := map[string]interface{}{"name": "huge-context"}
+
+	t.Run("fail fast", func(t *testing.T) {
+		c, _ := newClient(t, true)
+
+		start := time.Now()
+		_, err := c.Apply("extras", "config-contexts", slow, map[string]interface{}{"name": "huge-context"})
+		var timeoutErr *ObjectTimeoutError
+		if !errors.As(err, &timeoutErr) || !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Apply() error = %v, expected an ObjectTimeoutError", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Apply() took %s, expected to give up after the 50ms timeout", elapsed)
+		}
+		if c.SkipTimeout(err) {
+			t.Error("SkipTimeout() = true, expected fail-fast to keep the error")
+		}
+	})
+
+	t.Run("continue", func(t *testing.T) {
+		c, srv := newClient(t, false)
+
+		// The timeout is an error in both modes, never an object without an ID
+		_, err := c.Apply("extras", "config-contexts", slow, map[string]interface{}{"name": "huge-context"})
+		var timeoutErr *ObjectTimeoutError
+		if !errors.As(err, &timeoutErr) {
+			t.Fatalf("Apply() error = %v, expected an ObjectTimeoutError", err)
+		}
+		if !c.SkipTimeout(err) {
+			t.Fatal("SkipTimeout() = false, expected the timeout to be skipped without fail-fast")
+		}
+		if c.SkipTimeout(errors.New("other")) {
+			t.Error("SkipTimeout() = true for an error that is no timeout")
+		}
+		site := map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"}
+		if _, err := c.Apply("dcim", "sites", map[string]interface{}{"slug": "berlin-dc"}, site); err != nil {
+			t.Fatalf("Apply() after a timeout error = %v", err)
+		}
+
+		if srv.Find("dcim", "sites", "slug", "berlin-dc") == nil {
+			t.Error("Site after the timed-out object was not created")
+		}
+		timedOut := c.TimedOutObjects()
+		if len(timedOut) != 1 || timedOut[0] != "config-contexts name=huge-context" {
+			t.Errorf("TimedOutObjects() = %v, expected the config context", timedOut)
+		}
+	})
+}
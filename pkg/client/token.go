@@ -0,0 +1,107 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ResolveToken returns the NetBox API token.
+// Precedence: NETBOX_TOKEN env > token file (tokenFile, then NETBOX_TOKEN_FILE env) > error.
+// Token files are typically Kubernetes or Docker secret mounts (e.g., /run/secrets/netbox).
+func ResolveToken(tokenFile string) (string, error) {
+	if token := os.Getenv("NETBOX_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	if tokenFile == "" {
+		tokenFile = os.Getenv("NETBOX_TOKEN_FILE")
+	}
+	if tokenFile == "" {
+		return "", fmt.Errorf("no NetBox token: set NETBOX_TOKEN, NETBOX_TOKEN_FILE or --token-file")
+	}
+
+	return (&FileTokenProvider{Path: tokenFile}).Token()
+}
+
+// TokenProvider supplies the NetBox API token from a secret backend
+type TokenProvider interface {
+	Token() (string, error)
+}
+
+// TokenProviderFunc adapts a function to the TokenProvider interface
+type TokenProviderFunc func() (string, error)
+
+// Token returns the token
+func (f TokenProviderFunc) Token() (string, error) {
+	return f()
+}
+
+// Token sources selectable with --token-source
+const (
+	TokenSourceAuto  = "auto"
+	TokenSourceEnv   = "env"
+	TokenSourceFile  = "file"
+	TokenSourceVault = "vault"
+)
+
+// NewTokenProvider returns the provider for a token source. path is the environment
+// variable (env, default NETBOX_TOKEN), the file (file) or the secret path (vault).
+// The auto source uses the ResolveToken precedence with path as the token file.
+func NewTokenProvider(source, path string) (TokenProvider, error) {
+	switch source {
+	case "", TokenSourceAuto:
+		return TokenProviderFunc(func() (string, error) { return ResolveToken(path) }), nil
+	case TokenSourceEnv:
+		return &EnvTokenProvider{Var: path}, nil
+	case TokenSourceFile:
+		if path == "" {
+			return nil, fmt.Errorf("token source file requires a token path")
+		}
+		return &FileTokenProvider{Path: path}, nil
+	case TokenSourceVault:
+		if path == "" {
+			return nil, fmt.Errorf("token source vault requires a token path (e.g., secret/data/netbox)")
+		}
+		return NewVaultTokenProvider(path), nil
+	}
+	return nil, fmt.Errorf("unknown token source %q (valid: %s, %s, %s, %s)", source, TokenSourceAuto, TokenSourceEnv, TokenSourceFile, TokenSourceVault)
+}
+
+// EnvTokenProvider reads the token from an environment variable
+type EnvTokenProvider struct {
+	Var string // Defaults to NETBOX_TOKEN
+}
+
+// Token returns the value of the environment variable
+func (p *EnvTokenProvider) Token() (string, error) {
+	name := p.Var
+	if name == "" {
+		name = "NETBOX_TOKEN"
+	}
+
+	token := strings.TrimSpace(os.Getenv(name))
+	if token == "" {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return token, nil
+}
+
+// FileTokenProvider reads the token from a file, e.g. a Kubernetes or Docker secret mount
+type FileTokenProvider struct {
+	Path string
+}
+
+// Token returns the trimmed file content
+func (p *FileTokenProvider) Token() (string, error) {
+	data, err := os.ReadFile(p.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("token file %s is empty", p.Path)
+	}
+	return token, nil
+}
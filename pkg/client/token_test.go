@@ -0,0 +1,176 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+)
+
+func TestResolveTokenFromFile(t *testing.T) {
+	t.Setenv("NETBOX_TOKEN", "")
+	t.Setenv("NETBOX_TOKEN_FILE", "")
+
+	tokenFile := filepath.Join(t.TempDir(), "netbox")
+	if err := os.WriteFile(tokenFile, []byte("  file-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := ResolveToken(tokenFile)
+	if err != nil {
+		t.Fatalf("ResolveToken() error = %v", err)
+	}
+	if token != "file-secret" {
+		t.Fatalf("ResolveToken() = %q, expected trimmed %q", token, "file-secret")
+	}
+
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+	if _, err := NewClient(srv.URL, token, false); err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	requests := srv.Requests()
+	if len(requests) == 0 {
+		t.Fatal("Client sent no requests")
+	}
+	for _, r := range requests {
+		if got := r.Header.Get("Authorization"); got != "Token file-secret" {
+			t.Errorf("%s %s Authorization = %q", r.Method, r.Path, got)
+		}
+	}
+}
+
+func TestResolveTokenPrecedence(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "netbox")
+	if err := os.WriteFile(tokenFile, []byte("file-secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("env wins over file", func(t *testing.T) {
+		t.Setenv("NETBOX_TOKEN", "env-secret")
+		if token, _ := ResolveToken(tokenFile); token != "env-secret" {
+			t.Errorf("ResolveToken() = %q, expected env-secret", token)
+		}
+	})
+
+	t.Run("NETBOX_TOKEN_FILE", func(t *testing.T) {
+		t.Setenv("NETBOX_TOKEN", "")
+		t.Setenv("NETBOX_TOKEN_FILE", tokenFile)
+		if token, _ := ResolveToken(""); token != "file-secret" {
+			t.Errorf("ResolveToken() = %q, expected file-secret", token)
+		}
+	})
+
+	t.Run("nothing configured", func(t *testing.T) {
+		t.Setenv("NETBOX_TOKEN", "")
+		t.Setenv("NETBOX_TOKEN_FILE", "")
+		if _, err := ResolveToken(""); err == nil {
+			t.Error("ResolveToken() expected error")
+		}
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		t.Setenv("NETBOX_TOKEN", "")
+		empty := filepath.Join(t.TempDir(), "empty")
+		if err := os.WriteFile(empty, []byte(" \n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ResolveToken(empty); err == nil {
+			t.Error("ResolveToken() expected error for empty file")
+		}
+	})
+}
+
+func TestEnvTokenProvider(t *testing.T) {
+	t.Setenv("NETBOX_TOKEN", "default-secret")
+	t.Setenv("CUSTOM_NETBOX_TOKEN", " custom-secret\n")
+
+	provider, err := NewTokenProvider(TokenSourceEnv, "")
+	if err != nil {
+		t.Fatalf("NewTokenProvider() error = %v", err)
+	}
+	if token, err := provider.Token(); err != nil || token != "default-secret" {
+		t.Errorf("Token() = %q, %v, expected default-secret", token, err)
+	}
+
+	provider, _ = NewTokenProvider(TokenSourceEnv, "CUSTOM_NETBOX_TOKEN")
+	if token, err := provider.Token(); err != nil || token != "custom-secret" {
+		t.Errorf("Token() = %q, %v, expected custom-secret", token, err)
+	}
+
+	provider, _ = NewTokenProvider(TokenSourceEnv, "UNSET_NETBOX_TOKEN")
+	if _, err := provider.Token(); err == nil {
+		t.Error("Token() expected error for unset variable")
+	}
+}
+
+func TestFileTokenProvider(t *testing.T) {
+	// The file source ignores NETBOX_TOKEN, unlike auto
+	t.Setenv("NETBOX_TOKEN", "env-secret")
+
+	tokenFile := filepath.Join(t.TempDir(), "netbox")
+	if err := os.WriteFile(tokenFile, []byte("file-secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider, err := NewTokenProvider(TokenSourceFile, tokenFile)
+	if err != nil {
+		t.Fatalf("NewTokenProvider() error = %v", err)
+	}
+	if token, err := provider.Token(); err != nil || token != "file-secret" {
+		t.Errorf("Token() = %q, %v, expected file-secret", token, err)
+	}
+
+	if _, err := NewTokenProvider(TokenSourceFile, ""); err == nil {
+		t.Error("NewTokenProvider() expected error for file source without path")
+	}
+	if _, err := NewTokenProvider("keychain", ""); err == nil {
+		t.Error("NewTokenProvider() expected error for unknown source")
+	}
+}
+
+func TestVaultTokenProvider(t *testing.T) {
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/netbox":
+			_, _ = w.Write([]byte(`{"data": {"data": {"token": "kv2-secret", "api_token": "other"}}}`))
+		case "/v1/kv/netbox":
+			_, _ = w.Write([]byte(`{"data": {"token": "kv1-secret"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer vault.Close()
+
+	t.Setenv("VAULT_ADDR", vault.URL)
+	t.Setenv("VAULT_TOKEN", "root")
+
+	tests := map[string]string{
+		"secret/data/netbox":           "kv2-secret",
+		"secret/data/netbox#api_token": "other",
+		"kv/netbox":                    "kv1-secret",
+	}
+	for path, expected := range tests {
+		provider, err := NewTokenProvider(TokenSourceVault, path)
+		if err != nil {
+			t.Fatalf("NewTokenProvider(%s) error = %v", path, err)
+		}
+		if token, err := provider.Token(); err != nil || token != expected {
+			t.Errorf("Token(%s) = %q, %v, expected %s", path, token, err, expected)
+		}
+	}
+
+	for _, path := range []string{"secret/data/missing", "secret/data/netbox#nope"} {
+		provider, _ := NewTokenProvider(TokenSourceVault, path)
+		if _, err := provider.Token(); err == nil {
+			t.Errorf("Token(%s) expected error", path)
+		}
+	}
+}
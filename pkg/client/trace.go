@@ -0,0 +1,78 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// redacted replaces the API token in traced requests and responses
+const redacted = "<redacted>"
+
+// SetTraceHTTP logs the method, URL and JSON body of every request and the status and
+// body of every response at debug level, for --trace-http. The token is never logged.
+func (c *NetBoxClient) SetTraceHTTP(enabled bool) {
+	if tt, ok := c.httpClient.Transport.(*traceTransport); ok {
+		c.httpClient.Transport = tt.next
+	}
+	if enabled {
+		c.httpClient.Transport = &traceTransport{next: c.httpClient.Transport, logger: c.logger, token: c.token}
+	}
+}
+
+// traceTransport logs requests and responses passing through the wrapped transport
+type traceTransport struct {
+	next   http.RoundTripper
+	logger *utils.Logger
+	token  string
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.logger.Debug("→ %s %s", req.Method, t.redact(req.URL.String()))
+	if req.Header.Get("Authorization") != "" {
+		t.logger.Debug("  Authorization: %s", redacted)
+	}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if len(body) > 0 {
+			t.logger.Debug("  %s", t.redact(string(body)))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.logger.Debug("← %s %s failed: %v", req.Method, t.redact(req.URL.String()), err)
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.logger.Debug("← %s (%s)", resp.Status, time.Since(start).Round(time.Millisecond))
+	if len(body) > 0 {
+		t.logger.Debug("  %s", t.redact(string(body)))
+	}
+	return resp, nil
+}
+
+// redact removes the token from s, should NetBox or a proxy echo it back
+func (t *traceTransport) redact(s string) string {
+	if t.token == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, t.token, redacted)
+}
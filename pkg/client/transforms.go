@@ -0,0 +1,48 @@
+package client
+
+import "github.com/braunma/netbox-gitops-controller/internal/constants"
+
+// NetBox filters reference related objects with an _id suffix (device_type_id=5), while
+// payloads use the bare field (device_type: 5). constants.FieldTransforms lists the pairs;
+// the helpers below convert between the two, so a lookup and its payload stay in step.
+
+// PayloadKey returns the payload field of a lookup key, e.g. "device_type_id" → "device_type".
+// Keys without a transform are returned unchanged.
+func PayloadKey(lookupKey string) string {
+	if field, ok := constants.FieldTransforms[lookupKey]; ok {
+		return field
+	}
+	return lookupKey
+}
+
+// LookupKey returns the lookup key of a payload field, e.g. "device_type" → "device_type_id".
+// Fields without a transform are returned unchanged.
+func LookupKey(payloadField string) string {
+	for key, field := range constants.FieldTransforms {
+		if field == payloadField {
+			return key
+		}
+	}
+	return payloadField
+}
+
+// LookupToPayload returns a copy of a lookup with its keys converted to payload fields
+func LookupToPayload(lookup map[string]interface{}) map[string]interface{} {
+	payload := make(map[string]interface{}, len(lookup))
+	for key, value := range lookup {
+		payload[PayloadKey(key)] = value
+	}
+	return payload
+}
+
+// PayloadToLookup returns a lookup on the given payload fields, with their keys converted
+// to filter keys
+func PayloadToLookup(payload map[string]interface{}, fields ...string) map[string]interface{} {
+	lookup := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if value, ok := payload[field]; ok {
+			lookup[LookupKey(field)] = value
+		}
+	}
+	return lookup
+}
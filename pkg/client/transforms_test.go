@@ -0,0 +1,53 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
+)
+
+func TestFieldTransformKeys(t *testing.T) {
+	tests := []struct {
+		lookupKey, payloadField string
+	}{
+		{"device_type_id", "device_type"},
+		{"module_type_id", "module_type"},
+		{"module_bay_id", "module_bay"},
+		{"name", "name"}, // No transform
+	}
+	for _, tt := range tests {
+		if got := PayloadKey(tt.lookupKey); got != tt.payloadField {
+			t.Errorf("PayloadKey(%q) = %q, expected %q", tt.lookupKey, got, tt.payloadField)
+		}
+		if got := LookupKey(tt.payloadField); got != tt.lookupKey {
+			t.Errorf("LookupKey(%q) = %q, expected %q", tt.payloadField, got, tt.lookupKey)
+		}
+	}
+
+	// Every transform round-trips
+	for lookupKey, field := range constants.FieldTransforms {
+		if got := LookupKey(PayloadKey(lookupKey)); got != lookupKey {
+			t.Errorf("LookupKey(PayloadKey(%q)) = %q, expected %q (%s is the target of two transforms?)", lookupKey, got, lookupKey, field)
+		}
+	}
+}
+
+func TestLookupToPayload(t *testing.T) {
+	lookup := map[string]interface{}{"device_type_id": 5, "name": "eth0"}
+	expected := map[string]interface{}{"device_type": 5, "name": "eth0"}
+	if got := LookupToPayload(lookup); !reflect.DeepEqual(got, expected) {
+		t.Errorf("LookupToPayload() = %v, expected %v", got, expected)
+	}
+	if _, ok := lookup["device_type"]; ok {
+		t.Error("LookupToPayload() modified the lookup")
+	}
+}
+
+func TestPayloadToLookup(t *testing.T) {
+	payload := map[string]interface{}{"device": 3, "module_bay": 7, "module_type": 9, "status": "active"}
+	expected := map[string]interface{}{"device_id": 3, "module_bay_id": 7}
+	if got := PayloadToLookup(payload, "device", "module_bay", "serial"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("PayloadToLookup() = %v, expected %v", got, expected)
+	}
+}
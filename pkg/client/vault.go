@@ -0,0 +1,86 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultTokenProvider reads the token from a HashiCorp Vault KV secret.
+// Path is the API path below /v1/, optionally followed by "#field" (default field "token"),
+// e.g. "secret/data/netbox#api_token" for KV v2 or "kv/netbox" for KV v1.
+type VaultTokenProvider struct {
+	Addr       string // Vault address, defaults to VAULT_ADDR
+	VaultToken string // Vault token, defaults to VAULT_TOKEN
+	Path       string
+	HTTPClient *http.Client
+}
+
+// NewVaultTokenProvider creates a Vault provider configured from VAULT_ADDR and VAULT_TOKEN
+func NewVaultTokenProvider(path string) *VaultTokenProvider {
+	return &VaultTokenProvider{
+		Addr:       os.Getenv("VAULT_ADDR"),
+		VaultToken: os.Getenv("VAULT_TOKEN"),
+		Path:       path,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Token reads the secret and returns its token field
+func (p *VaultTokenProvider) Token() (string, error) {
+	if p.Addr == "" || p.VaultToken == "" {
+		return "", fmt.Errorf("vault token source requires VAULT_ADDR and VAULT_TOKEN")
+	}
+
+	path, field := p.Path, "token"
+	if i := strings.LastIndex(path, "#"); i >= 0 {
+		path, field = path[:i], path[i+1:]
+	}
+
+	req, err := http.NewRequest("GET", strings.TrimSuffix(p.Addr, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.VaultToken)
+
+	httpClient := p.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("vault error %d reading %s", resp.StatusCode, path)
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("failed to parse vault response: %w", err)
+	}
+
+	// KV v2 nests the secret under data.data, KV v1 returns it in data
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
+	token, _ := data[field].(string)
+	if token = strings.TrimSpace(token); token == "" {
+		return "", fmt.Errorf("vault secret %s has no field %q", path, field)
+	}
+	return token, nil
+}
@@ -0,0 +1,136 @@
+package loader
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Archive magic bytes: gzip streams start with gzipMagic, tar archives hold tarMagic at offset 257
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	tarMagic  = []byte("ustar")
+)
+
+const tarMagicOffset = 257
+
+// IsArchive reports whether path is a tar or gzipped tar archive rather than a directory,
+// judged by its extension (.tar, .tar.gz, .tgz) or, failing that, its magic bytes
+func IsArchive(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	for _, ext := range []string{".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(strings.ToLower(path), ext) {
+			return true
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	header := make([]byte, tarMagicOffset+len(tarMagic))
+	n, _ := io.ReadFull(f, header)
+	header = header[:n]
+	return bytes.HasPrefix(header, gzipMagic) ||
+		(n == len(header) && n > tarMagicOffset && bytes.Equal(header[tarMagicOffset:], tarMagic))
+}
+
+// ExtractArchive extracts a tar or gzipped tar archive into a temporary directory and returns
+// the directory holding the data. An archive whose content is wrapped in a single top-level
+// directory (e.g. data/definitions/...) is unwrapped. The returned cleanup removes the directory.
+func ExtractArchive(path string) (dir string, cleanup func(), err error) {
+	tmp, err := os.MkdirTemp("", "netbox-gitops-archive-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create extraction directory: %w", err)
+	}
+	cleanup = func() { os.RemoveAll(tmp) }
+
+	if err := extractTar(path, tmp); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to extract %s: %w", path, err)
+	}
+
+	dir = tmp
+	entries, err := os.ReadDir(tmp)
+	if err == nil && len(entries) == 1 && entries[0].IsDir() {
+		if _, err := os.Stat(filepath.Join(tmp, "definitions")); os.IsNotExist(err) {
+			dir = filepath.Join(tmp, entries[0].Name())
+		}
+	}
+	return dir, cleanup, nil
+}
+
+// extractTar writes the directories and regular files of the archive at path into dest.
+// Links and other special files are skipped.
+func extractTar(path, dest string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = bufio.NewReader(f)
+	if magic, _ := r.(*bufio.Reader).Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// Entries must stay inside dest, e.g. no "../" or absolute names
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("archive entry %q points outside the archive", header.Name)
+		}
+		target := filepath.Join(dest, name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeArchiveFile(target, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeArchiveFile(target string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
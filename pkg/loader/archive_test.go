@@ -0,0 +1,140 @@
+package loader
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// writeTarGz packages the files below src into a gzipped tar at path, under prefix
+func writeTarGz(t *testing.T, path, src, prefix string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(prefix, rel))
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		in, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		_, err = io.Copy(tw, in)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestExtractArchiveLoadsDefinitions tests loading the example definitions from a tar.gz bundle
+func TestExtractArchiveLoadsDefinitions(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	writeTarGz(t, archive, "../../example/definitions", "data/definitions")
+	if !IsArchive(archive) {
+		t.Fatalf("IsArchive(%s) = false, expected true", archive)
+	}
+
+	dir, cleanup, err := ExtractArchive(archive)
+	if err != nil {
+		t.Fatalf("ExtractArchive() error = %v", err)
+	}
+
+	logger := utils.NewLogger(false)
+	want, err := NewDataLoader("../../example", logger).LoadSites("definitions/sites")
+	if err != nil {
+		t.Fatalf("LoadSites() from example error = %v", err)
+	}
+	got, err := NewDataLoader(dir, logger).LoadSites("definitions/sites")
+	if err != nil {
+		t.Fatalf("LoadSites() from archive error = %v", err)
+	}
+	if len(got) == 0 || len(got) != len(want) {
+		t.Errorf("Loaded %d sites from the archive, expected the %d example sites", len(got), len(want))
+	}
+
+	cleanup()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Extraction directory %s was not removed", dir)
+	}
+}
+
+// TestIsArchive tests detecting archives by extension and magic bytes, but not directories or YAML
+func TestIsArchive(t *testing.T) {
+	tmp := t.TempDir()
+	writeTarGz(t, filepath.Join(tmp, "artifact"), "../../example/definitions/sites", "definitions/sites")
+	writeFile(t, tmp, "sites.yaml", "- name: Berlin DC\n")
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{filepath.Join(tmp, "artifact"), true},
+		{filepath.Join(tmp, "sites.yaml"), false},
+		{tmp, false},
+		{filepath.Join(tmp, "missing.tar.gz"), false},
+	}
+	for _, tt := range tests {
+		if got := IsArchive(tt.path); got != tt.want {
+			t.Errorf("IsArchive(%s) = %v, expected %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestExtractArchiveRejectsEscapingPaths tests that entries outside the archive are refused
+func TestExtractArchiveRejectsEscapingPaths(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "evil.tar")
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(f)
+	content := "- name: Evil\n"
+	if err := tw.WriteHeader(&tar.Header{Name: "../evil.yaml", Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	tw.Close()
+	f.Close()
+
+	if _, _, err := ExtractArchive(archive); err == nil || !strings.Contains(err.Error(), "outside the archive") {
+		t.Errorf("ExtractArchive() error = %v, expected the escaping entry to be refused", err)
+	}
+}
@@ -0,0 +1,146 @@
+package loader
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// resourceLoader loads a resource type from its folders, limited to the sites of a filter
+type resourceLoader struct {
+	load  func(dl *DataLoader, folders []string, siteFilter SiteFilter) (interface{}, error)
+	model reflect.Type // e.g. models.DeviceConfig, whose YAML fields --set may override
+}
+
+// resourceLoaders is the one place that knows which loader and site filter each resource type
+// uses. The sync, the desired state of --prune and --dump-plan-file all load through it.
+var resourceLoaders = map[string]resourceLoader{
+	"tags":                      loaderOf((*DataLoader).LoadTags, nil),
+	"custom_field_choice_sets":  loaderOf((*DataLoader).LoadCustomFieldChoiceSets, nil),
+	"custom_fields":             loaderOf((*DataLoader).LoadCustomFields, nil),
+	"contact_groups":            loaderOf((*DataLoader).LoadContactGroups, nil),
+	"contact_roles":             loaderOf((*DataLoader).LoadContactRoles, nil),
+	"tenant_groups":             loaderOf((*DataLoader).LoadTenantGroups, nil),
+	"tenants":                   loaderOf((*DataLoader).LoadTenants, nil),
+	"contacts":                  loaderOf((*DataLoader).LoadContacts, nil),
+	"role_groups":               loaderOf((*DataLoader).LoadRoleGroups, nil),
+	"roles":                     loaderOf((*DataLoader).LoadRoles, nil),
+	"inventory_item_roles":      loaderOf((*DataLoader).LoadInventoryItemRoles, nil),
+	"sites":                     loaderOf((*DataLoader).LoadSites, SiteFilter.Sites),
+	"racks":                     loaderOf((*DataLoader).LoadRacks, SiteFilter.Racks),
+	"config_contexts":           loaderOf((*DataLoader).LoadConfigContexts, nil),
+	"webhooks":                  loaderOf((*DataLoader).LoadWebhooks, nil),
+	"export_templates":          loaderOf((*DataLoader).LoadExportTemplates, nil),
+	"config_templates":          loaderOf((*DataLoader).LoadConfigTemplates, nil),
+	"providers":                 loaderOf((*DataLoader).LoadProviders, nil),
+	"circuit_types":             loaderOf((*DataLoader).LoadCircuitTypes, nil),
+	"vrfs":                      loaderOf((*DataLoader).LoadVRFs, nil),
+	"ipam_roles":                loaderOf((*DataLoader).LoadIPAMRoles, nil),
+	"vlan_groups":               loaderOf((*DataLoader).LoadVLANGroups, SiteFilter.VLANGroups),
+	"vlans":                     loaderOf((*DataLoader).LoadVLANs, SiteFilter.VLANs),
+	"prefixes":                  loaderOf((*DataLoader).LoadPrefixes, SiteFilter.Prefixes),
+	"vlan_translation_policies": loaderOf((*DataLoader).LoadVLANTranslationPolicies, nil),
+	"module_types":              loaderOf((*DataLoader).LoadModuleTypes, nil),
+	"device_types":              loaderOf((*DataLoader).LoadDeviceTypes, nil),
+	"virtual_chassis":           loaderOf((*DataLoader).LoadVirtualChassis, nil),
+	"fhrp_groups":               loaderOf((*DataLoader).LoadFHRPGroups, nil),
+	"circuits":                  loaderOf((*DataLoader).LoadCircuits, nil),
+	"devices":                   loaderOf((*DataLoader).LoadDevices, SiteFilter.Devices),
+}
+
+// loaderOf adapts a folder loader to load all folders of a resource type, with an optional site filter
+func loaderOf[T any](load func(dl *DataLoader, folder string) ([]T, error), filter func(SiteFilter, []T) []T) resourceLoader {
+	loadAll := func(dl *DataLoader, folders []string, siteFilter SiteFilter) (interface{}, error) {
+		var items []T
+		for _, folder := range folders {
+			loaded, err := load(dl, folder)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, loaded...)
+		}
+		if filter != nil {
+			items = filter(siteFilter, items)
+		}
+		if items == nil {
+			items = []T{} // an empty list rather than null in a plan
+		}
+		return items, nil
+	}
+	return resourceLoader{load: loadAll, model: reflect.TypeOf((*T)(nil)).Elem().Elem()}
+}
+
+// Definitions loads the definitions of a run through the loader registry, once per resource
+// type, and keeps them for later steps (e.g. the devices of virtual chassis, the desired state
+// of --prune). Steps run concurrently, so access is guarded.
+type Definitions struct {
+	loader     *DataLoader
+	layout     Layout
+	siteFilter SiteFilter
+
+	mu     sync.Mutex
+	loaded map[string]interface{}
+}
+
+// NewDefinitions creates the definitions of a run with the given layout and site filter
+func NewDefinitions(dl *DataLoader, layout Layout, siteFilter SiteFilter) *Definitions {
+	return &Definitions{
+		loader:     dl,
+		layout:     layout,
+		siteFilter: siteFilter,
+		loaded:     make(map[string]interface{}),
+	}
+}
+
+// HasLoader reports whether a resource type (or reconcile step) has definitions to load
+func HasLoader(resource string) bool {
+	_, ok := resourceLoaders[resource]
+	return ok
+}
+
+// load returns the definitions of a resource type, loading them on first use
+func (d *Definitions) load(resource string) (interface{}, error) {
+	d.mu.Lock()
+	items, ok := d.loaded[resource]
+	d.mu.Unlock()
+	if ok {
+		return items, nil
+	}
+
+	entry, ok := resourceLoaders[resource]
+	if !ok {
+		return nil, fmt.Errorf("no loader for resource type %s", resource)
+	}
+	items, err := entry.load(d.loader, d.layout.Folders(resource), d.siteFilter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", resource, err)
+	}
+
+	d.mu.Lock()
+	d.loaded[resource] = items
+	d.mu.Unlock()
+	return items, nil
+}
+
+// Load returns the definitions of a resource type, e.g. Load[*models.Site](definitions, "sites")
+func Load[T any](d *Definitions, resource string) ([]T, error) {
+	items, err := d.load(resource)
+	if err != nil {
+		return nil, err
+	}
+	typed, ok := items.([]T)
+	if !ok {
+		return nil, fmt.Errorf("resource type %s holds %T, not %T", resource, items, typed)
+	}
+	return typed, nil
+}
+
+// Loaded returns the definitions of a resource type loaded so far, nil if it was not loaded
+// (e.g. its step is disabled or failed)
+func Loaded[T any](d *Definitions, resource string) []T {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	typed, _ := d.loaded[resource].([]T)
+	return typed
+}
@@ -0,0 +1,48 @@
+package loader
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// RepoTokenEnv is the environment variable holding a token for HTTPS clones of --repo
+const RepoTokenEnv = "GITOPS_REPO_TOKEN"
+
+// CloneRepo shallow-clones ref (a branch or tag; empty for the default branch) of a git
+// repository into a temporary directory. The returned cleanup removes the directory.
+// A non-empty token is sent as HTTP basic auth, which works for GitHub, GitLab and Gitea tokens.
+func CloneRepo(url, ref, token string) (dir string, cleanup func(), err error) {
+	dir, err = os.MkdirTemp("", "netbox-gitops-repo-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create clone directory: %w", err)
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, url, dir)
+
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if token != "" {
+		// Passed through the environment so the token never shows up in the process list
+		auth := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+		cmd.Env = append(cmd.Env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+auth,
+		)
+	}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("git clone of %s failed: %w: %s", url, err, strings.TrimSpace(string(output)))
+	}
+
+	return dir, cleanup, nil
+}
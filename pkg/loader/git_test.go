@@ -0,0 +1,59 @@
+package loader
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// git runs a git command in dir, with a fixed identity for commits
+func git(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v: %s", args, err, output)
+	}
+}
+
+// TestCloneRepoLoadsDefinitions tests loading definitions from a shallow clone of a branch
+func TestCloneRepoLoadsDefinitions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	work := t.TempDir()
+	bare := t.TempDir()
+	git(t, bare, "init", "--quiet", "--bare")
+	git(t, work, "init", "--quiet")
+	writeFile(t, work, "definitions/sites/sites.yaml", "- name: Berlin DC\n  slug: berlin-dc\n")
+	git(t, work, "add", ".")
+	git(t, work, "commit", "--quiet", "-m", "Add sites")
+	git(t, work, "push", "--quiet", bare, "HEAD:refs/heads/production")
+
+	dir, cleanup, err := CloneRepo("file://"+bare, "production", "")
+	if err != nil {
+		t.Fatalf("CloneRepo() error = %v", err)
+	}
+
+	sites, err := NewDataLoader(dir, utils.NewLogger(false)).LoadSites("definitions/sites")
+	if err != nil {
+		t.Fatalf("LoadSites() error = %v", err)
+	}
+	if len(sites) != 1 || sites[0].Slug != "berlin-dc" {
+		t.Errorf("LoadSites() = %+v, expected berlin-dc from the clone", sites)
+	}
+
+	cleanup()
+	if _, err := os.Stat(filepath.Join(dir, "definitions")); !os.IsNotExist(err) {
+		t.Errorf("Clone directory %s was not removed", dir)
+	}
+
+	if _, _, err := CloneRepo("file://"+bare, "does-not-exist", ""); err == nil {
+		t.Error("CloneRepo() expected error for unknown ref")
+	}
+}
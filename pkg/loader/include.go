@@ -0,0 +1,90 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// IncludeTag includes another YAML file in place of the tagged value, so fragments
+// (e.g. a server's standard interfaces) can be shared across files:
+//
+//	interfaces: !include ../../fragments/server-nics.yaml
+//
+// An included sequence inside a sequence is spliced into it, so a fragment can be
+// combined with further items. Paths are relative to the including file.
+const IncludeTag = "!include"
+
+// parseYAMLFile parses a YAML file and resolves its includes
+func parseYAMLFile(path string) (*yaml.Node, error) {
+	return parseIncluded(path, nil)
+}
+
+// parseIncluded parses a YAML file, resolving includes; stack holds the absolute paths
+// of the files including it, to detect include cycles
+func parseIncluded(path string, stack []string) (*yaml.Node, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, including := range stack {
+		if including == absPath {
+			return nil, fmt.Errorf("include cycle: %s", strings.Join(append(stack, absPath), " -> "))
+		}
+	}
+
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return &doc, nil
+	}
+
+	if err := resolveIncludes(&doc, filepath.Dir(absPath), append(stack, absPath)); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// resolveIncludes replaces every !include node below node with the included file's content
+func resolveIncludes(node *yaml.Node, dir string, stack []string) error {
+	var content []*yaml.Node
+	for _, child := range node.Content {
+		if child.Tag != IncludeTag {
+			if err := resolveIncludes(child, dir, stack); err != nil {
+				return err
+			}
+			content = append(content, child)
+			continue
+		}
+
+		if child.Kind != yaml.ScalarNode || child.Value == "" {
+			return fmt.Errorf("line %d: %s expects a file path", child.Line, IncludeTag)
+		}
+		included, err := parseIncluded(filepath.Join(dir, child.Value), stack)
+		if err != nil {
+			return fmt.Errorf("line %d: failed to include %s: %w", child.Line, child.Value, err)
+		}
+		if len(included.Content) == 0 {
+			return fmt.Errorf("line %d: included file %s is empty", child.Line, child.Value)
+		}
+
+		root := included.Content[0]
+		if node.Kind == yaml.SequenceNode && root.Kind == yaml.SequenceNode {
+			content = append(content, root.Content...)
+		} else {
+			content = append(content, root)
+		}
+	}
+	node.Content = content
+	return nil
+}
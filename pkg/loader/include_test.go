@@ -0,0 +1,97 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// writeFiles writes files (relative path → content) below dir
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestLoadDevicesWithInclude tests that devices share an interface set from a fragment file
+func TestLoadDevicesWithInclude(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"fragments/server-nics.yaml": `- name: eth0
+  type: 25gbase-x-sfp28
+- name: eth1
+  type: 25gbase-x-sfp28
+`,
+		"inventory/hardware/active/servers.yaml": `- name: srv-01
+  site_slug: berlin-dc
+  role_slug: server
+  device_type_slug: generic-server
+  interfaces: !include ../../../fragments/server-nics.yaml
+- name: srv-02
+  site_slug: berlin-dc
+  role_slug: server
+  device_type_slug: generic-server
+  interfaces:
+    - name: idrac
+      type: 1000base-t
+      mgmt_only: true
+    - !include ../../../fragments/server-nics.yaml
+`,
+	})
+
+	dl := NewDataLoader(dir, utils.NewLogger(false))
+	devices, err := dl.LoadDevices("inventory/hardware/active")
+	if err != nil {
+		t.Fatalf("LoadDevices() error = %v", err)
+	}
+	if len(devices) != 2 {
+		t.Fatalf("LoadDevices() = %d devices, expected 2", len(devices))
+	}
+
+	var names []string
+	for _, iface := range devices[0].Interfaces {
+		names = append(names, iface.Name)
+	}
+	if got := strings.Join(names, ","); got != "eth0,eth1" {
+		t.Errorf("srv-01 interfaces = %s, expected eth0,eth1", got)
+	}
+
+	names = nil
+	for _, iface := range devices[1].Interfaces {
+		names = append(names, iface.Name)
+	}
+	if got := strings.Join(names, ","); got != "idrac,eth0,eth1" {
+		t.Errorf("srv-02 interfaces = %s, expected the fragment spliced after idrac", got)
+	}
+	if devices[1].Interfaces[1].Type != "25gbase-x-sfp28" {
+		t.Errorf("srv-02 eth0 type = %q, expected 25gbase-x-sfp28", devices[1].Interfaces[1].Type)
+	}
+}
+
+// TestLoadIncludeCycle tests that files including each other are rejected
+func TestLoadIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"fragments/a.yaml": "- !include b.yaml\n",
+		"fragments/b.yaml": "- !include a.yaml\n",
+		"devices/servers.yaml": `- name: srv-01
+  interfaces: !include ../fragments/a.yaml
+`,
+	})
+
+	dl := NewDataLoader(dir, utils.NewLogger(false))
+	_, err := dl.LoadDevices("devices")
+	if err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("LoadDevices() error = %v, expected include cycle error", err)
+	}
+}
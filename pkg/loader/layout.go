@@ -0,0 +1,97 @@
+package loader
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LayoutFile is the layout file auto-discovered at the root of the data directory
+const LayoutFile = "layout.yaml"
+
+// Layout maps resource types to the folders (relative to the data directory) they are loaded from
+type Layout map[string][]string
+
+// DefaultLayout returns the standard definitions/ and inventory/ layout
+func DefaultLayout() Layout {
+	return Layout{
+		"tags":                      {"definitions/extras"},
+		"custom_field_choice_sets":  {"definitions/custom_field_choice_sets"},
+		"custom_fields":             {"definitions/custom_fields"},
+		"contact_groups":            {"definitions/contact_groups"},
+		"contact_roles":             {"definitions/contact_roles"},
+		"tenant_groups":             {"definitions/tenant_groups"},
+		"tenants":                   {"definitions/tenants"},
+		"contacts":                  {"definitions/contacts"},
+		"role_groups":               {"definitions/role_groups"},
+		"roles":                     {"definitions/roles"},
+		"inventory_item_roles":      {"definitions/inventory_item_roles"},
+		"sites":                     {"definitions/sites"},
+		"racks":                     {"definitions/racks"},
+		"config_contexts":           {"definitions/config_contexts"},
+		"webhooks":                  {"definitions/webhooks"},
+		"export_templates":          {"definitions/export_templates"},
+		"config_templates":          {"definitions/config_templates"},
+		"providers":                 {"definitions/providers"},
+		"circuit_types":             {"definitions/circuit_types"},
+		"vrfs":                      {"definitions/vrfs"},
+		"ipam_roles":                {"definitions/ipam_roles"},
+		"vlan_groups":               {"definitions/vlan_groups"},
+		"vlans":                     {"definitions/vlans"},
+		"prefixes":                  {"definitions/prefixes"},
+		"vlan_translation_policies": {"definitions/vlan_translation_policies"},
+		"module_types":              {"definitions/module_types"},
+		"device_types":              {"definitions/device_types"},
+		"virtual_chassis":           {"definitions/virtual_chassis"},
+		"fhrp_groups":               {"definitions/fhrp_groups"},
+		"circuits":                  {"definitions/circuits"},
+		"devices":                   {"inventory/hardware/active", "inventory/hardware/passive"},
+	}
+}
+
+// LoadLayout reads a YAML layout file on top of the default layout.
+// Resource types the file omits keep their default folders; unknown resource types are an error.
+//
+//	devices:
+//	  - hardware/servers
+//	  - hardware/network
+//	sites: [global/sites]
+func LoadLayout(path string) (Layout, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read layout file: %w", err)
+	}
+
+	var overrides map[string][]string
+	if err := yaml.Unmarshal(content, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse layout file %s: %w", path, err)
+	}
+
+	layout := DefaultLayout()
+	for resource, folders := range overrides {
+		if _, ok := layout[resource]; !ok {
+			return nil, fmt.Errorf("unknown resource type %q in layout file %s (valid: %s)", resource, path, strings.Join(layout.Resources(), ", "))
+		}
+		layout[resource] = folders
+	}
+
+	return layout, nil
+}
+
+// Folders returns the folders a resource type is loaded from
+func (l Layout) Folders(resource string) []string {
+	return l[resource]
+}
+
+// Resources returns the resource types of the layout, sorted
+func (l Layout) Resources() []string {
+	resources := make([]string, 0, len(l))
+	for resource := range l {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	return resources
+}
@@ -0,0 +1,69 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// TestLoadLayoutCustomDeviceFolder tests loading devices from a folder set in a layout file
+func TestLoadLayoutCustomDeviceFolder(t *testing.T) {
+	dir := t.TempDir()
+
+	layoutYAML := "devices:\n  - hardware/servers\n"
+	if err := os.WriteFile(filepath.Join(dir, LayoutFile), []byte(layoutYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	serverDir := filepath.Join(dir, "hardware", "servers")
+	if err := os.MkdirAll(serverDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	deviceYAML := `- name: srv-01
+  site_slug: berlin-dc
+  role_slug: server
+  device_type_slug: generic-server
+`
+	if err := os.WriteFile(filepath.Join(serverDir, "servers.yaml"), []byte(deviceYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	layout, err := LoadLayout(filepath.Join(dir, LayoutFile))
+	if err != nil {
+		t.Fatalf("LoadLayout() error = %v", err)
+	}
+
+	folders := layout.Folders("devices")
+	if !reflect.DeepEqual(folders, []string{"hardware/servers"}) {
+		t.Fatalf("Folders(devices) = %v, expected [hardware/servers]", folders)
+	}
+
+	// Resource types not in the file keep their defaults
+	if got := layout.Folders("sites"); !reflect.DeepEqual(got, DefaultLayout().Folders("sites")) {
+		t.Errorf("Folders(sites) = %v, expected default %v", got, DefaultLayout().Folders("sites"))
+	}
+
+	dl := NewDataLoader(dir, utils.NewLogger(false))
+	devices, err := dl.LoadDevices(folders[0])
+	if err != nil {
+		t.Fatalf("LoadDevices() error = %v", err)
+	}
+	if len(devices) != 1 || devices[0].Name != "srv-01" {
+		t.Errorf("LoadDevices() = %d devices, expected srv-01", len(devices))
+	}
+}
+
+// TestLoadLayoutRejectsUnknownResource tests that typos in the layout file are reported
+func TestLoadLayoutRejectsUnknownResource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LayoutFile)
+	if err := os.WriteFile(path, []byte("device: [hardware]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadLayout(path); err == nil {
+		t.Error("LoadLayout() expected error for unknown resource type")
+	}
+}
@@ -2,9 +2,12 @@ package loader
 
 import (
 	"fmt"
-	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 
@@ -16,6 +19,9 @@ import (
 type DataLoader struct {
 	basePath string
 	logger   *utils.Logger
+	excludes []string
+	// overrides are --set field overrides, applied as objects are loaded
+	overrides *overrides
 }
 
 // NewDataLoader creates a new data loader
@@ -26,6 +32,13 @@ func NewDataLoader(basePath string, logger *utils.Logger) *DataLoader {
 	}
 }
 
+// SetExcludes sets glob patterns for files and directories to skip while loading.
+// Patterns are matched against paths relative to the base path; "**" matches any
+// number of directories, and a pattern without "/" matches a name at any depth.
+func (dl *DataLoader) SetExcludes(patterns []string) {
+	dl.excludes = patterns
+}
+
 // LoadSites loads site definitions from a folder
 func (dl *DataLoader) LoadSites(folder string) ([]*models.Site, error) {
 	var sites []*models.Site
@@ -48,6 +61,17 @@ func (dl *DataLoader) LoadRacks(folder string) ([]*models.Rack, error) {
 	return racks, nil
 }
 
+// LoadRoleGroups loads role group definitions from a folder
+func (dl *DataLoader) LoadRoleGroups(folder string) ([]*models.RoleGroup, error) {
+	var groups []*models.RoleGroup
+	err := dl.loadFromFolder(folder, &groups)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d role groups from %s", len(groups), folder)
+	return groups, nil
+}
+
 // LoadRoles loads role definitions from a folder
 func (dl *DataLoader) LoadRoles(folder string) ([]*models.Role, error) {
 	var roles []*models.Role
@@ -59,6 +83,17 @@ func (dl *DataLoader) LoadRoles(folder string) ([]*models.Role, error) {
 	return roles, nil
 }
 
+// LoadInventoryItemRoles loads inventory item role definitions from a folder
+func (dl *DataLoader) LoadInventoryItemRoles(folder string) ([]*models.InventoryItemRole, error) {
+	var roles []*models.InventoryItemRole
+	err := dl.loadFromFolder(folder, &roles)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d inventory item roles from %s", len(roles), folder)
+	return roles, nil
+}
+
 // LoadTags loads tag definitions from a folder
 func (dl *DataLoader) LoadTags(folder string) ([]*models.Tag, error) {
 	var tags []*models.Tag
@@ -70,6 +105,160 @@ func (dl *DataLoader) LoadTags(folder string) ([]*models.Tag, error) {
 	return tags, nil
 }
 
+// LoadConfigContexts loads config context definitions from a folder
+func (dl *DataLoader) LoadConfigContexts(folder string) ([]*models.ConfigContext, error) {
+	var contexts []*models.ConfigContext
+	err := dl.loadFromFolder(folder, &contexts)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d config contexts from %s", len(contexts), folder)
+	return contexts, nil
+}
+
+// LoadWebhooks loads webhook definitions from a folder
+func (dl *DataLoader) LoadWebhooks(folder string) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	err := dl.loadFromFolder(folder, &webhooks)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d webhooks from %s", len(webhooks), folder)
+	return webhooks, nil
+}
+
+// LoadCustomFieldChoiceSets loads custom field choice set definitions from a folder
+func (dl *DataLoader) LoadCustomFieldChoiceSets(folder string) ([]*models.CustomFieldChoiceSet, error) {
+	var choiceSets []*models.CustomFieldChoiceSet
+	err := dl.loadFromFolder(folder, &choiceSets)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d custom field choice sets from %s", len(choiceSets), folder)
+	return choiceSets, nil
+}
+
+// LoadCustomFields loads custom field definitions from a folder
+func (dl *DataLoader) LoadCustomFields(folder string) ([]*models.CustomFieldDef, error) {
+	var fields []*models.CustomFieldDef
+	err := dl.loadFromFolder(folder, &fields)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d custom fields from %s", len(fields), folder)
+	return fields, nil
+}
+
+// LoadExportTemplates loads export template definitions from a folder
+func (dl *DataLoader) LoadExportTemplates(folder string) ([]*models.ExportTemplate, error) {
+	var templates []*models.ExportTemplate
+	err := dl.loadFromFolder(folder, &templates)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d export templates from %s", len(templates), folder)
+	return templates, nil
+}
+
+// LoadConfigTemplates loads config template definitions from a folder
+func (dl *DataLoader) LoadConfigTemplates(folder string) ([]*models.ConfigTemplate, error) {
+	var templates []*models.ConfigTemplate
+	err := dl.loadFromFolder(folder, &templates)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d config templates from %s", len(templates), folder)
+	return templates, nil
+}
+
+// LoadContactGroups loads contact group definitions from a folder
+func (dl *DataLoader) LoadContactGroups(folder string) ([]*models.ContactGroup, error) {
+	var groups []*models.ContactGroup
+	err := dl.loadFromFolder(folder, &groups)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d contact groups from %s", len(groups), folder)
+	return groups, nil
+}
+
+// LoadTenantGroups loads tenant group definitions from a folder
+func (dl *DataLoader) LoadTenantGroups(folder string) ([]*models.TenantGroup, error) {
+	var groups []*models.TenantGroup
+	err := dl.loadFromFolder(folder, &groups)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d tenant groups from %s", len(groups), folder)
+	return groups, nil
+}
+
+// LoadTenants loads tenant definitions from a folder
+func (dl *DataLoader) LoadTenants(folder string) ([]*models.Tenant, error) {
+	var tenants []*models.Tenant
+	err := dl.loadFromFolder(folder, &tenants)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d tenants from %s", len(tenants), folder)
+	return tenants, nil
+}
+
+// LoadContactRoles loads contact role definitions from a folder
+func (dl *DataLoader) LoadContactRoles(folder string) ([]*models.ContactRole, error) {
+	var roles []*models.ContactRole
+	err := dl.loadFromFolder(folder, &roles)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d contact roles from %s", len(roles), folder)
+	return roles, nil
+}
+
+// LoadContacts loads contact definitions from a folder
+func (dl *DataLoader) LoadContacts(folder string) ([]*models.Contact, error) {
+	var contacts []*models.Contact
+	err := dl.loadFromFolder(folder, &contacts)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d contacts from %s", len(contacts), folder)
+	return contacts, nil
+}
+
+// LoadProviders loads circuit provider definitions from a folder
+func (dl *DataLoader) LoadProviders(folder string) ([]*models.Provider, error) {
+	var providers []*models.Provider
+	err := dl.loadFromFolder(folder, &providers)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d providers from %s", len(providers), folder)
+	return providers, nil
+}
+
+// LoadCircuitTypes loads circuit type definitions from a folder
+func (dl *DataLoader) LoadCircuitTypes(folder string) ([]*models.CircuitType, error) {
+	var types []*models.CircuitType
+	err := dl.loadFromFolder(folder, &types)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d circuit types from %s", len(types), folder)
+	return types, nil
+}
+
+// LoadCircuits loads circuit definitions from a folder
+func (dl *DataLoader) LoadCircuits(folder string) ([]*models.Circuit, error) {
+	var circuits []*models.Circuit
+	err := dl.loadFromFolder(folder, &circuits)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d circuits from %s", len(circuits), folder)
+	return circuits, nil
+}
+
 // LoadVLANs loads VLAN definitions from a folder
 func (dl *DataLoader) LoadVLANs(folder string) ([]*models.VLAN, error) {
 	var vlans []*models.VLAN
@@ -92,6 +281,17 @@ func (dl *DataLoader) LoadVLANGroups(folder string) ([]*models.VLANGroup, error)
 	return groups, nil
 }
 
+// LoadIPAMRoles loads IPAM role definitions from a folder
+func (dl *DataLoader) LoadIPAMRoles(folder string) ([]*models.IPAMRole, error) {
+	var roles []*models.IPAMRole
+	err := dl.loadFromFolder(folder, &roles)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d IPAM roles from %s", len(roles), folder)
+	return roles, nil
+}
+
 // LoadVRFs loads VRF definitions from a folder
 func (dl *DataLoader) LoadVRFs(folder string) ([]*models.VRF, error) {
 	var vrfs []*models.VRF
@@ -147,6 +347,39 @@ func (dl *DataLoader) LoadDevices(folder string) ([]*models.DeviceConfig, error)
 	return devices, nil
 }
 
+// LoadVirtualChassis loads virtual chassis definitions from a folder
+func (dl *DataLoader) LoadVirtualChassis(folder string) ([]*models.VirtualChassis, error) {
+	var chassis []*models.VirtualChassis
+	err := dl.loadFromFolder(folder, &chassis)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d virtual chassis from %s", len(chassis), folder)
+	return chassis, nil
+}
+
+// LoadVLANTranslationPolicies loads VLAN translation policy definitions from a folder
+func (dl *DataLoader) LoadVLANTranslationPolicies(folder string) ([]*models.VLANTranslationPolicy, error) {
+	var policies []*models.VLANTranslationPolicy
+	err := dl.loadFromFolder(folder, &policies)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d VLAN translation policies from %s", len(policies), folder)
+	return policies, nil
+}
+
+// LoadFHRPGroups loads FHRP group definitions from a folder
+func (dl *DataLoader) LoadFHRPGroups(folder string) ([]*models.FHRPGroup, error) {
+	var groups []*models.FHRPGroup
+	err := dl.loadFromFolder(folder, &groups)
+	if err != nil {
+		return nil, err
+	}
+	dl.logger.Debug("Loaded %d FHRP groups from %s", len(groups), folder)
+	return groups, nil
+}
+
 // loadFromFolder loads YAML files from a folder and unmarshals into the target
 func (dl *DataLoader) loadFromFolder(folder string, target interface{}) error {
 	targetDir := filepath.Join(dl.basePath, folder)
@@ -178,24 +411,43 @@ func (dl *DataLoader) loadFromFolder(folder string, target interface{}) error {
 	return nil
 }
 
-// loadFile loads a single YAML file and appends items to target
-// Matches Python loader.py line 56: results.extend([model(**item) for item in data])
-func (dl *DataLoader) loadFile(path string, target interface{}) error {
-	file, err := os.Open(path)
+// CheckFolder parses every YAML file of a folder (with !include fragments) without loading
+// the objects, and returns the number of files and an error for each file that doesn't parse
+func (dl *DataLoader) CheckFolder(folder string) (int, []error) {
+	yamlFiles, err := dl.findYAMLFiles(filepath.Join(dl.basePath, folder))
 	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
+		return 0, []error{fmt.Errorf("failed to find YAML files in %s: %w", folder, err)}
+	}
+
+	var errs []error
+	for _, file := range yamlFiles {
+		if _, err := parseYAMLFile(file); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file, err))
+		}
 	}
-	defer file.Close()
+	return len(yamlFiles), errs
+}
 
-	content, err := io.ReadAll(file)
+// loadFile loads a single YAML file and appends items to target
+// Matches Python loader.py line 56: results.extend([model(**item) for item in data])
+func (dl *DataLoader) loadFile(path string, target interface{}) error {
+	// Parse with !include fragments spliced in
+	doc, err := parseYAMLFile(path)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %w", err)
+		return err
 	}
 
 	// Unmarshal YAML - it should be a list
 	var items []map[string]interface{}
-	if err := yaml.Unmarshal(content, &items); err != nil {
-		return fmt.Errorf("failed to unmarshal YAML: %w", err)
+	if len(doc.Content) > 0 {
+		if err := doc.Decode(&items); err != nil {
+			return fmt.Errorf("failed to unmarshal YAML: %w", err)
+		}
+	}
+
+	dl.applyOverrides(path, items, target)
+	if err := checkModelMatch(items, target); err != nil {
+		return err
 	}
 
 	// Get current target slice and append items from this file
@@ -215,6 +467,13 @@ func (dl *DataLoader) loadFile(path string, target interface{}) error {
 			return fmt.Errorf("failed to unmarshal racks: %w", err)
 		}
 		*t = append(*t, newItems...)
+	case *[]*models.RoleGroup:
+		var newItems []*models.RoleGroup
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal role groups: %w", err)
+		}
+		*t = append(*t, newItems...)
 	case *[]*models.Role:
 		var newItems []*models.Role
 		data, _ := yaml.Marshal(items)
@@ -222,6 +481,13 @@ func (dl *DataLoader) loadFile(path string, target interface{}) error {
 			return fmt.Errorf("failed to unmarshal roles: %w", err)
 		}
 		*t = append(*t, newItems...)
+	case *[]*models.InventoryItemRole:
+		var newItems []*models.InventoryItemRole
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal inventory item roles: %w", err)
+		}
+		*t = append(*t, newItems...)
 	case *[]*models.Tag:
 		var newItems []*models.Tag
 		data, _ := yaml.Marshal(items)
@@ -229,6 +495,104 @@ func (dl *DataLoader) loadFile(path string, target interface{}) error {
 			return fmt.Errorf("failed to unmarshal tags: %w", err)
 		}
 		*t = append(*t, newItems...)
+	case *[]*models.ConfigContext:
+		var newItems []*models.ConfigContext
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal config contexts: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.Webhook:
+		var newItems []*models.Webhook
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal webhooks: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.CustomFieldChoiceSet:
+		var newItems []*models.CustomFieldChoiceSet
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal custom field choice sets: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.CustomFieldDef:
+		var newItems []*models.CustomFieldDef
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal custom fields: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.ExportTemplate:
+		var newItems []*models.ExportTemplate
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal export templates: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.ConfigTemplate:
+		var newItems []*models.ConfigTemplate
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal config templates: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.ContactGroup:
+		var newItems []*models.ContactGroup
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal contact groups: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.TenantGroup:
+		var newItems []*models.TenantGroup
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal tenant groups: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.Tenant:
+		var newItems []*models.Tenant
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal tenants: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.ContactRole:
+		var newItems []*models.ContactRole
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal contact roles: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.Contact:
+		var newItems []*models.Contact
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal contacts: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.Provider:
+		var newItems []*models.Provider
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal providers: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.CircuitType:
+		var newItems []*models.CircuitType
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal circuit types: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.Circuit:
+		var newItems []*models.Circuit
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal circuits: %w", err)
+		}
+		*t = append(*t, newItems...)
 	case *[]*models.VLAN:
 		var newItems []*models.VLAN
 		data, _ := yaml.Marshal(items)
@@ -243,6 +607,13 @@ func (dl *DataLoader) loadFile(path string, target interface{}) error {
 			return fmt.Errorf("failed to unmarshal vlan groups: %w", err)
 		}
 		*t = append(*t, newItems...)
+	case *[]*models.IPAMRole:
+		var newItems []*models.IPAMRole
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal ipam roles: %w", err)
+		}
+		*t = append(*t, newItems...)
 	case *[]*models.VRF:
 		var newItems []*models.VRF
 		data, _ := yaml.Marshal(items)
@@ -278,6 +649,27 @@ func (dl *DataLoader) loadFile(path string, target interface{}) error {
 			return fmt.Errorf("failed to unmarshal devices: %w", err)
 		}
 		*t = append(*t, newItems...)
+	case *[]*models.VirtualChassis:
+		var newItems []*models.VirtualChassis
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal virtual chassis: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.VLANTranslationPolicy:
+		var newItems []*models.VLANTranslationPolicy
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal VLAN translation policies: %w", err)
+		}
+		*t = append(*t, newItems...)
+	case *[]*models.FHRPGroup:
+		var newItems []*models.FHRPGroup
+		data, _ := yaml.Marshal(items)
+		if err := yaml.Unmarshal(data, &newItems); err != nil {
+			return fmt.Errorf("failed to unmarshal FHRP groups: %w", err)
+		}
+		*t = append(*t, newItems...)
 	default:
 		return fmt.Errorf("unsupported target type: %T", target)
 	}
@@ -285,6 +677,30 @@ func (dl *DataLoader) loadFile(path string, target interface{}) error {
 	return nil
 }
 
+// checkModelMatch fails if most fields of an item are not fields of its model. Any map
+// unmarshals into any model, so a file in the wrong folder (e.g. a device in sites/) would
+// otherwise load as objects with empty fields. Missing fields alone are left to NetBox, which
+// defaults many of them.
+func checkModelMatch(items []map[string]interface{}, target interface{}) error {
+	model := reflect.TypeOf(target).Elem().Elem().Elem() // *[]*models.X → models.X
+	fields := yamlFields(model)
+
+	for i, item := range items {
+		var unknown []string
+		for key := range item {
+			if !slices.Contains(fields, key) {
+				unknown = append(unknown, key)
+			}
+		}
+		if len(unknown) > len(item)-len(unknown) {
+			slices.Sort(unknown)
+			return fmt.Errorf("item %d does not look like a %s, %s are not %s fields (wrong folder?)",
+				i+1, model.Name(), strings.Join(unknown, ", "), model.Name())
+		}
+	}
+	return nil
+}
+
 // findYAMLFiles recursively finds all YAML files in a directory
 func (dl *DataLoader) findYAMLFiles(dir string) ([]string, error) {
 	var files []string
@@ -294,6 +710,14 @@ func (dl *DataLoader) findYAMLFiles(dir string) ([]string, error) {
 			return err
 		}
 
+		if dl.isExcluded(path) {
+			dl.logger.Debug("Excluding %s", path)
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		if !info.IsDir() {
 			ext := filepath.Ext(path)
 			if ext == ".yaml" || ext == ".yml" {
@@ -306,3 +730,51 @@ func (dl *DataLoader) findYAMLFiles(dir string) ([]string, error) {
 
 	return files, err
 }
+
+// isExcluded reports whether a path matches any exclude pattern
+func (dl *DataLoader) isExcluded(file string) bool {
+	if len(dl.excludes) == 0 {
+		return false
+	}
+
+	rel, err := filepath.Rel(dl.basePath, file)
+	if err != nil {
+		rel = file
+	}
+	rel = filepath.ToSlash(rel)
+
+	for _, pattern := range dl.excludes {
+		pattern = filepath.ToSlash(pattern)
+		if !strings.Contains(pattern, "/") {
+			pattern = "**/" + pattern
+		}
+		if matchGlob(strings.Split(pattern, "/"), strings.Split(rel, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob matches path segments against pattern segments, where "**" matches zero or more segments
+func matchGlob(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlob(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchGlob(pattern[1:], segments[1:])
+}
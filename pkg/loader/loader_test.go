@@ -3,8 +3,10 @@ package loader
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
 	"github.com/braunma/netbox-gitops-controller/pkg/utils"
 )
 
@@ -55,6 +57,30 @@ func TestLoadDefinitionFiles(t *testing.T) {
 		}
 	})
 
+	t.Run("Load Role Groups", func(t *testing.T) {
+		groups, err := loader.LoadRoleGroups("definitions/role_groups")
+		if err != nil {
+			t.Errorf("LoadRoleGroups() error = %v", err)
+		}
+		if len(groups) == 0 {
+			t.Error("LoadRoleGroups() returned 0 role groups")
+		}
+
+		slugs := make(map[string]bool)
+		for _, group := range groups {
+			slugs[group.Slug] = true
+		}
+		roles, err := loader.LoadRoles("definitions/roles")
+		if err != nil {
+			t.Fatalf("LoadRoles() error = %v", err)
+		}
+		for _, role := range roles {
+			if role.Group != "" && !slugs[role.Group] {
+				t.Errorf("Role %s references undefined role group %s", role.Name, role.Group)
+			}
+		}
+	})
+
 	t.Run("Load Roles", func(t *testing.T) {
 		roles, err := loader.LoadRoles("definitions/roles")
 		if err != nil {
@@ -106,6 +132,75 @@ func TestLoadDefinitionFiles(t *testing.T) {
 		}
 	})
 
+	t.Run("Load Contacts", func(t *testing.T) {
+		contacts, err := loader.LoadContacts("definitions/contacts")
+		if err != nil {
+			t.Errorf("LoadContacts() error = %v", err)
+		}
+		if len(contacts) == 0 {
+			t.Error("LoadContacts() returned 0 contacts")
+		}
+
+		roles, err := loader.LoadContactRoles("definitions/contact_roles")
+		if err != nil {
+			t.Errorf("LoadContactRoles() error = %v", err)
+		}
+		if len(roles) == 0 {
+			t.Error("LoadContactRoles() returned 0 roles")
+		}
+
+		groups, err := loader.LoadContactGroups("definitions/contact_groups")
+		if err != nil {
+			t.Errorf("LoadContactGroups() error = %v", err)
+		}
+		if len(groups) == 0 {
+			t.Error("LoadContactGroups() returned 0 groups")
+		}
+
+		sites, err := loader.LoadSites("definitions/sites")
+		if err != nil {
+			t.Errorf("LoadSites() error = %v", err)
+		}
+		foundAssignment := false
+		for _, site := range sites {
+			for _, assignment := range site.Contacts {
+				if assignment.Name == "DC Operations Berlin" && assignment.Role == "operations" {
+					foundAssignment = true
+				}
+			}
+		}
+		if !foundAssignment {
+			t.Error("Site contact assignment not loaded")
+		}
+	})
+
+	t.Run("Load Tenancy", func(t *testing.T) {
+		groups, err := loader.LoadTenantGroups("definitions/tenant_groups")
+		if err != nil {
+			t.Errorf("LoadTenantGroups() error = %v", err)
+		}
+		slugs := make(map[string]bool)
+		for _, group := range groups {
+			if group.Parent != "" && !slugs[group.Parent] {
+				t.Errorf("Tenant group %s is listed before its parent %s", group.Name, group.Parent)
+			}
+			slugs[group.Slug] = true
+		}
+
+		tenants, err := loader.LoadTenants("definitions/tenants")
+		if err != nil {
+			t.Errorf("LoadTenants() error = %v", err)
+		}
+		if len(tenants) == 0 {
+			t.Error("LoadTenants() returned 0 tenants")
+		}
+		for _, tenant := range tenants {
+			if tenant.Group != "" && !slugs[tenant.Group] {
+				t.Errorf("Tenant %s references undefined tenant group %s", tenant.Name, tenant.Group)
+			}
+		}
+	})
+
 	t.Run("Load Racks", func(t *testing.T) {
 		racks, err := loader.LoadRacks("definitions/racks")
 		if err != nil {
@@ -127,6 +222,17 @@ func TestLoadDefinitionFiles(t *testing.T) {
 				t.Error("Rack has empty site_slug")
 			}
 		}
+
+		var rackA01 *models.Rack
+		for _, rack := range racks {
+			if rack.Slug == "rack-a01" {
+				rackA01 = rack
+			}
+		}
+		if rackA01 == nil || rackA01.FacilityID == nil || *rackA01.FacilityID != "BER1-R0101" ||
+			rackA01.AssetTag == nil || *rackA01.AssetTag != "RACK-0001" {
+			t.Errorf("Rack A-01 = %+v, expected facility_id BER1-R0101 and asset_tag RACK-0001", rackA01)
+		}
 	})
 
 	t.Run("Load VRFs", func(t *testing.T) {
@@ -156,6 +262,22 @@ func TestLoadDefinitionFiles(t *testing.T) {
 		}
 	})
 
+	t.Run("Load IPAM Roles", func(t *testing.T) {
+		roles, err := loader.LoadIPAMRoles("definitions/ipam_roles")
+		if err != nil {
+			t.Errorf("LoadIPAMRoles() error = %v", err)
+		}
+		if len(roles) == 0 {
+			t.Error("LoadIPAMRoles() returned 0 roles")
+		}
+
+		for _, role := range roles {
+			if role.Slug == "" {
+				t.Errorf("IPAM role %s has empty slug", role.Name)
+			}
+		}
+	})
+
 	t.Run("Load VLAN Groups", func(t *testing.T) {
 		groups, err := loader.LoadVLANGroups("definitions/vlan_groups")
 		if err != nil {
@@ -193,8 +315,8 @@ func TestLoadDefinitionFiles(t *testing.T) {
 			if vlan.VID < 1 || vlan.VID > 4094 {
 				t.Errorf("VLAN %s has invalid VID: %d", vlan.Name, vlan.VID)
 			}
-			if vlan.SiteSlug == "" {
-				t.Errorf("VLAN %s has no site_slug", vlan.Name)
+			if vlan.SiteSlug == "" && vlan.GroupSlug == "" {
+				t.Errorf("VLAN %s has neither site_slug nor group_slug", vlan.Name)
 			}
 			if vlan.Status == "" {
 				t.Errorf("VLAN %s has no status", vlan.Name)
@@ -222,6 +344,208 @@ func TestLoadDefinitionFiles(t *testing.T) {
 		}
 	})
 
+	t.Run("Load Config Contexts", func(t *testing.T) {
+		contexts, err := loader.LoadConfigContexts("definitions/config_contexts")
+		if err != nil {
+			t.Errorf("LoadConfigContexts() error = %v", err)
+		}
+		if len(contexts) == 0 {
+			t.Fatal("LoadConfigContexts() returned 0 config contexts")
+		}
+
+		for _, cc := range contexts {
+			if cc.Name == "" {
+				t.Error("ConfigContext has empty name")
+			}
+			if len(cc.Data) == 0 {
+				t.Errorf("ConfigContext %s has no data", cc.Name)
+			}
+		}
+
+		// Nested data must survive loading as maps and lists
+		ntp, ok := contexts[0].Data["ntp"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("ConfigContext %s ntp data = %T, expected a map", contexts[0].Name, contexts[0].Data["ntp"])
+		}
+		if servers, _ := ntp["servers"].([]interface{}); len(servers) != 2 {
+			t.Errorf("ConfigContext %s ntp servers = %v, expected 2", contexts[0].Name, ntp["servers"])
+		}
+	})
+
+	t.Run("Load Webhooks", func(t *testing.T) {
+		webhooks, err := loader.LoadWebhooks("definitions/webhooks")
+		if err != nil {
+			t.Errorf("LoadWebhooks() error = %v", err)
+		}
+		if len(webhooks) == 0 {
+			t.Fatal("LoadWebhooks() returned 0 webhooks")
+		}
+
+		for _, webhook := range webhooks {
+			if webhook.PayloadURL == "" {
+				t.Errorf("Webhook %s has empty payload URL", webhook.Name)
+			}
+			if len(webhook.Events) > 0 && len(webhook.ObjectTypes) == 0 {
+				t.Errorf("Webhook %s has events but no object types", webhook.Name)
+			}
+		}
+	})
+
+	t.Run("Load Custom Fields", func(t *testing.T) {
+		choiceSets, err := loader.LoadCustomFieldChoiceSets("definitions/custom_field_choice_sets")
+		if err != nil {
+			t.Fatalf("LoadCustomFieldChoiceSets() error = %v", err)
+		}
+		for _, choiceSet := range choiceSets {
+			if len(choiceSet.Choices) == 0 {
+				t.Errorf("Choice set %s has no choices", choiceSet.Name)
+			}
+		}
+
+		fields, err := loader.LoadCustomFields("definitions/custom_fields")
+		if err != nil {
+			t.Fatalf("LoadCustomFields() error = %v", err)
+		}
+		if len(fields) == 0 {
+			t.Fatal("LoadCustomFields() returned 0 custom fields")
+		}
+		for _, field := range fields {
+			if field.Type == "" || len(field.ObjectTypes) == 0 {
+				t.Errorf("Custom field %s has no type or object types", field.Name)
+			}
+		}
+	})
+
+	t.Run("Load Export Templates", func(t *testing.T) {
+		templates, err := loader.LoadExportTemplates("definitions/export_templates")
+		if err != nil {
+			t.Errorf("LoadExportTemplates() error = %v", err)
+		}
+		if len(templates) == 0 {
+			t.Fatal("LoadExportTemplates() returned 0 export templates")
+		}
+
+		for _, tmpl := range templates {
+			if tmpl.TemplateCode == "" {
+				t.Errorf("ExportTemplate %s has empty template code", tmpl.Name)
+			}
+		}
+	})
+
+	t.Run("Load Config Templates", func(t *testing.T) {
+		templates, err := loader.LoadConfigTemplates("definitions/config_templates")
+		if err != nil {
+			t.Fatalf("LoadConfigTemplates() error = %v", err)
+		}
+		if len(templates) == 0 {
+			t.Fatal("LoadConfigTemplates() returned 0 config templates")
+		}
+
+		for _, tmpl := range templates {
+			if tmpl.TemplateCode == "" {
+				t.Errorf("ConfigTemplate %s has empty template code", tmpl.Name)
+			}
+		}
+	})
+
+	t.Run("Load Virtual Chassis", func(t *testing.T) {
+		chassis, err := loader.LoadVirtualChassis("definitions/virtual_chassis")
+		if err != nil {
+			t.Errorf("LoadVirtualChassis() error = %v", err)
+		}
+		if len(chassis) == 0 {
+			t.Fatal("LoadVirtualChassis() returned 0 virtual chassis")
+		}
+
+		for _, vc := range chassis {
+			if vc.Name == "" {
+				t.Error("VirtualChassis has empty name")
+			}
+		}
+	})
+
+	t.Run("Load FHRP Groups", func(t *testing.T) {
+		groups, err := loader.LoadFHRPGroups("definitions/fhrp_groups")
+		if err != nil {
+			t.Fatalf("LoadFHRPGroups() error = %v", err)
+		}
+		if len(groups) == 0 {
+			t.Fatal("LoadFHRPGroups() returned 0 FHRP groups")
+		}
+
+		for _, group := range groups {
+			if group.Protocol == "" || len(group.Assignments) == 0 {
+				t.Errorf("FHRPGroup %q has empty protocol or no assignments", group.Name)
+			}
+		}
+	})
+
+	t.Run("Load VLAN Translation Policies", func(t *testing.T) {
+		policies, err := loader.LoadVLANTranslationPolicies("definitions/vlan_translation_policies")
+		if err != nil {
+			t.Fatalf("LoadVLANTranslationPolicies() error = %v", err)
+		}
+		if len(policies) == 0 {
+			t.Fatal("LoadVLANTranslationPolicies() returned 0 policies")
+		}
+
+		for _, policy := range policies {
+			if len(policy.Rules) == 0 {
+				t.Errorf("VLANTranslationPolicy %q has no rules", policy.Name)
+			}
+		}
+	})
+
+	t.Run("Load Inventory Item Roles", func(t *testing.T) {
+		roles, err := loader.LoadInventoryItemRoles("definitions/inventory_item_roles")
+		if err != nil {
+			t.Fatalf("LoadInventoryItemRoles() error = %v", err)
+		}
+		if len(roles) == 0 {
+			t.Error("LoadInventoryItemRoles() returned 0 roles")
+		}
+		for _, role := range roles {
+			if role.Slug == "" || role.Color == "" {
+				t.Errorf("InventoryItemRole %q has empty slug or color", role.Name)
+			}
+		}
+	})
+
+	t.Run("Load Circuits", func(t *testing.T) {
+		providers, err := loader.LoadProviders("definitions/providers")
+		if err != nil {
+			t.Fatalf("LoadProviders() error = %v", err)
+		}
+		if len(providers) == 0 {
+			t.Error("LoadProviders() returned 0 providers")
+		}
+		circuitTypes, err := loader.LoadCircuitTypes("definitions/circuit_types")
+		if err != nil {
+			t.Fatalf("LoadCircuitTypes() error = %v", err)
+		}
+		if len(circuitTypes) == 0 {
+			t.Error("LoadCircuitTypes() returned 0 circuit types")
+		}
+
+		circuits, err := loader.LoadCircuits("definitions/circuits")
+		if err != nil {
+			t.Fatalf("LoadCircuits() error = %v", err)
+		}
+		if len(circuits) == 0 {
+			t.Fatal("LoadCircuits() returned 0 circuits")
+		}
+		for _, circuit := range circuits {
+			if circuit.CID == "" || circuit.Provider == "" || circuit.Type == "" {
+				t.Errorf("Circuit %q has empty cid, provider or type", circuit.CID)
+			}
+			for _, term := range circuit.Terminations {
+				if term.Side == "" || term.Site == "" {
+					t.Errorf("Circuit %s has a termination without side or site", circuit.CID)
+				}
+			}
+		}
+	})
+
 	t.Run("Load Device Types", func(t *testing.T) {
 		deviceTypes, err := loader.LoadDeviceTypes("definitions/device_types")
 		// Note: Device type files may be single objects or arrays
@@ -248,6 +572,9 @@ func TestLoadDefinitionFiles(t *testing.T) {
 			if dt.UHeight <= 0 {
 				t.Errorf("DeviceType %s has invalid UHeight: %d", dt.Model, dt.UHeight)
 			}
+			if dt.Weight > 0 && dt.WeightUnit == "" {
+				t.Errorf("DeviceType %s has a weight without a weight unit", dt.Model)
+			}
 		}
 	})
 
@@ -307,6 +634,13 @@ func TestLoadInventoryFiles(t *testing.T) {
 					}
 				}
 			}
+
+			// Verify services if present
+			for _, service := range device.Services {
+				if service.Protocol == "" || len(service.Ports) == 0 {
+					t.Errorf("Service %s on %s needs a protocol and ports", service.Name, device.Name)
+				}
+			}
 		}
 	})
 
@@ -395,3 +729,117 @@ func TestYAMLFileValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadSitesDistinguishesEmptyFromAbsent(t *testing.T) {
+	dir := t.TempDir()
+	content := `- name: "Cleared"
+  slug: "cleared"
+  description: ""
+- name: "Untouched"
+  slug: "untouched"
+`
+	if err := os.MkdirAll(filepath.Join(dir, "sites"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sites", "sites.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sites, err := NewDataLoader(dir, utils.NewLogger(false)).LoadSites("sites")
+	if err != nil {
+		t.Fatalf("LoadSites() error = %v", err)
+	}
+	if len(sites) != 2 {
+		t.Fatalf("LoadSites() returned %d sites, expected 2", len(sites))
+	}
+	if sites[0].Description == nil || *sites[0].Description != "" {
+		t.Errorf("Site %s description = %v, expected present and empty", sites[0].Name, sites[0].Description)
+	}
+	if sites[1].Description != nil {
+		t.Errorf("Site %s description = %q, expected absent", sites[1].Name, *sites[1].Description)
+	}
+}
+
+// TestLoadRejectsFileInWrongFolder tests that a device file in the sites folder is refused
+// with its path instead of loading as a site with empty fields, while a missing field is not
+func TestLoadRejectsFileInWrongFolder(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "definitions/sites/switches.yaml", `- name: "sw-leaf-01"
+  site_slug: "berlin-dc"
+  device_type_slug: "n9k"
+  role_slug: "leaf"
+`)
+
+	_, err := NewDataLoader(dir, utils.NewLogger(false)).LoadSites("definitions/sites")
+	if err == nil {
+		t.Fatal("LoadSites() expected error for a device file in the sites folder")
+	}
+	for _, want := range []string{"switches.yaml", "does not look like a Site", "device_type_slug, role_slug, site_slug"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadSites() error = %v, expected it to mention %q", err, want)
+		}
+	}
+
+	// Fields NetBox defaults may be left out
+	writeFile(t, dir, "definitions/tags/tags.yaml", `- name: "Production"
+  slug: "production"
+`)
+	tags, err := NewDataLoader(dir, utils.NewLogger(false)).LoadTags("definitions/tags")
+	if err != nil || len(tags) != 1 {
+		t.Errorf("LoadTags() = %v, %v, expected the tag without a color", tags, err)
+	}
+}
+
+func TestLoadExcludesMatchingFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		full := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("definitions/sites/sites.yaml", "- name: \"Berlin DC\"\n  slug: \"berlin-dc\"\n")
+	write("definitions/sites/_drafts/new.yaml", "- name: \"Draft DC\"\n  slug: \"draft-dc\"\n")
+	write("definitions/sites/lab.wip.yaml", "- name: \"Lab\"\n  slug: \"lab\"\n")
+
+	loader := NewDataLoader(dir, utils.NewLogger(false))
+	loader.SetExcludes([]string{"**/_drafts/*", "*.wip.yaml"})
+
+	sites, err := loader.LoadSites("definitions/sites")
+	if err != nil {
+		t.Fatalf("LoadSites() error = %v", err)
+	}
+	if len(sites) != 1 || sites[0].Slug != "berlin-dc" {
+		t.Errorf("LoadSites() = %d sites, expected only berlin-dc", len(sites))
+		for _, site := range sites {
+			t.Logf("  loaded %s", site.Slug)
+		}
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"**/_drafts/*", "definitions/sites/_drafts/new.yaml", true},
+		{"**/_drafts", "definitions/sites/_drafts", true},
+		{"**/_drafts/*", "definitions/sites/sites.yaml", false},
+		{"definitions/*/old.yaml", "definitions/sites/old.yaml", true},
+		{"definitions/*/old.yaml", "definitions/sites/x/old.yaml", false},
+		{"**", "anything/at/all.yaml", true},
+	}
+
+	for _, tt := range tests {
+		got := matchGlob(strings.Split(tt.pattern, "/"), strings.Split(tt.path, "/"))
+		if got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, expected %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package loader
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Override replaces one field of one loaded object (--set resource.identifier.field=value)
+type Override struct {
+	Resource   string      // Resource type, singular or plural (e.g. device, devices, vlan_groups)
+	Identifier string      // Name (or slug) of the object
+	Field      string      // YAML field name
+	Value      interface{} // Parsed as YAML, so numbers and booleans keep their type
+	Raw        string      // The flag as given, for messages
+}
+
+// overrides holds the overrides of a DataLoader and which of them matched an object.
+// Folders are loaded concurrently, so access is guarded.
+type overrides struct {
+	mu      sync.Mutex
+	list    []Override
+	applied map[int]bool
+}
+
+// ParseOverride parses "resource.identifier.field=value". The identifier may contain dots
+// (e.g. a host name); the resource ends at the first dot and the field starts after the last.
+func ParseOverride(raw string) (Override, error) {
+	path, value, ok := strings.Cut(raw, "=")
+	if !ok {
+		return Override{}, fmt.Errorf("invalid --set %q: expected resource.identifier.field=value", raw)
+	}
+	resource, rest, ok := strings.Cut(path, ".")
+	dot := strings.LastIndex(rest, ".")
+	if !ok || dot <= 0 || dot == len(rest)-1 || resource == "" {
+		return Override{}, fmt.Errorf("invalid --set %q: expected resource.identifier.field=value", raw)
+	}
+
+	var parsed interface{}
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		return Override{}, fmt.Errorf("invalid --set %q: %w", raw, err)
+	}
+	return Override{
+		Resource:   resource,
+		Identifier: rest[:dot],
+		Field:      rest[dot+1:],
+		Value:      parsed,
+		Raw:        raw,
+	}, nil
+}
+
+// SetOverrides parses --set flags; each is applied to the matching object as it is loaded
+func (dl *DataLoader) SetOverrides(raw []string) error {
+	list := make([]Override, 0, len(raw))
+	for _, r := range raw {
+		override, err := ParseOverride(r)
+		if err != nil {
+			return err
+		}
+		if err := validateOverride(override); err != nil {
+			return err
+		}
+		list = append(list, override)
+	}
+	dl.overrides = &overrides{list: list, applied: make(map[int]bool)}
+	return nil
+}
+
+// UnusedOverrides returns the overrides that matched no loaded object, e.g. a misspelled
+// name or a resource type that was not reconciled
+func (dl *DataLoader) UnusedOverrides() []Override {
+	if dl.overrides == nil {
+		return nil
+	}
+	dl.overrides.mu.Lock()
+	defer dl.overrides.mu.Unlock()
+
+	var unused []Override
+	for i, override := range dl.overrides.list {
+		if !dl.overrides.applied[i] {
+			unused = append(unused, override)
+		}
+	}
+	return unused
+}
+
+// applyOverrides sets the overridden fields on the items of a file before they are
+// unmarshaled into target (*[]*models.X)
+func (dl *DataLoader) applyOverrides(path string, items []map[string]interface{}, target interface{}) {
+	if dl.overrides == nil || len(dl.overrides.list) == 0 {
+		return
+	}
+	model := reflect.TypeOf(target).Elem().Elem().Elem().Name()
+
+	dl.overrides.mu.Lock()
+	defer dl.overrides.mu.Unlock()
+
+	for i, override := range dl.overrides.list {
+		if !resourceMatchesModel(override.Resource, model) {
+			continue
+		}
+		for _, item := range items {
+			if item["name"] != override.Identifier && item["slug"] != override.Identifier {
+				continue
+			}
+			dl.logger.Warning("Override --set %s: %s %s %s %v → %v (%s)",
+				override.Raw, model, override.Identifier, override.Field, item[override.Field], override.Value, path)
+			item[override.Field] = override.Value
+			dl.overrides.applied[i] = true
+		}
+	}
+}
+
+// validateOverride checks that an override names a resource type and one of the YAML fields of
+// its model, so a misspelled field fails the run instead of being silently ignored on unmarshal
+func validateOverride(override Override) error {
+	var fields []string
+	for _, entry := range resourceLoaders {
+		if !resourceMatchesModel(override.Resource, entry.model.Name()) {
+			continue
+		}
+		fields = yamlFields(entry.model)
+		if slices.Contains(fields, override.Field) {
+			return nil
+		}
+	}
+	if fields == nil {
+		return fmt.Errorf("invalid --set %q: unknown resource type %s", override.Raw, override.Resource)
+	}
+	return fmt.Errorf("invalid --set %q: %s has no field %s (valid: %s)",
+		override.Raw, override.Resource, override.Field, strings.Join(fields, ", "))
+}
+
+// yamlFields returns the YAML field names of a model, in declaration order
+func yamlFields(model reflect.Type) []string {
+	fields := make([]string, 0, model.NumField())
+	for i := 0; i < model.NumField(); i++ {
+		name, _, _ := strings.Cut(model.Field(i).Tag.Get("yaml"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// resourceMatchesModel reports whether an override's resource names a model, e.g. "device"
+// and "devices" name DeviceConfig, "vlan_groups" names VLANGroup
+func resourceMatchesModel(resource, model string) bool {
+	resource = strings.ToLower(strings.ReplaceAll(resource, "_", ""))
+	name := strings.ToLower(strings.TrimSuffix(model, "Config"))
+	return resource == name || resource == name+"s" || resource == name+"es"
+}
@@ -0,0 +1,107 @@
+package loader
+
+import (
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+func TestParseOverride(t *testing.T) {
+	tests := []struct {
+		raw                         string
+		resource, identifier, field string
+		value                       interface{}
+		wantErr                     bool
+	}{
+		{raw: "device.web-01.status=offline", resource: "device", identifier: "web-01", field: "status", value: "offline"},
+		{raw: "devices.web-01.example.com.position=12", resource: "devices", identifier: "web-01.example.com", field: "position", value: 12},
+		{raw: "vlan.Servers.description=", resource: "vlan", identifier: "Servers", field: "description", value: nil},
+		{raw: "device.web-01.status", wantErr: true},
+		{raw: "device.status=offline", wantErr: true},
+		{raw: "device.web-01.=offline", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseOverride(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseOverride() = %+v, expected error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseOverride() error = %v", err)
+			}
+			if got.Resource != tt.resource || got.Identifier != tt.identifier || got.Field != tt.field || got.Value != tt.value {
+				t.Errorf("ParseOverride() = %+v, expected %s/%s/%s = %v", got, tt.resource, tt.identifier, tt.field, tt.value)
+			}
+		})
+	}
+}
+
+// TestLoadDevicesWithStatusOverride tests that --set changes the status of one loaded device
+func TestLoadDevicesWithStatusOverride(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "inventory/servers.yaml", `- name: "web-01"
+  site_slug: "berlin-dc"
+  device_type_slug: "dell-r640"
+  role_slug: "server"
+  status: "active"
+- name: "web-02"
+  site_slug: "berlin-dc"
+  device_type_slug: "dell-r640"
+  role_slug: "server"
+  status: "active"
+`)
+
+	dl := NewDataLoader(dir, utils.NewLogger(false))
+	if err := dl.SetOverrides([]string{"device.web-01.status=offline", "device.web-99.status=offline"}); err != nil {
+		t.Fatalf("SetOverrides() error = %v", err)
+	}
+
+	devices, err := dl.LoadDevices("inventory")
+	if err != nil {
+		t.Fatalf("LoadDevices() error = %v", err)
+	}
+	if len(devices) != 2 || devices[0].Status != "offline" || devices[1].Status != "active" {
+		t.Errorf("LoadDevices() = %+v, expected only web-01 offline", devices)
+	}
+
+	unused := dl.UnusedOverrides()
+	if len(unused) != 1 || unused[0].Identifier != "web-99" {
+		t.Errorf("UnusedOverrides() = %+v, expected only the web-99 override", unused)
+	}
+
+	if err := dl.SetOverrides([]string{"device.web-01"}); err == nil {
+		t.Error("SetOverrides() expected error for an override without a value")
+	}
+	for _, raw := range []string{"device.web-01.stauts=offline", "devise.web-01.status=offline"} {
+		if err := dl.SetOverrides([]string{raw}); err == nil {
+			t.Errorf("SetOverrides(%s) expected error for an unknown field or resource type", raw)
+		}
+	}
+	if err := dl.SetOverrides([]string{"vlan_groups.servers.description=x", "racks.A-01.asset_tag=RACK-1"}); err != nil {
+		t.Errorf("SetOverrides() error = %v, expected known fields of other models to be accepted", err)
+	}
+}
+
+func TestResourceMatchesModel(t *testing.T) {
+	tests := []struct {
+		resource, model string
+		want            bool
+	}{
+		{"device", "DeviceConfig", true},
+		{"devices", "DeviceConfig", true},
+		{"prefixes", "Prefix", true},
+		{"vlan_groups", "VLANGroup", true},
+		{"virtual_chassis", "VirtualChassis", true},
+		{"site", "DeviceConfig", false},
+		{"vlan", "VLANGroup", false},
+	}
+	for _, tt := range tests {
+		if got := resourceMatchesModel(tt.resource, tt.model); got != tt.want {
+			t.Errorf("resourceMatchesModel(%q, %q) = %v, expected %v", tt.resource, tt.model, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package loader
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Plan is the compiled desired state: the objects of every resource type as they would be
+// reconciled, after environment expansion, !include fragments, --set overrides and --site.
+// References to other objects are kept as the slugs and names the YAML uses.
+type Plan map[string]interface{}
+
+// Plan loads the given resource types into a plan. Reconcile steps without definitions of
+// their own (cables) are left out, so an explicit reconcile order can be passed as is.
+func (d *Definitions) Plan(resources []string) (Plan, error) {
+	plan := make(Plan, len(resources))
+	for _, resource := range resources {
+		if !HasLoader(resource) {
+			continue
+		}
+		items, err := d.load(resource)
+		if err != nil {
+			return nil, err
+		}
+		plan[resource] = items
+	}
+	return plan, nil
+}
+
+// WritePlan writes a plan to a file, as JSON if the file name ends in .json and as YAML otherwise.
+// The plan holds secrets such as FHRP authentication keys, so only the owner may read it.
+func WritePlan(path string, plan Plan) error {
+	var (
+		content []byte
+		err     error
+	)
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		content, err = json.MarshalIndent(plan, "", "  ")
+		content = append(content, '\n')
+	} else {
+		content, err = yaml.Marshal(plan)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return fmt.Errorf("failed to write plan file: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0o600); err != nil {
+		return fmt.Errorf("failed to restrict plan file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package loader
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// TestWritePlan tests that the dumped plan holds the loaded devices with their references,
+// after --set and --site, in both the JSON and the YAML encoding
+func TestWritePlan(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "definitions/sites/sites.yaml", `- name: "Berlin DC"
+  slug: "berlin-dc"
+- name: "Munich DC"
+  slug: "munich-dc"
+`)
+	writeFile(t, dir, "inventory/hardware/active/servers.yaml", `- name: "web-01"
+  site_slug: "berlin-dc"
+  device_type_slug: "dell-r640"
+  role_slug: "server"
+  status: "active"
+- name: "web-02"
+  site_slug: "munich-dc"
+  device_type_slug: "dell-r640"
+  role_slug: "server"
+`)
+
+	dl := NewDataLoader(dir, utils.NewLogger(false))
+	if err := dl.SetOverrides([]string{"device.web-01.status=offline"}); err != nil {
+		t.Fatalf("SetOverrides() error = %v", err)
+	}
+	layout := DefaultLayout()
+	plan, err := NewDefinitions(dl, layout, NewSiteFilter([]string{"berlin-dc"})).Plan(layout.Resources())
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	for _, name := range []string{"plan.json", "plan.yaml"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := WritePlan(path, plan); err != nil {
+				t.Fatalf("WritePlan() error = %v", err)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if perm := info.Mode().Perm(); perm != 0o600 {
+				t.Errorf("Plan file mode = %v, expected 0600 as it may hold secrets", perm)
+			}
+
+			var dumped map[string][]map[string]interface{}
+			if filepath.Ext(name) == ".json" {
+				err = json.Unmarshal(content, &dumped)
+			} else {
+				err = yaml.Unmarshal(content, &dumped)
+			}
+			if err != nil {
+				t.Fatalf("Plan does not parse: %v\n%s", err, content)
+			}
+
+			devices := dumped["devices"]
+			if len(devices) != 1 {
+				t.Fatalf("Plan has %d devices, expected only web-01 of berlin-dc:\n%s", len(devices), content)
+			}
+			device := devices[0]
+			if device["name"] != "web-01" || device["site_slug"] != "berlin-dc" || device["role_slug"] != "server" || device["device_type_slug"] != "dell-r640" {
+				t.Errorf("Device = %v, expected web-01 with its site, role and device type slugs", device)
+			}
+			if device["status"] != "offline" {
+				t.Errorf("Device status = %v, expected the --set override offline", device["status"])
+			}
+			if sites := dumped["sites"]; len(sites) != 1 || sites[0]["slug"] != "berlin-dc" {
+				t.Errorf("Sites = %v, expected only berlin-dc", sites)
+			}
+			if racks, ok := dumped["racks"]; !ok || len(racks) != 0 {
+				t.Errorf("Racks = %v, expected an empty list", racks)
+			}
+		})
+	}
+}
+
+// TestResourceLoadersCoverLayout tests that every resource type of the default layout has a
+// loader in the registry and the registry has no types the layout doesn't know
+func TestResourceLoadersCoverLayout(t *testing.T) {
+	layout := DefaultLayout()
+	for _, resource := range layout.Resources() {
+		if !HasLoader(resource) {
+			t.Errorf("Resource type %s has no loader", resource)
+		}
+	}
+	for resource := range resourceLoaders {
+		if _, ok := layout[resource]; !ok {
+			t.Errorf("Loader for %s has no folders in the default layout", resource)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package loader
+
+import "github.com/braunma/netbox-gitops-controller/pkg/models"
+
+// SiteFilter limits a run to the objects of specific sites (--site).
+// An empty filter allows everything; objects without a site are never filtered out.
+type SiteFilter map[string]bool
+
+// NewSiteFilter creates a filter for the given site slugs
+func NewSiteFilter(slugs []string) SiteFilter {
+	f := make(SiteFilter, len(slugs))
+	for _, slug := range slugs {
+		f[slug] = true
+	}
+	return f
+}
+
+// Allows reports whether objects of a site are part of the run
+func (f SiteFilter) Allows(siteSlug string) bool {
+	return len(f) == 0 || siteSlug == "" || f[siteSlug]
+}
+
+// Sites returns the selected sites
+func (f SiteFilter) Sites(sites []*models.Site) []*models.Site {
+	return filterBySite(f, sites, func(s *models.Site) string { return s.Slug })
+}
+
+// Racks returns the racks of the selected sites
+func (f SiteFilter) Racks(racks []*models.Rack) []*models.Rack {
+	return filterBySite(f, racks, func(r *models.Rack) string { return r.SiteSlug })
+}
+
+// VLANGroups returns the VLAN groups of the selected sites and those without a site
+func (f SiteFilter) VLANGroups(groups []*models.VLANGroup) []*models.VLANGroup {
+	return filterBySite(f, groups, func(g *models.VLANGroup) string {
+		if g.ScopeType == "site" {
+			return g.ScopeSlug
+		}
+		return g.SiteSlug
+	})
+}
+
+// VLANs returns the VLANs of the selected sites and those without a site
+func (f SiteFilter) VLANs(vlans []*models.VLAN) []*models.VLAN {
+	return filterBySite(f, vlans, func(v *models.VLAN) string { return v.SiteSlug })
+}
+
+// Prefixes returns the prefixes of the selected sites and those without a site
+func (f SiteFilter) Prefixes(prefixes []*models.Prefix) []*models.Prefix {
+	return filterBySite(f, prefixes, func(p *models.Prefix) string { return p.SiteSlug })
+}
+
+// Devices returns the devices of the selected sites
+func (f SiteFilter) Devices(devices []*models.DeviceConfig) []*models.DeviceConfig {
+	return filterBySite(f, devices, func(d *models.DeviceConfig) string { return d.SiteSlug })
+}
+
+// filterBySite keeps the items whose site the filter allows
+func filterBySite[T any](f SiteFilter, items []T, siteOf func(T) string) []T {
+	if len(f) == 0 {
+		return items
+	}
+
+	var kept []T
+	for _, item := range items {
+		if f.Allows(siteOf(item)) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,117 @@
+package loader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/reconciler"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// writeFile writes a fixture file below dir, creating parent directories
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestSiteFilterAppliesOnlySelectedSite tests that a --site run only applies the selected site's objects
+func TestSiteFilterAppliesOnlySelectedSite(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "definitions/sites/sites.yaml", `
+- name: Berlin DC
+  slug: berlin-dc
+- name: Munich DC
+  slug: munich-dc
+`)
+	writeFile(t, dir, "definitions/racks/racks.yaml", `
+- name: BER-R01
+  site_slug: berlin-dc
+- name: MUC-R01
+  site_slug: munich-dc
+`)
+	writeFile(t, dir, "definitions/vlans/vlans.yaml", `
+- name: ber-servers
+  vid: 100
+  site_slug: berlin-dc
+- name: muc-servers
+  vid: 100
+  site_slug: munich-dc
+`)
+
+	dl := NewDataLoader(dir, utils.NewLogger(false))
+	sites, err := dl.LoadSites("definitions/sites")
+	if err != nil {
+		t.Fatalf("LoadSites() error = %v", err)
+	}
+	racks, err := dl.LoadRacks("definitions/racks")
+	if err != nil {
+		t.Fatalf("LoadRacks() error = %v", err)
+	}
+	vlans, err := dl.LoadVLANs("definitions/vlans")
+	if err != nil {
+		t.Fatalf("LoadVLANs() error = %v", err)
+	}
+
+	filter := NewSiteFilter([]string{"berlin-dc"})
+	sites, racks, vlans = filter.Sites(sites), filter.Racks(racks), filter.VLANs(vlans)
+
+	srv := netboxtest.NewServer()
+	defer srv.Close()
+	c, err := client.NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	foundation := reconciler.NewFoundationReconciler(c)
+	if err := foundation.ReconcileSites(sites); err != nil {
+		t.Fatalf("ReconcileSites() error = %v", err)
+	}
+	if err := foundation.ReconcileRacks(racks); err != nil {
+		t.Fatalf("ReconcileRacks() error = %v", err)
+	}
+	if err := reconciler.NewNetworkReconciler(c).ReconcileVLANs(vlans); err != nil {
+		t.Fatalf("ReconcileVLANs() error = %v", err)
+	}
+
+	for _, check := range []struct{ app, endpoint, field, present, absent string }{
+		{"dcim", "sites", "slug", "berlin-dc", "munich-dc"},
+		{"dcim", "racks", "name", "BER-R01", "MUC-R01"},
+		{"ipam", "vlans", "name", "ber-servers", "muc-servers"},
+	} {
+		if srv.Find(check.app, check.endpoint, check.field, check.present) == nil {
+			t.Errorf("%s %s was not applied", check.endpoint, check.present)
+		}
+		if srv.Find(check.app, check.endpoint, check.field, check.absent) != nil {
+			t.Errorf("%s %s of an unselected site was applied", check.endpoint, check.absent)
+		}
+	}
+}
+
+func TestSiteFilterAllows(t *testing.T) {
+	if !NewSiteFilter(nil).Allows("munich-dc") {
+		t.Error("Empty filter should allow every site")
+	}
+
+	filter := NewSiteFilter([]string{"berlin-dc", "munich-dc"})
+	tests := map[string]bool{
+		"berlin-dc":  true,
+		"munich-dc":  true,
+		"hamburg-dc": false,
+		"":           true, // not site-scoped
+	}
+	for site, expected := range tests {
+		if got := filter.Allows(site); got != expected {
+			t.Errorf("Allows(%q) = %v, expected %v", site, got, expected)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Package metrics exposes sync statistics in the Prometheus text format
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// Registry accumulates sync statistics across watch cycles
+type Registry struct {
+	mu           sync.Mutex
+	objects      map[string]map[string]int
+	syncs        map[string]int
+	lastSuccess  time.Time
+	lastDuration time.Duration
+}
+
+// NewRegistry creates an empty metrics registry
+func NewRegistry() *Registry {
+	return &Registry{
+		objects: make(map[string]map[string]int),
+		syncs:   make(map[string]int),
+	}
+}
+
+// ObserveSync records the outcome of one sync cycle
+func (r *Registry) ObserveSync(stats *client.Stats, duration time.Duration, syncErr error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stats != nil {
+		for resource, actions := range stats.Snapshot() {
+			if r.objects[resource] == nil {
+				r.objects[resource] = make(map[string]int)
+			}
+			for action, n := range actions {
+				r.objects[resource][action] += n
+			}
+		}
+	}
+
+	r.lastDuration = duration
+	if syncErr != nil {
+		r.syncs["failure"]++
+		return
+	}
+	r.syncs["success"]++
+	r.lastSuccess = time.Now()
+}
+
+// ServeHTTP writes all metrics in the Prometheus text exposition format
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	fmt.Fprintln(w, "# HELP netbox_gitops_objects_total Objects processed per resource type and action.")
+	fmt.Fprintln(w, "# TYPE netbox_gitops_objects_total counter")
+	for _, resource := range sortedKeys(r.objects) {
+		for _, action := range []string{client.ActionCreated, client.ActionUpdated, client.ActionDeleted, client.ActionUnchanged} {
+			fmt.Fprintf(w, "netbox_gitops_objects_total{resource=%q,action=%q} %d\n", resource, action, r.objects[resource][action])
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP netbox_gitops_syncs_total Completed sync cycles by result.")
+	fmt.Fprintln(w, "# TYPE netbox_gitops_syncs_total counter")
+	for _, result := range []string{"success", "failure"} {
+		fmt.Fprintf(w, "netbox_gitops_syncs_total{result=%q} %d\n", result, r.syncs[result])
+	}
+
+	fmt.Fprintln(w, "# HELP netbox_gitops_last_sync_success_timestamp_seconds Unix time of the last successful sync.")
+	fmt.Fprintln(w, "# TYPE netbox_gitops_last_sync_success_timestamp_seconds gauge")
+	lastSuccess := 0.0
+	if !r.lastSuccess.IsZero() {
+		lastSuccess = float64(r.lastSuccess.UnixNano()) / 1e9
+	}
+	fmt.Fprintf(w, "netbox_gitops_last_sync_success_timestamp_seconds %f\n", lastSuccess)
+
+	fmt.Fprintln(w, "# HELP netbox_gitops_sync_duration_seconds Duration of the last sync cycle.")
+	fmt.Fprintln(w, "# TYPE netbox_gitops_sync_duration_seconds gauge")
+	fmt.Fprintf(w, "netbox_gitops_sync_duration_seconds %f\n", r.lastDuration.Seconds())
+}
+
+// Server serves the registry on /metrics
+type Server struct {
+	httpServer *http.Server
+	listener   net.Listener
+	logger     *utils.Logger
+}
+
+// NewServer creates a metrics server listening on addr (e.g., ":9090"), logging serve errors to logger
+func NewServer(addr string, registry *Registry, logger *utils.Logger) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry)
+
+	return &Server{
+		httpServer: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// Start begins listening and serves requests in the background
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.httpServer.Addr, err)
+	}
+	s.listener = listener
+
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Metrics server stopped", err)
+		}
+	}()
+
+	return nil
+}
+
+// Addr returns the address the server is listening on
+func (s *Server) Addr() string {
+	if s.listener == nil {
+		return s.httpServer.Addr
+	}
+	return s.listener.Addr().String()
+}
+
+// Shutdown stops the server, waiting for in-flight scrapes to finish
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
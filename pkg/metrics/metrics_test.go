@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+	"github.com/braunma/netbox-gitops-controller/pkg/reconciler"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+func TestMetricsEndpointAfterSyncCycle(t *testing.T) {
+	netbox := netboxtest.NewServer()
+	defer netbox.Close()
+	netbox.Add("ipam", "vrfs", map[string]interface{}{"name": "Existing"})
+
+	registry := NewRegistry()
+	server := NewServer("127.0.0.1:0", registry, utils.NewLogger(false))
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			t.Errorf("Shutdown() error = %v", err)
+		}
+	}()
+
+	// One sync cycle: create one VRF and update another
+	start := time.Now()
+	c, err := client.NewClient(netbox.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	vrfs := []*models.VRF{{Name: "Existing", RD: "65000:1"}, {Name: "New"}}
+	syncErr := reconciler.NewNetworkReconciler(c).ReconcileVRFs(vrfs)
+	if syncErr != nil {
+		t.Fatalf("ReconcileVRFs() error = %v", syncErr)
+	}
+	registry.ObserveSync(c.Stats(), time.Since(start), syncErr)
+
+	resp, err := http.Get("http://" + server.Addr() + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	samples := parseSamples(t, resp.Body)
+
+	expected := map[string]float64{
+		`netbox_gitops_objects_total{resource="vrfs",action="created"}`: 1,
+		`netbox_gitops_objects_total{resource="vrfs",action="updated"}`: 1,
+		`netbox_gitops_objects_total{resource="vrfs",action="deleted"}`: 0,
+		`netbox_gitops_syncs_total{result="success"}`:                   1,
+	}
+	for name, want := range expected {
+		got, ok := samples[name]
+		if !ok {
+			t.Errorf("Metric %s missing", name)
+			continue
+		}
+		if got != want {
+			t.Errorf("Metric %s = %v, expected %v", name, got, want)
+		}
+	}
+
+	if ts := samples["netbox_gitops_last_sync_success_timestamp_seconds"]; ts < float64(start.Unix()) {
+		t.Errorf("last_sync_success_timestamp = %v, expected >= %d", ts, start.Unix())
+	}
+	if _, ok := samples["netbox_gitops_sync_duration_seconds"]; !ok {
+		t.Error("Metric netbox_gitops_sync_duration_seconds missing")
+	}
+}
+
+// parseSamples parses Prometheus text format into sample name (with labels) → value
+func parseSamples(t *testing.T, r io.Reader) map[string]float64 {
+	t.Helper()
+
+	samples := make(map[string]float64)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		idx := strings.LastIndex(line, " ")
+		if idx < 0 {
+			t.Fatalf("Malformed sample line: %q", line)
+		}
+		value, err := strconv.ParseFloat(line[idx+1:], 64)
+		if err != nil {
+			t.Fatalf("Unparseable value in %q: %v", line, err)
+		}
+		samples[line[:idx]] = value
+	}
+	return samples
+}
@@ -0,0 +1,46 @@
+package models
+
+// Provider represents a circuit provider (e.g., a carrier or ISP)
+type Provider struct {
+	Name        string   `yaml:"name" json:"name" validate:"required"`
+	Slug        string   `yaml:"slug" json:"slug" validate:"required"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Comments    string   `yaml:"comments,omitempty" json:"comments,omitempty"`
+	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// CircuitType represents a kind of circuit (e.g., "Internet Transit", "MPLS")
+type CircuitType struct {
+	Name        string   `yaml:"name" json:"name" validate:"required"`
+	Slug        string   `yaml:"slug" json:"slug" validate:"required"`
+	Color       string   `yaml:"color,omitempty" json:"color,omitempty"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// Circuit represents a provider circuit, identified by its circuit ID within the provider
+type Circuit struct {
+	CID          string               `yaml:"cid" json:"cid" validate:"required"`
+	Provider     string               `yaml:"provider" json:"provider" validate:"required"`
+	Type         string               `yaml:"type" json:"type" validate:"required"`
+	Status       string               `yaml:"status,omitempty" json:"status,omitempty"`
+	Tenant       string               `yaml:"tenant,omitempty" json:"tenant,omitempty"`
+	CommitRate   int                  `yaml:"commit_rate,omitempty" json:"commit_rate,omitempty"`
+	Description  string               `yaml:"description,omitempty" json:"description,omitempty"`
+	Comments     string               `yaml:"comments,omitempty" json:"comments,omitempty"`
+	Tags         []string             `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Terminations []CircuitTermination `yaml:"terminations,omitempty" json:"terminations,omitempty"`
+}
+
+// CircuitTermination connects one side (A or Z) of a circuit to a site.
+// An optional link cables the termination to a device port at that site.
+type CircuitTermination struct {
+	Side          string      `yaml:"side" json:"side" validate:"required"`
+	Site          string      `yaml:"site" json:"site" validate:"required"`
+	PortSpeed     int         `yaml:"port_speed,omitempty" json:"port_speed,omitempty"`
+	UpstreamSpeed int         `yaml:"upstream_speed,omitempty" json:"upstream_speed,omitempty"`
+	XConnectID    string      `yaml:"xconnect_id,omitempty" json:"xconnect_id,omitempty"`
+	PPInfo        string      `yaml:"pp_info,omitempty" json:"pp_info,omitempty"`
+	Description   string      `yaml:"description,omitempty" json:"description,omitempty"`
+	Link          *LinkConfig `yaml:"link,omitempty" json:"link,omitempty"`
+}
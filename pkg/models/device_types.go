@@ -9,10 +9,13 @@ type InterfaceTemplate struct {
 }
 
 // PortTemplate represents a port template for patch panels (Front/Rear)
+// Positions applies to rear ports, RearPortPosition to front ports; both default to 1
 type PortTemplate struct {
-	Name     string `yaml:"name" json:"name" validate:"required"`
-	Type     string `yaml:"type" json:"type" validate:"required"`
-	RearPort string `yaml:"rear_port,omitempty" json:"rear_port,omitempty"`
+	Name             string `yaml:"name" json:"name" validate:"required"`
+	Type             string `yaml:"type" json:"type" validate:"required"`
+	RearPort         string `yaml:"rear_port,omitempty" json:"rear_port,omitempty"`
+	RearPortPosition int    `yaml:"rear_port_position,omitempty" json:"rear_port_position,omitempty"`
+	Positions        int    `yaml:"positions,omitempty" json:"positions,omitempty"`
 }
 
 // ModuleBayTemplate represents a module bay template (e.g., for GPUs)
@@ -30,6 +33,18 @@ type DeviceBayTemplate struct {
 	Description string `yaml:"description,omitempty" json:"description,omitempty"`
 }
 
+// InventoryItemTemplate represents a standard component (e.g., a fixed optic) that NetBox
+// adds as an inventory item to every device created from the device type.
+// Role references an inventory item role by slug, Manufacturer a manufacturer by name.
+type InventoryItemTemplate struct {
+	Name         string `yaml:"name" json:"name" validate:"required"`
+	Label        string `yaml:"label,omitempty" json:"label,omitempty"`
+	Role         string `yaml:"role,omitempty" json:"role,omitempty"`
+	Manufacturer string `yaml:"manufacturer,omitempty" json:"manufacturer,omitempty"`
+	PartID       string `yaml:"part_id,omitempty" json:"part_id,omitempty"`
+	Description  string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
 // ModuleType represents a blueprint for a module (e.g., NVIDIA H200)
 type ModuleType struct {
 	Model        string   `yaml:"model" json:"model" validate:"required"`
@@ -37,20 +52,27 @@ type ModuleType struct {
 	Manufacturer string   `yaml:"manufacturer" json:"manufacturer" validate:"required"`
 	Description  string   `yaml:"description,omitempty" json:"description,omitempty"`
 	Tags         []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	// ModuleBays are created on every installed module (e.g., optic slots of a line card)
+	ModuleBays []ModuleBayTemplate `yaml:"module_bays,omitempty" json:"module_bays,omitempty"`
 }
 
 // DeviceType represents a device type definition (blueprint for devices)
 type DeviceType struct {
-	Model         string                  `yaml:"model" json:"model" validate:"required"`
-	Slug          string                  `yaml:"slug" json:"slug" validate:"required"`
-	Manufacturer  string                  `yaml:"manufacturer" json:"manufacturer" validate:"required"`
-	UHeight       int                     `yaml:"u_height,omitempty" json:"u_height,omitempty"`
-	IsFullDepth   bool                    `yaml:"is_full_depth,omitempty" json:"is_full_depth,omitempty"`
-	SubdeviceRole string                  `yaml:"subdevice_role,omitempty" json:"subdevice_role,omitempty"`
-	Tags          []string                `yaml:"tags,omitempty" json:"tags,omitempty"`
-	Interfaces    []InterfaceTemplate     `yaml:"interfaces,omitempty" json:"interfaces,omitempty"`
-	FrontPorts    []PortTemplate          `yaml:"front_ports,omitempty" json:"front_ports,omitempty"`
-	RearPorts     []PortTemplate          `yaml:"rear_ports,omitempty" json:"rear_ports,omitempty"`
-	ModuleBays    []ModuleBayTemplate     `yaml:"module_bays,omitempty" json:"module_bays,omitempty"`
-	DeviceBays    []DeviceBayTemplate     `yaml:"device_bays,omitempty" json:"device_bays,omitempty"`
+	Model          string                  `yaml:"model" json:"model" validate:"required"`
+	Slug           string                  `yaml:"slug" json:"slug" validate:"required"`
+	Manufacturer   string                  `yaml:"manufacturer" json:"manufacturer" validate:"required"`
+	UHeight        int                     `yaml:"u_height,omitempty" json:"u_height,omitempty"`
+	IsFullDepth    bool                    `yaml:"is_full_depth,omitempty" json:"is_full_depth,omitempty"`
+	SubdeviceRole  string                  `yaml:"subdevice_role,omitempty" json:"subdevice_role,omitempty"`
+	PartNumber     string                  `yaml:"part_number,omitempty" json:"part_number,omitempty"`
+	Airflow        string                  `yaml:"airflow,omitempty" json:"airflow,omitempty"` // e.g. front-to-rear
+	Weight         float64                 `yaml:"weight,omitempty" json:"weight,omitempty"`
+	WeightUnit     string                  `yaml:"weight_unit,omitempty" json:"weight_unit,omitempty"` // kg, g, lb or oz
+	Tags           []string                `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Interfaces     []InterfaceTemplate     `yaml:"interfaces,omitempty" json:"interfaces,omitempty"`
+	FrontPorts     []PortTemplate          `yaml:"front_ports,omitempty" json:"front_ports,omitempty"`
+	RearPorts      []PortTemplate          `yaml:"rear_ports,omitempty" json:"rear_ports,omitempty"`
+	ModuleBays     []ModuleBayTemplate     `yaml:"module_bays,omitempty" json:"module_bays,omitempty"`
+	DeviceBays     []DeviceBayTemplate     `yaml:"device_bays,omitempty" json:"device_bays,omitempty"`
+	InventoryItems []InventoryItemTemplate `yaml:"inventory_items,omitempty" json:"inventory_items,omitempty"`
 }
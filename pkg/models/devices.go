@@ -1,13 +1,27 @@
 package models
 
+import "github.com/braunma/netbox-gitops-controller/pkg/utils"
+
 // LinkConfig represents a cable connection definition
 type LinkConfig struct {
-	PeerDevice string  `yaml:"peer_device" json:"peer_device" validate:"required"`
-	PeerPort   string  `yaml:"peer_port" json:"peer_port" validate:"required"`
-	CableType  string  `yaml:"cable_type,omitempty" json:"cable_type,omitempty"`
-	Color      string  `yaml:"color,omitempty" json:"color,omitempty"`
-	Length     float64 `yaml:"length,omitempty" json:"length,omitempty"`
-	LengthUnit string  `yaml:"length_unit,omitempty" json:"length_unit,omitempty"`
+	PeerDevice string `yaml:"peer_device" json:"peer_device" validate:"required"`
+	PeerPort   string `yaml:"peer_port" json:"peer_port" validate:"required"`
+	PeerPortBy string `yaml:"peer_port_by,omitempty" json:"peer_port_by,omitempty"` // Port field to match peer ports by: name (default) or label
+	// PeerType makes the peer an unmanaged endpoint that is only looked up, never changed:
+	// dcim.rearport (peer_device/peer_port name a rear port of a device outside the inventory)
+	// or circuits.circuittermination (peer_device is the circuit ID, peer_port the side, A or Z)
+	PeerType string `yaml:"peer_type,omitempty" json:"peer_type,omitempty"`
+	// PeerPorts are further ports of the peer device on the same cable (breakout cables)
+	PeerPorts  []string `yaml:"peer_ports,omitempty" json:"peer_ports,omitempty"`
+	CableType  string   `yaml:"cable_type,omitempty" json:"cable_type,omitempty"`
+	Color      string   `yaml:"color,omitempty" json:"color,omitempty"`
+	Length     float64  `yaml:"length,omitempty" json:"length,omitempty"`
+	LengthUnit string   `yaml:"length_unit,omitempty" json:"length_unit,omitempty"`
+}
+
+// PeerPortNames returns all peer ports terminating the cable's far end
+func (l *LinkConfig) PeerPortNames() []string {
+	return append([]string{l.PeerPort}, l.PeerPorts...)
 }
 
 // IPConfig represents IP address configuration
@@ -23,20 +37,32 @@ type IPConfig struct {
 
 // InterfaceConfig represents an interface configuration (for concrete devices)
 type InterfaceConfig struct {
-	Name         string       `yaml:"name" json:"name" validate:"required"`
-	Type         string       `yaml:"type,omitempty" json:"type,omitempty"`
-	Enabled      bool         `yaml:"enabled,omitempty" json:"enabled,omitempty"`
-	Label        string       `yaml:"label,omitempty" json:"label,omitempty"`
-	Description  string       `yaml:"description,omitempty" json:"description,omitempty"`
-	MTU          int          `yaml:"mtu,omitempty" json:"mtu,omitempty"`
-	Link         *LinkConfig  `yaml:"link,omitempty" json:"link,omitempty"`
-	Mode         string       `yaml:"mode,omitempty" json:"mode,omitempty"`
-	UntaggedVLAN string       `yaml:"untagged_vlan,omitempty" json:"untagged_vlan,omitempty"`
-	TaggedVLANs  []string     `yaml:"tagged_vlans,omitempty" json:"tagged_vlans,omitempty"`
-	IP           *IPConfig    `yaml:"ip,omitempty" json:"ip,omitempty"`
-	AddressRole  string       `yaml:"address_role,omitempty" json:"address_role,omitempty"`
-	Members      []string     `yaml:"members,omitempty" json:"members,omitempty"`
-	Tags         []string     `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Name           string      `yaml:"name" json:"name" validate:"required"`
+	Type           string      `yaml:"type,omitempty" json:"type,omitempty"`
+	Enabled        *bool       `yaml:"enabled,omitempty" json:"enabled,omitempty"`     // nil (omitted) means enabled
+	MgmtOnly       *bool       `yaml:"mgmt_only,omitempty" json:"mgmt_only,omitempty"` // nil (omitted) leaves it as it is in NetBox
+	Label          string      `yaml:"label,omitempty" json:"label,omitempty"`
+	Description    string      `yaml:"description,omitempty" json:"description,omitempty"`
+	MTU            int         `yaml:"mtu,omitempty" json:"mtu,omitempty"`
+	WWN            string      `yaml:"wwn,omitempty" json:"wwn,omitempty"`                           // Fibre Channel World Wide Name
+	TxPower        int         `yaml:"tx_power,omitempty" json:"tx_power,omitempty"`                 // dBm, wireless only
+	RFChannel      string      `yaml:"rf_channel,omitempty" json:"rf_channel,omitempty"`             // e.g. 5g-36-5180-20, wireless only
+	RFChannelWidth float64     `yaml:"rf_channel_width,omitempty" json:"rf_channel_width,omitempty"` // MHz, wireless only
+	Link           *LinkConfig `yaml:"link,omitempty" json:"link,omitempty"`
+	Mode           string      `yaml:"mode,omitempty" json:"mode,omitempty"`
+	UntaggedVLAN   string      `yaml:"untagged_vlan,omitempty" json:"untagged_vlan,omitempty"`
+	TaggedVLANs    []string    `yaml:"tagged_vlans,omitempty" json:"tagged_vlans,omitempty"`
+	IP             *IPConfig   `yaml:"ip,omitempty" json:"ip,omitempty"`
+	AddressRole    string      `yaml:"address_role,omitempty" json:"address_role,omitempty"`
+	Members        []string    `yaml:"members,omitempty" json:"members,omitempty"`
+	Bridge         string      `yaml:"bridge,omitempty" json:"bridge,omitempty"`
+	LAG            string      `yaml:"lag,omitempty" json:"lag,omitempty"` // parent LAG interface on the same device
+	Tags           []string    `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// IsEnabled reports whether the interface is administratively up; interfaces are enabled unless "enabled: false"
+func (i *InterfaceConfig) IsEnabled() bool {
+	return i.Enabled == nil || *i.Enabled
 }
 
 // RearPortConfig represents a rear port configuration (Backbone)
@@ -63,17 +89,32 @@ type FrontPortConfig struct {
 }
 
 // ModuleConfig represents a module configuration (e.g., installed GPU)
+// Serial and AssetTag are managed only when present in YAML, like on DeviceConfig.
 type ModuleConfig struct {
 	Name           string   `yaml:"name" json:"name" validate:"required"`
 	ModuleTypeSlug string   `yaml:"module_type_slug" json:"module_type_slug" validate:"required"`
 	Status         string   `yaml:"status,omitempty" json:"status,omitempty"`
-	Serial         string   `yaml:"serial,omitempty" json:"serial,omitempty"`
-	AssetTag       string   `yaml:"asset_tag,omitempty" json:"asset_tag,omitempty"`
+	Serial         *string  `yaml:"serial,omitempty" json:"serial,omitempty"`
+	AssetTag       *string  `yaml:"asset_tag,omitempty" json:"asset_tag,omitempty"`
 	Description    string   `yaml:"description,omitempty" json:"description,omitempty"`
 	Tags           []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	// Modules are installed into the module bays provided by this module's type
+	Modules []ModuleConfig `yaml:"modules,omitempty" json:"modules,omitempty"`
+}
+
+// ServiceConfig represents a service (listening ports) running on a device
+type ServiceConfig struct {
+	Name        string   `yaml:"name" json:"name" validate:"required"`
+	Protocol    string   `yaml:"protocol" json:"protocol" validate:"required"` // tcp, udp, sctp
+	Ports       []int    `yaml:"ports" json:"ports" validate:"required"`
+	IPAddresses []string `yaml:"ipaddresses,omitempty" json:"ipaddresses,omitempty"` // Bind to these device IPs (CIDR)
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
 }
 
 // DeviceConfig represents a device configuration (concrete device)
+// Serial and AssetTag are managed only when present in YAML: an empty string
+// clears the field in NetBox, an absent (or null) field is left untouched.
+// DeviceTypeSlug also accepts the device type's model name (e.g. "PowerEdge R740").
 type DeviceConfig struct {
 	Name           string              `yaml:"name" json:"name" validate:"required"`
 	SiteSlug       string              `yaml:"site_slug" json:"site_slug" validate:"required"`
@@ -84,17 +125,39 @@ type DeviceConfig struct {
 	Face           string              `yaml:"face,omitempty" json:"face,omitempty"`
 	ParentDevice   string              `yaml:"parent_device,omitempty" json:"parent_device,omitempty"`
 	DeviceBay      string              `yaml:"device_bay,omitempty" json:"device_bay,omitempty"`
+	DependsOn      []string            `yaml:"depends_on,omitempty" json:"depends_on,omitempty"` // Devices reconciled before this one
 	Status         string              `yaml:"status,omitempty" json:"status,omitempty"`
-	Serial         string              `yaml:"serial,omitempty" json:"serial,omitempty"`
-	AssetTag       string              `yaml:"asset_tag,omitempty" json:"asset_tag,omitempty"`
+	Serial         *string             `yaml:"serial,omitempty" json:"serial,omitempty"`
+	AssetTag       *string             `yaml:"asset_tag,omitempty" json:"asset_tag,omitempty"`
+	ClusterName    string              `yaml:"cluster_name,omitempty" json:"cluster_name,omitempty"`
+	ConfigTemplate string              `yaml:"config_template,omitempty" json:"config_template,omitempty"` // Config template name
+	VirtualChassis string              `yaml:"virtual_chassis,omitempty" json:"virtual_chassis,omitempty"`
+	VCPosition     int                 `yaml:"vc_position,omitempty" json:"vc_position,omitempty"`
+	VCPriority     int                 `yaml:"vc_priority,omitempty" json:"vc_priority,omitempty"`
 	Tags           []string            `yaml:"tags,omitempty" json:"tags,omitempty"`
 	Modules        []ModuleConfig      `yaml:"modules,omitempty" json:"modules,omitempty"`
 	Interfaces     []InterfaceConfig   `yaml:"interfaces,omitempty" json:"interfaces,omitempty"`
 	FrontPorts     []FrontPortConfig   `yaml:"front_ports,omitempty" json:"front_ports,omitempty"`
 	RearPorts      []RearPortConfig    `yaml:"rear_ports,omitempty" json:"rear_ports,omitempty"`
+	Contacts       []ContactAssignment `yaml:"contacts,omitempty" json:"contacts,omitempty"`
+	Services       []ServiceConfig     `yaml:"services,omitempty" json:"services,omitempty"`
+	// ManagementIPs are IPs not on a declared interface. They are assigned to an implicit
+	// virtual interface (mgmt0), since NetBox only accepts a primary IP assigned to the
+	// device; address_role: primary makes one the device's primary IP.
+	ManagementIPs []IPConfig `yaml:"management_ips,omitempty" json:"management_ips,omitempty"`
+}
+
+// VirtualChassis represents a stack of devices managed as one (e.g., stacked switches).
+// Members reference the chassis by name via DeviceConfig.VirtualChassis.
+type VirtualChassis struct {
+	Name        string   `yaml:"name" json:"name" validate:"required"`
+	Domain      string   `yaml:"domain,omitempty" json:"domain,omitempty"`
+	Master      string   `yaml:"master,omitempty" json:"master,omitempty"` // Member device name
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
 }
 
 // Slug generates a slug from the device name
 func (d *DeviceConfig) Slug() string {
-	return slugify(d.Name)
+	return utils.Slugify(d.Name)
 }
@@ -0,0 +1,59 @@
+package models
+
+// Webhook represents a NetBox webhook.
+// Events and ObjectTypes define the event rule that triggers the webhook.
+type Webhook struct {
+	Name              string   `yaml:"name" json:"name" validate:"required"`
+	PayloadURL        string   `yaml:"payload_url" json:"payload_url" validate:"required"`
+	HTTPMethod        string   `yaml:"http_method,omitempty" json:"http_method,omitempty"`
+	HTTPContentType   string   `yaml:"http_content_type,omitempty" json:"http_content_type,omitempty"`
+	AdditionalHeaders string   `yaml:"additional_headers,omitempty" json:"additional_headers,omitempty"`
+	BodyTemplate      string   `yaml:"body_template,omitempty" json:"body_template,omitempty"`
+	Description       string   `yaml:"description,omitempty" json:"description,omitempty"`
+	ObjectTypes       []string `yaml:"object_types,omitempty" json:"object_types,omitempty"` // e.g. dcim.device
+	Events            []string `yaml:"events,omitempty" json:"events,omitempty"`             // create, update, delete
+}
+
+// ExportTemplate represents a NetBox export template
+type ExportTemplate struct {
+	Name          string   `yaml:"name" json:"name" validate:"required"`
+	ObjectTypes   []string `yaml:"object_types" json:"object_types" validate:"required"`
+	TemplateCode  string   `yaml:"template_code" json:"template_code" validate:"required"`
+	MimeType      string   `yaml:"mime_type,omitempty" json:"mime_type,omitempty"`
+	FileExtension string   `yaml:"file_extension,omitempty" json:"file_extension,omitempty"`
+	AsAttachment  bool     `yaml:"as_attachment,omitempty" json:"as_attachment,omitempty"`
+	Description   string   `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// ConfigTemplate represents a NetBox config template, which devices reference by name
+// to render their configuration
+type ConfigTemplate struct {
+	Name              string                 `yaml:"name" json:"name" validate:"required"`
+	TemplateCode      string                 `yaml:"template_code" json:"template_code" validate:"required"`
+	EnvironmentParams map[string]interface{} `yaml:"environment_params,omitempty" json:"environment_params,omitempty"` // Jinja2 environment options
+	Description       string                 `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags              []string               `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// CustomFieldChoiceSet represents a named list of choices for selection custom fields
+type CustomFieldChoiceSet struct {
+	Name                string   `yaml:"name" json:"name" validate:"required"`
+	Choices             []string `yaml:"choices" json:"choices" validate:"required"` // each value is also its label
+	OrderAlphabetically bool     `yaml:"order_alphabetically,omitempty" json:"order_alphabetically,omitempty"`
+	Description         string   `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// CustomFieldDef represents the definition of a custom field (not its values on objects).
+// Selection fields (select, multiselect) reference a choice set by name.
+type CustomFieldDef struct {
+	Name        string      `yaml:"name" json:"name" validate:"required"`
+	Label       string      `yaml:"label,omitempty" json:"label,omitempty"`
+	Type        string      `yaml:"type" json:"type" validate:"required"`                 // text, integer, boolean, select, ...
+	ObjectTypes []string    `yaml:"object_types" json:"object_types" validate:"required"` // content types, e.g. dcim.device
+	Required    bool        `yaml:"required,omitempty" json:"required,omitempty"`
+	Default     interface{} `yaml:"default,omitempty" json:"default,omitempty"`
+	ChoiceSet   string      `yaml:"choice_set,omitempty" json:"choice_set,omitempty"`
+	GroupName   string      `yaml:"group_name,omitempty" json:"group_name,omitempty"`
+	Weight      int         `yaml:"weight,omitempty" json:"weight,omitempty"`
+	Description string      `yaml:"description,omitempty" json:"description,omitempty"`
+}
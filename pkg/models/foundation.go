@@ -1,18 +1,23 @@
 package models
 
-// Site represents a NetBox site
+// Site represents a NetBox site.
+// Description and Comments are managed only when present in YAML: an empty
+// string clears the field in NetBox, an absent (or null) field is left untouched.
 type Site struct {
-	Name        string   `yaml:"name" json:"name" validate:"required"`
-	Slug        string   `yaml:"slug" json:"slug" validate:"required"`
-	Status      string   `yaml:"status,omitempty" json:"status,omitempty"`
-	Region      string   `yaml:"region,omitempty" json:"region,omitempty"`
-	TimeZone    string   `yaml:"time_zone,omitempty" json:"time_zone,omitempty"`
-	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
-	Comments    string   `yaml:"comments,omitempty" json:"comments,omitempty"`
-	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Name        string              `yaml:"name" json:"name" validate:"required"`
+	Slug        string              `yaml:"slug" json:"slug" validate:"required"`
+	Status      string              `yaml:"status,omitempty" json:"status,omitempty"`
+	Region      string              `yaml:"region,omitempty" json:"region,omitempty"`
+	TimeZone    string              `yaml:"time_zone,omitempty" json:"time_zone,omitempty"`
+	Description *string             `yaml:"description,omitempty" json:"description,omitempty"`
+	Comments    *string             `yaml:"comments,omitempty" json:"comments,omitempty"`
+	Tags        []string            `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Contacts    []ContactAssignment `yaml:"contacts,omitempty" json:"contacts,omitempty"`
 }
 
-// Rack represents a NetBox rack
+// Rack represents a NetBox rack.
+// FacilityID (the data center's own rack label) and AssetTag are managed only when present
+// in YAML: an empty string clears the field in NetBox, an absent field is left untouched.
 type Rack struct {
 	Name        string   `yaml:"name" json:"name" validate:"required"`
 	Slug        string   `yaml:"slug" json:"slug"`
@@ -20,19 +25,39 @@ type Rack struct {
 	Status      string   `yaml:"status,omitempty" json:"status,omitempty"`
 	Width       int      `yaml:"width,omitempty" json:"width,omitempty"`
 	UHeight     int      `yaml:"u_height,omitempty" json:"u_height,omitempty"`
+	FacilityID  *string  `yaml:"facility_id,omitempty" json:"facility_id,omitempty"`
+	AssetTag    *string  `yaml:"asset_tag,omitempty" json:"asset_tag,omitempty"`
 	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
 	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
 }
 
+// RoleGroup represents a group of device roles (a parent role in NetBox's role hierarchy)
+type RoleGroup struct {
+	Name        string `yaml:"name" json:"name" validate:"required"`
+	Slug        string `yaml:"slug" json:"slug" validate:"required"`
+	Color       string `yaml:"color" json:"color" validate:"required"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
 // Role represents a device role
+// Color may be omitted for roles in a group, which then inherit the group's color.
 type Role struct {
 	Name        string `yaml:"name" json:"name" validate:"required"`
 	Slug        string `yaml:"slug" json:"slug" validate:"required"`
-	Color       string `yaml:"color" json:"color" validate:"required"`
+	Group       string `yaml:"group,omitempty" json:"group,omitempty"`
+	Color       string `yaml:"color,omitempty" json:"color,omitempty" validate:"required_without=Group"`
 	VMRole      bool   `yaml:"vm_role,omitempty" json:"vm_role,omitempty"`
 	Description string `yaml:"description,omitempty" json:"description,omitempty"`
 }
 
+// InventoryItemRole represents the role of an inventory item (e.g., optic, PSU)
+type InventoryItemRole struct {
+	Name        string `yaml:"name" json:"name" validate:"required"`
+	Slug        string `yaml:"slug" json:"slug" validate:"required"`
+	Color       string `yaml:"color" json:"color" validate:"required"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
 // Tag represents a NetBox tag
 type Tag struct {
 	Name        string `yaml:"name" json:"name" validate:"required"`
@@ -41,6 +66,18 @@ type Tag struct {
 	Description string `yaml:"description,omitempty" json:"description,omitempty"`
 }
 
+// ConfigContext represents a NetBox config context.
+// Data is free-form JSON; Roles, Sites and Tags restrict which devices it applies to.
+type ConfigContext struct {
+	Name        string                 `yaml:"name" json:"name" validate:"required"`
+	Weight      int                    `yaml:"weight,omitempty" json:"weight,omitempty"`
+	Description string                 `yaml:"description,omitempty" json:"description,omitempty"`
+	Data        map[string]interface{} `yaml:"data" json:"data" validate:"required"`
+	Roles       []string               `yaml:"roles,omitempty" json:"roles,omitempty"`
+	Sites       []string               `yaml:"sites,omitempty" json:"sites,omitempty"`
+	Tags        []string               `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
 // Manufacturer represents a hardware manufacturer
 type Manufacturer struct {
 	Name        string   `yaml:"name" json:"name" validate:"required"`
@@ -25,6 +25,11 @@ func TestVRFSlug(t *testing.T) {
 			vrfName:  "MANAGEMENT",
 			expected: "management",
 		},
+		{
+			name:     "special characters",
+			vrfName:  "Prod/DMZ (Tenant A)",
+			expected: "prod-dmz-tenant-a",
+		},
 	}
 
 	for _, tt := range tests {
@@ -59,6 +64,11 @@ func TestDeviceConfigSlug(t *testing.T) {
 			deviceName: "Web Server 01",
 			expected:   "web-server-01",
 		},
+		{
+			name:       "fully qualified name",
+			deviceName: "srv01.example.com",
+			expected:   "srv01-example-com",
+		},
 	}
 
 	for _, tt := range tests {
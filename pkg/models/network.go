@@ -1,16 +1,28 @@
 package models
 
-import "strings"
+import "github.com/braunma/netbox-gitops-controller/pkg/utils"
 
 // VLAN represents a NetBox VLAN
+// VLANs are scoped to a site, or to a VLAN group when site_slug is omitted
 type VLAN struct {
 	Name        string   `yaml:"name" json:"name" validate:"required"`
 	VID         int      `yaml:"vid" json:"vid" validate:"required,min=1,max=4094"`
-	SiteSlug    string   `yaml:"site_slug" json:"site_slug" validate:"required"`
+	SiteSlug    string   `yaml:"site_slug,omitempty" json:"site_slug,omitempty"`
 	GroupSlug   string   `yaml:"group_slug,omitempty" json:"group_slug,omitempty"`
 	Status      string   `yaml:"status,omitempty" json:"status,omitempty"`
 	Role        string   `yaml:"role,omitempty" json:"role,omitempty"`
 	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	QinQRole    string   `yaml:"qinq_role,omitempty" json:"qinq_role,omitempty"`   // svlan or cvlan (NetBox 4.1+)
+	QinQSVLAN   string   `yaml:"qinq_svlan,omitempty" json:"qinq_svlan,omitempty"` // name of the S-VLAN of a C-VLAN, in the same site or group
+	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// IPAMRole represents a functional role for prefixes and VLANs (ipam/roles)
+type IPAMRole struct {
+	Name        string   `yaml:"name" json:"name" validate:"required"`
+	Slug        string   `yaml:"slug" json:"slug" validate:"required"`
+	Weight      int      `yaml:"weight,omitempty" json:"weight,omitempty"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
 	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
 }
 
@@ -19,6 +31,8 @@ type VLANGroup struct {
 	Name        string   `yaml:"name" json:"name" validate:"required"`
 	Slug        string   `yaml:"slug" json:"slug" validate:"required"`
 	SiteSlug    string   `yaml:"site_slug,omitempty" json:"site_slug,omitempty"`
+	ScopeType   string   `yaml:"scope_type,omitempty" json:"scope_type,omitempty"` // region, site_group, site, location, rack, cluster_group or cluster
+	ScopeSlug   string   `yaml:"scope_slug,omitempty" json:"scope_slug,omitempty"` // Slug of the scope object (name for racks and clusters)
 	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
 	MinVID      int      `yaml:"min_vid,omitempty" json:"min_vid,omitempty"`
 	MaxVID      int      `yaml:"max_vid,omitempty" json:"max_vid,omitempty"`
@@ -27,34 +41,68 @@ type VLANGroup struct {
 
 // VRF represents a NetBox VRF
 type VRF struct {
-	Name         string   `yaml:"name" json:"name" validate:"required"`
-	RD           string   `yaml:"rd,omitempty" json:"rd,omitempty"`
-	Description  string   `yaml:"description,omitempty" json:"description,omitempty"`
-	EnforceUnique bool    `yaml:"enforce_unique,omitempty" json:"enforce_unique,omitempty"`
-	Tags         []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Name          string   `yaml:"name" json:"name" validate:"required"`
+	RD            string   `yaml:"rd,omitempty" json:"rd,omitempty"`
+	Description   string   `yaml:"description,omitempty" json:"description,omitempty"`
+	EnforceUnique bool     `yaml:"enforce_unique,omitempty" json:"enforce_unique,omitempty"`
+	Tags          []string `yaml:"tags,omitempty" json:"tags,omitempty"`
 }
 
 // Slug generates a slug from the VRF name
 func (v *VRF) Slug() string {
-	return slugify(v.Name)
+	return utils.Slugify(v.Name)
 }
 
-// Prefix represents an IP prefix
-type Prefix struct {
-	Prefix      string   `yaml:"prefix" json:"prefix" validate:"required"`
-	SiteSlug    string   `yaml:"site_slug,omitempty" json:"site_slug,omitempty"`
-	VRFName     string   `yaml:"vrf_name,omitempty" json:"vrf_name,omitempty"`
-	VLANName    string   `yaml:"vlan_name,omitempty" json:"vlan_name,omitempty"`
-	Status      string   `yaml:"status,omitempty" json:"status,omitempty"`
-	Role        string   `yaml:"role,omitempty" json:"role,omitempty"`
-	IsPool      bool     `yaml:"is_pool,omitempty" json:"is_pool,omitempty"`
-	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
-	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+// VLANTranslationPolicy represents a VLAN translation policy and its rules (NetBox 4.2+)
+type VLANTranslationPolicy struct {
+	Name        string                `yaml:"name" json:"name" validate:"required"`
+	Description string                `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags        []string              `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Rules       []VLANTranslationRule `yaml:"rules,omitempty" json:"rules,omitempty"`
 }
 
-// slugify converts a string to a slug
-func slugify(s string) string {
-	s = strings.ToLower(s)
-	s = strings.ReplaceAll(s, " ", "-")
-	return s
+// VLANTranslationRule translates the inner (local) VID of an interface to an outer (remote) VID.
+// Rules are matched by policy and both VIDs.
+type VLANTranslationRule struct {
+	LocalVID    int    `yaml:"local_vid" json:"local_vid" validate:"required,min=1,max=4094"`
+	RemoteVID   int    `yaml:"remote_vid" json:"remote_vid" validate:"required,min=1,max=4094"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// FHRPGroup represents a first-hop redundancy group (VRRP, HSRP, ...) and the interfaces
+// it is assigned to. Groups are matched by protocol and group_id, and by name if set, so
+// groups reusing a group ID (e.g. VRRP 10 at every site) need distinct names.
+type FHRPGroup struct {
+	Name        string                `yaml:"name,omitempty" json:"name,omitempty"`
+	Protocol    string                `yaml:"protocol" json:"protocol" validate:"required"` // vrrp2, vrrp3, hsrp, glbp, carp, clusterxl or other
+	GroupID     int                   `yaml:"group_id" json:"group_id" validate:"min=0"`
+	AuthType    string                `yaml:"auth_type,omitempty" json:"auth_type,omitempty"` // plaintext or md5
+	AuthKey     string                `yaml:"auth_key,omitempty" json:"auth_key,omitempty"`
+	Description string                `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags        []string              `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Assignments []FHRPGroupAssignment `yaml:"assignments,omitempty" json:"assignments,omitempty"`
+}
+
+// FHRPGroupAssignment binds an FHRP group to a device interface
+type FHRPGroupAssignment struct {
+	Device    string `yaml:"device" json:"device" validate:"required"`
+	Interface string `yaml:"interface" json:"interface" validate:"required"`
+	Priority  *int   `yaml:"priority,omitempty" json:"priority,omitempty"` // 0-255, default DefaultFHRPPriority
+}
+
+// Prefix represents an IP prefix
+// When Prefix is omitted, the next available child of NewPrefixLength is allocated from ParentPrefix.
+type Prefix struct {
+	Prefix          string   `yaml:"prefix,omitempty" json:"prefix,omitempty" validate:"required_without=ParentPrefix"`
+	ParentPrefix    string   `yaml:"parent_prefix,omitempty" json:"parent_prefix,omitempty"`
+	NewPrefixLength int      `yaml:"new_prefix_length,omitempty" json:"new_prefix_length,omitempty"`
+	SiteSlug        string   `yaml:"site_slug,omitempty" json:"site_slug,omitempty"`
+	LocationSlug    string   `yaml:"location_slug,omitempty" json:"location_slug,omitempty"`
+	VRFName         string   `yaml:"vrf_name,omitempty" json:"vrf_name,omitempty"`
+	VLANName        string   `yaml:"vlan_name,omitempty" json:"vlan_name,omitempty"`
+	Status          string   `yaml:"status,omitempty" json:"status,omitempty"`
+	Role            string   `yaml:"role,omitempty" json:"role,omitempty"`
+	IsPool          bool     `yaml:"is_pool,omitempty" json:"is_pool,omitempty"`
+	Description     string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags            []string `yaml:"tags,omitempty" json:"tags,omitempty"`
 }
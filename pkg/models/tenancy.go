@@ -0,0 +1,57 @@
+package models
+
+// ContactGroup represents a group of contacts (e.g., a team)
+type ContactGroup struct {
+	Name        string   `yaml:"name" json:"name" validate:"required"`
+	Slug        string   `yaml:"slug" json:"slug" validate:"required"`
+	Parent      string   `yaml:"parent,omitempty" json:"parent,omitempty"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// ContactRole represents the function a contact serves for an object (e.g., "Owner")
+type ContactRole struct {
+	Name        string   `yaml:"name" json:"name" validate:"required"`
+	Slug        string   `yaml:"slug" json:"slug" validate:"required"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// Contact represents a person or team that can be assigned to objects
+type Contact struct {
+	Name        string   `yaml:"name" json:"name" validate:"required"`
+	Group       string   `yaml:"group,omitempty" json:"group,omitempty"`
+	Title       string   `yaml:"title,omitempty" json:"title,omitempty"`
+	Phone       string   `yaml:"phone,omitempty" json:"phone,omitempty"`
+	Email       string   `yaml:"email,omitempty" json:"email,omitempty"`
+	Address     string   `yaml:"address,omitempty" json:"address,omitempty"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Comments    string   `yaml:"comments,omitempty" json:"comments,omitempty"`
+	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// ContactAssignment references a contact by name with a role, used on sites and devices
+type ContactAssignment struct {
+	Name     string `yaml:"name" json:"name" validate:"required"`
+	Role     string `yaml:"role" json:"role" validate:"required"`
+	Priority string `yaml:"priority,omitempty" json:"priority,omitempty"`
+}
+
+// TenantGroup represents a group of tenants, optionally nested below a parent group
+type TenantGroup struct {
+	Name        string   `yaml:"name" json:"name" validate:"required"`
+	Slug        string   `yaml:"slug" json:"slug" validate:"required"`
+	Parent      string   `yaml:"parent,omitempty" json:"parent,omitempty"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// Tenant represents a customer or department that owns resources
+type Tenant struct {
+	Name        string   `yaml:"name" json:"name" validate:"required"`
+	Slug        string   `yaml:"slug" json:"slug" validate:"required"`
+	Group       string   `yaml:"group,omitempty" json:"group,omitempty"`
+	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Comments    string   `yaml:"comments,omitempty" json:"comments,omitempty"`
+	Tags        []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
@@ -5,6 +5,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
 	"github.com/braunma/netbox-gitops-controller/pkg/client"
 	"github.com/braunma/netbox-gitops-controller/pkg/models"
 	"github.com/braunma/netbox-gitops-controller/pkg/utils"
@@ -40,8 +41,8 @@ var cableColorMap = map[string]string{
 	"green":  "008000",
 }
 
-// normalizeColor converts color names to hex codes and strips # prefix
-// Matches Python utils.py:normalize_color (lines 44-50)
+// normalizeColor converts color names and hex codes ("#0000FF", "00f") to NetBox's
+// 6-char lowercase hex. Unrecognized input is returned lowercased without #.
 func normalizeColor(colorInput string) string {
 	if colorInput == "" {
 		return ""
@@ -55,11 +56,12 @@ func normalizeColor(colorInput string) string {
 		return hexCode
 	}
 
-	// Already hex format, strip # if present
-	raw = strings.TrimPrefix(raw, "#")
+	if hexCode := utils.NormalizeColor(raw); hexCode != "" {
+		return hexCode
+	}
 
-	// Return as-is (could be hex without #, or invalid)
-	return raw
+	// Invalid, let NetBox reject it
+	return strings.TrimPrefix(raw, "#")
 }
 
 // CableEndpoint represents one end of a cable
@@ -68,6 +70,72 @@ type CableEndpoint struct {
 	PortName   string
 	ObjectType string // "dcim.interface", "dcim.frontport", "dcim.rearport"
 	ObjectID   int
+	ObjectIDs  []int // All terminations of a bundled (breakout) end; empty means just ObjectID
+	Unmanaged  bool  // Explicit peer_type endpoint: a blocking cable on it is reported, not deleted
+}
+
+// IDs returns the object IDs of all terminations of the endpoint
+func (e *CableEndpoint) IDs() []int {
+	if len(e.ObjectIDs) > 0 {
+		return e.ObjectIDs
+	}
+	return []int{e.ObjectID}
+}
+
+// split returns one single-termination endpoint per termination
+func (e *CableEndpoint) split() []*CableEndpoint {
+	ids := e.IDs()
+	endpoints := make([]*CableEndpoint, len(ids))
+	for i, id := range ids {
+		endpoints[i] = &CableEndpoint{
+			DeviceName: e.DeviceName,
+			PortName:   e.PortName,
+			ObjectType: e.ObjectType,
+			ObjectID:   id,
+			Unmanaged:  e.Unmanaged,
+		}
+	}
+	return endpoints
+}
+
+// key returns a stable identifier of the endpoint for pair IDs
+func (e *CableEndpoint) key() string {
+	ids := append([]int(nil), e.IDs()...)
+	sort.Ints(ids)
+
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprintf("%d", id)
+	}
+	return fmt.Sprintf("%s:%s:%s", e.ObjectType, e.DeviceName, strings.Join(parts, "+"))
+}
+
+// terminations builds the a_terminations/b_terminations payload of the endpoint
+func (e *CableEndpoint) terminations() []map[string]interface{} {
+	var terms []map[string]interface{}
+	for _, id := range e.IDs() {
+		terms = append(terms, map[string]interface{}{
+			"object_type": e.ObjectType,
+			"object_id":   id,
+		})
+	}
+	return terms
+}
+
+// terminationPath returns the API app and endpoint of a cable termination object type
+func terminationPath(objectType string) (string, string, error) {
+	switch objectType {
+	case "dcim.interface":
+		return "dcim", "interfaces", nil
+	case "dcim.frontport":
+		return "dcim", "front-ports", nil
+	case "dcim.rearport":
+		return "dcim", "rear-ports", nil
+	case "circuits.circuittermination":
+		return "circuits", "circuit-terminations", nil
+	default:
+		return "", "", fmt.Errorf("unknown endpoint type: %s", objectType)
+	}
 }
 
 // ReconcileCable reconciles a cable between two endpoints (IDEMPOTENT)
@@ -136,9 +204,12 @@ func (cr *CableReconciler) ReconcileCable(aEnd, bEnd *CableEndpoint, link *model
 		// Python device_controller.py lines 607-639 (Section E)
 		cr.logger.Debug("│ Checking peer port for existing cables...")
 
-		skipCreation, err = cr.checkAndCleanPeerPort(aEnd, bEnd, link)
-		if err != nil {
-			return fmt.Errorf("failed to check peer port: %w", err)
+		for _, peer := range bEnd.split() {
+			skipPeer, err := cr.checkAndCleanPeerPort(aEnd, peer, link)
+			if err != nil {
+				return fmt.Errorf("failed to check peer port: %w", err)
+			}
+			skipCreation = skipCreation || skipPeer
 		}
 
 		if skipCreation {
@@ -162,8 +233,8 @@ func (cr *CableReconciler) ReconcileCable(aEnd, bEnd *CableEndpoint, link *model
 // createPairID creates a canonical identifier for a cable pair (order-independent)
 func (cr *CableReconciler) createPairID(aEnd, bEnd *CableEndpoint) string {
 	// Create stable IDs for both ends
-	aID := fmt.Sprintf("%s:%s:%d", aEnd.ObjectType, aEnd.DeviceName, aEnd.ObjectID)
-	bID := fmt.Sprintf("%s:%s:%d", bEnd.ObjectType, bEnd.DeviceName, bEnd.ObjectID)
+	aID := aEnd.key()
+	bID := bEnd.key()
 
 	// Sort to ensure A->B == B->A
 	ids := []string{aID, bID}
@@ -214,8 +285,16 @@ func (cr *CableReconciler) findExistingCable(aEnd, bEnd *CableEndpoint) (client.
 	return nil, nil
 }
 
-// matchesEndpoint checks if a cable endpoint matches the given endpoint
+// matchesEndpoint checks if a cable endpoint matches the given endpoint.
+// Cables with <side>_terminations lists (NetBox 3.3+) must terminate on exactly the endpoint's objects.
 func (cr *CableReconciler) matchesEndpoint(cable client.Object, side string, endpoint *CableEndpoint) bool {
+	if terms, ok := cable[side+"_terminations"].([]interface{}); ok {
+		return cr.matchesTerminations(terms, endpoint)
+	}
+	if len(endpoint.IDs()) > 1 {
+		return false // Legacy single-termination fields can't describe a bundled end
+	}
+
 	typeKey := fmt.Sprintf("termination_%s_type", side)
 	idKey := fmt.Sprintf("termination_%s_id", side)
 
@@ -234,6 +313,36 @@ func (cr *CableReconciler) matchesEndpoint(cable client.Object, side string, end
 	return cableType == endpoint.ObjectType && cableID == endpoint.ObjectID
 }
 
+// matchesTerminations compares a termination list with the endpoint's set of objects
+func (cr *CableReconciler) matchesTerminations(terms []interface{}, endpoint *CableEndpoint) bool {
+	want := make(map[int]bool)
+	for _, id := range endpoint.IDs() {
+		want[id] = true
+	}
+
+	got := make(map[int]bool)
+	for _, term := range terms {
+		termMap, ok := term.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if termType, _ := termMap["object_type"].(string); termType != endpoint.ObjectType {
+			return false
+		}
+		got[utils.GetIDFromObject(termMap["object_id"])] = true
+	}
+
+	if len(got) != len(want) {
+		return false
+	}
+	for id := range want {
+		if !got[id] {
+			return false
+		}
+	}
+	return true
+}
+
 // verifyCable checks if an existing cable matches the desired configuration
 func (cr *CableReconciler) verifyCable(cable client.Object, aEnd, bEnd *CableEndpoint, link *models.LinkConfig) bool {
 	if link == nil {
@@ -250,12 +359,11 @@ func (cr *CableReconciler) verifyCable(cable client.Object, aEnd, bEnd *CableEnd
 		}
 	}
 
-	// Check color (normalize color name to hex for comparison)
+	// Check color (normalize both sides to hex for comparison)
 	if link.Color != "" {
 		normalizedColor := normalizeColor(link.Color)
 		if color, ok := cable["color"].(string); ok {
-			// NetBox stores color without # prefix
-			if color != normalizedColor {
+			if normalizeColor(color) != normalizedColor {
 				cr.logger.Debug("│ Cable color mismatch: %s != %s (normalized: %s)", color, link.Color, normalizedColor)
 				return false
 			}
@@ -278,25 +386,15 @@ func (cr *CableReconciler) verifyCable(cable client.Object, aEnd, bEnd *CableEnd
 // createCable creates a new cable
 func (cr *CableReconciler) createCable(aEnd, bEnd *CableEndpoint, link *models.LinkConfig) error {
 	payload := map[string]interface{}{
-		"a_terminations": []map[string]interface{}{
-			{
-				"object_type": aEnd.ObjectType,
-				"object_id":   aEnd.ObjectID,
-			},
-		},
-		"b_terminations": []map[string]interface{}{
-			{
-				"object_type": bEnd.ObjectType,
-				"object_id":   bEnd.ObjectID,
-			},
-		},
-		"status": "connected",
+		"a_terminations": aEnd.terminations(),
+		"b_terminations": bEnd.terminations(),
+		"status":         constants.DefaultCableStatus,
+		"type":           constants.DefaultCableType,
 	}
 
 	if link != nil {
 		if link.CableType != "" {
 			payload["type"] = link.CableType
-			cr.logger.Debug("│   Type: %s", link.CableType)
 		}
 		if link.Color != "" {
 			normalizedColor := normalizeColor(link.Color)
@@ -304,13 +402,19 @@ func (cr *CableReconciler) createCable(aEnd, bEnd *CableEndpoint, link *models.L
 			cr.logger.Debug("│   Color: %s (normalized: %s)", link.Color, normalizedColor)
 		}
 		if link.Length > 0 {
+			// A length without a unit is read as meters
+			lengthUnit := link.LengthUnit
+			if lengthUnit == "" {
+				lengthUnit = constants.DefaultLengthUnit
+			}
 			payload["length"] = link.Length
-			cr.logger.Debug("│   Length: %.2f %s", link.Length, link.LengthUnit)
-		}
-		if link.LengthUnit != "" {
+			payload["length_unit"] = lengthUnit
+			cr.logger.Debug("│   Length: %.2f %s", link.Length, lengthUnit)
+		} else if link.LengthUnit != "" {
 			payload["length_unit"] = link.LengthUnit
 		}
 	}
+	cr.logger.Debug("│   Type: %s", payload["type"])
 
 	if cr.client.IsDryRun() {
 		cr.logger.DryRun("CREATE", "Cable: %s[%s] <-> %s[%s]",
@@ -353,7 +457,13 @@ func (cr *CableReconciler) updateCable(cable client.Object, link *models.LinkCon
 	}
 
 	if cr.client.IsDryRun() {
-		cr.logger.DryRun("UPDATE", "Cable ID %d with %v", cableID, updates)
+		var changes []string
+		for _, field := range []string{"type", "color", "length", "length_unit"} {
+			if desired, ok := updates[field]; ok {
+				changes = append(changes, fmt.Sprintf("%s: %v -> %v", field, cableFieldValue(cable, field), desired))
+			}
+		}
+		cr.logger.DryRun("UPDATE", "Cable ID %d (%s)", cableID, strings.Join(changes, ", "))
 		return nil
 	}
 
@@ -365,20 +475,13 @@ func (cr *CableReconciler) updateCable(cable client.Object, link *models.LinkCon
 // Returns (skipCreation, error) - skipCreation=true means cable already exists correctly
 func (cr *CableReconciler) checkAndCleanLocalPort(aEnd, bEnd *CableEndpoint) (bool, error) {
 	// Determine the endpoint type for A-end
-	var endpoint string
-	switch aEnd.ObjectType {
-	case "dcim.interface":
-		endpoint = "interfaces"
-	case "dcim.frontport":
-		endpoint = "front-ports"
-	case "dcim.rearport":
-		endpoint = "rear-ports"
-	default:
-		return false, fmt.Errorf("unknown endpoint type: %s", aEnd.ObjectType)
+	app, endpoint, err := terminationPath(aEnd.ObjectType)
+	if err != nil {
+		return false, err
 	}
 
 	// Fetch the local port (A-end) to check if it has a cable
-	localPorts, err := cr.client.Filter("dcim", endpoint, map[string]interface{}{
+	localPorts, err := cr.client.Filter(app, endpoint, map[string]interface{}{
 		"id": aEnd.ObjectID,
 	})
 	if err != nil || len(localPorts) == 0 {
@@ -422,7 +525,7 @@ func (cr *CableReconciler) checkAndCleanLocalPort(aEnd, bEnd *CableEndpoint) (bo
 
 	// Check if this cable connects to our B-end (correct cable - idempotent case)
 	// Python: if self._cable_connects_to(existing, peer.id)
-	if cr.cableConnectsTo(existingCable, bEnd.ObjectID) {
+	if cr.cableConnectsTo(existingCable, bEnd.ObjectType, bEnd.ObjectID) {
 		cr.logger.Info("│ Local port already has correct cable (ID: %d)", cableID)
 		cr.logger.Info("│ Action: No changes needed (idempotent)")
 		return true, nil
@@ -441,7 +544,8 @@ func (cr *CableReconciler) checkAndCleanLocalPort(aEnd, bEnd *CableEndpoint) (bo
 		}
 		cr.logger.Success("│ Deleted wrong cable on local port")
 	} else {
-		cr.logger.DryRun("DELETE", "Wrong cable ID %d on local port", cableID)
+		cr.logger.DryRun("RECONNECT", "%s[%s] currently connected to %s (cable ID %d), would reconnect to %s[%s]",
+			aEnd.DeviceName, aEnd.PortName, describeFarEnd(existingCable, aEnd.ObjectID), cableID, bEnd.DeviceName, bEnd.PortName)
 	}
 
 	return false, nil
@@ -452,20 +556,13 @@ func (cr *CableReconciler) checkAndCleanLocalPort(aEnd, bEnd *CableEndpoint) (bo
 // Matches Python device_controller.py lines 607-639: "Peer-Port prüfen (Stray cables)"
 func (cr *CableReconciler) checkAndCleanPeerPort(aEnd, bEnd *CableEndpoint, link *models.LinkConfig) (bool, error) {
 	// Determine the endpoint type to query
-	var endpoint string
-	switch bEnd.ObjectType {
-	case "dcim.interface":
-		endpoint = "interfaces"
-	case "dcim.frontport":
-		endpoint = "front-ports"
-	case "dcim.rearport":
-		endpoint = "rear-ports"
-	default:
-		return false, fmt.Errorf("unknown endpoint type: %s", bEnd.ObjectType)
+	app, endpoint, err := terminationPath(bEnd.ObjectType)
+	if err != nil {
+		return false, err
 	}
 
 	// Fetch the fresh peer port object to check if it has a cable
-	peerPorts, err := cr.client.Filter("dcim", endpoint, map[string]interface{}{
+	peerPorts, err := cr.client.Filter(app, endpoint, map[string]interface{}{
 		"id": bEnd.ObjectID,
 	})
 	if err != nil || len(peerPorts) == 0 {
@@ -509,7 +606,7 @@ func (cr *CableReconciler) checkAndCleanPeerPort(aEnd, bEnd *CableEndpoint, link
 	cr.logger.Debug("│ Peer port already has cable ID: %d", cableID)
 
 	// Check if this cable connects to our A-end (correct cable - idempotent case)
-	if cr.cableConnectsTo(existingCable, aEnd.ObjectID) {
+	if cr.cableConnectsTo(existingCable, aEnd.ObjectType, aEnd.ObjectID) {
 		cr.logger.Info("│ Peer port already has correct cable (ID: %d)", cableID)
 		cr.logger.Info("│ Action: No changes needed (idempotent)")
 		// This is OK - the cable already exists correctly, skip creation
@@ -520,6 +617,12 @@ func (cr *CableReconciler) checkAndCleanPeerPort(aEnd, bEnd *CableEndpoint, link
 	cr.logger.Warning("│ Peer port has cable to DIFFERENT device")
 	cr.logger.Warning("│ Existing cable ID %d blocks our connection", cableID)
 
+	// Cables on unmanaged endpoints (e.g. a provider's circuit termination) are not ours to remove
+	if bEnd.Unmanaged {
+		return false, fmt.Errorf("unmanaged %s %s[%s] is already connected to %s (cable ID %d), not deleting it",
+			bEnd.ObjectType, bEnd.DeviceName, bEnd.PortName, describeFarEnd(existingCable, bEnd.ObjectID), cableID)
+	}
+
 	// Python device_controller.py lines 628-637:
 	// Special handling for backbone cables (rearport to rearport between patch panels)
 	// vs. regular blocking cables - but BOTH use force=True to skip managed check
@@ -544,38 +647,84 @@ func (cr *CableReconciler) checkAndCleanPeerPort(aEnd, bEnd *CableEndpoint, link
 		}
 		cr.logger.Success("│ Deleted blocking cable")
 	} else {
-		cr.logger.DryRun("DELETE", "Blocking cable ID %d", cableID)
+		cr.logger.DryRun("RECONNECT", "%s[%s] currently connected to %s (cable ID %d), would reconnect to %s[%s]",
+			bEnd.DeviceName, bEnd.PortName, describeFarEnd(existingCable, bEnd.ObjectID), cableID, aEnd.DeviceName, aEnd.PortName)
 	}
 
 	return false, nil
 }
 
-// cableConnectsTo checks if a cable has a termination connecting to the specified object ID
+// cableConnectsTo checks if a cable has a termination connecting to the specified object.
+// IDs are only unique per object type (interface 5 is not circuit termination 5), so the
+// type is compared as well where the termination carries one.
 // Matches Python _cable_connects_to helper
-func (cr *CableReconciler) cableConnectsTo(cable client.Object, targetObjectID int) bool {
-	// Check A terminations
-	if aTerms, ok := cable["a_terminations"].([]interface{}); ok {
-		for _, term := range aTerms {
-			if termMap, ok := term.(map[string]interface{}); ok {
-				if objID, ok := termMap["object_id"].(float64); ok && int(objID) == targetObjectID {
-					return true
-				}
+func (cr *CableReconciler) cableConnectsTo(cable client.Object, targetType string, targetObjectID int) bool {
+	for _, side := range []string{"a_terminations", "b_terminations"} {
+		terms, _ := cable[side].([]interface{})
+		for _, term := range terms {
+			termMap, ok := term.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if termType, ok := termMap["object_type"].(string); ok && termType != targetType {
+				continue
+			}
+			if objID, ok := termMap["object_id"].(float64); ok && int(objID) == targetObjectID {
+				return true
 			}
 		}
 	}
 
-	// Check B terminations
-	if bTerms, ok := cable["b_terminations"].([]interface{}); ok {
-		for _, term := range bTerms {
-			if termMap, ok := term.(map[string]interface{}); ok {
-				if objID, ok := termMap["object_id"].(float64); ok && int(objID) == targetObjectID {
-					return true
-				}
+	return false
+}
+
+// describeFarEnd describes the terminations of a cable on the opposite side of localID,
+// e.g. "server-02[eth0]", for dry-run output
+func describeFarEnd(cable client.Object, localID int) string {
+	aTerms, _ := cable["a_terminations"].([]interface{})
+	bTerms, _ := cable["b_terminations"].([]interface{})
+
+	far := bTerms
+	for _, term := range bTerms {
+		if termMap, ok := term.(map[string]interface{}); ok {
+			if objID, ok := termMap["object_id"].(float64); ok && int(objID) == localID {
+				far = aTerms
+				break
 			}
 		}
 	}
 
-	return false
+	var names []string
+	for _, term := range far {
+		termMap, ok := term.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		object, _ := termMap["object"].(map[string]interface{})
+		device, _ := object["device"].(map[string]interface{})
+		if name, ok := object["name"].(string); ok && device != nil {
+			names = append(names, fmt.Sprintf("%v[%s]", device["name"], name))
+			continue
+		}
+		names = append(names, fmt.Sprintf("%v ID %v", termMap["object_type"], termMap["object_id"]))
+	}
+
+	if len(names) == 0 {
+		return "an unknown peer"
+	}
+	return strings.Join(names, ", ")
+}
+
+// cableFieldValue returns the current value of a cable field, unwrapping choice fields ({"value": ..., "label": ...})
+func cableFieldValue(cable client.Object, field string) interface{} {
+	value := cable[field]
+	if choice, ok := value.(map[string]interface{}); ok {
+		return choice["value"]
+	}
+	if value == nil || value == "" {
+		return "<none>"
+	}
+	return value
 }
 
 // Reset clears the processed pairs cache (call between reconciliation runs)
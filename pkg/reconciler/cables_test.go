@@ -1,8 +1,13 @@
 package reconciler
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
 	"github.com/braunma/netbox-gitops-controller/pkg/models"
 )
 
@@ -227,6 +232,42 @@ func TestVerifyCable(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name: "color name matches stored hex",
+			cable: map[string]interface{}{
+				"type":  "dac-active",
+				"color": "0000ff",
+			},
+			link: &models.LinkConfig{
+				CableType: "dac-active",
+				Color:     "blue",
+			},
+			expected: true,
+		},
+		{
+			name: "hex with hash matches stored hex",
+			cable: map[string]interface{}{
+				"type":  "dac-active",
+				"color": "0000ff",
+			},
+			link: &models.LinkConfig{
+				CableType: "dac-active",
+				Color:     "#0000FF",
+			},
+			expected: true,
+		},
+		{
+			name: "bare hex matches stored hex",
+			cable: map[string]interface{}{
+				"type":  "dac-active",
+				"color": "0000ff",
+			},
+			link: &models.LinkConfig{
+				CableType: "dac-active",
+				Color:     "0000ff",
+			},
+			expected: true,
+		},
 		{
 			name: "mismatched color",
 			cable: map[string]interface{}{
@@ -362,3 +403,309 @@ func TestLinkConfigFields(t *testing.T) {
 		t.Errorf("LengthUnit = %q, expected %q", link.LengthUnit, "m")
 	}
 }
+
+func TestReconcileCableBreakout(t *testing.T) {
+	c, srv := newTestClient(t)
+	cr := NewCableReconciler(c)
+
+	src := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "Eth1/1", "device": "switch-01"})
+	peer1 := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "eth0", "device": "server-01"})
+	peer2 := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "eth1", "device": "server-01"})
+
+	aEnd := &CableEndpoint{DeviceName: "switch-01", PortName: "Eth1/1", ObjectType: "dcim.interface", ObjectID: netboxtest.ID(src)}
+	bEnd := &CableEndpoint{
+		DeviceName: "server-01",
+		PortName:   "eth0,eth1",
+		ObjectType: "dcim.interface",
+		ObjectID:   netboxtest.ID(peer1),
+		ObjectIDs:  []int{netboxtest.ID(peer1), netboxtest.ID(peer2)},
+	}
+
+	if err := cr.ReconcileCable(aEnd, bEnd, &models.LinkConfig{CableType: "dac-passive"}); err != nil {
+		t.Fatalf("ReconcileCable() error = %v", err)
+	}
+
+	cables := srv.Objects("dcim", "cables")
+	if len(cables) != 1 {
+		t.Fatalf("Expected 1 cable, got %d", len(cables))
+	}
+
+	bTerms, _ := cables[0]["b_terminations"].([]interface{})
+	if len(bTerms) != 2 {
+		t.Fatalf("b_terminations = %v, expected 2 terminations", cables[0]["b_terminations"])
+	}
+	if !cr.matchesEndpoint(cables[0], "b", bEnd) {
+		t.Errorf("Created cable does not match the bundled B end: %v", bTerms)
+	}
+	if aTerms, _ := cables[0]["a_terminations"].([]interface{}); len(aTerms) != 1 {
+		t.Errorf("a_terminations = %v, expected 1 termination", cables[0]["a_terminations"])
+	}
+}
+
+func TestMatchesEndpointMultipleTerminations(t *testing.T) {
+	cr := &CableReconciler{processedPairs: make(map[string]bool)}
+
+	cable := map[string]interface{}{
+		"b_terminations": []interface{}{
+			map[string]interface{}{"object_type": "dcim.interface", "object_id": float64(201)},
+			map[string]interface{}{"object_type": "dcim.interface", "object_id": float64(202)},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		endpoint *CableEndpoint
+		expected bool
+	}{
+		{"same set", &CableEndpoint{ObjectType: "dcim.interface", ObjectIDs: []int{201, 202}}, true},
+		{"same set reordered", &CableEndpoint{ObjectType: "dcim.interface", ObjectIDs: []int{202, 201}}, true},
+		{"subset", &CableEndpoint{ObjectType: "dcim.interface", ObjectID: 201}, false},
+		{"superset", &CableEndpoint{ObjectType: "dcim.interface", ObjectIDs: []int{201, 202, 203}}, false},
+		{"other type", &CableEndpoint{ObjectType: "dcim.frontport", ObjectIDs: []int{201, 202}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cr.matchesEndpoint(cable, "b", tt.endpoint); got != tt.expected {
+				t.Errorf("matchesEndpoint() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+
+	// Bundled ends produce one pair ID regardless of termination order
+	a := &CableEndpoint{DeviceName: "switch-01", ObjectType: "dcim.interface", ObjectID: 100}
+	b1 := &CableEndpoint{DeviceName: "server-01", ObjectType: "dcim.interface", ObjectIDs: []int{201, 202}}
+	b2 := &CableEndpoint{DeviceName: "server-01", ObjectType: "dcim.interface", ObjectIDs: []int{202, 201}}
+	if cr.createPairID(a, b1) != cr.createPairID(b2, a) {
+		t.Errorf("Pair IDs differ: %q vs %q", cr.createPairID(a, b1), cr.createPairID(b2, a))
+	}
+}
+
+func TestReconcileCableDryRunReconnect(t *testing.T) {
+	srv := netboxtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	c, err := client.NewClient(srv.URL, "test-token", true)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	var out bytes.Buffer
+	c.Logger().SetOutput(&out, &out)
+	cr := NewCableReconciler(c)
+
+	src := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "Eth1/1", "device": "switch-01"})
+	oldPeer := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "eth0", "device": "server-02"})
+	newPeer := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "eth0", "device": "server-01"})
+
+	cable := srv.Add("dcim", "cables", map[string]interface{}{
+		"a_terminations": []interface{}{
+			map[string]interface{}{"object_type": "dcim.interface", "object_id": src["id"]},
+		},
+		"b_terminations": []interface{}{
+			map[string]interface{}{
+				"object_type": "dcim.interface",
+				"object_id":   oldPeer["id"],
+				"object":      map[string]interface{}{"name": "eth0", "device": map[string]interface{}{"name": "server-02"}},
+			},
+		},
+	})
+	src["cable"] = map[string]interface{}{"id": cable["id"]}
+
+	aEnd := &CableEndpoint{DeviceName: "switch-01", PortName: "Eth1/1", ObjectType: "dcim.interface", ObjectID: netboxtest.ID(src)}
+	bEnd := &CableEndpoint{DeviceName: "server-01", PortName: "eth0", ObjectType: "dcim.interface", ObjectID: netboxtest.ID(newPeer)}
+
+	if err := cr.ReconcileCable(aEnd, bEnd, nil); err != nil {
+		t.Fatalf("ReconcileCable() error = %v", err)
+	}
+
+	expected := "switch-01[Eth1/1] currently connected to server-02[eth0]"
+	if !strings.Contains(out.String(), expected) || !strings.Contains(out.String(), "would reconnect to server-01[eth0]") {
+		t.Errorf("Dry-run output missing reconnect message %q:\n%s", expected, out.String())
+	}
+	if len(srv.Objects("dcim", "cables")) != 1 {
+		t.Errorf("Dry-run changed cables: %v", srv.Objects("dcim", "cables"))
+	}
+}
+
+// TestFindPeerPortByLabel tests resolving a peer port by its label when the name doesn't match
+func TestFindPeerPortByLabel(t *testing.T) {
+	c, srv := newTestClient(t)
+	dr := NewDeviceReconciler(c)
+
+	leaf := srv.Add("dcim", "devices", map[string]interface{}{"name": "leaf-01", "role": map[string]interface{}{"slug": "switch"}})
+	port := srv.Add("dcim", "interfaces", map[string]interface{}{
+		"name":   "Ethernet1/1/1",
+		"label":  "uplink-spine1",
+		"device": map[string]interface{}{"id": leaf["id"]},
+	})
+
+	link := &models.LinkConfig{PeerDevice: "leaf-01", PeerPort: "uplink-spine1"}
+	if info, _, _ := dr.findPeerPorts(link, "srv-01::eth0", "server"); info != nil {
+		t.Errorf("findPeerPorts() by name = %+v, expected no match for a label", info)
+	}
+
+	link.PeerPortBy = "label"
+	info, ids, err := dr.findPeerPorts(link, "srv-01::eth0", "server")
+	if err != nil {
+		t.Fatalf("findPeerPorts() error = %v", err)
+	}
+	if info == nil {
+		t.Fatal("findPeerPorts() by label found no port")
+	}
+	if info.objectType != "dcim.interface" || len(ids) != 1 || ids[0] != netboxtest.ID(port) {
+		t.Errorf("findPeerPorts() = %+v %v, expected interface %v", info, ids, port["id"])
+	}
+	if info.port != "Ethernet1/1/1" {
+		t.Errorf("findPeerPorts() port = %q, expected the name Ethernet1/1/1 rather than the label", info.port)
+	}
+
+	link.PeerPortBy = "description"
+	if info, _, _ := dr.findPeerPorts(link, "srv-01::eth0", "server"); info != nil {
+		t.Errorf("findPeerPorts() with an invalid peer_port_by = %+v, expected nil", info)
+	}
+
+	// Labels are not unique: a label two ports share is an error rather than either port
+	srv.Add("dcim", "interfaces", map[string]interface{}{
+		"name":   "Ethernet1/1/2",
+		"label":  "uplink-spine1",
+		"device": map[string]interface{}{"id": leaf["id"]},
+	})
+	link.PeerPortBy = "label"
+	next, err := client.NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	next.Logger().SetOutput(&bytes.Buffer{}, &bytes.Buffer{})
+	dr = NewDeviceReconciler(next)
+	if info, _, err := dr.findPeerPorts(link, "srv-01::eth0", "server"); err == nil || !strings.Contains(err.Error(), "Ethernet1/1/1, Ethernet1/1/2") {
+		t.Errorf("findPeerPorts() = %+v, %v, expected an error naming both ports", info, err)
+	}
+}
+
+func TestCreateCableDefaults(t *testing.T) {
+	tests := []struct {
+		name           string
+		link           *models.LinkConfig
+		wantType       string
+		wantLengthUnit interface{}
+	}{
+		{"no link config", nil, constants.DefaultCableType, nil},
+		{"blank fields", &models.LinkConfig{}, constants.DefaultCableType, nil},
+		{"length without unit", &models.LinkConfig{Length: 3}, constants.DefaultCableType, constants.DefaultLengthUnit},
+		{"explicit values", &models.LinkConfig{CableType: "smf", Length: 30, LengthUnit: "ft"}, "smf", "ft"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, srv := newTestClient(t)
+			cr := NewCableReconciler(c)
+
+			src := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "Eth1/1", "device": "switch-01"})
+			peer := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "eth0", "device": "server-01"})
+			aEnd := &CableEndpoint{DeviceName: "switch-01", PortName: "Eth1/1", ObjectType: "dcim.interface", ObjectID: netboxtest.ID(src)}
+			bEnd := &CableEndpoint{DeviceName: "server-01", PortName: "eth0", ObjectType: "dcim.interface", ObjectID: netboxtest.ID(peer)}
+
+			if err := cr.ReconcileCable(aEnd, bEnd, tt.link); err != nil {
+				t.Fatalf("ReconcileCable() error = %v", err)
+			}
+
+			cables := srv.Objects("dcim", "cables")
+			if len(cables) != 1 {
+				t.Fatalf("Expected 1 cable, got %d", len(cables))
+			}
+			if got := cables[0]["type"]; got != tt.wantType {
+				t.Errorf("type = %v, expected %q", got, tt.wantType)
+			}
+			if got := cables[0]["status"]; got != constants.DefaultCableStatus {
+				t.Errorf("status = %v, expected %q", got, constants.DefaultCableStatus)
+			}
+			if got := cables[0]["length_unit"]; got != tt.wantLengthUnit {
+				t.Errorf("length_unit = %v, expected %v", got, tt.wantLengthUnit)
+			}
+		})
+	}
+}
+
+// TestReconcileCableToCircuitTermination tests cabling an interface to the termination of a
+// circuit that is only looked up, and that a cable already on that termination is left alone
+func TestReconcileCableToCircuitTermination(t *testing.T) {
+	c, srv := newTestClient(t)
+	dr := NewDeviceReconciler(c)
+
+	src := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "Eth1/48", "device": "edge-01"})
+	circuit := srv.Add("circuits", "circuits", map[string]interface{}{"cid": "CID-1001"})
+	term := srv.Add("circuits", "circuit-terminations", map[string]interface{}{"circuit": circuit["id"], "term_side": "A"})
+	srv.Add("circuits", "circuit-terminations", map[string]interface{}{"circuit": circuit["id"], "term_side": "Z"})
+
+	link := &models.LinkConfig{PeerDevice: "CID-1001", PeerPort: "a", PeerType: constants.TerminationCircuitTermination, CableType: "smf"}
+	dr.pendingCables = []pendingCable{{
+		sourceDevice: "edge-01",
+		sourcePort:   "Eth1/48",
+		sourceType:   constants.TerminationInterface,
+		sourceID:     netboxtest.ID(src),
+		sourceRole:   "router",
+		link:         link,
+	}}
+	if err := dr.ReconcileCables(); err != nil {
+		t.Fatalf("ReconcileCables() error = %v", err)
+	}
+
+	cables := srv.Objects("dcim", "cables")
+	if len(cables) != 1 {
+		t.Fatalf("Expected 1 cable, got %d", len(cables))
+	}
+	bEnd := &CableEndpoint{ObjectType: constants.TerminationCircuitTermination, ObjectID: netboxtest.ID(term)}
+	if !dr.cableReconciler.matchesEndpoint(cables[0], "b", bEnd) {
+		t.Errorf("b_terminations = %v, expected circuit termination A %v", cables[0]["b_terminations"], term["id"])
+	}
+	if got := srv.CountRequests("PATCH", "/api/circuits/") + srv.CountRequests("POST", "/api/circuits/"); got != 0 {
+		t.Errorf("Unmanaged circuit was changed: %d requests", got)
+	}
+
+	// IDs are only unique per type: the termination's ID does not name an interface on the cable
+	if dr.cableReconciler.cableConnectsTo(cables[0], constants.TerminationInterface, netboxtest.ID(term)) {
+		t.Error("cableConnectsTo() matched a circuit termination as an interface")
+	}
+
+	// Next run: another cable on the termination is reported, not deleted
+	other := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "Eth1/47", "device": "edge-01"})
+	term["cable"] = map[string]interface{}{"id": cables[0]["id"]}
+	next, err := client.NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	next.Logger().SetOutput(&bytes.Buffer{}, &bytes.Buffer{})
+	dr = NewDeviceReconciler(next)
+	dr.pendingCables = []pendingCable{{
+		sourceDevice: "edge-01",
+		sourcePort:   "Eth1/47",
+		sourceType:   constants.TerminationInterface,
+		sourceID:     netboxtest.ID(other),
+		sourceRole:   "router",
+		link:         link,
+	}}
+	if err := dr.ReconcileCables(); err == nil || !strings.Contains(err.Error(), "not deleting it") {
+		t.Errorf("ReconcileCables() error = %v, expected the blocking cable to be reported", err)
+	}
+	if got := srv.CountRequests("DELETE", "/api/dcim/cables/"); got != 0 {
+		t.Errorf("Cable on the unmanaged termination was deleted: %d requests", got)
+	}
+}
+
+// TestFindPeerPortAmbiguousCircuitID tests that a circuit ID two providers use fails instead of
+// cabling one of the circuits
+func TestFindPeerPortAmbiguousCircuitID(t *testing.T) {
+	c, srv := newTestClient(t)
+	dr := NewDeviceReconciler(c)
+
+	for _, provider := range []string{"Telia", "Lumen"} {
+		circuit := srv.Add("circuits", "circuits", map[string]interface{}{"cid": "CID-1001", "provider": map[string]interface{}{"name": provider}})
+		srv.Add("circuits", "circuit-terminations", map[string]interface{}{"circuit": circuit["id"], "term_side": "A"})
+	}
+
+	link := &models.LinkConfig{PeerDevice: "CID-1001", PeerPort: "A", PeerType: constants.TerminationCircuitTermination}
+	info, _, err := dr.findPeerPorts(link, "edge-01::Eth1/48", "router")
+	if err == nil || !strings.Contains(err.Error(), "Telia, Lumen") {
+		t.Errorf("findPeerPorts() = %+v, %v, expected an error naming both providers", info, err)
+	}
+}
@@ -0,0 +1,86 @@
+package reconciler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// CacheRequirements lists the global caches each resource type resolves references through.
+// Resource types without an entry need no global cache.
+var CacheRequirements = map[string][]string{
+	"custom_fields":   {"custom_field_choice_sets"},
+	"contacts":        {"contact_groups"},
+	"contact_groups":  {"contact_groups"},
+	"tenant_groups":   {"tenant_groups"},
+	"tenants":         {"tenant_groups"},
+	"roles":           {"roles"},
+	"sites":           {"contacts", "contact_roles"},
+	"vlan_groups":     {"sites"},
+	"vlans":           {"sites", "ipam_roles"},
+	"prefixes":        {"sites", "vrfs", "ipam_roles"},
+	"module_types":    {"manufacturers"},
+	"device_types":    {"manufacturers", "inventory_item_roles"},
+	"devices":         {"sites", "roles", "device_types", "module_types", "vrfs", "clusters", "contacts", "contact_roles", "config_templates"},
+	"virtual_chassis": {"sites"},
+	"fhrp_groups":     {"sites"},
+	"circuits":        {"sites", "tenants", "providers", "circuit_types"},
+}
+
+// CacheGuard skips the resource types whose required global caches failed to load,
+// so that the other resource types are still reconciled
+type CacheGuard struct {
+	cache  *client.CacheManager
+	logger *utils.Logger
+
+	mu      sync.Mutex
+	skipped []error
+}
+
+// NewCacheGuard creates a cache guard for the client's cache
+func NewCacheGuard(c *client.NetBoxClient) *CacheGuard {
+	return &CacheGuard{
+		cache:  c.Cache(),
+		logger: c.Logger(),
+	}
+}
+
+// Check returns an error naming the first unavailable cache the resource type needs, or nil
+func (g *CacheGuard) Check(name string) error {
+	for _, resource := range CacheRequirements[name] {
+		if err := g.cache.Unavailable(resource); err != nil {
+			return fmt.Errorf("cannot reconcile %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Wrap returns the runners with a cache check in front. A resource type that fails the check
+// is skipped instead of failing the run, and its error is reported by Err.
+func (g *CacheGuard) Wrap(runners map[string]func() error) map[string]func() error {
+	wrapped := make(map[string]func() error, len(runners))
+	for name, run := range runners {
+		wrapped[name] = func() error {
+			if err := g.Check(name); err != nil {
+				g.logger.Error("Skipping resource type", err)
+				g.mu.Lock()
+				g.skipped = append(g.skipped, err)
+				g.mu.Unlock()
+				return nil
+			}
+			return run()
+		}
+	}
+	return wrapped
+}
+
+// Err returns the errors of all skipped resource types, or nil if none were skipped
+func (g *CacheGuard) Err() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return errors.Join(g.skipped...)
+}
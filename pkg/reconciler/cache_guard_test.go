@@ -0,0 +1,76 @@
+package reconciler
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+)
+
+// TestCacheGuardSkipsStepsNeedingUnavailableCache tests that a cache resource failing to load
+// only skips the resource types that need it
+func TestCacheGuardSkipsStepsNeedingUnavailableCache(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Intercept("GET", "/api/ipam/vrfs/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"detail": "You do not have permission to perform this action."}`, http.StatusForbidden)
+	})
+
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v, expected the other caches to load", err)
+	}
+	if err := c.Cache().Unavailable("vrfs"); err == nil {
+		t.Fatal("Unavailable(vrfs) = nil, expected the 403 to be recorded")
+	}
+	if err := c.Cache().Unavailable("sites"); err != nil {
+		t.Errorf("Unavailable(sites) = %v, expected nil", err)
+	}
+
+	tenancy := NewTenancyReconciler(c)
+	var (
+		mu  sync.Mutex
+		ran = make(map[string]bool)
+	)
+	runners := make(map[string]func() error)
+	for _, dep := range ResourceDependencies {
+		name := dep.Name
+		runners[name] = func() error {
+			mu.Lock()
+			ran[name] = true
+			mu.Unlock()
+			return nil
+		}
+	}
+	runners["tenants"] = func() error {
+		return tenancy.ReconcileTenants([]*models.Tenant{{Name: "Acme", Slug: "acme"}})
+	}
+
+	guard := NewCacheGuard(c)
+	graph, err := NewResourceGraph(guard.Wrap(runners))
+	if err != nil {
+		t.Fatalf("NewResourceGraph() error = %v", err)
+	}
+	if err := graph.Execute(4); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	for _, name := range []string{"prefixes", "devices"} {
+		if ran[name] {
+			t.Errorf("%s ran, expected it to be skipped without the vrfs cache", name)
+		}
+	}
+	for _, name := range []string{"sites", "vlans", "racks", "cables", "circuits"} {
+		if !ran[name] {
+			t.Errorf("%s did not run, expected it to proceed without the vrfs cache", name)
+		}
+	}
+	if srv.Find("tenancy", "tenants", "slug", "acme") == nil {
+		t.Error("Tenant not created, expected tenants to be reconciled")
+	}
+
+	err = guard.Err()
+	if err == nil || !strings.Contains(err.Error(), "cannot reconcile prefixes: vrfs cache unavailable (403)") {
+		t.Errorf("Err() = %v, expected an actionable error for prefixes", err)
+	}
+}
@@ -0,0 +1,275 @@
+package reconciler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// CircuitReconciler handles circuit resources (providers, circuit types, circuits and their terminations)
+type CircuitReconciler struct {
+	client          *client.NetBoxClient
+	logger          *utils.Logger
+	cableReconciler *CableReconciler
+	// ports looks up the device ports that terminations are cabled to
+	ports *DeviceReconciler
+}
+
+// NewCircuitReconciler creates a new circuit reconciler
+func NewCircuitReconciler(c *client.NetBoxClient) *CircuitReconciler {
+	return &CircuitReconciler{
+		client:          c,
+		logger:          c.Logger(),
+		cableReconciler: NewCableReconciler(c),
+		ports:           NewDeviceReconciler(c),
+	}
+}
+
+// ReconcileProviders reconciles circuit provider definitions
+func (cr *CircuitReconciler) ReconcileProviders(providers []*models.Provider) error {
+	cr.logger.Info("Reconciling %d providers...", len(providers))
+
+	for _, provider := range providers {
+		payload := map[string]interface{}{
+			"name": provider.Name,
+			"slug": provider.Slug,
+		}
+
+		if provider.Description != "" {
+			payload["description"] = provider.Description
+		}
+		if provider.Comments != "" {
+			payload["comments"] = provider.Comments
+		}
+		setTags(cr.client, payload, provider.Tags, "provider "+provider.Name)
+
+		lookup := map[string]interface{}{"slug": provider.Slug}
+		providerObj, err := cr.client.Apply("circuits", "providers", lookup, payload)
+		if err != nil {
+			if cr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile provider %s: %w", provider.Name, err)
+		}
+
+		if providerID := utils.GetIDFromObject(providerObj); providerID > 0 {
+			cr.client.Cache().Set("providers", provider.Slug, providerID)
+			cr.client.Cache().Set("providers", provider.Name, providerID)
+		}
+	}
+
+	return nil
+}
+
+// ReconcileCircuitTypes reconciles circuit type definitions
+func (cr *CircuitReconciler) ReconcileCircuitTypes(types []*models.CircuitType) error {
+	cr.logger.Info("Reconciling %d circuit types...", len(types))
+
+	for _, circuitType := range types {
+		payload := map[string]interface{}{
+			"name": circuitType.Name,
+			"slug": circuitType.Slug,
+		}
+
+		if color := utils.NormalizeColor(circuitType.Color); color != "" {
+			payload["color"] = color
+		}
+		if circuitType.Description != "" {
+			payload["description"] = circuitType.Description
+		}
+		setTags(cr.client, payload, circuitType.Tags, "circuit type "+circuitType.Name)
+
+		lookup := map[string]interface{}{"slug": circuitType.Slug}
+		typeObj, err := cr.client.Apply("circuits", "circuit-types", lookup, payload)
+		if err != nil {
+			if cr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile circuit type %s: %w", circuitType.Name, err)
+		}
+
+		if typeID := utils.GetIDFromObject(typeObj); typeID > 0 {
+			cr.client.Cache().Set("circuit_types", circuitType.Slug, typeID)
+			cr.client.Cache().Set("circuit_types", circuitType.Name, typeID)
+		}
+	}
+
+	return nil
+}
+
+// ReconcileCircuits reconciles circuit definitions and their terminations.
+// Circuits are matched by circuit ID within their provider.
+func (cr *CircuitReconciler) ReconcileCircuits(circuits []*models.Circuit) error {
+	cr.logger.Info("Reconciling %d circuits...", len(circuits))
+
+	for _, circuit := range circuits {
+		if err := cr.reconcileCircuit(circuit); err != nil {
+			if cr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile circuit %s: %w", circuit.CID, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileCircuit reconciles a single circuit and its terminations
+func (cr *CircuitReconciler) reconcileCircuit(circuit *models.Circuit) error {
+	providerID, ok := cr.client.Cache().GetGlobalIDOrReload("providers", circuit.Provider)
+	if !ok {
+		return fmt.Errorf("provider %s not found", circuit.Provider)
+	}
+	typeID, ok := cr.client.Cache().GetGlobalIDOrReload("circuit_types", circuit.Type)
+	if !ok {
+		return fmt.Errorf("circuit type %s not found", circuit.Type)
+	}
+
+	status := circuit.Status
+	if status == "" {
+		status = "active"
+	}
+
+	payload := map[string]interface{}{
+		"cid":      circuit.CID,
+		"provider": providerID,
+		"type":     typeID,
+		"status":   status,
+	}
+
+	if circuit.Tenant != "" {
+		tenantID, ok := cr.client.Cache().GetGlobalID("tenants", circuit.Tenant)
+		if !ok {
+			return fmt.Errorf("tenant %s not found", circuit.Tenant)
+		}
+		payload["tenant"] = tenantID
+	}
+	if circuit.CommitRate > 0 {
+		payload["commit_rate"] = circuit.CommitRate
+	}
+	if circuit.Description != "" {
+		payload["description"] = circuit.Description
+	}
+	if circuit.Comments != "" {
+		payload["comments"] = circuit.Comments
+	}
+	setTags(cr.client, payload, circuit.Tags, "circuit "+circuit.CID)
+
+	lookup := map[string]interface{}{
+		"cid":         circuit.CID,
+		"provider_id": providerID,
+	}
+	circuitObj, err := cr.client.Apply("circuits", "circuits", lookup, payload)
+	if err != nil {
+		return err
+	}
+
+	circuitID := utils.GetIDFromObject(circuitObj)
+	if circuitID == 0 {
+		// Dry-run without --simulate: the circuit doesn't exist yet, so neither can its terminations
+		if len(circuit.Terminations) > 0 {
+			cr.logger.Debug("Skipping terminations of circuit %s (not created in dry-run)", circuit.CID)
+		}
+		return nil
+	}
+
+	for _, term := range circuit.Terminations {
+		if err := cr.reconcileTermination(circuit, circuitID, term); err != nil {
+			return fmt.Errorf("failed to reconcile termination %s: %w", term.Side, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileTermination connects one side of a circuit to a site, and cables it to a device port if a link is declared
+func (cr *CircuitReconciler) reconcileTermination(circuit *models.Circuit, circuitID int, term models.CircuitTermination) error {
+	side := strings.ToUpper(term.Side)
+	if side != "A" && side != "Z" {
+		return fmt.Errorf("invalid side %q (expected A or Z)", term.Side)
+	}
+
+	siteID, ok := cr.client.Cache().GetGlobalIDOrReload("sites", term.Site)
+	if !ok {
+		return fmt.Errorf("site %s not found", term.Site)
+	}
+
+	payload := map[string]interface{}{
+		"circuit":   circuitID,
+		"term_side": side,
+	}
+	// NetBox 4.2 replaced the site field with a generic termination
+	if cr.client.AtLeastVersion(4, 2) {
+		payload["termination_type"] = "dcim.site"
+		payload["termination_id"] = siteID
+	} else {
+		payload["site"] = siteID
+	}
+
+	if term.PortSpeed > 0 {
+		payload["port_speed"] = term.PortSpeed
+	}
+	if term.UpstreamSpeed > 0 {
+		payload["upstream_speed"] = term.UpstreamSpeed
+	}
+	if term.XConnectID != "" {
+		payload["xconnect_id"] = term.XConnectID
+	}
+	if term.PPInfo != "" {
+		payload["pp_info"] = term.PPInfo
+	}
+	if term.Description != "" {
+		payload["description"] = term.Description
+	}
+
+	lookup := map[string]interface{}{
+		"circuit_id": circuitID,
+		"term_side":  side,
+	}
+	termObj, err := cr.client.Apply("circuits", "circuit-terminations", lookup, payload)
+	if err != nil {
+		return err
+	}
+
+	if term.Link == nil {
+		return nil
+	}
+	termID := utils.GetIDFromObject(termObj)
+	if termID <= 0 {
+		cr.logger.Debug("Skipping cable of circuit %s side %s (termination not created in dry-run)", circuit.CID, side)
+		return nil
+	}
+
+	termName := fmt.Sprintf("Side %s", side)
+	peer, peerIDs, err := cr.ports.findPeerPorts(term.Link, circuit.CID+"::"+termName, "")
+	if err != nil {
+		return fmt.Errorf("failed to find peer of circuit %s side %s: %w", circuit.CID, side, err)
+	}
+	if peer == nil {
+		return nil
+	}
+
+	aEnd := &CableEndpoint{
+		DeviceName: circuit.CID,
+		PortName:   termName,
+		ObjectType: "circuits.circuittermination",
+		ObjectID:   termID,
+	}
+	bEnd := &CableEndpoint{
+		DeviceName: peer.device,
+		PortName:   peer.port,
+		ObjectType: peer.objectType,
+		ObjectID:   peer.objectID,
+	}
+	if len(peerIDs) > 1 {
+		bEnd.ObjectIDs = peerIDs
+	}
+
+	if err := cr.cableReconciler.ReconcileCable(aEnd, bEnd, term.Link); err != nil {
+		return fmt.Errorf("failed to reconcile cable to %s[%s]: %w", peer.device, peer.port, err)
+	}
+	return nil
+}
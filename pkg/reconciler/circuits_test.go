@@ -0,0 +1,135 @@
+package reconciler
+
+import (
+	"os"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+)
+
+// loadCircuitFixtures reads the example providers, circuit types and circuits
+func loadCircuitFixtures(t *testing.T) ([]*models.Provider, []*models.CircuitType, []*models.Circuit) {
+	t.Helper()
+
+	var (
+		providers    []*models.Provider
+		circuitTypes []*models.CircuitType
+		circuits     []*models.Circuit
+	)
+	for path, target := range map[string]interface{}{
+		"../../example/definitions/providers/providers.yaml":         &providers,
+		"../../example/definitions/circuit_types/circuit_types.yaml": &circuitTypes,
+		"../../example/definitions/circuits/circuits.yaml":           &circuits,
+	} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read fixture: %v", err)
+		}
+		if err := yaml.Unmarshal(data, target); err != nil {
+			t.Fatalf("Failed to parse fixture %s: %v", path, err)
+		}
+	}
+	return providers, circuitTypes, circuits
+}
+
+// TestReconcileCircuitTerminatedAtSite tests a circuit whose A side is terminated at a site
+// and cabled to a switch interface
+func TestReconcileCircuitTerminatedAtSite(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		siteKey string // field expected to reference the site
+	}{
+		{"4.1.3", "site"},
+		{"4.2.0", "termination_id"},
+	} {
+		t.Run("NetBox "+tc.version, func(t *testing.T) {
+			c, srv := newTestClient(t)
+			srv.Intercept("GET", "/api/status/", statusHandler(tc.version))
+			site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+			device := srv.Add("dcim", "devices", map[string]interface{}{
+				"name": "example-switch-01",
+				"role": map[string]interface{}{"slug": "switch"},
+			})
+			iface := srv.Add("dcim", "interfaces", map[string]interface{}{
+				"name":   "GigabitEthernet1/0/2",
+				"device": map[string]interface{}{"id": device["id"]},
+			})
+
+			providers, circuitTypes, circuits := loadCircuitFixtures(t)
+			cr := NewCircuitReconciler(c)
+			if err := cr.ReconcileProviders(providers); err != nil {
+				t.Fatalf("ReconcileProviders() error = %v", err)
+			}
+			if err := cr.ReconcileCircuitTypes(circuitTypes); err != nil {
+				t.Fatalf("ReconcileCircuitTypes() error = %v", err)
+			}
+			if err := cr.ReconcileCircuits(circuits); err != nil {
+				t.Fatalf("ReconcileCircuits() error = %v", err)
+			}
+
+			provider := srv.Find("circuits", "providers", "slug", "example-carrier")
+			circuitType := srv.Find("circuits", "circuit-types", "slug", "internet-transit")
+			circuit := srv.Find("circuits", "circuits", "cid", "EC-100234")
+			if provider == nil || circuitType == nil || circuit == nil {
+				t.Fatalf("provider = %v, circuit type = %v, circuit = %v, expected all created", provider, circuitType, circuit)
+			}
+			if netboxtest.ID(circuit["provider"]) != netboxtest.ID(provider) || netboxtest.ID(circuit["type"]) != netboxtest.ID(circuitType) {
+				t.Errorf("circuit provider/type = %v/%v, expected %v/%v", circuit["provider"], circuit["type"], provider["id"], circuitType["id"])
+			}
+			if circuitType["color"] != "4caf50" {
+				t.Errorf("circuit type color = %v, expected 4caf50", circuitType["color"])
+			}
+
+			term := srv.Find("circuits", "circuit-terminations", "term_side", "A")
+			if term == nil {
+				t.Fatal("Circuit termination A was not created")
+			}
+			if netboxtest.ID(term["circuit"]) != netboxtest.ID(circuit) {
+				t.Errorf("termination circuit = %v, expected %v", term["circuit"], circuit["id"])
+			}
+			if netboxtest.ID(term[tc.siteKey]) != netboxtest.ID(site) {
+				t.Errorf("termination %s = %v, expected site %v", tc.siteKey, term[tc.siteKey], site["id"])
+			}
+			if tc.siteKey == "termination_id" && term["termination_type"] != "dcim.site" {
+				t.Errorf("termination_type = %v, expected dcim.site", term["termination_type"])
+			}
+			if term["xconnect_id"] != "XC-4711" {
+				t.Errorf("xconnect_id = %v, expected XC-4711", term["xconnect_id"])
+			}
+
+			cables := srv.Objects("dcim", "cables")
+			if len(cables) != 1 {
+				t.Fatalf("Created %d cables, expected 1", len(cables))
+			}
+			aEnd := &CableEndpoint{ObjectType: "circuits.circuittermination", ObjectID: netboxtest.ID(term)}
+			bEnd := &CableEndpoint{ObjectType: "dcim.interface", ObjectID: netboxtest.ID(iface)}
+			if !cr.cableReconciler.matchesEndpoint(cables[0], "a", aEnd) || !cr.cableReconciler.matchesEndpoint(cables[0], "b", bEnd) {
+				t.Errorf("cable terminations = %v -> %v, expected termination A -> GigabitEthernet1/0/2", cables[0]["a_terminations"], cables[0]["b_terminations"])
+			}
+
+			// Second run must not produce any updates
+			srv.ResetRequests()
+			if err := NewCircuitReconciler(c).ReconcileCircuits(circuits); err != nil {
+				t.Fatalf("ReconcileCircuits() second run error = %v", err)
+			}
+			for _, path := range []string{"/api/circuits/circuits/", "/api/circuits/circuit-terminations/"} {
+				if got := srv.CountRequests("PATCH", path) + srv.CountRequests("POST", path); got != 0 {
+					t.Errorf("Second run sent %d writes to %s, expected none", got, path)
+				}
+			}
+		})
+	}
+}
+
+// TestReconcileCircuitUnknownProvider tests that circuits fail for providers that don't exist
+func TestReconcileCircuitUnknownProvider(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	circuits := []*models.Circuit{{CID: "X-1", Provider: "missing", Type: "internet-transit"}}
+	if err := NewCircuitReconciler(c).ReconcileCircuits(circuits); err == nil {
+		t.Error("ReconcileCircuits() expected error for unknown provider")
+	}
+}
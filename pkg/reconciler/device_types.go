@@ -2,7 +2,10 @@ package reconciler
 
 import (
 	"fmt"
+	"math"
+	"strings"
 
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
 	"github.com/braunma/netbox-gitops-controller/pkg/client"
 	"github.com/braunma/netbox-gitops-controller/pkg/models"
 	"github.com/braunma/netbox-gitops-controller/pkg/utils"
@@ -53,10 +56,19 @@ func (dtr *DeviceTypeReconciler) ReconcileModuleTypes(moduleTypes []*models.Modu
 		}
 
 		lookup := map[string]interface{}{"slug": mt.Slug}
-		_, err := dtr.client.Apply("dcim", "module-types", lookup, payload)
+		mtObj, err := dtr.client.Apply("dcim", "module-types", lookup, payload)
 		if err != nil {
+			if dtr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile module type %s: %w", mt.Model, err)
 		}
+
+		if mtID := utils.GetIDFromObject(mtObj); mtID > 0 {
+			if err := dtr.reconcileModuleBayTemplates("module_type", mtID, mt.ModuleBays); err != nil {
+				return fmt.Errorf("failed to reconcile module bay templates for %s: %w", mt.Model, err)
+			}
+		}
 	}
 
 	return nil
@@ -77,11 +89,18 @@ func (dtr *DeviceTypeReconciler) ReconcileDeviceTypes(deviceTypes []*models.Devi
 			}
 			mfgObj, err := dtr.client.Apply("dcim", "manufacturers", map[string]interface{}{"slug": utils.Slugify(dt.Manufacturer)}, mfgPayload)
 			if err != nil {
+				if dtr.client.SkipTimeout(err) {
+					continue
+				}
 				return fmt.Errorf("failed to create manufacturer %s: %w", dt.Manufacturer, err)
 			}
 			mfgID = utils.GetIDFromObject(mfgObj)
 		}
 
+		if err := validateDeviceTypeSpecs(dt); err != nil {
+			return fmt.Errorf("device type %s: %w", dt.Model, err)
+		}
+
 		payload := map[string]interface{}{
 			"model":         dt.Model,
 			"slug":          dt.Slug,
@@ -93,10 +112,24 @@ func (dtr *DeviceTypeReconciler) ReconcileDeviceTypes(deviceTypes []*models.Devi
 		if dt.SubdeviceRole != "" {
 			payload["subdevice_role"] = dt.SubdeviceRole
 		}
+		if dt.PartNumber != "" {
+			payload["part_number"] = dt.PartNumber
+		}
+		if dt.Airflow != "" {
+			payload["airflow"] = dt.Airflow
+		}
+		if dt.Weight > 0 {
+			// NetBox stores two decimal places, more would show up as a change on every run
+			payload["weight"] = math.Round(dt.Weight*100) / 100
+			payload["weight_unit"] = dt.WeightUnit
+		}
 
 		lookup := map[string]interface{}{"slug": dt.Slug}
 		dtObj, err := dtr.client.Apply("dcim", "device-types", lookup, payload)
 		if err != nil {
+			if dtr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile device type %s: %w", dt.Model, err)
 		}
 
@@ -108,31 +141,70 @@ func (dtr *DeviceTypeReconciler) ReconcileDeviceTypes(deviceTypes []*models.Devi
 		// CRITICAL: Order matters! (matches Python device_types.py lines 52-112)
 		// 1. REAR PORTS FIRST - they must exist before front ports
 		if err := dtr.reconcileRearPortTemplates(dtID, dt.RearPorts); err != nil {
+			if dtr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile rear port templates for %s: %w", dt.Model, err)
 		}
 
 		// 2. FRONT PORTS SECOND - they reference rear ports by ID
 		if err := dtr.reconcileFrontPortTemplates(dtID, dt.FrontPorts); err != nil {
+			if dtr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile front port templates for %s: %w", dt.Model, err)
 		}
 
 		// 3. INTERFACES LAST
 		if err := dtr.reconcileInterfaceTemplates(dtID, dt.Interfaces); err != nil {
+			if dtr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile interface templates for %s: %w", dt.Model, err)
 		}
 
-		if err := dtr.reconcileModuleBayTemplates(dtID, dt.ModuleBays); err != nil {
+		if err := dtr.reconcileModuleBayTemplates("device_type", dtID, dt.ModuleBays); err != nil {
+			if dtr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile module bay templates for %s: %w", dt.Model, err)
 		}
 
 		if err := dtr.reconcileDeviceBayTemplates(dtID, dt.DeviceBays); err != nil {
+			if dtr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile device bay templates for %s: %w", dt.Model, err)
 		}
+
+		if err := dtr.reconcileInventoryItemTemplates(dtID, dt.InventoryItems); err != nil {
+			if dtr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile inventory item templates for %s: %w", dt.Model, err)
+		}
 	}
 
 	return nil
 }
 
+// validateDeviceTypeSpecs checks airflow and weight against NetBox's choices
+func validateDeviceTypeSpecs(dt *models.DeviceType) error {
+	if dt.Airflow != "" && !utils.Contains(constants.AirflowChoices, dt.Airflow) {
+		return fmt.Errorf("invalid airflow %q (expected one of %s)", dt.Airflow, strings.Join(constants.AirflowChoices, ", "))
+	}
+	if dt.Weight < 0 {
+		return fmt.Errorf("weight %v must not be negative", dt.Weight)
+	}
+	if dt.Weight > 0 && dt.WeightUnit == "" {
+		return fmt.Errorf("weight %v requires a weight_unit", dt.Weight)
+	}
+	if dt.WeightUnit != "" && !utils.Contains(constants.WeightUnitChoices, dt.WeightUnit) {
+		return fmt.Errorf("invalid weight_unit %q (expected one of %s)", dt.WeightUnit, strings.Join(constants.WeightUnitChoices, ", "))
+	}
+	return nil
+}
+
 // reconcileInterfaceTemplates reconciles interface templates for a device type
 func (dtr *DeviceTypeReconciler) reconcileInterfaceTemplates(deviceTypeID int, templates []models.InterfaceTemplate) error {
 	for _, tmpl := range templates {
@@ -143,10 +215,7 @@ func (dtr *DeviceTypeReconciler) reconcileInterfaceTemplates(deviceTypeID int, t
 			"mgmt_only":   tmpl.MgmtOnly,
 		}
 
-		lookup := map[string]interface{}{
-			"device_type_id": deviceTypeID,
-			"name":           tmpl.Name,
-		}
+		lookup := client.PayloadToLookup(payload, "device_type", "name")
 
 		// Remove tags from templates (they don't support tags)
 		delete(payload, "tags")
@@ -178,14 +247,11 @@ func (dtr *DeviceTypeReconciler) reconcileFrontPortTemplates(deviceTypeID int, t
 			})
 			if err == nil && len(rearPorts) > 0 {
 				payload["rear_port"] = utils.GetIDFromObject(rearPorts[0])
-				payload["rear_port_position"] = 1
+				payload["rear_port_position"] = portPosition(tmpl.RearPortPosition)
 			}
 		}
 
-		lookup := map[string]interface{}{
-			"device_type_id": deviceTypeID,
-			"name":           tmpl.Name,
-		}
+		lookup := client.PayloadToLookup(payload, "device_type", "name")
 
 		delete(payload, "tags")
 
@@ -205,13 +271,10 @@ func (dtr *DeviceTypeReconciler) reconcileRearPortTemplates(deviceTypeID int, te
 			"device_type": deviceTypeID,
 			"name":        tmpl.Name,
 			"type":        tmpl.Type,
-			"positions":   1,
+			"positions":   portPosition(tmpl.Positions),
 		}
 
-		lookup := map[string]interface{}{
-			"device_type_id": deviceTypeID,
-			"name":           tmpl.Name,
-		}
+		lookup := client.PayloadToLookup(payload, "device_type", "name")
 
 		delete(payload, "tags")
 
@@ -224,12 +287,21 @@ func (dtr *DeviceTypeReconciler) reconcileRearPortTemplates(deviceTypeID int, te
 	return nil
 }
 
-// reconcileModuleBayTemplates reconciles module bay templates
-func (dtr *DeviceTypeReconciler) reconcileModuleBayTemplates(deviceTypeID int, templates []models.ModuleBayTemplate) error {
+// portPosition returns a rear port position/count, defaulting to 1 (single-position port)
+func portPosition(n int) int {
+	if n > 0 {
+		return n
+	}
+	return 1
+}
+
+// reconcileModuleBayTemplates reconciles the module bay templates of a device type or module type
+// (ownerField "device_type" or "module_type")
+func (dtr *DeviceTypeReconciler) reconcileModuleBayTemplates(ownerField string, ownerID int, templates []models.ModuleBayTemplate) error {
 	for _, tmpl := range templates {
 		payload := map[string]interface{}{
-			"device_type": deviceTypeID,
-			"name":        tmpl.Name,
+			ownerField: ownerID,
+			"name":     tmpl.Name,
 		}
 
 		if tmpl.Label != "" {
@@ -242,10 +314,7 @@ func (dtr *DeviceTypeReconciler) reconcileModuleBayTemplates(deviceTypeID int, t
 			payload["position"] = tmpl.Position
 		}
 
-		lookup := map[string]interface{}{
-			"device_type_id": deviceTypeID,
-			"name":           tmpl.Name,
-		}
+		lookup := client.PayloadToLookup(payload, ownerField, "name")
 
 		delete(payload, "tags")
 
@@ -273,10 +342,7 @@ func (dtr *DeviceTypeReconciler) reconcileDeviceBayTemplates(deviceTypeID int, t
 			payload["description"] = tmpl.Description
 		}
 
-		lookup := map[string]interface{}{
-			"device_type_id": deviceTypeID,
-			"name":           tmpl.Name,
-		}
+		lookup := client.PayloadToLookup(payload, "device_type", "name")
 
 		delete(payload, "tags")
 
@@ -288,3 +354,53 @@ func (dtr *DeviceTypeReconciler) reconcileDeviceBayTemplates(deviceTypeID int, t
 
 	return nil
 }
+
+// reconcileInventoryItemTemplates reconciles inventory item templates, which NetBox
+// instantiates as inventory items on every device created from the device type
+func (dtr *DeviceTypeReconciler) reconcileInventoryItemTemplates(deviceTypeID int, templates []models.InventoryItemTemplate) error {
+	for _, tmpl := range templates {
+		payload := map[string]interface{}{
+			"device_type": deviceTypeID,
+			"name":        tmpl.Name,
+		}
+
+		if tmpl.Role != "" {
+			roleID, ok := dtr.client.Cache().GetGlobalIDOrReload("inventory_item_roles", tmpl.Role)
+			switch {
+			case !ok && dtr.client.IsDryRun():
+				dtr.logger.Warning("Inventory item role %s of template %s not found (created in dry-run mode), planning the template without it", tmpl.Role, tmpl.Name)
+			case !ok:
+				return fmt.Errorf("inventory item role %s not found for inventory item template %s", tmpl.Role, tmpl.Name)
+			default:
+				payload["role"] = roleID
+			}
+		}
+		if tmpl.Manufacturer != "" {
+			mfgID, ok := dtr.client.Cache().GetGlobalIDOrReload("manufacturers", tmpl.Manufacturer)
+			if !ok {
+				return fmt.Errorf("manufacturer %s not found for inventory item template %s", tmpl.Manufacturer, tmpl.Name)
+			}
+			payload["manufacturer"] = mfgID
+		}
+		if tmpl.Label != "" {
+			payload["label"] = tmpl.Label
+		}
+		if tmpl.PartID != "" {
+			payload["part_id"] = tmpl.PartID
+		}
+		if tmpl.Description != "" {
+			payload["description"] = tmpl.Description
+		}
+
+		lookup := client.PayloadToLookup(payload, "device_type", "name")
+
+		delete(payload, "tags")
+
+		_, err := dtr.client.Apply("dcim", "inventory-item-templates", lookup, payload)
+		if err != nil {
+			return fmt.Errorf("failed to reconcile inventory item template %s: %w", tmpl.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package reconciler
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+)
+
+// TestReconcileMultiPositionPortTemplates tests an MPO rear port feeding 12 front ports
+func TestReconcileMultiPositionPortTemplates(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	// Device type fixtures are single-object files
+	data, err := os.ReadFile("../../example/definitions/device_types/example-mpo-panel.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	panel := &models.DeviceType{}
+	if err := yaml.Unmarshal(data, panel); err != nil {
+		t.Fatalf("Failed to parse fixture: %v", err)
+	}
+
+	if err := NewDeviceTypeReconciler(c).ReconcileDeviceTypes([]*models.DeviceType{panel}); err != nil {
+		t.Fatalf("ReconcileDeviceTypes() error = %v", err)
+	}
+
+	rear := srv.Find("dcim", "rear-port-templates", "name", "MPO-1")
+	if rear == nil {
+		t.Fatal("Rear port template MPO-1 was not created")
+	}
+	if got := netboxtest.ID(rear["positions"]); got != 12 {
+		t.Errorf("MPO-1 positions = %v, expected 12", rear["positions"])
+	}
+
+	fronts := srv.Objects("dcim", "front-port-templates")
+	if len(fronts) != 12 {
+		t.Fatalf("Created %d front port templates, expected 12", len(fronts))
+	}
+	seen := make(map[int]bool)
+	for _, front := range fronts {
+		if netboxtest.ID(front["rear_port"]) != netboxtest.ID(rear) {
+			t.Errorf("%v is not mapped to MPO-1", front["name"])
+		}
+		position := netboxtest.ID(front["rear_port_position"])
+		if position < 1 || position > 12 || seen[position] {
+			t.Errorf("%v has invalid or duplicate rear_port_position %v", front["name"], front["rear_port_position"])
+		}
+		seen[position] = true
+	}
+}
+
+// TestReconcileInventoryItemTemplates tests that inventory item templates resolve their role and manufacturer
+func TestReconcileInventoryItemTemplates(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Add("dcim", "manufacturers", map[string]interface{}{"name": "Example Vendor", "slug": "example-vendor"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	roles := []*models.InventoryItemRole{
+		{Name: "Optic", Slug: "optic", Color: "#00BCD4"},
+		{Name: "Power Supply", Slug: "power-supply", Color: "ff9800"},
+	}
+	if err := NewFoundationReconciler(c).ReconcileInventoryItemRoles(roles); err != nil {
+		t.Fatalf("ReconcileInventoryItemRoles() error = %v", err)
+	}
+	if optic := srv.Find("dcim", "inventory-item-roles", "slug", "optic"); optic == nil || optic["color"] != "00bcd4" {
+		t.Fatalf("Inventory item role optic = %v, expected color 00bcd4", optic)
+	}
+
+	data, err := os.ReadFile("../../example/definitions/device_types/example-switch.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	sw := &models.DeviceType{}
+	if err := yaml.Unmarshal(data, sw); err != nil {
+		t.Fatalf("Failed to parse fixture: %v", err)
+	}
+
+	dtr := NewDeviceTypeReconciler(c)
+	if err := dtr.ReconcileDeviceTypes([]*models.DeviceType{sw}); err != nil {
+		t.Fatalf("ReconcileDeviceTypes() error = %v", err)
+	}
+
+	dt := srv.Find("dcim", "device-types", "slug", "example-switch-48")
+	optic := srv.Find("dcim", "inventory-item-templates", "name", "Uplink Optic 1")
+	if optic == nil {
+		t.Fatal("Inventory item template Uplink Optic 1 was not created")
+	}
+	if netboxtest.ID(optic["device_type"]) != netboxtest.ID(dt) {
+		t.Errorf("Uplink Optic 1 device_type = %v, expected %v", optic["device_type"], dt["id"])
+	}
+	if netboxtest.ID(optic["role"]) != netboxtest.ID(srv.Find("dcim", "inventory-item-roles", "slug", "optic")) {
+		t.Errorf("Uplink Optic 1 role = %v, expected optic", optic["role"])
+	}
+	if netboxtest.ID(optic["manufacturer"]) != netboxtest.ID(srv.Find("dcim", "manufacturers", "slug", "example-vendor")) {
+		t.Errorf("Uplink Optic 1 manufacturer = %v, expected Example Vendor", optic["manufacturer"])
+	}
+	if optic["part_id"] != "QSFP-100G-SR4" {
+		t.Errorf("Uplink Optic 1 part_id = %v", optic["part_id"])
+	}
+	if psu := srv.Find("dcim", "inventory-item-templates", "name", "PSU1"); psu == nil || psu["label"] != "Power Supply 1" {
+		t.Errorf("PSU1 template = %v, expected label Power Supply 1", psu)
+	}
+
+	// Second run must not produce any updates
+	srv.ResetRequests()
+	if err := dtr.ReconcileDeviceTypes([]*models.DeviceType{sw}); err != nil {
+		t.Fatalf("ReconcileDeviceTypes() second run error = %v", err)
+	}
+	if got := srv.CountRequests("PATCH", "/api/dcim/inventory-item-templates/"); got != 0 {
+		t.Errorf("Second run sent %d inventory item template updates, expected none", got)
+	}
+
+	sw.InventoryItems = []models.InventoryItemTemplate{{Name: "Fan1", Role: "fan"}}
+	if err := dtr.ReconcileDeviceTypes([]*models.DeviceType{sw}); err == nil {
+		t.Error("ReconcileDeviceTypes() expected error for unknown inventory item role")
+	}
+
+	// A dry-run plans a template whose role is only created in the same run
+	for _, simulate := range []bool{false, true} {
+		dry, err := client.NewClient(srv.URL, "test-token", true)
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		dry.Logger().SetOutput(&bytes.Buffer{}, &bytes.Buffer{})
+		dry.SetSimulate(simulate)
+		if err := NewFoundationReconciler(dry).ReconcileInventoryItemRoles([]*models.InventoryItemRole{{Name: "Fan", Slug: "fan", Color: "607d8b"}}); err != nil {
+			t.Fatalf("ReconcileInventoryItemRoles(simulate=%v) error = %v", simulate, err)
+		}
+		if err := NewDeviceTypeReconciler(dry).ReconcileDeviceTypes([]*models.DeviceType{sw}); err != nil {
+			t.Errorf("ReconcileDeviceTypes(simulate=%v) error = %v, expected the template planned", simulate, err)
+		}
+	}
+	if srv.Find("dcim", "inventory-item-templates", "name", "Fan1") != nil {
+		t.Error("Dry-run created inventory item template Fan1")
+	}
+}
+
+// TestReconcileDeviceTypeSpecs tests part number, airflow and weight on a device type, and that
+// the weight does not show up as a change on the next run
+func TestReconcileDeviceTypeSpecs(t *testing.T) {
+	c, srv := newTestClient(t)
+	dtr := NewDeviceTypeReconciler(c)
+
+	data, err := os.ReadFile("../../example/definitions/device_types/example-server.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	server := &models.DeviceType{}
+	if err := yaml.Unmarshal(data, server); err != nil {
+		t.Fatalf("Failed to parse fixture: %v", err)
+	}
+
+	for run := 1; run <= 2; run++ {
+		if err := dtr.ReconcileDeviceTypes([]*models.DeviceType{server}); err != nil {
+			t.Fatalf("ReconcileDeviceTypes() run %d error = %v", run, err)
+		}
+	}
+
+	dt := srv.Find("dcim", "device-types", "slug", "example-server-r100")
+	if dt == nil {
+		t.Fatal("Device type example-server-r100 was not created")
+	}
+	expected := map[string]interface{}{"part_number": "EX-R100-2U", "airflow": "front-to-rear", "weight": 21.5, "weight_unit": "kg"}
+	for field, value := range expected {
+		if dt[field] != value {
+			t.Errorf("%s = %v, expected %v", field, dt[field], value)
+		}
+	}
+	if got := srv.CountRequests("PATCH", "/api/dcim/device-types/"); got != 0 {
+		t.Errorf("Unchanged device type was updated %d times", got)
+	}
+
+	invalid := []*models.DeviceType{
+		{Model: "A", Slug: "a", Manufacturer: "Example Vendor", Airflow: "front-to-back"},
+		{Model: "B", Slug: "b", Manufacturer: "Example Vendor", Weight: 3},
+		{Model: "C", Slug: "c", Manufacturer: "Example Vendor", Weight: 3, WeightUnit: "stone"},
+	}
+	for _, dt := range invalid {
+		if err := dtr.ReconcileDeviceTypes([]*models.DeviceType{dt}); err == nil {
+			t.Errorf("ReconcileDeviceTypes(%s) expected a validation error", dt.Model)
+		}
+	}
+}
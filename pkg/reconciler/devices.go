@@ -2,7 +2,11 @@ package reconciler
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
 
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
 	"github.com/braunma/netbox-gitops-controller/pkg/client"
 	"github.com/braunma/netbox-gitops-controller/pkg/models"
 	"github.com/braunma/netbox-gitops-controller/pkg/utils"
@@ -15,6 +19,21 @@ type DeviceReconciler struct {
 	cableReconciler *CableReconciler
 	// Track all device interfaces/ports for cable reconciliation at the end
 	pendingCables []pendingCable
+	// deferCables leaves pending cables to an explicit ReconcileCables call
+	deferCables bool
+	// Mass status change safety (see checkStatusChanges)
+	allowMassStatusChange   bool
+	massStatusChangePercent int
+	// allowInterfaceTypeChange permits type changes of module-backed and breakout interfaces
+	// (see checkInterfaceTypeChange)
+	allowInterfaceTypeChange bool
+	// interfacesFromTemplate leaves interfaces the device type's templates provide to NetBox
+	// (see templatedInterface)
+	interfacesFromTemplate bool
+	// lookupStrategy selects how devices are matched to existing NetBox devices (see lookup.go)
+	lookupStrategy string
+	// templateNames memoizes the interface template names per device type slug for the run
+	templateNames map[string]map[string]bool
 }
 
 // pendingCable tracks a cable that needs to be created after all devices are processed
@@ -34,23 +53,143 @@ func NewDeviceReconciler(c *client.NetBoxClient) *DeviceReconciler {
 		logger:          c.Logger(),
 		cableReconciler: NewCableReconciler(c),
 		pendingCables:   make([]pendingCable, 0),
+		templateNames:   make(map[string]map[string]bool),
+
+		massStatusChangePercent: constants.DefaultMassStatusChangePercent,
+		lookupStrategy:          LookupNameSite,
+	}
+}
+
+// SetLookupStrategy sets how devices are matched to existing NetBox devices
+// (LookupNameSite, LookupName or "cf:<field>")
+func (dr *DeviceReconciler) SetLookupStrategy(strategy string) error {
+	if err := validateLookupStrategy(strategy); err != nil {
+		return err
 	}
+	dr.lookupStrategy = strategy
+	return nil
+}
+
+// SetStatusChangeGuard configures the mass status change safety check
+func (dr *DeviceReconciler) SetStatusChangeGuard(allow bool, thresholdPercent int) {
+	dr.allowMassStatusChange = allow
+	dr.massStatusChangePercent = thresholdPercent
+}
+
+// AllowInterfaceTypeChange permits changing the type of module-backed and breakout interfaces
+func (dr *DeviceReconciler) AllowInterfaceTypeChange(allow bool) {
+	dr.allowInterfaceTypeChange = allow
+}
+
+// InterfacesFromTemplate makes interface reconciliation only update the device-specific
+// settings of interfaces the device type's interface templates provide, never create them
+func (dr *DeviceReconciler) InterfacesFromTemplate(enable bool) {
+	dr.interfacesFromTemplate = enable
+}
+
+// DeferCables makes ReconcileDevices leave cable reconciliation to a later ReconcileCables call
+func (dr *DeviceReconciler) DeferCables() {
+	dr.deferCables = true
 }
 
 // ReconcileDevices reconciles device configurations
 func (dr *DeviceReconciler) ReconcileDevices(devices []*models.DeviceConfig) error {
 	dr.logger.Info("Reconciling %d devices...", len(devices))
 
+	// Asset tags are globally unique in NetBox, catch conflicts before the opaque 400
+	if err := dr.checkAssetTags(devices); err != nil {
+		return err
+	}
+
+	// Two components with the same name would issue conflicting applies for one object
+	if err := checkDuplicateComponents(devices); err != nil {
+		return err
+	}
+
+	// Refuse to flip the status of most of the fleet by accident (e.g., a bad default)
+	if err := dr.checkStatusChanges(devices); err != nil {
+		return err
+	}
+
+	// Mismatched LAG MTUs and jumbo MTUs are applied, but usually a mistake
+	dr.checkMTUs(devices)
+
+	// Parents must exist before their children are installed into a bay, and depends_on
+	// devices before the devices naming them
+	devices, err := orderDevices(devices)
+	if err != nil {
+		return err
+	}
+
 	// Phase 1: Reconcile all devices and their ports
 	dr.logger.Debug("═══ Phase 1: Devices and Ports ═══")
 	for i, device := range devices {
 		dr.logger.Debug("──── Device %d/%d: %s ────", i+1, len(devices), device.Name)
 		if err := dr.reconcileDevice(device); err != nil {
+			// A device that timed out is skipped with its components (--fail-fast=false)
+			if dr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile device %s: %w", device.Name, err)
 		}
 	}
 
+	if dr.deferCables {
+		return nil
+	}
+
 	// Phase 2: Reconcile all cables (after all devices/ports exist)
+	return dr.ReconcileCables()
+}
+
+// orderDevices returns the devices with every parent_device and depends_on entry ahead of
+// the devices referencing it, otherwise keeping the loaded order. Referenced devices that are
+// not in the list must already exist.
+func orderDevices(devices []*models.DeviceConfig) ([]*models.DeviceConfig, error) {
+	byName := make(map[string]*models.DeviceConfig, len(devices))
+	for _, device := range devices {
+		byName[device.Name] = device
+	}
+
+	ordered := make([]*models.DeviceConfig, 0, len(devices))
+	done := make(map[*models.DeviceConfig]bool, len(devices))
+	visiting := make(map[*models.DeviceConfig]bool)
+
+	var visit func(device *models.DeviceConfig, path []string) error
+	visit = func(device *models.DeviceConfig, path []string) error {
+		if done[device] {
+			return nil
+		}
+		path = append(path, device.Name)
+		if visiting[device] {
+			return fmt.Errorf("device dependency cycle (parent_device/depends_on): %s", strings.Join(path, " → "))
+		}
+		visiting[device] = true
+
+		for _, name := range append([]string{device.ParentDevice}, device.DependsOn...) {
+			if dependency, ok := byName[name]; ok {
+				if err := visit(dependency, path); err != nil {
+					return err
+				}
+			}
+		}
+
+		visiting[device] = false
+		done[device] = true
+		ordered = append(ordered, device)
+		return nil
+	}
+
+	for _, device := range devices {
+		if err := visit(device, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// ReconcileCables reconciles the cables collected by ReconcileDevices
+func (dr *DeviceReconciler) ReconcileCables() error {
 	dr.logger.Debug("═══ Phase 2: Cables ═══")
 	dr.logger.Info("Reconciling %d pending cable connections...", len(dr.pendingCables))
 	if err := dr.reconcilePendingCables(); err != nil {
@@ -60,22 +199,284 @@ func (dr *DeviceReconciler) ReconcileDevices(devices []*models.DeviceConfig) err
 	return nil
 }
 
+// checkAssetTags fails if an asset tag is used twice in the inventory (devices and modules)
+// or is already held by a NetBox device or module that is not part of the inventory
+func (dr *DeviceReconciler) checkAssetTags(devices []*models.DeviceConfig) error {
+	owners := make(map[string][]string)
+	var tags []string
+	inventory := make(map[string]bool, len(devices))
+
+	addOwner := func(tag, owner string) {
+		if _, seen := owners[tag]; !seen {
+			tags = append(tags, tag)
+		}
+		owners[tag] = append(owners[tag], owner)
+	}
+
+	var conflicts []string
+	check := func(serial, assetTag *string, owner string) {
+		if err := validateSerial(serial); err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %v", owner, err))
+		}
+		if err := validateAssetTag(assetTag); err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %v", owner, err))
+		}
+		if assetTag != nil && *assetTag != "" {
+			addOwner(*assetTag, owner)
+		}
+	}
+
+	for _, device := range devices {
+		inventory[device.Name] = true
+		check(device.Serial, device.AssetTag, "device "+device.Name)
+		forEachModule(device.Modules, func(module *models.ModuleConfig) {
+			check(module.Serial, module.AssetTag, fmt.Sprintf("module %s on %s", module.Name, device.Name))
+		})
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("invalid serials or asset tags in inventory:\n  %s", strings.Join(conflicts, "\n  "))
+	}
+
+	for _, tag := range tags {
+		if len(owners[tag]) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("asset tag %q is used by %s", tag, strings.Join(owners[tag], ", ")))
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("duplicate asset tags in inventory:\n  %s", strings.Join(conflicts, "\n  "))
+	}
+
+	// Asset tags already held by objects outside the inventory
+	holders, err := assetTagHolders(dr.client, "dcim", "devices", tags)
+	if err != nil {
+		return err
+	}
+	modules, err := assetTagHolders(dr.client, "dcim", "modules", tags)
+	if err != nil {
+		return err
+	}
+	for _, holder := range holders {
+		tag, _ := holder["asset_tag"].(string)
+		if name, _ := holder["name"].(string); !inventory[name] {
+			conflicts = append(conflicts, dr.assetTagConflict(tag, owners[tag], holder, "device "+name))
+		}
+	}
+	for _, holder := range modules {
+		tag, _ := holder["asset_tag"].(string)
+		if name := nestedString(holder, "device", "name"); !inventory[name] {
+			conflicts = append(conflicts, dr.assetTagConflict(tag, owners[tag], holder, "module on device "+name))
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("asset tags already in use:\n  %s", strings.Join(conflicts, "\n  "))
+	}
+
+	return nil
+}
+
+// checkDuplicateComponents fails if a device declares an interface, front port, rear port
+// or module bay name more than once
+func checkDuplicateComponents(devices []*models.DeviceConfig) error {
+	var duplicates []string
+
+	check := func(device, kind string, names []string) {
+		seen := make(map[string]bool, len(names))
+		reported := make(map[string]bool)
+		for _, name := range names {
+			if seen[name] && !reported[name] {
+				duplicates = append(duplicates, fmt.Sprintf("device %s declares %s %q more than once", device, kind, name))
+				reported[name] = true
+			}
+			seen[name] = true
+		}
+	}
+
+	for _, device := range devices {
+		var interfaces, frontPorts, rearPorts, moduleBays []string
+		for _, iface := range device.Interfaces {
+			interfaces = append(interfaces, iface.Name)
+		}
+		for _, port := range device.FrontPorts {
+			frontPorts = append(frontPorts, port.Name)
+		}
+		for _, port := range device.RearPorts {
+			rearPorts = append(rearPorts, port.Name)
+		}
+		for _, module := range device.Modules {
+			moduleBays = append(moduleBays, module.Name)
+		}
+
+		check(device.Name, "interface", interfaces)
+		check(device.Name, "front port", frontPorts)
+		check(device.Name, "rear port", rearPorts)
+		check(device.Name, "module bay", moduleBays)
+
+		// Bays of nested modules are scoped to their parent module
+		forEachModule(device.Modules, func(module *models.ModuleConfig) {
+			var childBays []string
+			for _, child := range module.Modules {
+				childBays = append(childBays, child.Name)
+			}
+			check(device.Name, "module bay in module "+module.Name, childBays)
+		})
+	}
+
+	if len(duplicates) > 0 {
+		return fmt.Errorf("duplicate component names in inventory:\n  %s", strings.Join(duplicates, "\n  "))
+	}
+	return nil
+}
+
+// forEachModule calls fn for every module, including modules nested in other modules
+func forEachModule(modules []models.ModuleConfig, fn func(module *models.ModuleConfig)) {
+	for i := range modules {
+		fn(&modules[i])
+		forEachModule(modules[i].Modules, fn)
+	}
+}
+
+// assetTagValue returns the payload value for an asset tag. Asset tags are unique,
+// so a cleared tag is sent as null rather than as an empty string.
+func assetTagValue(tag string) interface{} {
+	if tag == "" {
+		return client.Null
+	}
+	return tag
+}
+
+// validateSerial checks a serial against NetBox's constraints
+func validateSerial(serial *string) error {
+	if serial == nil {
+		return nil
+	}
+	if len(*serial) > constants.MaxSerialLength {
+		return fmt.Errorf("serial %q is longer than %d characters", *serial, constants.MaxSerialLength)
+	}
+	return nil
+}
+
+// validateAssetTag checks an asset tag against NetBox's constraints. Surrounding whitespace
+// is rejected because NetBox strips it, which would make the tag differ on every run.
+func validateAssetTag(tag *string) error {
+	if tag == nil {
+		return nil
+	}
+	if len(*tag) > constants.MaxAssetTagLength {
+		return fmt.Errorf("asset tag %q is longer than %d characters", *tag, constants.MaxAssetTagLength)
+	}
+	if strings.TrimSpace(*tag) != *tag {
+		return fmt.Errorf("asset tag %q has leading or trailing whitespace", *tag)
+	}
+	for _, r := range *tag {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("asset tag %q contains control characters", *tag)
+		}
+	}
+	return nil
+}
+
+// assetTagConflict describes an asset tag held by an object outside the inventory
+func (dr *DeviceReconciler) assetTagConflict(tag string, owners []string, holder client.Object, holderDesc string) string {
+	managed := "unmanaged"
+	if dr.client.Tags().IsManaged(holder, dr.client.ManagedTagID()) {
+		managed = "managed"
+	}
+	return fmt.Sprintf("asset tag %q (%s) is already held by %s %s (ID: %d)",
+		tag, strings.Join(owners, ", "), managed, holderDesc, utils.GetIDFromObject(holder))
+}
+
+// assetTagHolders returns the objects of an endpoint holding any of the asset tags, with a
+// repeated asset_tag filter per batch of tags instead of a request per tag
+func assetTagHolders(c *client.NetBoxClient, app, endpoint string, tags []string) ([]client.Object, error) {
+	var holders []client.Object
+	for start := 0; start < len(tags); start += constants.AssetTagBatchSize {
+		batch := tags[start:min(start+constants.AssetTagBatchSize, len(tags))]
+		objects, err := c.FilterAll(app, endpoint, map[string]interface{}{"asset_tag": batch})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up asset tags on %s: %w", endpoint, err)
+		}
+		holders = append(holders, objects...)
+	}
+	return holders, nil
+}
+
+// checkStatusChanges compares desired and current status of existing managed devices
+// and fails if more than the configured percentage would change in this run
+func (dr *DeviceReconciler) checkStatusChanges(devices []*models.DeviceConfig) error {
+	existing, err := dr.client.FilterManaged("dcim", "devices")
+	if err != nil {
+		return fmt.Errorf("failed to list managed devices: %w", err)
+	}
+	if len(existing) == 0 {
+		return nil
+	}
+
+	currentStatus := make(map[string]string, len(existing))
+	for _, obj := range existing {
+		name, _ := obj["name"].(string)
+		currentStatus[name] = statusValue(obj["status"])
+	}
+
+	var changed []string
+	for _, device := range devices {
+		current, ok := currentStatus[device.Name]
+		if ok && current != desiredStatus(device) {
+			changed = append(changed, device.Name)
+		}
+	}
+
+	percent := len(changed) * 100 / len(existing)
+	if len(changed) < constants.MassStatusChangeMinDevices || percent <= dr.massStatusChangePercent {
+		return nil
+	}
+
+	msg := fmt.Sprintf("this run would change the status of %d of %d managed devices (%d%%, threshold %d%%)",
+		len(changed), len(existing), percent, dr.massStatusChangePercent)
+	if dr.allowMassStatusChange {
+		dr.logger.Warning("%s, allowed by --allow-mass-status-change", msg)
+		return nil
+	}
+
+	return fmt.Errorf("%s: %v; re-run with --allow-mass-status-change if this is intended", msg, changed)
+}
+
+// desiredStatus returns the device status from YAML
+// Default status to "active" if not provided (matches Python exclude_none behavior)
+func desiredStatus(device *models.DeviceConfig) string {
+	if device.Status == "" {
+		return "active"
+	}
+	return device.Status
+}
+
+// statusValue extracts the status value from a NetBox status field ({"value": ..., "label": ...} or string)
+func statusValue(status interface{}) string {
+	switch v := status.(type) {
+	case map[string]interface{}:
+		value, _ := v["value"].(string)
+		return value
+	case string:
+		return v
+	}
+	return ""
+}
+
 // reconcileDevice reconciles a single device
 func (dr *DeviceReconciler) reconcileDevice(device *models.DeviceConfig) error {
-	// Get required IDs
-	siteID, ok := dr.client.Cache().GetID("sites", device.SiteSlug)
+	// Get required IDs (reloading once on a miss, the object may have been created this run)
+	siteID, ok := dr.client.Cache().GetGlobalIDOrReload("sites", device.SiteSlug)
 	if !ok {
 		return fmt.Errorf("site %s not found", device.SiteSlug)
 	}
 
-	roleID, ok := dr.client.Cache().GetID("roles", device.RoleSlug)
+	roleID, ok := dr.client.Cache().GetGlobalIDOrReload("roles", device.RoleSlug)
 	if !ok {
 		return fmt.Errorf("role %s not found", device.RoleSlug)
 	}
 
-	deviceTypeID, ok := dr.client.Cache().GetID("device_types", device.DeviceTypeSlug)
-	if !ok {
-		return fmt.Errorf("device type %s not found", device.DeviceTypeSlug)
+	deviceTypeID, err := dr.resolveDeviceType(device.DeviceTypeSlug)
+	if err != nil {
+		return err
 	}
 
 	// A. Rack & Parent Logic (matches Python lines 155-179)
@@ -133,11 +534,7 @@ func (dr *DeviceReconciler) reconcileDevice(device *models.DeviceConfig) error {
 	}
 
 	// B. Build device payload
-	// Default status to "active" if not provided (matches Python exclude_none behavior)
-	status := device.Status
-	if status == "" {
-		status = "active"
-	}
+	status := desiredStatus(device)
 
 	payload := map[string]interface{}{
 		"name":        device.Name,
@@ -167,18 +564,34 @@ func (dr *DeviceReconciler) reconcileDevice(device *models.DeviceConfig) error {
 	}
 	// Else: No rack and no bay - position/face cannot be set
 
-	if device.Serial != "" {
-		payload["serial"] = device.Serial
+	if device.Serial != nil {
+		payload["serial"] = *device.Serial
+	}
+	if device.AssetTag != nil {
+		payload["asset_tag"] = assetTagValue(*device.AssetTag)
 	}
 
-	if device.AssetTag != "" {
-		payload["asset_tag"] = device.AssetTag
+	// Virtualization host: cluster membership
+	if device.ClusterName != "" {
+		if clusterID, ok := dr.client.Cache().GetGlobalID("clusters", device.ClusterName); ok {
+			payload["cluster"] = clusterID
+		} else {
+			dr.logger.Warning("Cluster %s not found for device %s, skipping cluster assignment", device.ClusterName, device.Name)
+		}
+	}
+
+	if device.ConfigTemplate != "" {
+		tmplID, ok := dr.client.Cache().GetGlobalIDOrReload("config_templates", device.ConfigTemplate)
+		if !ok {
+			return fmt.Errorf("config template %s not found for device %s", device.ConfigTemplate, device.Name)
+		}
+		payload["config_template"] = tmplID
 	}
 
 	// C. Create or update device
-	lookup := map[string]interface{}{
-		"name":    device.Name,
-		"site_id": siteID,
+	lookup := lookupFields(dr.lookupStrategy, device.Name, siteID)
+	if customFields := lookupCustomFields(dr.lookupStrategy, device.Name); customFields != nil {
+		payload["custom_fields"] = customFields
 	}
 
 	deviceObj, err := dr.client.Apply("dcim", "devices", lookup, payload)
@@ -205,6 +618,13 @@ func (dr *DeviceReconciler) reconcileDevice(device *models.DeviceConfig) error {
 		return fmt.Errorf("failed to reconcile interfaces: %w", err)
 	}
 
+	if len(device.ManagementIPs) > 0 {
+		dr.logger.Debug("  Reconciling management IPs for %s...", device.Name)
+		if err := dr.reconcileManagementIPs(deviceID, device); err != nil {
+			return fmt.Errorf("failed to reconcile management IPs: %w", err)
+		}
+	}
+
 	dr.logger.Debug("  Reconciling front ports for %s...", device.Name)
 	if err := dr.reconcileFrontPorts(deviceID, device); err != nil {
 		return fmt.Errorf("failed to reconcile front ports: %w", err)
@@ -215,6 +635,12 @@ func (dr *DeviceReconciler) reconcileDevice(device *models.DeviceConfig) error {
 		return fmt.Errorf("failed to reconcile rear ports: %w", err)
 	}
 
+	// Verify front port → rear port mappings (catches typos in large panel definitions)
+	if len(device.FrontPorts) > 0 {
+		dr.logger.Debug("  Verifying port mappings for %s...", device.Name)
+		dr.verifyPortMappings(deviceID, device)
+	}
+
 	// Self-healing: Create missing device bays from device type templates
 	dr.logger.Debug("  Reconciling device bays for %s...", device.Name)
 	if err := dr.reconcileDeviceBays(deviceID, deviceTypeID); err != nil {
@@ -226,6 +652,188 @@ func (dr *DeviceReconciler) reconcileDevice(device *models.DeviceConfig) error {
 		return fmt.Errorf("failed to reconcile modules: %w", err)
 	}
 
+	if len(device.Services) > 0 {
+		dr.logger.Debug("  Reconciling services for %s...", device.Name)
+		if err := dr.reconcileServices(deviceID, device); err != nil {
+			return fmt.Errorf("failed to reconcile services: %w", err)
+		}
+	}
+
+	if len(device.Contacts) > 0 {
+		dr.logger.Debug("  Reconciling contacts for %s...", device.Name)
+		if err := reconcileContactAssignments(dr.client, "dcim.device", deviceID, device.Contacts); err != nil {
+			return fmt.Errorf("failed to reconcile contacts: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// resolveDeviceType resolves device_type_slug, which holds the device type's slug or,
+// failing that, its model name (e.g. "PowerEdge R740"). The global cache is tried first;
+// NetBox is only asked on a miss, which also reports models shared by several manufacturers.
+func (dr *DeviceReconciler) resolveDeviceType(ref string) (int, error) {
+	if id, ok := dr.client.Cache().GetGlobalID("device_types", ref); ok {
+		if slug, ok := dr.client.Cache().GetSlug("device_types", id); ok && slug != ref {
+			dr.logger.Info("  Device type %q matched by model name (slug: %v)", ref, slug)
+		}
+		return id, nil
+	}
+
+	bySlug, err := dr.client.Filter("dcim", "device-types", map[string]interface{}{"slug": ref})
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up device type %s: %w", ref, err)
+	}
+	if len(bySlug) > 0 {
+		id := utils.GetIDFromObject(bySlug[0])
+		dr.client.Cache().Set("device_types", ref, id)
+		return id, nil
+	}
+
+	byModel, err := dr.client.Filter("dcim", "device-types", map[string]interface{}{"model": ref})
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up device type %s: %w", ref, err)
+	}
+	switch {
+	case len(byModel) == 1:
+		dr.logger.Info("  Device type %q matched by model name (slug: %v)", ref, byModel[0]["slug"])
+		id := utils.GetIDFromObject(byModel[0])
+		dr.client.Cache().Set("device_types", ref, id)
+		return id, nil
+	case len(byModel) > 1:
+		// Models are only unique per manufacturer
+		return 0, fmt.Errorf("device type model %q matches %d device types of different manufacturers, use the slug", ref, len(byModel))
+	}
+
+	return 0, fmt.Errorf("device type %s not found by slug or model name", ref)
+}
+
+// validateWireless checks that radio settings are only set on wireless interfaces and in range
+func validateWireless(iface models.InterfaceConfig) error {
+	if iface.TxPower == 0 && iface.RFChannel == "" && iface.RFChannelWidth == 0 {
+		return nil
+	}
+	if !utils.Contains(constants.WirelessInterfaceTypes, iface.Type) {
+		return fmt.Errorf("tx_power and rf_channel are only valid on wireless interfaces, not type %q", iface.Type)
+	}
+	if iface.TxPower < 0 || iface.TxPower > constants.MaxTxPower {
+		return fmt.Errorf("tx_power %d out of range (0-%d dBm)", iface.TxPower, constants.MaxTxPower)
+	}
+	return nil
+}
+
+// validateVLANMode checks that the VLAN assignment fits the 802.1Q mode:
+// access ports carry no tagged VLANs, tagged-all ports no explicit untagged VLAN
+func validateVLANMode(iface models.InterfaceConfig) error {
+	switch iface.Mode {
+	case "access":
+		if len(iface.TaggedVLANs) > 0 {
+			return fmt.Errorf("tagged_vlans cannot be set in access mode")
+		}
+	case "tagged-all":
+		if iface.UntaggedVLAN != "" {
+			return fmt.Errorf("untagged_vlan cannot be set in tagged-all mode")
+		}
+	}
+	return nil
+}
+
+// checkMTUs warns about LAG members whose MTU differs from their LAG's, which breaks the
+// bundle on most platforms, and about MTUs above MaxJumboMTU, which few platforms support.
+// A member without an MTU keeps the platform default, so it is warned about under a jumbo LAG.
+func (dr *DeviceReconciler) checkMTUs(devices []*models.DeviceConfig) {
+	for _, device := range devices {
+		// Invalid members lists are reported when the device is reconciled
+		interfaces, err := applyLAGMembers(device.Interfaces)
+		if err != nil {
+			interfaces = device.Interfaces
+		}
+
+		lagMTUs := make(map[string]int)
+		for _, iface := range interfaces {
+			if iface.Type == "lag" && iface.MTU > 0 {
+				lagMTUs[iface.Name] = iface.MTU
+			}
+		}
+
+		for _, iface := range interfaces {
+			if iface.MTU > constants.MaxJumboMTU {
+				dr.logger.Warning("Interface %s on %s has MTU %d, above the %d most platforms support",
+					iface.Name, device.Name, iface.MTU, constants.MaxJumboMTU)
+			}
+			lagMTU, ok := lagMTUs[iface.LAG]
+			if !ok {
+				continue
+			}
+			switch {
+			case iface.MTU > 0 && iface.MTU != lagMTU:
+				dr.logger.Warning("Interface %s on %s has MTU %d, but its LAG %s has MTU %d",
+					iface.Name, device.Name, iface.MTU, iface.LAG, lagMTU)
+			case iface.MTU == 0 && lagMTU > constants.DefaultMTU:
+				dr.logger.Warning("Interface %s on %s has no MTU, but its LAG %s has jumbo MTU %d",
+					iface.Name, device.Name, iface.LAG, lagMTU)
+			}
+		}
+	}
+}
+
+// validateLAG checks that a member does not join itself and that a LAG declared on the
+// same device is of type lag
+func validateLAG(iface models.InterfaceConfig, interfaces []models.InterfaceConfig) error {
+	if iface.LAG == "" {
+		return nil
+	}
+	if iface.LAG == iface.Name {
+		return fmt.Errorf("cannot be a member of itself")
+	}
+	for _, other := range interfaces {
+		if other.Name == iface.LAG && other.Type != "" && other.Type != "lag" {
+			return fmt.Errorf("lag %s is of type %s, expected lag", iface.LAG, other.Type)
+		}
+	}
+	return nil
+}
+
+// reconcileServices reconciles services bound to a device (IPs must be reconciled first)
+func (dr *DeviceReconciler) reconcileServices(deviceID int, device *models.DeviceConfig) error {
+	for _, service := range device.Services {
+		payload := map[string]interface{}{
+			"device":   deviceID,
+			"name":     service.Name,
+			"protocol": service.Protocol,
+			"ports":    service.Ports,
+		}
+
+		if len(service.IPAddresses) > 0 {
+			var ipIDs []int
+			for _, address := range service.IPAddresses {
+				ips, err := dr.client.Filter("ipam", "ip-addresses", map[string]interface{}{
+					"address":   address,
+					"device_id": deviceID,
+				})
+				if err != nil || len(ips) == 0 {
+					dr.logger.Warning("IP address %s not found on device %s, not binding service %s to it", address, device.Name, service.Name)
+					continue
+				}
+				ipIDs = append(ipIDs, utils.GetIDFromObject(ips[0]))
+			}
+			payload["ipaddresses"] = ipIDs
+		}
+		if service.Description != "" {
+			payload["description"] = service.Description
+		}
+
+		lookup := map[string]interface{}{
+			"device_id": deviceID,
+			"name":      service.Name,
+			"protocol":  service.Protocol,
+		}
+
+		if _, err := dr.client.Apply("ipam", "services", lookup, payload); err != nil {
+			return fmt.Errorf("failed to reconcile service %s: %w", service.Name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -238,19 +846,42 @@ func (dr *DeviceReconciler) reconcileInterfaces(deviceID int, device *models.Dev
 		return fmt.Errorf("site %s not found in cache", device.SiteSlug)
 	}
 
+	// A bad LAG reference fails the device before any of its interfaces is written
+	bundled, err := applyLAGMembers(device.Interfaces)
+	if err != nil {
+		return err
+	}
+	existingLAGs, err := dr.resolveLAGs(deviceID, device)
+	if err != nil {
+		return err
+	}
+
+	// Interface IDs by name, used to resolve bridges in the second pass
+	ifaceIDs := make(map[string]int)
+
+	var templated map[string]bool
+	if dr.interfacesFromTemplate {
+		if templated, err = dr.templateInterfaceNames(device); err != nil {
+			return err
+		}
+	}
+
 	for i, iface := range device.Interfaces {
 		dr.logger.Debug("    Interface %d/%d: %s", i+1, len(device.Interfaces), iface.Name)
 
 		payload := map[string]interface{}{
 			"device":  deviceID,
 			"name":    iface.Name,
-			"enabled": iface.Enabled,
+			"enabled": iface.IsEnabled(),
 		}
 
 		// Only include type if not empty (NetBox rejects empty string)
 		if iface.Type != "" {
 			payload["type"] = iface.Type
 		}
+		if iface.MgmtOnly != nil {
+			payload["mgmt_only"] = *iface.MgmtOnly
+		}
 
 		if iface.Label != "" {
 			payload["label"] = iface.Label
@@ -261,18 +892,44 @@ func (dr *DeviceReconciler) reconcileInterfaces(deviceID int, device *models.Dev
 		if iface.MTU > 0 {
 			payload["mtu"] = iface.MTU
 		}
+		if err := validateWireless(iface); err != nil {
+			return fmt.Errorf("interface %s: %w", iface.Name, err)
+		}
+		if iface.TxPower > 0 {
+			payload["tx_power"] = iface.TxPower
+		}
+		if iface.RFChannel != "" {
+			payload["rf_channel"] = iface.RFChannel
+		}
+		if iface.RFChannelWidth > 0 {
+			payload["rf_channel_width"] = iface.RFChannelWidth
+		}
+		if iface.WWN != "" {
+			wwn, err := utils.NormalizeWWN(iface.WWN)
+			if err != nil {
+				return fmt.Errorf("interface %s: %w", iface.Name, err)
+			}
+			payload["wwn"] = wwn
+		}
 
 		// VLAN configuration
+		if err := validateVLANMode(iface); err != nil {
+			return fmt.Errorf("interface %s: %w", iface.Name, err)
+		}
 		if iface.Mode != "" {
 			payload["mode"] = iface.Mode
 		}
+		if iface.Mode == "access" {
+			// Clear tagged VLANs left over from a previous trunk configuration
+			payload["tagged_vlans"] = []int{}
+		}
 
 		// CRITICAL: Use site-scoped cache lookup for VLANs
 		// VLANs are cached with composite keys: "site-{siteID}:{vlanName}"
 		// This prevents collisions when multiple sites have VLANs with same name
 		// (Enterprise fix: matches Python pattern but with proper site scoping)
 		if iface.UntaggedVLAN != "" {
-			vlanID, ok := dr.client.Cache().GetSiteID("vlans", siteID, iface.UntaggedVLAN)
+			vlanID, ok := dr.resolveVLAN(siteID, iface.UntaggedVLAN)
 			if ok {
 				payload["untagged_vlan"] = vlanID
 				dr.logger.Debug("      Untagged VLAN: %s (ID: %d)", iface.UntaggedVLAN, vlanID)
@@ -281,53 +938,442 @@ func (dr *DeviceReconciler) reconcileInterfaces(deviceID int, device *models.Dev
 			}
 		}
 
-		if len(iface.TaggedVLANs) > 0 {
-			var vlanIDs []int
-			for _, vlanName := range iface.TaggedVLANs {
-				if vlanID, ok := dr.client.Cache().GetSiteID("vlans", siteID, vlanName); ok {
-					vlanIDs = append(vlanIDs, vlanID)
-				} else {
-					dr.logger.Warning("      Tagged VLAN %s not found at site %s (ID: %d)", vlanName, device.SiteSlug, siteID)
-				}
-			}
-			if len(vlanIDs) > 0 {
-				payload["tagged_vlans"] = vlanIDs
-				dr.logger.Debug("      Tagged VLANs: %d configured", len(vlanIDs))
-			}
+		if len(iface.TaggedVLANs) > 0 {
+			var vlanIDs []int
+			for _, vlanName := range iface.TaggedVLANs {
+				if vlanID, ok := dr.resolveVLAN(siteID, vlanName); ok {
+					vlanIDs = append(vlanIDs, vlanID)
+				} else {
+					dr.logger.Warning("      Tagged VLAN %s not found at site %s (ID: %d)", vlanName, device.SiteSlug, siteID)
+				}
+			}
+			if len(vlanIDs) > 0 {
+				payload["tagged_vlans"] = vlanIDs
+				dr.logger.Debug("      Tagged VLANs: %d configured", len(vlanIDs))
+			}
+		}
+
+		setTags(dr.client, payload, iface.Tags, "interface "+iface.Name)
+
+		lookup := map[string]interface{}{
+			"device_id": deviceID,
+			"name":      iface.Name,
+		}
+		if templated[iface.Name] {
+			exists, err := dr.templatedInterface(device, iface, lookup, payload)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				continue
+			}
+		} else if err := dr.checkInterfaceTypeChange(device, iface, lookup); err != nil {
+			return err
+		}
+
+		ifaceObj, err := dr.client.Apply("dcim", "interfaces", lookup, payload)
+		if err != nil {
+			return fmt.Errorf("failed to apply interface %s: %w", iface.Name, err)
+		}
+
+		ifaceID := utils.GetIDFromObject(ifaceObj)
+		ifaceIDs[iface.Name] = ifaceID
+
+		// Reconcile IP address if configured (also on interfaces simulated in dry-run)
+		if iface.IP != nil && ifaceID != 0 {
+			dr.logger.Debug("      IP Address: %s", iface.IP.Address)
+			if err := dr.reconcileIPAddress(deviceID, ifaceID, &iface); err != nil {
+				return fmt.Errorf("failed to reconcile IP for %s: %w", iface.Name, err)
+			}
+		}
+
+		// Queue cable for later reconciliation (after all devices are processed)
+		if iface.Link != nil && ifaceID > 0 {
+			dr.logger.Debug("      Cable: %s → %s[%s]", iface.Name, iface.Link.PeerDevice, iface.Link.PeerPort)
+			dr.pendingCables = append(dr.pendingCables, pendingCable{
+				sourceDevice: device.Name,
+				sourcePort:   iface.Name,
+				sourceType:   "dcim.interface",
+				sourceID:     ifaceID,
+				sourceRole:   device.RoleSlug, // Track device role for port type determination
+				link:         iface.Link,
+			})
+		}
+	}
+
+	// Second pass: bridges and LAGs reference other interfaces, which now all exist
+	for _, iface := range bundled {
+		if iface.Bridge != "" {
+			if err := dr.reconcileBridge(deviceID, device, iface, ifaceIDs); err != nil {
+				return err
+			}
+		}
+		if iface.LAG != "" {
+			if err := dr.reconcileLAG(deviceID, iface, ifaceIDs, existingLAGs); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Third pass: members removed from a LAG in YAML would otherwise stay bundled
+	for _, iface := range device.Interfaces {
+		if iface.Type == "lag" && ifaceIDs[iface.Name] > 0 {
+			if err := dr.releaseLAGMembers(deviceID, device, iface, ifaceIDs[iface.Name]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// templateInterfaceNames returns the names of the interface templates of the device's type
+func (dr *DeviceReconciler) templateInterfaceNames(device *models.DeviceConfig) (map[string]bool, error) {
+	if names, ok := dr.templateNames[device.DeviceTypeSlug]; ok {
+		return names, nil
+	}
+	deviceTypeID, err := dr.resolveDeviceType(device.DeviceTypeSlug)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := dr.client.Filter("dcim", "interface-templates", map[string]interface{}{
+		"device_type_id": deviceTypeID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get interface templates: %w", err)
+	}
+
+	names := make(map[string]bool, len(templates))
+	for _, tmpl := range templates {
+		if name, ok := tmpl["name"].(string); ok {
+			names[name] = true
+		}
+	}
+	dr.templateNames[device.DeviceTypeSlug] = names
+	return names, nil
+}
+
+// templatedInterface prepares the update of an interface the device type's templates provide:
+// NetBox creates it with the device, so the template owns its type, label and mgmt_only and
+// only the device-specific settings are reconciled. It reports false if the interface does not
+// exist, in which case it is left to NetBox instead of being created.
+func (dr *DeviceReconciler) templatedInterface(device *models.DeviceConfig, iface models.InterfaceConfig, lookup, payload map[string]interface{}) (bool, error) {
+	existing, err := dr.client.Filter("dcim", "interfaces", lookup)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up interface %s: %w", iface.Name, err)
+	}
+	if len(existing) == 0 {
+		if dr.client.IsDryRun() {
+			dr.logger.Debug("      Interface %s comes from the device type template, not creating it", iface.Name)
+		} else {
+			dr.logger.Warning("      Interface %s on %s comes from the device type template but does not exist, not creating it", iface.Name, device.Name)
+		}
+		return false, nil
+	}
+
+	for _, field := range []string{"type", "label", "mgmt_only"} {
+		delete(payload, field)
+	}
+	return true, nil
+}
+
+// checkInterfaceTypeChange warns before an existing interface changes its type, which NetBox
+// may reject while a cable or VLAN depends on the old type. Module-backed interfaces and
+// interfaces of breakout cables are only changed with AllowInterfaceTypeChange.
+func (dr *DeviceReconciler) checkInterfaceTypeChange(device *models.DeviceConfig, iface models.InterfaceConfig, lookup map[string]interface{}) error {
+	if iface.Type == "" {
+		return nil
+	}
+
+	// Same lookup as the following Apply, so this is answered from the response memo
+	existing, err := dr.client.Filter("dcim", "interfaces", lookup)
+	if err != nil || len(existing) == 0 {
+		return nil
+	}
+	current := statusValue(existing[0]["type"])
+	if current == "" || current == iface.Type {
+		return nil
+	}
+
+	var dependents []string
+	if existing[0]["cable"] != nil {
+		dependents = append(dependents, "cable")
+	}
+	if tagged, _ := existing[0]["tagged_vlans"].([]interface{}); existing[0]["untagged_vlan"] != nil || len(tagged) > 0 {
+		dependents = append(dependents, "VLANs")
+	}
+	dependsOn := ""
+	if len(dependents) > 0 {
+		dependsOn = fmt.Sprintf(" (has %s, NetBox may reject the change)", strings.Join(dependents, " and "))
+	}
+	dr.logger.Warning("Interface %s on %s changes type %s → %s%s", iface.Name, device.Name, current, iface.Type, dependsOn)
+
+	var backing string
+	switch {
+	case existing[0]["module"] != nil:
+		backing = "module-backed"
+	case iface.Link != nil && len(iface.Link.PeerPorts) > 0:
+		backing = "breakout"
+	}
+	if backing != "" && !dr.allowInterfaceTypeChange {
+		return fmt.Errorf("interface %s on %s: changing the type of a %s interface (%s → %s) requires --allow-interface-type-change",
+			iface.Name, device.Name, backing, current, iface.Type)
+	}
+	return nil
+}
+
+// applyLAGMembers returns a copy of the interfaces with the members listed by a LAG interface
+// joining it as if they had set lag themselves. Members must be interfaces of the device and
+// can't be in a different LAG.
+func applyLAGMembers(interfaces []models.InterfaceConfig) ([]models.InterfaceConfig, error) {
+	bundled := make([]models.InterfaceConfig, len(interfaces))
+	copy(bundled, interfaces)
+
+	index := make(map[string]int, len(bundled))
+	for i, iface := range bundled {
+		index[iface.Name] = i
+	}
+	for _, lag := range interfaces {
+		for _, member := range lag.Members {
+			i, ok := index[member]
+			switch {
+			case member == lag.Name:
+				return nil, fmt.Errorf("interface %s: cannot be a member of itself", lag.Name)
+			case !ok:
+				return nil, fmt.Errorf("interface %s: member %s is not an interface of the device", lag.Name, member)
+			case bundled[i].LAG != "" && bundled[i].LAG != lag.Name:
+				return nil, fmt.Errorf("interface %s: member %s is already in LAG %s", lag.Name, member, bundled[i].LAG)
+			}
+			bundled[i].LAG = lag.Name
+		}
+	}
+	return bundled, nil
+}
+
+// resolveLAGs validates the LAG references of the device's interfaces and looks up the LAGs
+// that are not declared on the device, returning their IDs by name. NetBox only accepts a LAG
+// on the member's own device, so a LAG found on another device (MLAG) is rejected with a hint.
+func (dr *DeviceReconciler) resolveLAGs(deviceID int, device *models.DeviceConfig) (map[string]int, error) {
+	declared := make(map[string]bool, len(device.Interfaces))
+	for _, iface := range device.Interfaces {
+		declared[iface.Name] = true
+	}
+
+	existingLAGs := make(map[string]int)
+	for _, iface := range device.Interfaces {
+		if err := validateLAG(iface, device.Interfaces); err != nil {
+			return nil, fmt.Errorf("interface %s: %w", iface.Name, err)
+		}
+		if iface.LAG == "" || declared[iface.LAG] {
+			continue
+		}
+		if _, ok := existingLAGs[iface.LAG]; ok {
+			continue
+		}
+
+		existing, err := dr.client.Filter("dcim", "interfaces", map[string]interface{}{
+			"device_id": deviceID,
+			"name":      iface.LAG,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up LAG %s: %w", iface.LAG, err)
+		}
+		if len(existing) == 0 {
+			return nil, dr.missingLAGError(device, iface)
+		}
+		existingLAGs[iface.LAG] = utils.GetIDFromObject(existing[0])
+	}
+	return existingLAGs, nil
+}
+
+// reconcileLAG sets the parent LAG of a member interface, declared on the device or
+// resolved beforehand by resolveLAGs
+func (dr *DeviceReconciler) reconcileLAG(deviceID int, iface models.InterfaceConfig, ifaceIDs, existingLAGs map[string]int) error {
+	lagID, declared := ifaceIDs[iface.LAG]
+	if !declared {
+		lagID = existingLAGs[iface.LAG]
+	}
+
+	if lagID == 0 || ifaceIDs[iface.Name] == 0 {
+		dr.logger.Debug("      LAG %s → %s skipped (created in dry-run mode)", iface.Name, iface.LAG)
+		return nil
+	}
+
+	dr.logger.Debug("      LAG: %s → %s (ID: %d)", iface.Name, iface.LAG, lagID)
+
+	lookup := map[string]interface{}{
+		"device_id": deviceID,
+		"name":      iface.Name,
+	}
+	payload := map[string]interface{}{
+		"device": deviceID,
+		"name":   iface.Name,
+		"lag":    lagID,
+	}
+
+	if _, err := dr.client.Apply("dcim", "interfaces", lookup, payload); err != nil {
+		return fmt.Errorf("failed to set LAG on interface %s: %w", iface.Name, err)
+	}
+
+	return nil
+}
+
+// releaseLAGMembers clears the lag of managed interfaces that NetBox has in a LAG but the
+// device definition no longer does, either as a member's lag or in the LAG's members list.
+// Unmanaged interfaces are left bundled.
+func (dr *DeviceReconciler) releaseLAGMembers(deviceID int, device *models.DeviceConfig, lag models.InterfaceConfig, lagID int) error {
+	desired := make(map[string]bool, len(lag.Members))
+	for _, name := range lag.Members {
+		desired[name] = true
+	}
+	for _, iface := range device.Interfaces {
+		if iface.LAG == lag.Name {
+			desired[iface.Name] = true
+		}
+	}
+
+	members, err := dr.client.Filter("dcim", "interfaces", map[string]interface{}{
+		"device_id": deviceID,
+		"lag_id":    lagID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to look up members of LAG %s: %w", lag.Name, err)
+	}
+
+	managedTagID := dr.client.ManagedTagID()
+	for _, member := range members {
+		name, _ := member["name"].(string)
+		if desired[name] {
+			continue
+		}
+		if !utils.IsManaged(member, managedTagID) {
+			dr.logger.Debug("      LAG %s: member %s is not managed by gitops, leaving it", lag.Name, name)
+			continue
 		}
 
+		dr.logger.Info("      LAG %s: removing member %s", lag.Name, name)
 		lookup := map[string]interface{}{
 			"device_id": deviceID,
-			"name":      iface.Name,
+			"name":      name,
 		}
-
-		ifaceObj, err := dr.client.Apply("dcim", "interfaces", lookup, payload)
-		if err != nil {
-			return fmt.Errorf("failed to apply interface %s: %w", iface.Name, err)
+		payload := map[string]interface{}{
+			"device": deviceID,
+			"name":   name,
+			"lag":    client.Null,
 		}
+		if _, err := dr.client.Apply("dcim", "interfaces", lookup, payload); err != nil {
+			return fmt.Errorf("failed to remove interface %s from LAG %s: %w", name, lag.Name, err)
+		}
+	}
 
-		ifaceID := utils.GetIDFromObject(ifaceObj)
+	return nil
+}
 
-		// Reconcile IP address if configured
-		if iface.IP != nil && ifaceID > 0 {
-			dr.logger.Debug("      IP Address: %s", iface.IP.Address)
-			if err := dr.reconcileIPAddress(deviceID, ifaceID, &iface); err != nil {
-				return fmt.Errorf("failed to reconcile IP for %s: %w", iface.Name, err)
-			}
+// missingLAGError reports a LAG that is not on the member's device, naming the devices
+// that do have a LAG of that name when the member was meant to join a cross-device LAG
+func (dr *DeviceReconciler) missingLAGError(device *models.DeviceConfig, iface models.InterfaceConfig) error {
+	elsewhere, err := dr.client.Filter("dcim", "interfaces", map[string]interface{}{
+		"name": iface.LAG,
+		"type": "lag",
+	})
+	if err != nil || len(elsewhere) == 0 {
+		return fmt.Errorf("LAG %s for %s not found on device %s", iface.LAG, iface.Name, device.Name)
+	}
+
+	var owners []string
+	for _, lag := range elsewhere {
+		if owner, ok := lag["device"].(map[string]interface{}); ok {
+			owners = append(owners, fmt.Sprint(owner["name"]))
+		} else {
+			owners = append(owners, fmt.Sprintf("device %v", lag["device"]))
 		}
+	}
+	return fmt.Errorf("LAG %s for %s not found on device %s but on %s: LAG members must be on the same device as the LAG; "+
+		"model an MLAG as one LAG per peer device (usually with the same name) or put the peers in a virtual chassis",
+		iface.LAG, iface.Name, device.Name, strings.Join(owners, ", "))
+}
 
-		// Queue cable for later reconciliation (after all devices are processed)
-		if iface.Link != nil && ifaceID > 0 {
-			dr.logger.Debug("      Cable: %s → %s[%s]", iface.Name, iface.Link.PeerDevice, iface.Link.PeerPort)
-			dr.pendingCables = append(dr.pendingCables, pendingCable{
-				sourceDevice: device.Name,
-				sourcePort:   iface.Name,
-				sourceType:   "dcim.interface",
-				sourceID:     ifaceID,
-				sourceRole:   device.RoleSlug, // Track device role for port type determination
-				link:         iface.Link,
-			})
+// resolveVLAN resolves an interface VLAN reference within the device's site: a VLAN name,
+// "vid:100" for the VLAN with that VID at the site, or "group/100" for the VLAN with that
+// VID in a VLAN group (by slug). Names win over "group/100" if a VLAN is named that way.
+func (dr *DeviceReconciler) resolveVLAN(siteID int, ref string) (int, bool) {
+	var filters map[string]interface{}
+
+	if vid, ok := strings.CutPrefix(ref, "vid:"); ok {
+		filters = map[string]interface{}{"site_id": siteID, "vid": vid}
+	} else if id, ok := dr.client.Cache().GetSiteID("vlans", siteID, ref); ok {
+		return id, true
+	} else if group, vid, ok := cutGroupVID(ref); ok {
+		filters = map[string]interface{}{"group": group, "vid": vid}
+	} else {
+		return 0, false
+	}
+
+	vlans, err := dr.client.Filter("ipam", "vlans", filters)
+	if err != nil {
+		dr.logger.Debug("      Failed to look up VLAN %s: %v", ref, err)
+		return 0, false
+	}
+	if len(vlans) != 1 {
+		return 0, false
+	}
+	return utils.GetIDFromObject(vlans[0]), true
+}
+
+// cutGroupVID splits a "group/100" VLAN reference into group slug and VID
+func cutGroupVID(ref string) (group, vid string, ok bool) {
+	i := strings.LastIndex(ref, "/")
+	if i <= 0 {
+		return "", "", false
+	}
+	group, vid = ref[:i], ref[i+1:]
+	if _, err := strconv.Atoi(vid); err != nil {
+		return "", "", false
+	}
+	return group, vid, true
+}
+
+// reconcileBridge sets the bridge of an interface to another interface on the same device
+func (dr *DeviceReconciler) reconcileBridge(deviceID int, device *models.DeviceConfig, iface models.InterfaceConfig, ifaceIDs map[string]int) error {
+	if iface.Bridge == iface.Name {
+		return fmt.Errorf("interface %s on %s cannot be bridged to itself", iface.Name, device.Name)
+	}
+
+	bridgeID, declared := ifaceIDs[iface.Bridge]
+	if !declared {
+		// Not in YAML, but it may still exist in NetBox (e.g., created from the device type)
+		existing, err := dr.client.Filter("dcim", "interfaces", map[string]interface{}{
+			"device_id": deviceID,
+			"name":      iface.Bridge,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to look up bridge interface %s: %w", iface.Bridge, err)
+		}
+		if len(existing) == 0 {
+			return fmt.Errorf("bridge interface %s for %s not found on device %s", iface.Bridge, iface.Name, device.Name)
 		}
+		bridgeID = utils.GetIDFromObject(existing[0])
+	}
+
+	if bridgeID == 0 || ifaceIDs[iface.Name] == 0 {
+		dr.logger.Debug("      Bridge %s → %s skipped (created in dry-run mode)", iface.Name, iface.Bridge)
+		return nil
+	}
+
+	dr.logger.Debug("      Bridge: %s → %s (ID: %d)", iface.Name, iface.Bridge, bridgeID)
+
+	lookup := map[string]interface{}{
+		"device_id": deviceID,
+		"name":      iface.Name,
+	}
+	payload := map[string]interface{}{
+		"device": deviceID,
+		"name":   iface.Name,
+		"bridge": bridgeID,
+	}
+
+	if _, err := dr.client.Apply("dcim", "interfaces", lookup, payload); err != nil {
+		return fmt.Errorf("failed to set bridge on interface %s: %w", iface.Name, err)
 	}
 
 	return nil
@@ -361,6 +1407,7 @@ func (dr *DeviceReconciler) reconcileIPAddress(deviceID, ifaceID int, iface *mod
 			payload["vrf"] = vrfID
 		}
 	}
+	setTags(dr.client, payload, ipConfig.Tags, "IP address "+ipConfig.Address)
 
 	lookup := map[string]interface{}{
 		"address": ipConfig.Address,
@@ -372,6 +1419,14 @@ func (dr *DeviceReconciler) reconcileIPAddress(deviceID, ifaceID int, iface *mod
 		}
 	}
 
+	move, err := dr.checkIPReassignment(ifaceID, iface, lookup)
+	if err != nil {
+		return err
+	}
+	if !move {
+		return nil
+	}
+
 	ipObj, err := dr.client.Apply("ipam", "ip-addresses", lookup, payload)
 	if err != nil {
 		return fmt.Errorf("failed to apply IP address: %w", err)
@@ -380,7 +1435,7 @@ func (dr *DeviceReconciler) reconcileIPAddress(deviceID, ifaceID int, iface *mod
 	// Set as primary IP if requested
 	if iface.AddressRole == "primary" {
 		ipID := utils.GetIDFromObject(ipObj)
-		if ipID > 0 {
+		if ipID != 0 {
 			if err := dr.setPrimaryIP(deviceID, ipID); err != nil {
 				return fmt.Errorf("failed to set primary IP: %w", err)
 			}
@@ -390,6 +1445,95 @@ func (dr *DeviceReconciler) reconcileIPAddress(deviceID, ifaceID int, iface *mod
 	return nil
 }
 
+// reconcileManagementIPs assigns the device-level management IPs to the implicit management
+// interface, creating the interface if needed. Declaring an interface of the same name is an
+// error: its IPs belong on that interface instead.
+func (dr *DeviceReconciler) reconcileManagementIPs(deviceID int, device *models.DeviceConfig) error {
+	for _, iface := range device.Interfaces {
+		if iface.Name == constants.ManagementInterfaceName {
+			return fmt.Errorf("device %s declares interface %s, which holds management_ips; assign its IPs on the interface instead", device.Name, iface.Name)
+		}
+	}
+
+	payload := map[string]interface{}{
+		"device": deviceID,
+		"name":   constants.ManagementInterfaceName,
+		"type":   constants.ManagementInterfaceType,
+	}
+	lookup := map[string]interface{}{
+		"device_id": deviceID,
+		"name":      constants.ManagementInterfaceName,
+	}
+	ifaceObj, err := dr.client.Apply("dcim", "interfaces", lookup, payload)
+	if err != nil {
+		return fmt.Errorf("failed to apply interface %s: %w", constants.ManagementInterfaceName, err)
+	}
+
+	ifaceID := utils.GetIDFromObject(ifaceObj)
+	if ifaceID == 0 {
+		dr.logger.Debug("      Management IPs skipped (interface created in dry-run mode)")
+		return nil
+	}
+
+	for _, ip := range device.ManagementIPs {
+		dr.logger.Debug("      Management IP: %s", ip.Address)
+		mgmt := &models.InterfaceConfig{
+			Name:        constants.ManagementInterfaceName,
+			IP:          &ip,
+			AddressRole: ip.AddressRole,
+		}
+		if err := dr.reconcileIPAddress(deviceID, ifaceID, mgmt); err != nil {
+			return fmt.Errorf("failed to reconcile management IP %s: %w", ip.Address, err)
+		}
+	}
+
+	return nil
+}
+
+// checkIPReassignment reports whether the IP may be assigned to the declared interface.
+// An IP currently assigned to a different interface is only moved if both the IP and
+// that interface are managed; otherwise the conflict is logged and the IP is left alone.
+func (dr *DeviceReconciler) checkIPReassignment(ifaceID int, iface *models.InterfaceConfig, lookup map[string]interface{}) (bool, error) {
+	existing, err := dr.client.Filter("ipam", "ip-addresses", lookup)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up IP address %s: %w", iface.IP.Address, err)
+	}
+	if len(existing) == 0 {
+		return true, nil
+	}
+
+	ip := existing[0]
+	currentID := utils.GetIDFromObject(ip["assigned_object_id"])
+	currentType, _ := ip["assigned_object_type"].(string)
+	if currentID == 0 || (currentID == ifaceID && currentType == "dcim.interface") {
+		return true, nil
+	}
+
+	current := fmt.Sprintf("%s %d", currentType, currentID)
+	if assigned, ok := ip["assigned_object"].(map[string]interface{}); ok {
+		if device, ok := assigned["device"].(map[string]interface{}); ok {
+			current = fmt.Sprintf("%v[%v]", device["name"], assigned["name"])
+		}
+	}
+
+	managedTagID := dr.client.ManagedTagID()
+	ipManaged := utils.IsManaged(ip, managedTagID)
+	ifaceManaged := false
+	if currentType == "dcim.interface" {
+		if currentIface, err := dr.client.Get("dcim", "interfaces", currentID); err == nil && currentIface != nil {
+			ifaceManaged = utils.IsManaged(currentIface, managedTagID)
+		}
+	}
+
+	if !ipManaged || !ifaceManaged {
+		dr.logger.Warning("      IP %s is assigned to %s, which is not managed by gitops; not moving it to %s", iface.IP.Address, current, iface.Name)
+		return false, nil
+	}
+
+	dr.logger.Warning("      Moving IP %s from %s to %s", iface.IP.Address, current, iface.Name)
+	return true, nil
+}
+
 // setPrimaryIP sets the primary IP for a device
 func (dr *DeviceReconciler) setPrimaryIP(deviceID, ipID int) error {
 	// Get the IP address to determine family
@@ -428,70 +1572,93 @@ func (dr *DeviceReconciler) setPrimaryIP(deviceID, ipID int) error {
 // reconcileModules reconciles device modules
 func (dr *DeviceReconciler) reconcileModules(deviceID int, device *models.DeviceConfig) error {
 	for _, module := range device.Modules {
-		// Get module type ID
-		moduleTypeID, ok := dr.client.Cache().GetID("module_types", module.ModuleTypeSlug)
-		if !ok {
-			dr.logger.Warning("Module type %s not found, skipping", module.ModuleTypeSlug)
-			continue
+		if err := dr.reconcileModule(deviceID, 0, module); err != nil {
+			return err
 		}
+	}
 
-		// Find module bay
-		bays, err := dr.client.Filter("dcim", "module-bays", map[string]interface{}{
-			"device_id": deviceID,
-			"name":      module.Name,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to find module bay: %w", err)
-		}
+	return nil
+}
 
-		if len(bays) == 0 {
-			dr.logger.Warning("Module bay %s not found on device, skipping", module.Name)
-			continue
-		}
+// reconcileModule installs a module into a bay of the device (parentModuleID 0) or of a parent
+// module, then installs its child modules into the bays its module type provides
+func (dr *DeviceReconciler) reconcileModule(deviceID, parentModuleID int, module models.ModuleConfig) error {
+	// Get module type ID
+	moduleTypeID, ok := dr.client.Cache().GetID("module_types", module.ModuleTypeSlug)
+	if !ok {
+		dr.logger.Warning("Module type %s not found, skipping", module.ModuleTypeSlug)
+		return nil
+	}
 
-		bayID := utils.GetIDFromObject(bays[0])
+	// Find module bay, scoped to the parent module so bay names may repeat across levels
+	bayFilter := map[string]interface{}{
+		"device_id": deviceID,
+		"name":      module.Name,
+		"module_id": "null",
+	}
+	if parentModuleID != 0 {
+		bayFilter["module_id"] = parentModuleID
+	}
+	bays, err := dr.client.Filter("dcim", "module-bays", bayFilter)
+	if err != nil {
+		return fmt.Errorf("failed to find module bay: %w", err)
+	}
 
-		// Default status to "active" if not provided (matches Python line 378)
-		status := module.Status
-		if status == "" {
-			status = "active"
-		}
+	if len(bays) == 0 {
+		dr.logger.Warning("Module bay %s not found on device, skipping", module.Name)
+		return nil
+	}
 
-		payload := map[string]interface{}{
-			"device":      deviceID,
-			"module_bay":  bayID,
-			"module_type": moduleTypeID,
-			"status":      status,
-		}
+	bayID := utils.GetIDFromObject(bays[0])
 
-		// Add serial - always set to empty string if not provided (matches Python behavior)
-		// This avoids 400 errors from NetBox API
-		if module.Serial != "" {
-			payload["serial"] = module.Serial
-		} else {
-			payload["serial"] = ""
-		}
+	// Default status to "active" if not provided (matches Python line 378)
+	status := module.Status
+	if status == "" {
+		status = "active"
+	}
 
-		if module.AssetTag != "" {
-			payload["asset_tag"] = module.AssetTag
-		}
-		if module.Description != "" {
-			payload["description"] = module.Description
-		}
+	payload := map[string]interface{}{
+		"device":      deviceID,
+		"module_bay":  bayID,
+		"module_type": moduleTypeID,
+		"status":      status,
+	}
 
-		// Add managed tag if available (matches Python behavior)
-		if dr.client.ManagedTagID() > 0 {
-			payload["tags"] = []int{dr.client.ManagedTagID()}
-		}
+	if module.Serial != nil {
+		payload["serial"] = *module.Serial
+	}
+	if module.AssetTag != nil {
+		payload["asset_tag"] = assetTagValue(*module.AssetTag)
+	}
+	if module.Description != "" {
+		payload["description"] = module.Description
+	}
 
-		lookup := map[string]interface{}{
-			"device_id":     deviceID,
-			"module_bay_id": bayID,
-		}
+	// Add managed tag if available (matches Python behavior)
+	if dr.client.ManagedTagID() > 0 {
+		payload["tags"] = []int{dr.client.ManagedTagID()}
+	}
 
-		_, err = dr.client.Apply("dcim", "modules", lookup, payload)
-		if err != nil {
-			return fmt.Errorf("failed to apply module %s: %w", module.Name, err)
+	lookup := client.PayloadToLookup(payload, "device", "module_bay")
+
+	moduleObj, err := dr.client.Apply("dcim", "modules", lookup, payload)
+	if err != nil {
+		return fmt.Errorf("failed to apply module %s: %w", module.Name, err)
+	}
+
+	if len(module.Modules) == 0 {
+		return nil
+	}
+
+	moduleID := utils.GetIDFromObject(moduleObj)
+	if moduleID <= 0 {
+		// Module would be created in this run (negative with --simulate), so its bays don't exist yet
+		dr.logger.DryRun("CREATE", "%d child modules in module %s", len(module.Modules), module.Name)
+		return nil
+	}
+	for _, child := range module.Modules {
+		if err := dr.reconcileModule(deviceID, moduleID, child); err != nil {
+			return err
 		}
 	}
 
@@ -666,6 +1833,8 @@ func (dr *DeviceReconciler) reconcileFrontPorts(deviceID int, device *models.Dev
 			payload["description"] = port.Description
 		}
 
+		setTags(dr.client, payload, port.Tags, "front port "+port.Name)
+
 		lookup := map[string]interface{}{
 			"device_id": deviceID,
 			"name":      port.Name,
@@ -723,6 +1892,8 @@ func (dr *DeviceReconciler) reconcileRearPorts(deviceID int, device *models.Devi
 			payload["description"] = port.Description
 		}
 
+		setTags(dr.client, payload, port.Tags, "rear port "+port.Name)
+
 		lookup := map[string]interface{}{
 			"device_id": deviceID,
 			"name":      port.Name,
@@ -751,6 +1922,70 @@ func (dr *DeviceReconciler) reconcileRearPorts(deviceID int, device *models.Devi
 	return nil
 }
 
+// verifyPortMappings checks that each front port's rear_port reference resolves to an
+// existing rear port on the device and that NetBox holds the same mapping.
+// Mismatches are logged as warnings and returned for reporting; they don't fail the device.
+func (dr *DeviceReconciler) verifyPortMappings(deviceID int, device *models.DeviceConfig) []string {
+	rearPorts, err := dr.client.Filter("dcim", "rear-ports", map[string]interface{}{
+		"device_id": deviceID,
+	})
+	if err != nil {
+		dr.logger.Warning("    Could not verify port mappings for %s: %v", device.Name, err)
+		return nil
+	}
+
+	frontPorts, err := dr.client.Filter("dcim", "front-ports", map[string]interface{}{
+		"device_id": deviceID,
+	})
+	if err != nil {
+		dr.logger.Warning("    Could not verify port mappings for %s: %v", device.Name, err)
+		return nil
+	}
+
+	rearPortIDs := make(map[string]int)
+	rearPortNames := make(map[int]string)
+	for _, rp := range rearPorts {
+		if name, ok := rp["name"].(string); ok {
+			id := utils.GetIDFromObject(rp)
+			rearPortIDs[name] = id
+			rearPortNames[id] = name
+		}
+	}
+
+	existingFrontPorts := make(map[string]client.Object)
+	for _, fp := range frontPorts {
+		if name, ok := fp["name"].(string); ok {
+			existingFrontPorts[name] = fp
+		}
+	}
+
+	var mismatches []string
+	for _, port := range device.FrontPorts {
+		rearPortID, ok := rearPortIDs[port.RearPort]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("front port %s references rear port %s which does not exist on %s",
+				port.Name, port.RearPort, device.Name))
+			continue
+		}
+
+		existing, ok := existingFrontPorts[port.Name]
+		if !ok {
+			continue
+		}
+		actualID := utils.GetIDFromObject(existing["rear_port"])
+		if actualID != 0 && actualID != rearPortID {
+			mismatches = append(mismatches, fmt.Sprintf("front port %s on %s is mapped to rear port %s, expected %s",
+				port.Name, device.Name, rearPortNames[actualID], port.RearPort))
+		}
+	}
+
+	for _, msg := range mismatches {
+		dr.logger.Warning("    Port mapping: %s", msg)
+	}
+
+	return mismatches
+}
+
 // reconcilePendingCables processes all pending cable connections
 func (dr *DeviceReconciler) reconcilePendingCables() error {
 	// Build a lookup map ONLY for source ports (which are already known from device reconciliation)
@@ -791,10 +2026,11 @@ func (dr *DeviceReconciler) reconcilePendingCables() error {
 		// Look up peer port dynamically using role-based logic (NOT from cached lookup)
 		// This ensures pp-rack-a-01[2] is found as frontport when source is server,
 		// but as rearport when source is patch-panel (for backbone cables)
-		peerInfo := dr.findPort(pc.link.PeerDevice, pc.link.PeerPort, pc.sourceRole)
+		peerInfo, peerIDs, err := dr.findPeerPorts(pc.link, sourceKey, pc.sourceRole)
+		if err != nil {
+			return fmt.Errorf("failed to find peer of %s: %w", sourceKey, err)
+		}
 		if peerInfo == nil {
-			dr.logger.Warning("Peer port not found: %s::%s (from %s, role=%s)",
-				pc.link.PeerDevice, pc.link.PeerPort, sourceKey, pc.sourceRole)
 			continue
 		}
 
@@ -811,10 +2047,17 @@ func (dr *DeviceReconciler) reconcilePendingCables() error {
 			PortName:   peerInfo.port,
 			ObjectType: peerInfo.objectType,
 			ObjectID:   peerInfo.objectID,
+			Unmanaged:  peerInfo.unmanaged,
+		}
+		if len(peerIDs) > 1 {
+			bEnd.ObjectIDs = peerIDs
 		}
 
 		// Reconcile the cable
 		if err := dr.cableReconciler.ReconcileCable(aEnd, bEnd, pc.link); err != nil {
+			if dr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile cable %s[%s] <-> %s[%s]: %w",
 				source.device, source.port, peerInfo.device, peerInfo.port, err)
 		}
@@ -823,17 +2066,169 @@ func (dr *DeviceReconciler) reconcilePendingCables() error {
 	return nil
 }
 
+// findPeerPorts looks up all peer ports of a link. For breakout cables (peer_ports) the
+// returned info describes the first port, with PortName listing all ports, plus every port's ID.
+// All ports of a bundled end must be of the same type. Returns nil if any port is missing,
+// and an error if a port reference is ambiguous.
+func (dr *DeviceReconciler) findPeerPorts(link *models.LinkConfig, sourceKey, sourceRole string) (*portInfo, []int, error) {
+	var (
+		first *portInfo
+		ids   []int
+		names []string
+	)
+	portField := link.PeerPortBy
+	switch portField {
+	case "":
+		portField = constants.PeerPortByName
+	case constants.PeerPortByName, constants.PeerPortByLabel:
+	default:
+		dr.logger.Warning("Invalid peer_port_by %q on %s (expected %s or %s), skipping cable",
+			link.PeerPortBy, sourceKey, constants.PeerPortByName, constants.PeerPortByLabel)
+		return nil, nil, nil
+	}
+
+	switch link.PeerType {
+	case "", constants.TerminationRearPort:
+	case constants.TerminationCircuitTermination:
+		if len(link.PeerPorts) > 0 {
+			dr.logger.Warning("peer_ports are not supported for circuit terminations (%s), skipping cable", sourceKey)
+			return nil, nil, nil
+		}
+	default:
+		dr.logger.Warning("Invalid peer_type %q on %s (expected %s or %s), skipping cable",
+			link.PeerType, sourceKey, constants.TerminationRearPort, constants.TerminationCircuitTermination)
+		return nil, nil, nil
+	}
+
+	for _, portName := range link.PeerPortNames() {
+		var (
+			info *portInfo
+			err  error
+		)
+		if link.PeerType != "" {
+			info, err = dr.findUnmanagedPort(link.PeerType, link.PeerDevice, portField, portName)
+		} else {
+			info, err = dr.findPort(link.PeerDevice, portField, portName, sourceRole)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if info == nil {
+			dr.logger.Warning("Peer port not found: %s::%s (by %s, from %s, role=%s)",
+				link.PeerDevice, portName, portField, sourceKey, sourceRole)
+			return nil, nil, nil
+		}
+		if first == nil {
+			first = info
+		} else if info.objectType != first.objectType {
+			dr.logger.Warning("Peer ports of %s mix %s and %s, skipping cable", sourceKey, first.objectType, info.objectType)
+			return nil, nil, nil
+		}
+		ids = append(ids, info.objectID)
+		names = append(names, info.port)
+	}
+
+	if len(ids) > 1 {
+		first.port = strings.Join(names, ",")
+	}
+	return first, ids, nil
+}
+
 // portInfo stores port information for cable reconciliation
 type portInfo struct {
 	objectType string
 	objectID   int
 	device     string
 	port       string
+	unmanaged  bool // Explicit peer_type endpoint, never changed (see findUnmanagedPort)
+}
+
+// findUnmanagedPort looks up the explicit peer_type endpoint of a link: a rear port of a
+// device, or the A/Z termination of a circuit. The endpoint is only read, so it can be gear
+// or a provider circuit that this controller does not manage. A circuit ID is only unique per
+// provider, so a circuit ID several providers use is an error rather than a guess.
+func (dr *DeviceReconciler) findUnmanagedPort(objectType, deviceName, portField, portName string) (*portInfo, error) {
+	var (
+		app, endpoint string
+		filters       map[string]interface{}
+	)
+
+	switch objectType {
+	case constants.TerminationCircuitTermination:
+		circuits, err := dr.client.Filter("circuits", "circuits", map[string]interface{}{"cid": deviceName})
+		if err != nil || len(circuits) == 0 {
+			dr.logger.Debug("    Circuit %s not found", deviceName)
+			return nil, nil
+		}
+		if len(circuits) > 1 {
+			providers := make([]string, 0, len(circuits))
+			for _, circuit := range circuits {
+				providers = append(providers, nestedString(circuit, "provider", "name"))
+			}
+			return nil, fmt.Errorf("circuit ID %s is used by %d circuits (providers: %s)",
+				deviceName, len(circuits), strings.Join(providers, ", "))
+		}
+		app, endpoint = "circuits", "circuit-terminations"
+		filters = map[string]interface{}{
+			"circuit_id": utils.GetIDFromObject(circuits[0]),
+			"term_side":  strings.ToUpper(portName),
+		}
+	default:
+		devices, err := dr.client.Filter("dcim", "devices", map[string]interface{}{"name": deviceName})
+		if err != nil || len(devices) == 0 {
+			dr.logger.Debug("    Device %s not found", deviceName)
+			return nil, nil
+		}
+		app, endpoint = "dcim", "rear-ports"
+		filters = map[string]interface{}{
+			"device_id": utils.GetIDFromObject(devices[0]),
+			portField:   portName,
+		}
+	}
+
+	objects, err := dr.client.Filter(app, endpoint, filters)
+	if err != nil || len(objects) == 0 {
+		dr.logger.Debug("    ✗ %s %s[%s] not found (err=%v)", objectType, deviceName, portName, err)
+		return nil, nil
+	}
+	if err := uniquePort(objects, deviceName, portField, portName); err != nil {
+		return nil, err
+	}
+	return &portInfo{
+		objectType: objectType,
+		objectID:   utils.GetIDFromObject(objects[0]),
+		device:     deviceName,
+		port:       foundPortName(objects[0], portName),
+		unmanaged:  true,
+	}, nil
+}
+
+// uniquePort fails if a port reference matches more than one port: names are unique per
+// device, labels are not
+func uniquePort(ports []client.Object, deviceName, portField, portName string) error {
+	if len(ports) <= 1 {
+		return nil
+	}
+	names := make([]string, 0, len(ports))
+	for _, port := range ports {
+		names = append(names, stringField(port, "name"))
+	}
+	return fmt.Errorf("%s %q matches %d ports on %s (%s)", portField, portName, len(ports), deviceName, strings.Join(names, ", "))
 }
 
-// findPort searches for a port by device and port name, using role-based logic to determine port type
+// foundPortName returns the name of a port that was looked up, which differs from the YAML
+// reference when it was found by label. Circuit terminations have no name and keep the reference.
+func foundPortName(obj client.Object, reference string) string {
+	if name := stringField(obj, "name"); name != "" {
+		return name
+	}
+	return reference
+}
+
+// findPort searches for a port by device and port name (or label, if portField is "label"),
+// using role-based logic to determine port type. A label several ports share is an error.
 // Matches Python device_controller.py lines 536-558
-func (dr *DeviceReconciler) findPort(deviceName, portName, sourceRole string) *portInfo {
+func (dr *DeviceReconciler) findPort(deviceName, portField, portName, sourceRole string) (*portInfo, error) {
 	// Get device ID using LIVE lookup (not cache) - matches Python device_controller.py line 492
 	// Devices are not loaded into cache, so we must query NetBox directly
 	devices, err := dr.client.Filter("dcim", "devices", map[string]interface{}{
@@ -841,14 +2236,14 @@ func (dr *DeviceReconciler) findPort(deviceName, portName, sourceRole string) *p
 	})
 	if err != nil || len(devices) == 0 {
 		dr.logger.Debug("    Device %s not found", deviceName)
-		return nil
+		return nil, nil
 	}
 
 	device := devices[0]
 	deviceID := utils.GetIDFromObject(device)
 	if deviceID == 0 {
 		dr.logger.Debug("    Device %s has invalid ID", deviceName)
-		return nil
+		return nil, nil
 	}
 
 	// Get peer device role
@@ -877,16 +2272,19 @@ func (dr *DeviceReconciler) findPort(deviceName, portName, sourceRole string) *p
 		// Patchpanel ↔ Patchpanel = Rear ↔ Rear (Backbone)
 		rearPorts, err := dr.client.Filter("dcim", "rear-ports", map[string]interface{}{
 			"device_id": deviceID,
-			"name":      portName,
+			portField:   portName,
 		})
 		if err == nil && len(rearPorts) > 0 {
+			if err := uniquePort(rearPorts, deviceName, portField, portName); err != nil {
+				return nil, err
+			}
 			dr.logger.Debug("    ✓ Found rearport ID %d", utils.GetIDFromObject(rearPorts[0]))
 			return &portInfo{
 				objectType: "dcim.rearport",
 				objectID:   utils.GetIDFromObject(rearPorts[0]),
 				device:     deviceName,
-				port:       portName,
-			}
+				port:       foundPortName(rearPorts[0], portName),
+			}, nil
 		}
 		dr.logger.Debug("    ✗ Rearport not found (err=%v, count=%d)", err, len(rearPorts))
 	} else if isPeerPP {
@@ -894,16 +2292,19 @@ func (dr *DeviceReconciler) findPort(deviceName, portName, sourceRole string) *p
 		// Device → Patchpanel = FrontPort (Server/Switch Access)
 		frontPorts, err := dr.client.Filter("dcim", "front-ports", map[string]interface{}{
 			"device_id": deviceID,
-			"name":      portName,
+			portField:   portName,
 		})
 		if err == nil && len(frontPorts) > 0 {
+			if err := uniquePort(frontPorts, deviceName, portField, portName); err != nil {
+				return nil, err
+			}
 			dr.logger.Debug("    ✓ Found frontport ID %d", utils.GetIDFromObject(frontPorts[0]))
 			return &portInfo{
 				objectType: "dcim.frontport",
 				objectID:   utils.GetIDFromObject(frontPorts[0]),
 				device:     deviceName,
-				port:       portName,
-			}
+				port:       foundPortName(frontPorts[0], portName),
+			}, nil
 		}
 		dr.logger.Debug("    ✗ Frontport not found (err=%v, count=%d)", err, len(frontPorts))
 	} else {
@@ -911,20 +2312,23 @@ func (dr *DeviceReconciler) findPort(deviceName, portName, sourceRole string) *p
 		// Device → Device (Interface)
 		interfaces, err := dr.client.Filter("dcim", "interfaces", map[string]interface{}{
 			"device_id": deviceID,
-			"name":      portName,
+			portField:   portName,
 		})
 		if err == nil && len(interfaces) > 0 {
+			if err := uniquePort(interfaces, deviceName, portField, portName); err != nil {
+				return nil, err
+			}
 			dr.logger.Debug("    ✓ Found interface ID %d", utils.GetIDFromObject(interfaces[0]))
 			return &portInfo{
 				objectType: "dcim.interface",
 				objectID:   utils.GetIDFromObject(interfaces[0]),
 				device:     deviceName,
-				port:       portName,
-			}
+				port:       foundPortName(interfaces[0], portName),
+			}, nil
 		}
 		dr.logger.Debug("    ✗ Interface not found (err=%v, count=%d)", err, len(interfaces))
 	}
 
 	dr.logger.Warning("    ✗ Port not found with any type (interface/frontport/rearport)")
-	return nil
+	return nil, nil
 }
@@ -1,8 +1,18 @@
 package reconciler
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
+	"gopkg.in/yaml.v3"
+
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
 	"github.com/braunma/netbox-gitops-controller/pkg/models"
 )
 
@@ -86,44 +96,31 @@ func TestRackFaceLogic(t *testing.T) {
 	}
 }
 
-// TestModuleSerialHandling tests that module serial is always set
-func TestModuleSerialHandling(t *testing.T) {
-	tests := []struct {
-		name           string
-		module         models.ModuleConfig
-		expectedSerial string
-	}{
-		{
-			name: "module with serial",
-			module: models.ModuleConfig{
-				Name:           "GPU-1",
-				ModuleTypeSlug: "gpu-a100",
-				Serial:         "ABC123",
-			},
-			expectedSerial: "ABC123",
-		},
-		{
-			name: "module without serial should use empty string",
-			module: models.ModuleConfig{
-				Name:           "GPU-2",
-				ModuleTypeSlug: "gpu-a100",
-			},
-			expectedSerial: "",
-		},
+// TestModuleSerialPresence tests that an absent serial is told apart from an empty one
+func TestModuleSerialPresence(t *testing.T) {
+	var modules []models.ModuleConfig
+	data := `
+- name: GPU-1
+  module_type_slug: gpu-a100
+  serial: ABC123
+- name: GPU-2
+  module_type_slug: gpu-a100
+  serial: ""
+- name: GPU-3
+  module_type_slug: gpu-a100
+`
+	if err := yaml.Unmarshal([]byte(data), &modules); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Build payload like the actual code does
-			serial := tt.module.Serial
-			if serial == "" {
-				serial = "" // Explicitly set to empty string
-			}
-
-			if serial != tt.expectedSerial {
-				t.Errorf("Expected serial=%q, got %q", tt.expectedSerial, serial)
-			}
-		})
+	if modules[0].Serial == nil || *modules[0].Serial != "ABC123" {
+		t.Errorf("GPU-1 serial = %v, expected ABC123", modules[0].Serial)
+	}
+	if modules[1].Serial == nil || *modules[1].Serial != "" {
+		t.Errorf("GPU-2 serial = %v, expected explicit empty string", modules[1].Serial)
+	}
+	if modules[2].Serial != nil {
+		t.Errorf("GPU-3 serial = %q, expected absent", *modules[2].Serial)
 	}
 }
 
@@ -353,3 +350,1602 @@ func TestDeviceBayTemplateCreation(t *testing.T) {
 		})
 	}
 }
+
+// TestVerifyPortMappings tests detection of dangling front port → rear port references
+func TestVerifyPortMappings(t *testing.T) {
+	c, srv := newTestClient(t)
+	dr := NewDeviceReconciler(c)
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "pp-01"})
+	deviceID := device["id"].(int)
+	rear1 := srv.Add("dcim", "rear-ports", map[string]interface{}{"device": deviceID, "name": "Rear 1"})
+	srv.Add("dcim", "rear-ports", map[string]interface{}{"device": deviceID, "name": "Rear 2"})
+	srv.Add("dcim", "front-ports", map[string]interface{}{"device": deviceID, "name": "Front 1", "rear_port": rear1["id"]})
+	srv.Add("dcim", "front-ports", map[string]interface{}{"device": deviceID, "name": "Front 2", "rear_port": rear1["id"]})
+
+	config := &models.DeviceConfig{
+		Name: "pp-01",
+		FrontPorts: []models.FrontPortConfig{
+			{Name: "Front 1", Type: "8p8c", RearPort: "Rear 1"},
+			{Name: "Front 2", Type: "8p8c", RearPort: "Rear 2"},
+			{Name: "Front 3", Type: "8p8c", RearPort: "Rear 3"}, // typo: no such rear port
+		},
+	}
+
+	mismatches := dr.verifyPortMappings(deviceID, config)
+	if len(mismatches) != 2 {
+		t.Fatalf("verifyPortMappings() returned %d mismatches, expected 2: %v", len(mismatches), mismatches)
+	}
+
+	if !strings.Contains(mismatches[0], "Front 2") || !strings.Contains(mismatches[0], "Rear 2") {
+		t.Errorf("Expected remapped Front 2 mismatch, got %q", mismatches[0])
+	}
+	if !strings.Contains(mismatches[1], "Rear 3") || !strings.Contains(mismatches[1], "does not exist") {
+		t.Errorf("Expected dangling Rear 3 mismatch, got %q", mismatches[1])
+	}
+
+}
+
+// TestReconcileInterfacesBridge tests that bridged interfaces are resolved on the same device
+func TestReconcileInterfacesBridge(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "hv-01", "site": site["id"]})
+	deviceID := device["id"].(int)
+	// Same interface name on another device must not be picked up
+	srv.Add("dcim", "interfaces", map[string]interface{}{"device": deviceID + 100, "name": "br0"})
+
+	config := &models.DeviceConfig{
+		Name:     "hv-01",
+		SiteSlug: "berlin-dc",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "eth0", Type: "1000base-t", Bridge: "br0"}, // declared before its bridge
+			{Name: "br0", Type: "bridge"},
+		},
+	}
+
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() error = %v", err)
+	}
+
+	var eth0, br0 map[string]interface{}
+	for _, obj := range srv.Objects("dcim", "interfaces") {
+		if netboxtest.ID(obj["device"]) != deviceID {
+			continue
+		}
+		switch obj["name"] {
+		case "eth0":
+			eth0 = obj
+		case "br0":
+			br0 = obj
+		}
+	}
+	if eth0 == nil || br0 == nil {
+		t.Fatal("Interfaces eth0 and br0 were not created")
+	}
+	if got := netboxtest.ID(eth0["bridge"]); got != netboxtest.ID(br0) {
+		t.Errorf("eth0 bridge = %v, expected br0 ID %v", eth0["bridge"], br0["id"])
+	}
+	if _, ok := br0["bridge"]; ok {
+		t.Errorf("br0 should not have a bridge, got %v", br0["bridge"])
+	}
+
+	// Bridge to an interface that exists nowhere on the device
+	config.Interfaces[0].Bridge = "br1"
+	err := dr.reconcileInterfaces(deviceID, config)
+	if err == nil || !strings.Contains(err.Error(), "br1") {
+		t.Errorf("reconcileInterfaces() error = %v, expected missing bridge br1", err)
+	}
+}
+
+// TestReconcileInterfacesLAG tests that LAG members join a LAG on their own device and
+// that a LAG on a peer device (MLAG) is rejected with a hint instead of a bad payload
+func TestReconcileInterfacesLAG(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	leaf1 := srv.Add("dcim", "devices", map[string]interface{}{"name": "leaf-01", "site": site["id"]})
+	leaf2 := srv.Add("dcim", "devices", map[string]interface{}{"name": "leaf-02", "site": site["id"]})
+	srv.Add("dcim", "interfaces", map[string]interface{}{
+		"device": map[string]interface{}{"id": leaf2["id"], "name": "leaf-02"},
+		"name":   "Port-Channel10",
+		"type":   "lag",
+	})
+	deviceID := leaf1["id"].(int)
+
+	config := &models.DeviceConfig{
+		Name:     "leaf-01",
+		SiteSlug: "berlin-dc",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "Ethernet1", Type: "10gbase-x-sfpp", LAG: "Port-Channel1"},
+			{Name: "Port-Channel1", Type: "lag"},
+		},
+	}
+
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() error = %v", err)
+	}
+
+	member := srv.Find("dcim", "interfaces", "name", "Ethernet1")
+	lag := srv.Find("dcim", "interfaces", "name", "Port-Channel1")
+	if member == nil || lag == nil {
+		t.Fatal("Interfaces Ethernet1 and Port-Channel1 were not created")
+	}
+	if got := netboxtest.ID(member["lag"]); got != netboxtest.ID(lag) {
+		t.Errorf("Ethernet1 lag = %v, expected Port-Channel1 ID %v", member["lag"], lag["id"])
+	}
+
+	// Port-Channel10 only exists on the MLAG peer, which fails the device before any interface is written
+	srv.ResetRequests()
+	config.Interfaces[0].LAG = "Port-Channel10"
+	config.Interfaces[1].Description = "changed"
+	err := dr.reconcileInterfaces(deviceID, config)
+	if err == nil || !strings.Contains(err.Error(), "leaf-02") || !strings.Contains(err.Error(), "MLAG") {
+		t.Errorf("reconcileInterfaces() error = %v, expected cross-device LAG error naming leaf-02", err)
+	}
+	if got := srv.CountRequests("POST", "/api/dcim/interfaces/") + srv.CountRequests("PATCH", "/api/dcim/interfaces/"); got != 0 {
+		t.Errorf("Got %d interface writes, expected none before the LAG error", got)
+	}
+	config.Interfaces[1].Description = ""
+
+	// A declared LAG target must be a LAG interface
+	config.Interfaces[0].LAG = "Port-Channel1"
+	config.Interfaces[1].Type = "virtual"
+	err = dr.reconcileInterfaces(deviceID, config)
+	if err == nil || !strings.Contains(err.Error(), "expected lag") {
+		t.Errorf("reconcileInterfaces() error = %v, expected non-LAG target error", err)
+	}
+}
+
+// TestReconcileInterfacesLAGMemberRemoved tests that a managed interface dropped from a LAG in
+// YAML has its lag cleared, while an unmanaged member of the same LAG is left alone
+func TestReconcileInterfacesLAGMemberRemoved(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	leaf := srv.Add("dcim", "devices", map[string]interface{}{"name": "leaf-01", "site": site["id"]})
+	deviceID := leaf["id"].(int)
+
+	config := &models.DeviceConfig{
+		Name:     "leaf-01",
+		SiteSlug: "berlin-dc",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "Ethernet1", Type: "10gbase-x-sfpp", LAG: "Port-Channel1"},
+			{Name: "Ethernet2", Type: "10gbase-x-sfpp", LAG: "Port-Channel1"},
+			{Name: "Port-Channel1", Type: "lag"},
+		},
+	}
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() error = %v", err)
+	}
+
+	lag := srv.Find("dcim", "interfaces", "name", "Port-Channel1")
+	manual := srv.Add("dcim", "interfaces", map[string]interface{}{
+		"device": deviceID,
+		"name":   "Ethernet9",
+		"type":   "10gbase-x-sfpp",
+		"lag":    lag["id"],
+	})
+
+	config.Interfaces[1].LAG = ""
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() error = %v", err)
+	}
+
+	if got := netboxtest.ID(srv.Find("dcim", "interfaces", "name", "Ethernet1")["lag"]); got != netboxtest.ID(lag) {
+		t.Errorf("Ethernet1 lag = %v, expected Port-Channel1 ID %v", got, lag["id"])
+	}
+	if removed := srv.Find("dcim", "interfaces", "name", "Ethernet2"); removed["lag"] != nil {
+		t.Errorf("Ethernet2 lag = %v, expected it to be cleared", removed["lag"])
+	}
+	if netboxtest.ID(manual["lag"]) != netboxtest.ID(lag) {
+		t.Errorf("Unmanaged Ethernet9 lag = %v, expected it to stay in Port-Channel1", manual["lag"])
+	}
+}
+
+// TestReconcileInterfacesLAGMembersList tests that the members listed by a LAG interface join
+// it, leave it when dropped from the list, and can't be in another LAG or missing from the device
+func TestReconcileInterfacesLAGMembersList(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	leaf := srv.Add("dcim", "devices", map[string]interface{}{"name": "leaf-01", "site": site["id"]})
+	deviceID := leaf["id"].(int)
+
+	config := &models.DeviceConfig{
+		Name:     "leaf-01",
+		SiteSlug: "berlin-dc",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "Ethernet1", Type: "10gbase-x-sfpp"},
+			{Name: "Ethernet2", Type: "10gbase-x-sfpp"},
+			{Name: "Port-Channel1", Type: "lag", Members: []string{"Ethernet1", "Ethernet2"}},
+		},
+	}
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() error = %v", err)
+	}
+
+	lag := srv.Find("dcim", "interfaces", "name", "Port-Channel1")
+	for _, name := range []string{"Ethernet1", "Ethernet2"} {
+		if got := netboxtest.ID(srv.Find("dcim", "interfaces", "name", name)["lag"]); got != netboxtest.ID(lag) {
+			t.Errorf("%s lag = %v, expected Port-Channel1 ID %v", name, got, lag["id"])
+		}
+	}
+
+	config.Interfaces[2].Members = []string{"Ethernet1"}
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() error = %v", err)
+	}
+	if got := netboxtest.ID(srv.Find("dcim", "interfaces", "name", "Ethernet1")["lag"]); got != netboxtest.ID(lag) {
+		t.Errorf("Ethernet1 lag = %v, expected Port-Channel1 ID %v", got, lag["id"])
+	}
+	if removed := srv.Find("dcim", "interfaces", "name", "Ethernet2"); removed["lag"] != nil {
+		t.Errorf("Ethernet2 lag = %v, expected it to be cleared", removed["lag"])
+	}
+
+	for _, tt := range []struct {
+		members []string
+		lag     string
+		want    string
+	}{
+		{members: []string{"Ethernet9"}, want: "not an interface of the device"},
+		{members: []string{"Ethernet1"}, lag: "Port-Channel2", want: "already in LAG Port-Channel2"},
+	} {
+		config.Interfaces[0].LAG = tt.lag
+		config.Interfaces[2].Members = tt.members
+		err := dr.reconcileInterfaces(deviceID, config)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("reconcileInterfaces() with members %v error = %v, expected %q", tt.members, err, tt.want)
+		}
+	}
+}
+
+// TestMassStatusChangeBlocked tests that flipping the status of most managed devices needs explicit approval
+func TestMassStatusChangeBlocked(t *testing.T) {
+	c, srv := newTestClient(t)
+	managed := []interface{}{map[string]interface{}{"id": c.ManagedTagID(), "slug": constants.ManagedTagSlug}}
+
+	var devices []*models.DeviceConfig
+	for i := 1; i <= 10; i++ {
+		name := fmt.Sprintf("srv-%02d", i)
+		srv.Add("dcim", "devices", map[string]interface{}{
+			"name":   name,
+			"status": map[string]interface{}{"value": "active", "label": "Active"},
+			"tags":   managed,
+		})
+
+		// 9 of 10 devices flip to offline (e.g., a broken default in a shared template)
+		status := "offline"
+		if i == 10 {
+			status = ""
+		}
+		devices = append(devices, &models.DeviceConfig{Name: name, SiteSlug: "berlin-dc", Status: status})
+	}
+
+	dr := NewDeviceReconciler(c)
+	err := dr.ReconcileDevices(devices)
+	if err == nil || !strings.Contains(err.Error(), "--allow-mass-status-change") {
+		t.Fatalf("ReconcileDevices() error = %v, expected mass status change to be blocked", err)
+	}
+	if got := srv.CountRequests("PATCH", "/api/dcim/devices/"); got != 0 {
+		t.Errorf("Blocked run still sent %d device updates", got)
+	}
+
+	dr.SetStatusChangeGuard(true, constants.DefaultMassStatusChangePercent)
+	if err := dr.checkStatusChanges(devices); err != nil {
+		t.Errorf("checkStatusChanges() with --allow-mass-status-change error = %v", err)
+	}
+
+	// A single decommissioned device is a normal change
+	dr.SetStatusChangeGuard(false, constants.DefaultMassStatusChangePercent)
+	for _, device := range devices[1:] {
+		device.Status = "active"
+	}
+	if err := dr.checkStatusChanges(devices); err != nil {
+		t.Errorf("checkStatusChanges() for one device error = %v", err)
+	}
+}
+
+// TestReconcileDeviceCluster tests that virtualization hosts are assigned to their cluster
+func TestReconcileDeviceCluster(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	srv.Add("dcim", "device-roles", map[string]interface{}{"name": "Hypervisor", "slug": "hypervisor"})
+	srv.Add("dcim", "device-types", map[string]interface{}{"model": "R750", "slug": "r750"})
+	cluster := srv.Add("virtualization", "clusters", map[string]interface{}{"name": "prod-cluster"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	dr := NewDeviceReconciler(c)
+	for _, device := range []*models.DeviceConfig{
+		{Name: "hv-01", SiteSlug: "berlin-dc", RoleSlug: "hypervisor", DeviceTypeSlug: "r750", ClusterName: "prod-cluster"},
+		{Name: "hv-02", SiteSlug: "berlin-dc", RoleSlug: "hypervisor", DeviceTypeSlug: "r750", ClusterName: "no-such-cluster"},
+	} {
+		if err := dr.reconcileDevice(device); err != nil {
+			t.Fatalf("reconcileDevice(%s) error = %v", device.Name, err)
+		}
+	}
+
+	hv1 := srv.Find("dcim", "devices", "name", "hv-01")
+	if hv1 == nil {
+		t.Fatal("Device hv-01 was not created")
+	}
+	if got := netboxtest.ID(hv1["cluster"]); got != netboxtest.ID(cluster) {
+		t.Errorf("hv-01 cluster = %v, expected %v", hv1["cluster"], cluster["id"])
+	}
+
+	hv2 := srv.Find("dcim", "devices", "name", "hv-02")
+	if hv2 == nil {
+		t.Fatal("Device hv-02 should be created despite the unknown cluster")
+	}
+	if _, ok := hv2["cluster"]; ok {
+		t.Errorf("hv-02 should have no cluster, got %v", hv2["cluster"])
+	}
+}
+
+// TestReconcileDeviceLookupByName tests that a name-only lookup strategy finds a device in another site
+func TestReconcileDeviceLookupByName(t *testing.T) {
+	c, srv := newTestClient(t)
+	oldSite := srv.Add("dcim", "sites", map[string]interface{}{"name": "Old DC", "slug": "old-dc"})
+	newSite := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	srv.Add("dcim", "device-roles", map[string]interface{}{"name": "Server", "slug": "server"})
+	srv.Add("dcim", "device-types", map[string]interface{}{"model": "R750", "slug": "r750"})
+	existing := srv.Add("dcim", "devices", map[string]interface{}{
+		"name": "srv-01",
+		"site": map[string]interface{}{"id": oldSite["id"]},
+		"tags": []interface{}{map[string]interface{}{"id": c.ManagedTagID()}},
+	})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+	if err := dr.SetLookupStrategy(LookupName); err != nil {
+		t.Fatalf("SetLookupStrategy() error = %v", err)
+	}
+
+	device := &models.DeviceConfig{Name: "srv-01", SiteSlug: "berlin-dc", RoleSlug: "server", DeviceTypeSlug: "r750"}
+	if err := dr.reconcileDevice(device); err != nil {
+		t.Fatalf("reconcileDevice() error = %v", err)
+	}
+
+	if got := srv.CountRequests("POST", "/api/dcim/devices/"); got != 0 {
+		t.Errorf("Name-only lookup created %d devices, expected the existing one to be updated", got)
+	}
+	if got := netboxtest.ID(srv.Find("dcim", "devices", "id", existing["id"])["site"]); got != netboxtest.ID(newSite) {
+		t.Errorf("srv-01 site = %v, expected it moved to %v", got, newSite["id"])
+	}
+
+	if err := dr.SetLookupStrategy("serial"); err == nil {
+		t.Error("SetLookupStrategy() expected error for unknown strategy")
+	}
+}
+
+// TestReconcileDeviceLookupByCustomField tests that a device created under a custom field
+// lookup strategy carries that custom field, so the next run finds and updates it
+func TestReconcileDeviceLookupByCustomField(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	srv.Add("dcim", "device-roles", map[string]interface{}{"name": "Server", "slug": "server"})
+	srv.Add("dcim", "device-types", map[string]interface{}{"model": "R750", "slug": "r750"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+	if err := dr.SetLookupStrategy("cf:hostname"); err != nil {
+		t.Fatalf("SetLookupStrategy() error = %v", err)
+	}
+
+	device := &models.DeviceConfig{Name: "srv-01", SiteSlug: "berlin-dc", RoleSlug: "server", DeviceTypeSlug: "r750", Status: "active"}
+	if err := dr.reconcileDevice(device); err != nil {
+		t.Fatalf("reconcileDevice() error = %v", err)
+	}
+	created := srv.Find("dcim", "devices", "name", "srv-01")
+	if created == nil {
+		t.Fatal("Device srv-01 was not created")
+	}
+	if fields, _ := created["custom_fields"].(map[string]interface{}); fields["hostname"] != "srv-01" {
+		t.Errorf("srv-01 custom_fields = %v, expected hostname srv-01", created["custom_fields"])
+	}
+
+	device.Status = "offline"
+	srv.ResetRequests()
+	if err := dr.reconcileDevice(device); err != nil {
+		t.Fatalf("reconcileDevice() error = %v", err)
+	}
+	if got := srv.CountRequests("POST", "/api/dcim/devices/"); got != 0 {
+		t.Errorf("Second run created %d devices, expected srv-01 to be found by its custom field", got)
+	}
+	if got := srv.CountRequests("PATCH", "/api/dcim/devices/"); got != 1 {
+		t.Errorf("Second run sent %d device updates, expected 1", got)
+	}
+	if len(srv.Objects("dcim", "devices")) != 1 {
+		t.Errorf("Expected 1 device, got %d", len(srv.Objects("dcim", "devices")))
+	}
+}
+
+// TestReconcileDeviceDryRunSimulate tests that simulated dry-runs plan the interfaces of a new device
+func TestReconcileDeviceDryRunSimulate(t *testing.T) {
+	srv := netboxtest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	srv.Add("dcim", "device-roles", map[string]interface{}{"name": "Server", "slug": "server"})
+	srv.Add("dcim", "device-types", map[string]interface{}{"model": "R750", "slug": "r750"})
+
+	device := &models.DeviceConfig{
+		Name: "srv-01", SiteSlug: "berlin-dc", RoleSlug: "server", DeviceTypeSlug: "r750",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "eth0", Type: "25gbase-x-sfp28", IP: &models.IPConfig{Address: "10.0.20.50/24"}},
+		},
+	}
+
+	for _, simulate := range []bool{false, true} {
+		c, err := client.NewClient(srv.URL, "test-token", true)
+		if err != nil {
+			t.Fatalf("NewClient() error = %v", err)
+		}
+		c.SetSimulate(simulate)
+		if err := c.Cache().LoadGlobal(); err != nil {
+			t.Fatalf("LoadGlobal() error = %v", err)
+		}
+		var out bytes.Buffer
+		c.Logger().SetOutput(&out, &out)
+
+		if err := NewDeviceReconciler(c).reconcileDevice(device); err != nil {
+			t.Fatalf("reconcileDevice(simulate=%v) error = %v", simulate, err)
+		}
+
+		planned := strings.Contains(out.String(), "Creating interfaces: name=eth0") &&
+			strings.Contains(out.String(), "Creating ip-addresses: address=10.0.20.50/24")
+		if planned != simulate {
+			t.Errorf("simulate=%v: interface and IP planned = %v, output:\n%s", simulate, planned, out.String())
+		}
+	}
+
+	if n := len(srv.Objects("dcim", "devices")) + len(srv.Objects("dcim", "interfaces")) + len(srv.Objects("ipam", "ip-addresses")); n != 0 {
+		t.Errorf("Dry-run created %d objects, expected none", n)
+	}
+}
+
+// TestReconcileDeviceTypeByModel tests that device_type_slug falls back to the device type's model name
+func TestReconcileDeviceTypeByModel(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	srv.Add("dcim", "device-roles", map[string]interface{}{"name": "Server", "slug": "server"})
+	deviceType := srv.Add("dcim", "device-types", map[string]interface{}{"model": "PowerEdge R740", "slug": "dell-r740"})
+	srv.Add("dcim", "device-types", map[string]interface{}{"model": "Chassis", "slug": "vendor-a-chassis", "manufacturer": "Vendor A"})
+	srv.Add("dcim", "device-types", map[string]interface{}{"model": "Chassis", "slug": "vendor-b-chassis", "manufacturer": "Vendor B"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+	var out bytes.Buffer
+	c.Logger().SetOutput(&out, &out)
+
+	device := &models.DeviceConfig{Name: "srv-01", SiteSlug: "berlin-dc", RoleSlug: "server", DeviceTypeSlug: "PowerEdge R740"}
+	if err := dr.reconcileDevice(device); err != nil {
+		t.Fatalf("reconcileDevice() error = %v", err)
+	}
+	// The model name is a cache hit, it is still reported like the Filter fallback
+	if !strings.Contains(out.String(), `Device type "PowerEdge R740" matched by model name (slug: dell-r740)`) {
+		t.Errorf("Output should report the model name match, got:\n%s", out.String())
+	}
+
+	created := srv.Find("dcim", "devices", "name", "srv-01")
+	if created == nil {
+		t.Fatal("Device srv-01 was not created")
+	}
+	if got := netboxtest.ID(created["device_type"]); got != netboxtest.ID(deviceType) {
+		t.Errorf("device_type = %v, expected PowerEdge R740 ID %v", created["device_type"], deviceType["id"])
+	}
+
+	// Cached device types are resolved without asking NetBox
+	srv.ResetRequests()
+	device = &models.DeviceConfig{Name: "srv-02", SiteSlug: "berlin-dc", RoleSlug: "server", DeviceTypeSlug: "dell-r740"}
+	if err := dr.reconcileDevice(device); err != nil {
+		t.Fatalf("reconcileDevice() error = %v", err)
+	}
+	if got := srv.CountRequests("GET", "/api/dcim/device-types/"); got != 0 {
+		t.Errorf("Resolving a cached device type sent %d requests, expected none", got)
+	}
+	if strings.Count(out.String(), "matched by model name") != 1 {
+		t.Errorf("A slug should not be reported as a model name match, got:\n%s", out.String())
+	}
+
+	// A model shared by several manufacturers needs the slug
+	device = &models.DeviceConfig{Name: "chassis-01", SiteSlug: "berlin-dc", RoleSlug: "server", DeviceTypeSlug: "Chassis"}
+	if err := dr.reconcileDevice(device); err == nil || !strings.Contains(err.Error(), "use the slug") {
+		t.Errorf("reconcileDevice() error = %v, expected ambiguous model error", err)
+	}
+
+	device.DeviceTypeSlug = "no-such-type"
+	if err := dr.reconcileDevice(device); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("reconcileDevice() error = %v, expected device type not found", err)
+	}
+}
+
+// TestReconcileDevicesChildBeforeParent tests that a child device loaded before its parent is
+// reconciled after the parent, so its device bay exists
+func TestReconcileDevicesChildBeforeParent(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	srv.Add("dcim", "device-roles", map[string]interface{}{"name": "Server", "slug": "server"})
+	chassisType := srv.Add("dcim", "device-types", map[string]interface{}{"model": "Blade Chassis", "slug": "blade-chassis"})
+	srv.Add("dcim", "device-types", map[string]interface{}{"model": "Blade", "slug": "blade"})
+	srv.Add("dcim", "device-bay-templates", map[string]interface{}{"device_type": chassisType["id"], "name": "Bay 1"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	devices := []*models.DeviceConfig{
+		{Name: "blade-01", SiteSlug: "berlin-dc", RoleSlug: "server", DeviceTypeSlug: "blade", ParentDevice: "chassis-01", DeviceBay: "Bay 1"},
+		{Name: "chassis-01", SiteSlug: "berlin-dc", RoleSlug: "server", DeviceTypeSlug: "blade-chassis"},
+	}
+	if err := dr.ReconcileDevices(devices); err != nil {
+		t.Fatalf("ReconcileDevices() error = %v", err)
+	}
+
+	blade := srv.Find("dcim", "devices", "name", "blade-01")
+	bay := srv.Find("dcim", "device-bays", "name", "Bay 1")
+	if blade == nil || bay == nil {
+		t.Fatal("Blade or chassis bay was not created")
+	}
+	if got := netboxtest.ID(bay["installed_device"]); got != netboxtest.ID(blade) {
+		t.Errorf("Bay 1 installed_device = %v, expected blade-01 ID %v", bay["installed_device"], blade["id"])
+	}
+
+	// Parents that reference each other can never be ordered
+	devices[1].ParentDevice, devices[1].DeviceBay = "blade-01", "Bay 1"
+	if _, err := orderDevices(devices); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("orderDevices() error = %v, expected parent_device cycle", err)
+	}
+}
+
+// TestReconcileDevicesDependsOn tests that a device is reconciled after the devices it
+// depends_on, and that dependency cycles are refused
+func TestReconcileDevicesDependsOn(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	srv.Add("dcim", "device-roles", map[string]interface{}{"name": "Switch", "slug": "switch"})
+	srv.Add("dcim", "device-types", map[string]interface{}{"model": "N9K", "slug": "n9k"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	devices := []*models.DeviceConfig{
+		{Name: "sw-member", SiteSlug: "berlin-dc", RoleSlug: "switch", DeviceTypeSlug: "n9k", DependsOn: []string{"sw-master"}},
+		{Name: "sw-master", SiteSlug: "berlin-dc", RoleSlug: "switch", DeviceTypeSlug: "n9k"},
+	}
+	if err := dr.ReconcileDevices(devices); err != nil {
+		t.Fatalf("ReconcileDevices() error = %v", err)
+	}
+
+	master := srv.Find("dcim", "devices", "name", "sw-master")
+	member := srv.Find("dcim", "devices", "name", "sw-member")
+	if master == nil || member == nil {
+		t.Fatal("Devices were not created")
+	}
+	if netboxtest.ID(master) > netboxtest.ID(member) {
+		t.Errorf("sw-master (ID %d) was created after sw-member (ID %d), expected it first", netboxtest.ID(master), netboxtest.ID(member))
+	}
+
+	devices[1].DependsOn = []string{"sw-member"}
+	if _, err := orderDevices(devices); err == nil || !strings.Contains(err.Error(), "sw-member → sw-master → sw-member") {
+		t.Errorf("orderDevices() error = %v, expected the depends_on cycle", err)
+	}
+}
+
+// TestReconcileInterfacesMgmtOnly tests that out-of-band management interfaces keep mgmt_only without churn,
+// and that interfaces without mgmt_only in YAML keep the value they have in NetBox
+func TestReconcileInterfacesMgmtOnly(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "srv-01", "site": site["id"]})
+	deviceID := device["id"].(int)
+	srv.Add("dcim", "interfaces", map[string]interface{}{
+		"name": "bmc", "type": "1000base-t", "enabled": true, "mgmt_only": true,
+		"device": map[string]interface{}{"id": deviceID, "name": "srv-01"},
+	})
+
+	config := &models.DeviceConfig{
+		Name:     "srv-01",
+		SiteSlug: "berlin-dc",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "idrac", Type: "1000base-t", MgmtOnly: boolPtr(true), Description: "Out-of-band management"},
+			{Name: "eth0", Type: "10gbase-x-sfpp", MgmtOnly: boolPtr(false)},
+			{Name: "bmc", Type: "1000base-t"},
+		},
+	}
+
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() error = %v", err)
+	}
+
+	idrac := srv.Find("dcim", "interfaces", "name", "idrac")
+	if idrac == nil || idrac["mgmt_only"] != true {
+		t.Fatalf("idrac mgmt_only = %v, expected true", idrac)
+	}
+	if idrac["description"] != "Out-of-band management" {
+		t.Errorf("idrac description = %v", idrac["description"])
+	}
+	if eth0 := srv.Find("dcim", "interfaces", "name", "eth0"); eth0 == nil || eth0["mgmt_only"] != false {
+		t.Errorf("eth0 mgmt_only = %v, expected false", eth0)
+	}
+	if bmc := srv.Find("dcim", "interfaces", "name", "bmc"); bmc == nil || bmc["mgmt_only"] != true {
+		t.Errorf("bmc mgmt_only = %v, expected true as set in NetBox", bmc)
+	}
+
+	// Second run must not produce any updates
+	srv.ResetRequests()
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() second run error = %v", err)
+	}
+	if got := srv.CountRequests("PATCH", "/api/dcim/interfaces/"); got != 0 {
+		t.Errorf("Second run sent %d interface updates, expected none", got)
+	}
+}
+
+// TestReconcileInterfacesEnabledDefault tests that interfaces without "enabled" are created enabled
+func TestReconcileInterfacesEnabledDefault(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "srv-01", "site": site["id"]})
+	deviceID := device["id"].(int)
+
+	config := &models.DeviceConfig{
+		Name:     "srv-01",
+		SiteSlug: "berlin-dc",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "eth0", Type: "10gbase-x-sfpp"},
+			{Name: "eth1", Type: "10gbase-x-sfpp", Enabled: boolPtr(false)},
+		},
+	}
+
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() error = %v", err)
+	}
+
+	if eth0 := srv.Find("dcim", "interfaces", "name", "eth0"); eth0 == nil || eth0["enabled"] != true {
+		t.Errorf("eth0 enabled = %v, expected true", eth0)
+	}
+	if eth1 := srv.Find("dcim", "interfaces", "name", "eth1"); eth1 == nil || eth1["enabled"] != false {
+		t.Errorf("eth1 enabled = %v, expected false", eth1)
+	}
+}
+
+// TestReconcileComponentTags tests that user tags on interfaces, ports and IPs are set alongside the managed tag
+func TestReconcileComponentTags(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	uplink := srv.Add("extras", "tags", map[string]interface{}{"name": "Uplink", "slug": "uplink"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "sw-01", "site": site["id"]})
+	deviceID := device["id"].(int)
+
+	config := &models.DeviceConfig{
+		Name:     "sw-01",
+		SiteSlug: "berlin-dc",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "Ethernet49", Type: "100gbase-x-qsfp28", Tags: []string{"uplink"},
+				IP: &models.IPConfig{Address: "10.0.0.1/31", Tags: []string{"uplink"}}},
+			{Name: "Ethernet1", Type: "10gbase-x-sfpp", Tags: []string{"no-such-tag"}},
+		},
+		RearPorts: []models.RearPortConfig{{Name: "R1", Type: "lc", Tags: []string{"uplink"}}},
+	}
+
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() error = %v", err)
+	}
+	if err := dr.reconcileRearPorts(deviceID, config); err != nil {
+		t.Fatalf("reconcileRearPorts() error = %v", err)
+	}
+
+	tagIDs := func(obj map[string]interface{}) map[int]bool {
+		ids := make(map[int]bool)
+		tags, _ := obj["tags"].([]interface{})
+		for _, tag := range tags {
+			ids[netboxtest.ID(tag)] = true
+		}
+		return ids
+	}
+
+	for _, obj := range []struct {
+		app, endpoint, field, value string
+	}{
+		{"dcim", "interfaces", "name", "Ethernet49"},
+		{"ipam", "ip-addresses", "address", "10.0.0.1/31"},
+		{"dcim", "rear-ports", "name", "R1"},
+	} {
+		found := srv.Find(obj.app, obj.endpoint, obj.field, obj.value)
+		if found == nil {
+			t.Fatalf("%s %s was not created", obj.endpoint, obj.value)
+		}
+		ids := tagIDs(found)
+		if !ids[netboxtest.ID(uplink)] || !ids[c.ManagedTagID()] {
+			t.Errorf("%s %s tags = %v, expected uplink and the managed tag", obj.endpoint, obj.value, found["tags"])
+		}
+	}
+
+	// Unknown tags are skipped, the managed tag is still set
+	eth1 := srv.Find("dcim", "interfaces", "name", "Ethernet1")
+	if ids := tagIDs(eth1); len(ids) != 1 || !ids[c.ManagedTagID()] {
+		t.Errorf("Ethernet1 tags = %v, expected only the managed tag", eth1["tags"])
+	}
+
+	// Second run must not produce any updates
+	srv.ResetRequests()
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() second run error = %v", err)
+	}
+	if got := srv.CountRequests("PATCH", "/api/"); got != 0 {
+		t.Errorf("Second run sent %d updates, expected none", got)
+	}
+}
+
+// TestReconcileManagementIPPrimary tests a device-level management IP set as the primary IP
+func TestReconcileManagementIPPrimary(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "sw-01", "site": site["id"]})
+	deviceID := device["id"].(int)
+
+	config := &models.DeviceConfig{
+		Name:          "sw-01",
+		SiteSlug:      "berlin-dc",
+		ManagementIPs: []models.IPConfig{{Address: "10.0.10.5/24", AddressRole: "primary"}},
+	}
+	if err := dr.reconcileManagementIPs(deviceID, config); err != nil {
+		t.Fatalf("reconcileManagementIPs() error = %v", err)
+	}
+
+	mgmt := srv.Find("dcim", "interfaces", "name", "mgmt0")
+	if mgmt == nil {
+		t.Fatal("Management interface mgmt0 was not created")
+	}
+	if mgmt["type"] != "virtual" {
+		t.Errorf("mgmt0 type = %v, expected virtual", mgmt["type"])
+	}
+
+	ip := srv.Find("ipam", "ip-addresses", "address", "10.0.10.5/24")
+	if ip == nil {
+		t.Fatal("Management IP was not created")
+	}
+	if ip["assigned_object_type"] != "dcim.interface" || netboxtest.ID(ip["assigned_object_id"]) != netboxtest.ID(mgmt) {
+		t.Errorf("Management IP assigned to %v %v, expected mgmt0", ip["assigned_object_type"], ip["assigned_object_id"])
+	}
+	if got := netboxtest.ID(srv.Find("dcim", "devices", "name", "sw-01")["primary_ip4"]); got != netboxtest.ID(ip) {
+		t.Errorf("primary_ip4 = %d, expected the management IP %d", got, netboxtest.ID(ip))
+	}
+
+	// An interface declared with the management interface's name is ambiguous
+	config.Interfaces = []models.InterfaceConfig{{Name: "mgmt0", Type: "1000base-t"}}
+	if err := dr.reconcileManagementIPs(deviceID, config); err == nil {
+		t.Error("reconcileManagementIPs() expected error for a declared mgmt0 interface")
+	}
+}
+
+// TestCheckAssetTagsDuplicateInInventory tests that shared asset tags in YAML fail early
+func TestCheckAssetTagsDuplicateInInventory(t *testing.T) {
+	c, srv := newTestClient(t)
+	dr := NewDeviceReconciler(c)
+
+	devices := []*models.DeviceConfig{
+		{Name: "srv-01", AssetTag: strPtr("INV-1000")},
+		{Name: "srv-02", AssetTag: strPtr("INV-1001"), Modules: []models.ModuleConfig{
+			{Name: "GPU-1", ModuleTypeSlug: "gpu-a100", AssetTag: strPtr("INV-1000")},
+		}},
+		{Name: "srv-03", AssetTag: strPtr("INV-1002")},
+	}
+
+	err := dr.ReconcileDevices(devices)
+	if err == nil {
+		t.Fatal("ReconcileDevices() expected duplicate asset tag error")
+	}
+	for _, want := range []string{"INV-1000", "device srv-01", "module GPU-1 on srv-02"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Error %q does not mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "INV-1001") {
+		t.Errorf("Error %q mentions unique asset tag INV-1001", err)
+	}
+	if got := srv.CountRequests("POST", "/api/dcim/devices/"); got != 0 {
+		t.Errorf("Devices were created despite the conflict (%d POSTs)", got)
+	}
+}
+
+// TestCheckAssetTagsHeldByOther tests asset tags already held by devices outside the inventory
+func TestCheckAssetTagsHeldByOther(t *testing.T) {
+	c, srv := newTestClient(t)
+	dr := NewDeviceReconciler(c)
+
+	srv.Add("dcim", "devices", map[string]interface{}{"name": "legacy-box", "asset_tag": "INV-2000"})
+	srv.Add("dcim", "devices", map[string]interface{}{"name": "srv-01", "asset_tag": "INV-2001"})
+
+	devices := []*models.DeviceConfig{
+		{Name: "srv-01", AssetTag: strPtr("INV-2001")}, // held by itself: fine
+		{Name: "srv-02", AssetTag: strPtr("INV-2000")},
+		{Name: "srv-03", AssetTag: strPtr("INV-2002")},
+	}
+
+	err := dr.checkAssetTags(devices)
+	if err == nil {
+		t.Fatal("checkAssetTags() expected error for asset tag held by legacy-box")
+	}
+	// All tags are looked up at once, per endpoint
+	if got := srv.CountRequests("GET", "/api/dcim/devices/") + srv.CountRequests("GET", "/api/dcim/modules/"); got != 2 {
+		t.Errorf("Got %d asset tag lookups, expected one for devices and one for modules", got)
+	}
+	for _, want := range []string{"INV-2000", "unmanaged device legacy-box", "device srv-02"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Error %q does not mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "INV-2001") {
+		t.Errorf("Error %q reports the device's own asset tag", err)
+	}
+}
+
+// TestCheckMTUsLAGMismatch tests warnings for a LAG member whose MTU differs from its LAG's
+// and for jumbo MTUs above MaxJumboMTU
+func TestCheckMTUsLAGMismatch(t *testing.T) {
+	c, _ := newTestClient(t)
+	var out bytes.Buffer
+	c.Logger().SetOutput(&out, &out)
+	dr := NewDeviceReconciler(c)
+
+	devices := []*models.DeviceConfig{{
+		Name: "leaf-01",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "Po1", Type: "lag", MTU: 9000},
+			{Name: "Eth1", LAG: "Po1", MTU: 1500},
+			{Name: "Eth2", LAG: "Po1", MTU: 9000},
+			{Name: "Eth3", LAG: "Po1"},
+			{Name: "Eth4", MTU: 9600},
+			{Name: "Po2", Type: "lag", MTU: 9000, Members: []string{"Eth5", "Eth6"}},
+			{Name: "Eth5", MTU: 1500},
+			{Name: "Eth6", MTU: 9000},
+			{Name: "Po3", Type: "lag", MTU: 1500, Members: []string{"Eth7"}},
+			{Name: "Eth7"},
+		},
+	}}
+	dr.checkMTUs(devices)
+
+	for _, want := range []string{
+		"Interface Eth1 on leaf-01 has MTU 1500, but its LAG Po1 has MTU 9000",
+		"Interface Eth3 on leaf-01 has no MTU, but its LAG Po1 has jumbo MTU 9000",
+		"Interface Eth4 on leaf-01 has MTU 9600, above the 9216",
+		"Interface Eth5 on leaf-01 has MTU 1500, but its LAG Po2 has MTU 9000",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Missing warning %q:\n%s", want, out.String())
+		}
+	}
+	for _, unexpected := range []string{"Eth2", "Eth6", "Eth7"} {
+		if strings.Contains(out.String(), unexpected) {
+			t.Errorf("Unexpected warning about %s:\n%s", unexpected, out.String())
+		}
+	}
+}
+
+// TestReconcileInterfacesWWN tests a Fibre Channel interface with a WWN in non-canonical form
+func TestReconcileInterfacesWWN(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "stor-01", "site": site["id"]})
+	deviceID := device["id"].(int)
+
+	config := &models.DeviceConfig{
+		Name:     "stor-01",
+		SiteSlug: "berlin-dc",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "fc0", Type: "32gfc-sfp28", WWN: "500143801234abcd"},
+		},
+	}
+
+	for run := 1; run <= 2; run++ {
+		if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+			t.Fatalf("reconcileInterfaces() run %d error = %v", run, err)
+		}
+	}
+
+	fc0 := srv.Find("dcim", "interfaces", "name", "fc0")
+	if fc0 == nil || fc0["wwn"] != "50:01:43:80:12:34:AB:CD" {
+		t.Fatalf("fc0 wwn = %v, expected 50:01:43:80:12:34:AB:CD", fc0)
+	}
+	if got := srv.CountRequests("PATCH", "/api/dcim/interfaces/"); got != 0 {
+		t.Errorf("Unchanged WWN was updated %d times", got)
+	}
+
+	config.Interfaces[0].WWN = "00:11:22:33:44:55"
+	if err := dr.reconcileInterfaces(deviceID, config); err == nil {
+		t.Error("reconcileInterfaces() expected error for invalid WWN")
+	}
+}
+
+// TestReconcileDeviceReloadsCacheOnMiss tests a device type created after the caches were loaded
+func TestReconcileDeviceReloadsCacheOnMiss(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	srv.Add("dcim", "device-roles", map[string]interface{}{"name": "Server", "slug": "server"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	// Created after the global caches were loaded (e.g., earlier in the same run)
+	deviceType := srv.Add("dcim", "device-types", map[string]interface{}{"model": "R740", "slug": "r740"})
+
+	device := &models.DeviceConfig{
+		Name:           "srv-01",
+		SiteSlug:       "berlin-dc",
+		RoleSlug:       "server",
+		DeviceTypeSlug: "r740",
+	}
+	if err := NewDeviceReconciler(c).reconcileDevice(device); err != nil {
+		t.Fatalf("reconcileDevice() error = %v", err)
+	}
+
+	created := srv.Find("dcim", "devices", "name", "srv-01")
+	if created == nil {
+		t.Fatal("Device srv-01 was not created")
+	}
+	if netboxtest.ID(created["device_type"]) != netboxtest.ID(deviceType["id"]) {
+		t.Errorf("Device device_type = %v, expected %v", created["device_type"], deviceType["id"])
+	}
+}
+
+// TestReconcileServicesSSH tests an SSH service bound to one of the device's IPs
+func TestReconcileServicesSSH(t *testing.T) {
+	c, srv := newTestClient(t)
+	dr := NewDeviceReconciler(c)
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "srv-01"})
+	deviceID := device["id"].(int)
+	iface := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "eth0", "device": map[string]interface{}{"id": deviceID}})
+	ip := srv.Add("ipam", "ip-addresses", map[string]interface{}{
+		"address":         "10.0.0.11/24",
+		"assigned_object": map[string]interface{}{"id": iface["id"], "device": map[string]interface{}{"id": deviceID}},
+	})
+
+	config := &models.DeviceConfig{
+		Name: "srv-01",
+		Services: []models.ServiceConfig{
+			{Name: "ssh", Protocol: "tcp", Ports: []int{22}, IPAddresses: []string{"10.0.0.11/24"}},
+		},
+	}
+
+	for run := 1; run <= 2; run++ {
+		if err := dr.reconcileServices(deviceID, config); err != nil {
+			t.Fatalf("reconcileServices() run %d error = %v", run, err)
+		}
+	}
+
+	services := srv.Objects("ipam", "services")
+	if len(services) != 1 {
+		t.Fatalf("Expected 1 service, got %d", len(services))
+	}
+	ssh := services[0]
+	if netboxtest.ID(ssh["device"]) != deviceID || ssh["protocol"] != "tcp" {
+		t.Errorf("Service = %v, expected tcp on device %d", ssh, deviceID)
+	}
+	if ports, _ := ssh["ports"].([]interface{}); len(ports) != 1 || netboxtest.ID(ports[0]) != 22 {
+		t.Errorf("Service ports = %v, expected [22]", ssh["ports"])
+	}
+	if ips, _ := ssh["ipaddresses"].([]interface{}); len(ips) != 1 || netboxtest.ID(ips[0]) != netboxtest.ID(ip["id"]) {
+		t.Errorf("Service ipaddresses = %v, expected [%v]", ssh["ipaddresses"], ip["id"])
+	}
+	if got := srv.CountRequests("PATCH", "/api/ipam/services/"); got != 0 {
+		t.Errorf("Unchanged service was updated %d times", got)
+	}
+}
+
+// TestReconcileInterfacesWireless tests radio settings on a Wi-Fi 6 interface
+func TestReconcileInterfacesWireless(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "ap-01", "site": site["id"]})
+	deviceID := device["id"].(int)
+
+	config := &models.DeviceConfig{
+		Name:     "ap-01",
+		SiteSlug: "berlin-dc",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "radio1", Type: "ieee802.11ax", TxPower: 20, RFChannel: "5g-36-5180-20", RFChannelWidth: 20},
+		},
+	}
+
+	for run := 1; run <= 2; run++ {
+		if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+			t.Fatalf("reconcileInterfaces() run %d error = %v", run, err)
+		}
+	}
+
+	radio := srv.Find("dcim", "interfaces", "name", "radio1")
+	if radio == nil {
+		t.Fatal("Interface radio1 was not created")
+	}
+	if radio["rf_channel"] != "5g-36-5180-20" || netboxtest.ID(radio["tx_power"]) != 20 || netboxtest.ID(radio["rf_channel_width"]) != 20 {
+		t.Errorf("radio1 = tx_power %v, rf_channel %v, rf_channel_width %v", radio["tx_power"], radio["rf_channel"], radio["rf_channel_width"])
+	}
+	if got := srv.CountRequests("PATCH", "/api/dcim/interfaces/"); got != 0 {
+		t.Errorf("Unchanged radio settings were updated %d times", got)
+	}
+}
+
+func TestValidateWireless(t *testing.T) {
+	tests := []struct {
+		name    string
+		iface   models.InterfaceConfig
+		wantErr bool
+	}{
+		{"wired without radio settings", models.InterfaceConfig{Type: "1000base-t"}, false},
+		{"wireless with channel", models.InterfaceConfig{Type: "ieee802.11ax", RFChannel: "5g-36-5180-20", TxPower: 20}, false},
+		{"wired with tx_power", models.InterfaceConfig{Type: "1000base-t", TxPower: 20}, true},
+		{"wired with channel", models.InterfaceConfig{Type: "10gbase-x-sfpp", RFChannel: "2.4g-1-2412-22"}, true},
+		{"tx_power too high", models.InterfaceConfig{Type: "ieee802.11ac", TxPower: 200}, true},
+		{"negative tx_power", models.InterfaceConfig{Type: "ieee802.11ac", TxPower: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateWireless(tt.iface); (err != nil) != tt.wantErr {
+				t.Errorf("validateWireless() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReconcileInterfacesTrunkToAccess(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	servers := srv.Add("ipam", "vlans", map[string]interface{}{"name": "servers", "vid": 10, "site": site["id"]})
+	storage := srv.Add("ipam", "vlans", map[string]interface{}{"name": "storage", "vid": 20, "site": site["id"]})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	if err := c.Cache().LoadSite("berlin-dc"); err != nil {
+		t.Fatalf("LoadSite() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "sw-01", "site": site["id"]})
+	deviceID := device["id"].(int)
+
+	// Previously configured as a trunk
+	srv.Add("dcim", "interfaces", map[string]interface{}{
+		"name":   "Eth1/1",
+		"device": deviceID,
+		"type":   "10gbase-x-sfpp",
+		"mode":   map[string]interface{}{"value": "tagged", "label": "Tagged"},
+		"tagged_vlans": []interface{}{
+			map[string]interface{}{"id": servers["id"], "name": "servers"},
+			map[string]interface{}{"id": storage["id"], "name": "storage"},
+		},
+		"enabled":   true,
+		"mgmt_only": false,
+	})
+
+	config := &models.DeviceConfig{
+		Name:     "sw-01",
+		SiteSlug: "berlin-dc",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "Eth1/1", Type: "10gbase-x-sfpp", Mode: "access", UntaggedVLAN: "servers"},
+		},
+	}
+
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() error = %v", err)
+	}
+
+	iface := srv.Find("dcim", "interfaces", "name", "Eth1/1")
+	if tagged, _ := iface["tagged_vlans"].([]interface{}); len(tagged) != 0 {
+		t.Errorf("tagged_vlans = %v, expected them to be cleared", iface["tagged_vlans"])
+	}
+	if iface["mode"] != "access" || netboxtest.ID(iface["untagged_vlan"]) != netboxtest.ID(servers) {
+		t.Errorf("Eth1/1 mode = %v, untagged_vlan = %v", iface["mode"], iface["untagged_vlan"])
+	}
+
+	// Converged: no further updates
+	srv.ResetRequests()
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() second run error = %v", err)
+	}
+	if got := srv.CountRequests("PATCH", "/api/dcim/interfaces/"); got != 0 {
+		t.Errorf("Second run sent %d interface updates, expected none", got)
+	}
+}
+
+func TestValidateVLANMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		iface   models.InterfaceConfig
+		wantErr bool
+	}{
+		{"access with untagged", models.InterfaceConfig{Mode: "access", UntaggedVLAN: "servers"}, false},
+		{"access with tagged", models.InterfaceConfig{Mode: "access", TaggedVLANs: []string{"storage"}}, true},
+		{"tagged with both", models.InterfaceConfig{Mode: "tagged", UntaggedVLAN: "servers", TaggedVLANs: []string{"storage"}}, false},
+		{"tagged-all alone", models.InterfaceConfig{Mode: "tagged-all"}, false},
+		{"tagged-all with untagged", models.InterfaceConfig{Mode: "tagged-all", UntaggedVLAN: "servers"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateVLANMode(tt.iface); (err != nil) != tt.wantErr {
+				t.Errorf("validateVLANMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReconcileIPAddressReassignment(t *testing.T) {
+	tests := []struct {
+		name          string
+		managedIface  bool
+		expectedOnNew bool
+	}{
+		{"both managed: moved", true, true},
+		{"current interface unmanaged: left alone", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, srv := newTestClient(t)
+			dr := NewDeviceReconciler(c)
+			managed := []interface{}{c.ManagedTagID()}
+
+			oldTags := []interface{}{}
+			if tt.managedIface {
+				oldTags = managed
+			}
+			oldIface := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "eth0", "device": 1, "tags": oldTags})
+			newIface := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "eth1", "device": 1, "tags": managed})
+			ip := srv.Add("ipam", "ip-addresses", map[string]interface{}{
+				"address":              "10.0.0.10/24",
+				"assigned_object_type": "dcim.interface",
+				"assigned_object_id":   oldIface["id"],
+				"tags":                 managed,
+			})
+
+			iface := &models.InterfaceConfig{Name: "eth1", IP: &models.IPConfig{Address: "10.0.0.10/24"}}
+			if err := dr.reconcileIPAddress(1, netboxtest.ID(newIface), iface); err != nil {
+				t.Fatalf("reconcileIPAddress() error = %v", err)
+			}
+
+			onNew := netboxtest.ID(ip["assigned_object_id"]) == netboxtest.ID(newIface)
+			if onNew != tt.expectedOnNew {
+				t.Errorf("IP assigned to interface %v, expected moved = %v", ip["assigned_object_id"], tt.expectedOnNew)
+			}
+			if got := len(srv.Objects("ipam", "ip-addresses")); got != 1 {
+				t.Errorf("Expected the existing IP to be reused, found %d IPs", got)
+			}
+		})
+	}
+}
+
+// TestReconcileDeviceSerialPresence tests set, clear and leave-untouched semantics of a device serial
+func TestReconcileDeviceSerialPresence(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	srv.Add("dcim", "device-roles", map[string]interface{}{"name": "Server", "slug": "server"})
+	srv.Add("dcim", "device-types", map[string]interface{}{"model": "R740", "slug": "r740"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{
+		"name":      "srv-01",
+		"site":      site["id"],
+		"serial":    "SET-IN-UI",
+		"asset_tag": "INV-3000",
+		"tags":      []interface{}{map[string]interface{}{"id": c.ManagedTagID()}},
+	})
+
+	reconcile := func(serial, assetTag *string) {
+		t.Helper()
+		config := &models.DeviceConfig{
+			Name: "srv-01", SiteSlug: "berlin-dc", RoleSlug: "server", DeviceTypeSlug: "r740",
+			Serial: serial, AssetTag: assetTag,
+		}
+		if err := dr.reconcileDevice(config); err != nil {
+			t.Fatalf("reconcileDevice() error = %v", err)
+		}
+	}
+
+	t.Run("absent leaves untouched", func(t *testing.T) {
+		reconcile(nil, nil)
+		if device["serial"] != "SET-IN-UI" || device["asset_tag"] != "INV-3000" {
+			t.Errorf("serial/asset_tag = %v/%v, expected them to be left untouched", device["serial"], device["asset_tag"])
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		reconcile(strPtr("ABC123"), nil)
+		if device["serial"] != "ABC123" {
+			t.Errorf("serial = %v, expected ABC123", device["serial"])
+		}
+	})
+
+	t.Run("empty clears", func(t *testing.T) {
+		reconcile(strPtr(""), strPtr(""))
+		if device["serial"] != "" {
+			t.Errorf("serial = %v, expected it to be cleared", device["serial"])
+		}
+		// Asset tags are unique, so they are cleared to null rather than ""
+		if device["asset_tag"] != nil {
+			t.Errorf("asset_tag = %v, expected null", device["asset_tag"])
+		}
+
+		srv.ResetRequests()
+		reconcile(strPtr(""), strPtr(""))
+		if got := srv.CountRequests("PATCH", "/api/dcim/devices/"); got != 0 {
+			t.Errorf("Cleared serial was updated again %d times", got)
+		}
+	})
+}
+
+func TestValidateAssetTag(t *testing.T) {
+	tests := []struct {
+		tag     *string
+		wantErr bool
+	}{
+		{nil, false},
+		{strPtr(""), false},
+		{strPtr("INV-1000"), false},
+		{strPtr(strings.Repeat("x", constants.MaxAssetTagLength)), false},
+		{strPtr(strings.Repeat("x", constants.MaxAssetTagLength+1)), true},
+		{strPtr(" INV-1000"), true},
+		{strPtr("INV\t1000"), true},
+	}
+
+	for _, tt := range tests {
+		if err := validateAssetTag(tt.tag); (err != nil) != tt.wantErr {
+			t.Errorf("validateAssetTag(%v) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+		}
+	}
+}
+
+// TestCheckDuplicateComponents tests that a device declaring eth0 twice fails before any apply
+func TestCheckDuplicateComponents(t *testing.T) {
+	c, srv := newTestClient(t)
+	dr := NewDeviceReconciler(c)
+
+	devices := []*models.DeviceConfig{
+		{Name: "srv-01", Interfaces: []models.InterfaceConfig{{Name: "eth0"}, {Name: "eth1"}, {Name: "eth0"}}},
+		{Name: "srv-02", Interfaces: []models.InterfaceConfig{{Name: "eth0"}}},
+		{Name: "pp-01", RearPorts: []models.RearPortConfig{{Name: "R1"}, {Name: "R1"}}},
+	}
+
+	srv.ResetRequests()
+	err := dr.ReconcileDevices(devices)
+	if err == nil {
+		t.Fatal("ReconcileDevices() expected duplicate component error")
+	}
+	for _, want := range []string{`device srv-01 declares interface "eth0"`, `device pp-01 declares rear port "R1"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Error %q does not mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "srv-02") {
+		t.Errorf("Error %q reports srv-02, which has no duplicates", err)
+	}
+	if got := srv.CountRequests("POST", "/api/"); got != 0 {
+		t.Errorf("Objects were created despite the duplicates (%d POSTs)", got)
+	}
+}
+
+// TestReconcileNestedModules tests installing an optic into a bay provided by a line card module
+func TestReconcileNestedModules(t *testing.T) {
+	c, srv := newTestClient(t)
+	linecard := srv.Add("dcim", "module-types", map[string]interface{}{"model": "Line Card", "slug": "linecard"})
+	optic := srv.Add("dcim", "module-types", map[string]interface{}{"model": "QSFP28", "slug": "qsfp28"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "sw-01"})
+	deviceID := device["id"].(int)
+	slot := srv.Add("dcim", "module-bays", map[string]interface{}{"name": "Slot 1", "device": deviceID})
+	// A device-level bay with the same name as the line card's bay must not be used
+	srv.Add("dcim", "module-bays", map[string]interface{}{"name": "Port 1", "device": deviceID})
+
+	// NetBox creates the bays of a module type's templates when the module is installed
+	var portBay map[string]interface{}
+	srv.Intercept("POST", "/api/dcim/modules/", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		module := srv.Add("dcim", "modules", body)
+		if netboxtest.ID(body["module_type"]) == netboxtest.ID(linecard["id"]) {
+			portBay = srv.Add("dcim", "module-bays", map[string]interface{}{"name": "Port 1", "device": deviceID, "module": module["id"]})
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(module)
+	})
+
+	config := &models.DeviceConfig{
+		Name: "sw-01",
+		Modules: []models.ModuleConfig{{
+			Name:           "Slot 1",
+			ModuleTypeSlug: "linecard",
+			Modules:        []models.ModuleConfig{{Name: "Port 1", ModuleTypeSlug: "qsfp28"}},
+		}},
+	}
+	if err := NewDeviceReconciler(c).reconcileModules(deviceID, config); err != nil {
+		t.Fatalf("reconcileModules() error = %v", err)
+	}
+
+	modules := srv.Objects("dcim", "modules")
+	if len(modules) != 2 {
+		t.Fatalf("Installed %d modules, expected 2", len(modules))
+	}
+	for _, module := range modules {
+		switch netboxtest.ID(module["module_type"]) {
+		case netboxtest.ID(linecard["id"]):
+			if netboxtest.ID(module["module_bay"]) != netboxtest.ID(slot["id"]) {
+				t.Errorf("Line card bay = %v, expected device bay %v", module["module_bay"], slot["id"])
+			}
+		case netboxtest.ID(optic["id"]):
+			if portBay == nil || netboxtest.ID(module["module_bay"]) != netboxtest.ID(portBay["id"]) {
+				t.Errorf("Optic bay = %v, expected the line card's bay", module["module_bay"])
+			}
+		}
+	}
+}
+
+// TestReconcileNestedModulesSimulate tests that the child modules of a line card simulated with
+// --simulate are not planned into a device-level bay of the same name
+func TestReconcileNestedModulesSimulate(t *testing.T) {
+	srv := netboxtest.NewServer()
+	t.Cleanup(srv.Close)
+	srv.Add("dcim", "module-types", map[string]interface{}{"model": "Line Card", "slug": "linecard"})
+	srv.Add("dcim", "module-types", map[string]interface{}{"model": "QSFP28", "slug": "qsfp28"})
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "sw-01"})
+	deviceID := device["id"].(int)
+	srv.Add("dcim", "module-bays", map[string]interface{}{"name": "Slot 1", "device": deviceID})
+	srv.Add("dcim", "module-bays", map[string]interface{}{"name": "Port 1", "device": deviceID})
+
+	c, err := client.NewClient(srv.URL, "test-token", true)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	c.SetSimulate(true)
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	config := &models.DeviceConfig{
+		Name: "sw-01",
+		Modules: []models.ModuleConfig{{
+			Name:           "Slot 1",
+			ModuleTypeSlug: "linecard",
+			Modules:        []models.ModuleConfig{{Name: "Port 1", ModuleTypeSlug: "qsfp28"}},
+		}},
+	}
+	if err := NewDeviceReconciler(c).reconcileModules(deviceID, config); err != nil {
+		t.Fatalf("reconcileModules() error = %v", err)
+	}
+	if got := srv.CountRequests("GET", "/api/dcim/module-bays/"); got != 1 {
+		t.Errorf("Got %d module bay lookups, expected only Slot 1 as the simulated line card has no bays", got)
+	}
+}
+
+// TestReconcileInterfacesVLANByVID tests selecting VLANs by "vid:N" within the device's site and by "group/N"
+func TestReconcileInterfacesVLANByVID(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	other := srv.Add("dcim", "sites", map[string]interface{}{"name": "Munich DC", "slug": "munich-dc"})
+	srv.Add("ipam", "vlans", map[string]interface{}{"name": "servers", "vid": 100, "site": other["id"]})
+	servers := srv.Add("ipam", "vlans", map[string]interface{}{"name": "servers", "vid": 100, "site": site["id"]})
+	backup := srv.Add("ipam", "vlans", map[string]interface{}{
+		"name":  "backup",
+		"vid":   200,
+		"group": map[string]interface{}{"id": 900, "slug": "dc-vlans"},
+	})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	if err := c.Cache().LoadSite("berlin-dc"); err != nil {
+		t.Fatalf("LoadSite() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "sw-01", "site": site["id"]})
+	deviceID := device["id"].(int)
+
+	config := &models.DeviceConfig{
+		Name:     "sw-01",
+		SiteSlug: "berlin-dc",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "Eth1/1", Type: "10gbase-x-sfpp", Mode: "tagged", UntaggedVLAN: "vid:100", TaggedVLANs: []string{"dc-vlans/200", "vid:999"}},
+		},
+	}
+
+	if err := dr.reconcileInterfaces(deviceID, config); err != nil {
+		t.Fatalf("reconcileInterfaces() error = %v", err)
+	}
+
+	iface := srv.Find("dcim", "interfaces", "name", "Eth1/1")
+	if iface == nil {
+		t.Fatal("Interface Eth1/1 was not created")
+	}
+	if got := netboxtest.ID(iface["untagged_vlan"]); got != netboxtest.ID(servers) {
+		t.Errorf("untagged_vlan = %v, expected VID 100 at berlin-dc (ID %v)", iface["untagged_vlan"], servers["id"])
+	}
+	tagged, _ := iface["tagged_vlans"].([]interface{})
+	if len(tagged) != 1 || netboxtest.ID(tagged[0]) != netboxtest.ID(backup) {
+		t.Errorf("tagged_vlans = %v, expected only dc-vlans/200 (ID %v)", iface["tagged_vlans"], backup["id"])
+	}
+}
+
+// TestReconcileInterfaceTypeChangeFlagged tests that an interface type change is warned about,
+// and only applied to module-backed interfaces with AllowInterfaceTypeChange
+func TestReconcileInterfaceTypeChangeFlagged(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	var out bytes.Buffer
+	c.Logger().SetOutput(&out, &out)
+	dr := NewDeviceReconciler(c)
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "sw-01", "site": site["id"]})
+	deviceRef := map[string]interface{}{"id": device["id"]}
+	srv.Add("dcim", "interfaces", map[string]interface{}{
+		"name": "Eth1", "device": deviceRef, "type": map[string]interface{}{"value": "1000base-t"}, "cable": map[string]interface{}{"id": 7},
+	})
+	srv.Add("dcim", "interfaces", map[string]interface{}{
+		"name": "Eth2", "device": deviceRef, "type": map[string]interface{}{"value": "1000base-t"}, "module": map[string]interface{}{"id": 3},
+	})
+
+	config := &models.DeviceConfig{
+		Name:       "sw-01",
+		SiteSlug:   "berlin-dc",
+		Interfaces: []models.InterfaceConfig{{Name: "Eth1", Type: "10gbase-x-sfpp"}},
+	}
+	if err := dr.reconcileInterfaces(netboxtest.ID(device), config); err != nil {
+		t.Fatalf("reconcileInterfaces() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Interface Eth1 on sw-01 changes type 1000base-t → 10gbase-x-sfpp (has cable") {
+		t.Errorf("Type change was not flagged:\n%s", out.String())
+	}
+	if got := statusValue(srv.Find("dcim", "interfaces", "name", "Eth1")["type"]); got != "10gbase-x-sfpp" {
+		t.Errorf("Eth1 type = %s, expected the change to be applied", got)
+	}
+
+	config.Interfaces = []models.InterfaceConfig{{Name: "Eth2", Type: "10gbase-x-sfpp"}}
+	if err := dr.reconcileInterfaces(netboxtest.ID(device), config); err == nil || !strings.Contains(err.Error(), "--allow-interface-type-change") {
+		t.Errorf("reconcileInterfaces() error = %v, expected the module-backed type change to be refused", err)
+	}
+	if got := statusValue(srv.Find("dcim", "interfaces", "name", "Eth2")["type"]); got != "1000base-t" {
+		t.Errorf("Eth2 type = %s, expected it unchanged", got)
+	}
+
+	dr.AllowInterfaceTypeChange(true)
+	if err := dr.reconcileInterfaces(netboxtest.ID(device), config); err != nil {
+		t.Fatalf("reconcileInterfaces() with AllowInterfaceTypeChange error = %v", err)
+	}
+	if got := statusValue(srv.Find("dcim", "interfaces", "name", "Eth2")["type"]); got != "10gbase-x-sfpp" {
+		t.Errorf("Eth2 type = %s, expected the allowed change to be applied", got)
+	}
+}
+
+// TestReconcileInterfacesFromTemplate tests that with InterfacesFromTemplate, interfaces the
+// device type's templates provide are only updated with device-specific settings, never created
+func TestReconcileInterfacesFromTemplate(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	dr := NewDeviceReconciler(c)
+	dr.InterfacesFromTemplate(true)
+
+	deviceType := srv.Add("dcim", "device-types", map[string]interface{}{"model": "PowerEdge R640", "slug": "dell-r640"})
+	deviceTypeRef := map[string]interface{}{"id": deviceType["id"]}
+	srv.Add("dcim", "interface-templates", map[string]interface{}{"name": "eth0", "device_type": deviceTypeRef, "type": "25gbase-x-sfp28"})
+	srv.Add("dcim", "interface-templates", map[string]interface{}{"name": "eth1", "device_type": deviceTypeRef, "type": "25gbase-x-sfp28"})
+
+	device := srv.Add("dcim", "devices", map[string]interface{}{"name": "srv-01", "site": site["id"]})
+	srv.Add("dcim", "interfaces", map[string]interface{}{
+		"name": "eth0", "device": map[string]interface{}{"id": device["id"]}, "type": map[string]interface{}{"value": "25gbase-x-sfp28"},
+	})
+
+	config := &models.DeviceConfig{
+		Name:           "srv-01",
+		SiteSlug:       "berlin-dc",
+		DeviceTypeSlug: "dell-r640",
+		Interfaces: []models.InterfaceConfig{
+			{Name: "eth0", Type: "1000base-t", Description: "uplink"},
+			{Name: "eth1", Type: "25gbase-x-sfp28"},
+			{Name: "bond0", Type: "lag"},
+		},
+	}
+	if err := dr.reconcileInterfaces(netboxtest.ID(device), config); err != nil {
+		t.Fatalf("reconcileInterfaces() error = %v", err)
+	}
+
+	if n := len(srv.Objects("dcim", "interfaces")); n != 2 {
+		t.Errorf("Got %d interfaces, expected eth0 and the non-template bond0", n)
+	}
+	eth0 := srv.Find("dcim", "interfaces", "name", "eth0")
+	if eth0["description"] != "uplink" {
+		t.Errorf("eth0 description = %v, expected the device-specific setting to be applied", eth0["description"])
+	}
+	if got := statusValue(eth0["type"]); got != "25gbase-x-sfp28" {
+		t.Errorf("eth0 type = %s, expected the template's type to be kept", got)
+	}
+	if srv.Find("dcim", "interfaces", "name", "eth1") != nil {
+		t.Error("eth1 was created, expected it left to the device type template")
+	}
+	if srv.Find("dcim", "interfaces", "name", "bond0") == nil {
+		t.Error("bond0 was not created, expected interfaces without a template to be created")
+	}
+
+	// A second device of the same type reuses the template names of the first
+	srv.ResetRequests()
+	device2 := srv.Add("dcim", "devices", map[string]interface{}{"name": "srv-02", "site": site["id"]})
+	config2 := &models.DeviceConfig{
+		Name:           "srv-02",
+		SiteSlug:       "berlin-dc",
+		DeviceTypeSlug: "dell-r640",
+		Interfaces:     []models.InterfaceConfig{{Name: "eth1", Type: "25gbase-x-sfp28"}},
+	}
+	if err := dr.reconcileInterfaces(netboxtest.ID(device2), config2); err != nil {
+		t.Fatalf("reconcileInterfaces() for srv-02 error = %v", err)
+	}
+	if got := srv.CountRequests("GET", "/api/dcim/interface-templates/"); got != 0 {
+		t.Errorf("Got %d interface template queries for srv-02, expected the names memoized per device type", got)
+	}
+	if got := srv.CountRequests("GET", "/api/dcim/device-types/"); got != 0 {
+		t.Errorf("Got %d device type queries for srv-02, expected the names memoized per device type", got)
+	}
+}
@@ -0,0 +1,264 @@
+package reconciler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// ExtrasReconciler handles NetBox automation objects (webhooks, event rules, export templates, custom fields)
+type ExtrasReconciler struct {
+	client *client.NetBoxClient
+	logger *utils.Logger
+}
+
+// NewExtrasReconciler creates a new extras reconciler
+func NewExtrasReconciler(c *client.NetBoxClient) *ExtrasReconciler {
+	return &ExtrasReconciler{
+		client: c,
+		logger: c.Logger(),
+	}
+}
+
+// ReconcileWebhooks reconciles webhook definitions and the event rules that trigger them
+func (er *ExtrasReconciler) ReconcileWebhooks(webhooks []*models.Webhook) error {
+	er.logger.Info("Reconciling %d webhooks...", len(webhooks))
+
+	for _, webhook := range webhooks {
+		payload := map[string]interface{}{
+			"name":        webhook.Name,
+			"payload_url": webhook.PayloadURL,
+		}
+
+		if webhook.HTTPMethod != "" {
+			payload["http_method"] = webhook.HTTPMethod
+		}
+		if webhook.HTTPContentType != "" {
+			payload["http_content_type"] = webhook.HTTPContentType
+		}
+		if webhook.AdditionalHeaders != "" {
+			payload["additional_headers"] = normalizeText(webhook.AdditionalHeaders)
+		}
+		if webhook.BodyTemplate != "" {
+			payload["body_template"] = normalizeText(webhook.BodyTemplate)
+		}
+		if webhook.Description != "" {
+			payload["description"] = webhook.Description
+		}
+
+		lookup := map[string]interface{}{"name": webhook.Name}
+		webhookObj, err := er.client.Apply("extras", "webhooks", lookup, payload)
+		if err != nil {
+			if er.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile webhook %s: %w", webhook.Name, err)
+		}
+
+		if len(webhook.Events) > 0 {
+			if err := er.reconcileEventRule(webhook, utils.GetIDFromObject(webhookObj)); err != nil {
+				return fmt.Errorf("failed to reconcile event rule for webhook %s: %w", webhook.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// reconcileEventRule reconciles the event rule (named after the webhook) that fires it
+func (er *ExtrasReconciler) reconcileEventRule(webhook *models.Webhook, webhookID int) error {
+	if len(webhook.ObjectTypes) == 0 {
+		return fmt.Errorf("events require at least one object type")
+	}
+
+	eventTypes := make([]string, 0, len(webhook.Events))
+	for _, event := range webhook.Events {
+		eventType, ok := constants.WebhookEventTypes[event]
+		if !ok {
+			return fmt.Errorf("unknown event %q (valid: create, update, delete)", event)
+		}
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	if webhookID == 0 {
+		return nil // Webhook was created in dry-run mode
+	}
+
+	payload := map[string]interface{}{
+		"name":               webhook.Name,
+		"object_types":       webhook.ObjectTypes,
+		"event_types":        eventTypes,
+		"action_type":        "webhook",
+		"action_object_type": "extras.webhook",
+		"action_object_id":   webhookID,
+		"enabled":            true,
+	}
+
+	lookup := map[string]interface{}{"name": webhook.Name}
+	_, err := er.client.Apply("extras", "event-rules", lookup, payload)
+	return err
+}
+
+// ReconcileExportTemplates reconciles export template definitions
+func (er *ExtrasReconciler) ReconcileExportTemplates(templates []*models.ExportTemplate) error {
+	er.logger.Info("Reconciling %d export templates...", len(templates))
+
+	for _, tmpl := range templates {
+		payload := map[string]interface{}{
+			"name":          tmpl.Name,
+			"object_types":  tmpl.ObjectTypes,
+			"template_code": normalizeText(tmpl.TemplateCode),
+			"as_attachment": tmpl.AsAttachment,
+		}
+
+		if tmpl.MimeType != "" {
+			payload["mime_type"] = tmpl.MimeType
+		}
+		if tmpl.FileExtension != "" {
+			payload["file_extension"] = tmpl.FileExtension
+		}
+		if tmpl.Description != "" {
+			payload["description"] = tmpl.Description
+		}
+
+		lookup := map[string]interface{}{"name": tmpl.Name}
+		if _, err := er.client.Apply("extras", "export-templates", lookup, payload); err != nil {
+			if er.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile export template %s: %w", tmpl.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ReconcileConfigTemplates reconciles config templates, so devices can reference them by name
+func (er *ExtrasReconciler) ReconcileConfigTemplates(templates []*models.ConfigTemplate) error {
+	er.logger.Info("Reconciling %d config templates...", len(templates))
+
+	for _, tmpl := range templates {
+		payload := map[string]interface{}{
+			"name":          tmpl.Name,
+			"template_code": normalizeText(tmpl.TemplateCode),
+		}
+
+		if len(tmpl.EnvironmentParams) > 0 {
+			payload["environment_params"] = tmpl.EnvironmentParams
+		}
+		if tmpl.Description != "" {
+			payload["description"] = tmpl.Description
+		}
+		setTags(er.client, payload, tmpl.Tags, "config template "+tmpl.Name)
+
+		lookup := map[string]interface{}{"name": tmpl.Name}
+		tmplObj, err := er.client.Apply("extras", "config-templates", lookup, payload)
+		if err != nil {
+			if er.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile config template %s: %w", tmpl.Name, err)
+		}
+
+		if tmplID := utils.GetIDFromObject(tmplObj); tmplID > 0 {
+			er.client.Cache().Set("config_templates", tmpl.Name, tmplID)
+		}
+	}
+
+	return nil
+}
+
+// ReconcileCustomFieldChoiceSets reconciles the choice sets referenced by selection custom fields
+func (er *ExtrasReconciler) ReconcileCustomFieldChoiceSets(choiceSets []*models.CustomFieldChoiceSet) error {
+	er.logger.Info("Reconciling %d custom field choice sets...", len(choiceSets))
+
+	for _, choiceSet := range choiceSets {
+		// NetBox stores choices as [value, label] pairs
+		choices := make([][]string, 0, len(choiceSet.Choices))
+		for _, choice := range choiceSet.Choices {
+			choices = append(choices, []string{choice, choice})
+		}
+
+		payload := map[string]interface{}{
+			"name":                 choiceSet.Name,
+			"extra_choices":        choices,
+			"order_alphabetically": choiceSet.OrderAlphabetically,
+		}
+		if choiceSet.Description != "" {
+			payload["description"] = choiceSet.Description
+		}
+
+		lookup := map[string]interface{}{"name": choiceSet.Name}
+		choiceSetObj, err := er.client.Apply("extras", "custom-field-choice-sets", lookup, payload)
+		if err != nil {
+			if er.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile custom field choice set %s: %w", choiceSet.Name, err)
+		}
+
+		if choiceSetID := utils.GetIDFromObject(choiceSetObj); choiceSetID > 0 {
+			er.client.Cache().Set("custom_field_choice_sets", choiceSet.Name, choiceSetID)
+		}
+	}
+
+	return nil
+}
+
+// ReconcileCustomFields reconciles custom field definitions, so values can be set on objects
+func (er *ExtrasReconciler) ReconcileCustomFields(fields []*models.CustomFieldDef) error {
+	er.logger.Info("Reconciling %d custom fields...", len(fields))
+
+	for _, field := range fields {
+		payload := map[string]interface{}{
+			"name":         field.Name,
+			"type":         field.Type,
+			"object_types": field.ObjectTypes,
+			"required":     field.Required,
+		}
+
+		if field.ChoiceSet != "" {
+			choiceSetID, ok := er.client.Cache().GetGlobalIDOrReload("custom_field_choice_sets", field.ChoiceSet)
+			if !ok {
+				return fmt.Errorf("choice set %s not found for custom field %s", field.ChoiceSet, field.Name)
+			}
+			payload["choice_set"] = choiceSetID
+		}
+		if field.Label != "" {
+			payload["label"] = field.Label
+		}
+		if field.Default != nil {
+			payload["default"] = field.Default
+		}
+		if field.GroupName != "" {
+			payload["group_name"] = field.GroupName
+		}
+		if field.Weight > 0 {
+			payload["weight"] = field.Weight
+		}
+		if field.Description != "" {
+			payload["description"] = field.Description
+		}
+
+		lookup := map[string]interface{}{"name": field.Name}
+		if _, err := er.client.Apply("extras", "custom-fields", lookup, payload); err != nil {
+			if er.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile custom field %s: %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// normalizeText brings multi-line text into the form NetBox stores: the API
+// trims surrounding whitespace, so a YAML block scalar's trailing newline
+// would otherwise show up as a change on every run
+func normalizeText(s string) string {
+	return strings.TrimSpace(strings.ReplaceAll(s, "\r\n", "\n"))
+}
@@ -0,0 +1,213 @@
+package reconciler
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+)
+
+// loadFixture unmarshals a list fixture from the example definitions
+func loadFixture(t *testing.T, path string, target interface{}) {
+	t.Helper()
+	data, err := os.ReadFile("../../example/definitions/" + path)
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	if err := yaml.Unmarshal(data, target); err != nil {
+		t.Fatalf("Failed to parse fixture: %v", err)
+	}
+}
+
+// TestReconcileWebhookOnDeviceCreate tests a webhook firing on device create, and that
+// multi-line body templates don't churn
+func TestReconcileWebhookOnDeviceCreate(t *testing.T) {
+	c, srv := newTestClient(t)
+	er := NewExtrasReconciler(c)
+
+	var webhooks []*models.Webhook
+	loadFixture(t, "webhooks/webhooks.yaml", &webhooks)
+
+	if err := er.ReconcileWebhooks(webhooks); err != nil {
+		t.Fatalf("ReconcileWebhooks() error = %v", err)
+	}
+
+	webhook := srv.Find("extras", "webhooks", "name", "Device Provisioning")
+	if webhook == nil {
+		t.Fatal("Webhook Device Provisioning was not created")
+	}
+	if body, _ := webhook["body_template"].(string); strings.HasSuffix(body, "\n") || !strings.Contains(body, "{{ data.name }}") {
+		t.Errorf("Webhook body_template = %q, expected trimmed template", body)
+	}
+
+	rule := srv.Find("extras", "event-rules", "name", "Device Provisioning")
+	if rule == nil {
+		t.Fatal("Event rule for webhook Device Provisioning was not created")
+	}
+	if netboxtest.ID(rule["action_object_id"]) != netboxtest.ID(webhook["id"]) {
+		t.Errorf("Event rule action_object_id = %v, expected webhook ID %v", rule["action_object_id"], webhook["id"])
+	}
+	objectTypes, _ := rule["object_types"].([]interface{})
+	if len(objectTypes) != 1 || objectTypes[0] != "dcim.device" {
+		t.Errorf("Event rule object_types = %v, expected [dcim.device]", rule["object_types"])
+	}
+	eventTypes, _ := rule["event_types"].([]interface{})
+	if len(eventTypes) != 1 || eventTypes[0] != "object_created" {
+		t.Errorf("Event rule event_types = %v, expected [object_created]", rule["event_types"])
+	}
+
+	srv.ResetRequests()
+	if err := er.ReconcileWebhooks(webhooks); err != nil {
+		t.Fatalf("ReconcileWebhooks() second run error = %v", err)
+	}
+	if got := srv.CountRequests("PATCH", "/api/extras/"); got != 0 {
+		t.Errorf("Unchanged webhook was updated %d times", got)
+	}
+}
+
+// TestReconcileWebhookUnknownEvent tests that an unknown event name is rejected
+func TestReconcileWebhookUnknownEvent(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	webhooks := []*models.Webhook{{
+		Name:        "Bad",
+		PayloadURL:  "https://example.com",
+		ObjectTypes: []string{"dcim.device"},
+		Events:      []string{"created"},
+	}}
+	if err := NewExtrasReconciler(c).ReconcileWebhooks(webhooks); err == nil {
+		t.Error("ReconcileWebhooks() expected error for unknown event")
+	}
+}
+
+// TestReconcileExportTemplatesNoChurn tests that multi-line template code is idempotent
+func TestReconcileExportTemplatesNoChurn(t *testing.T) {
+	c, srv := newTestClient(t)
+	er := NewExtrasReconciler(c)
+
+	var templates []*models.ExportTemplate
+	loadFixture(t, "export_templates/export_templates.yaml", &templates)
+
+	for run := 1; run <= 2; run++ {
+		if err := er.ReconcileExportTemplates(templates); err != nil {
+			t.Fatalf("ReconcileExportTemplates() run %d error = %v", run, err)
+		}
+	}
+
+	if got := srv.CountRequests("POST", "/api/extras/export-templates/"); got != 1 {
+		t.Errorf("Expected 1 export template create, got %d", got)
+	}
+	if got := srv.CountRequests("PATCH", "/api/extras/export-templates/"); got != 0 {
+		t.Errorf("Unchanged export template was updated %d times", got)
+	}
+}
+
+// TestReconcileCustomFieldsOnDevices tests a text custom field on devices and a selection
+// field referencing a choice set reconciled before it
+func TestReconcileCustomFieldsOnDevices(t *testing.T) {
+	c, srv := newTestClient(t)
+	er := NewExtrasReconciler(c)
+
+	var choiceSets []*models.CustomFieldChoiceSet
+	loadFixture(t, "custom_field_choice_sets/custom_field_choice_sets.yaml", &choiceSets)
+	var fields []*models.CustomFieldDef
+	loadFixture(t, "custom_fields/custom_fields.yaml", &fields)
+
+	for run := 1; run <= 2; run++ {
+		if err := er.ReconcileCustomFieldChoiceSets(choiceSets); err != nil {
+			t.Fatalf("ReconcileCustomFieldChoiceSets() run %d error = %v", run, err)
+		}
+		if err := er.ReconcileCustomFields(fields); err != nil {
+			t.Fatalf("ReconcileCustomFields() run %d error = %v", run, err)
+		}
+	}
+
+	hostname := srv.Find("extras", "custom-fields", "name", "hostname")
+	if hostname == nil {
+		t.Fatal("Custom field hostname was not created")
+	}
+	if hostname["type"] != "text" {
+		t.Errorf("hostname type = %v, expected text", hostname["type"])
+	}
+	if types, _ := hostname["object_types"].([]interface{}); len(types) != 1 || types[0] != "dcim.device" {
+		t.Errorf("hostname object_types = %v, expected [dcim.device]", hostname["object_types"])
+	}
+	if _, tagged := hostname["tags"]; tagged {
+		t.Errorf("hostname tags = %v, custom fields have no tags", hostname["tags"])
+	}
+
+	tier := srv.Find("extras", "custom-fields", "name", "support_tier")
+	choiceSet := srv.Find("extras", "custom-field-choice-sets", "name", "Support Tiers")
+	if tier == nil || choiceSet == nil {
+		t.Fatalf("support_tier = %v, choice set = %v, expected both created", tier, choiceSet)
+	}
+	if netboxtest.ID(tier["choice_set"]) != netboxtest.ID(choiceSet) {
+		t.Errorf("support_tier choice_set = %v, expected %v", tier["choice_set"], choiceSet["id"])
+	}
+	if tier["default"] != "silver" {
+		t.Errorf("support_tier default = %v, expected silver", tier["default"])
+	}
+
+	if got := srv.CountRequests("PATCH", "/api/extras/"); got != 0 {
+		t.Errorf("Second run sent %d updates, expected none", got)
+	}
+
+	if err := er.ReconcileCustomFields([]*models.CustomFieldDef{{Name: "rack_tier", Type: "select", ObjectTypes: []string{"dcim.rack"}, ChoiceSet: "missing"}}); err == nil {
+		t.Error("ReconcileCustomFields() expected error for unknown choice set")
+	}
+}
+
+// TestReconcileConfigTemplateOnDevice tests assigning a config template, reconciled from
+// the example fixture, to a device by name
+func TestReconcileConfigTemplateOnDevice(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	srv.Add("dcim", "device-roles", map[string]interface{}{"name": "Switch", "slug": "switch"})
+	srv.Add("dcim", "device-types", map[string]interface{}{"model": "Example Switch 48", "slug": "example-switch-48"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	var templates []*models.ConfigTemplate
+	loadFixture(t, "config_templates/config_templates.yaml", &templates)
+	if err := NewExtrasReconciler(c).ReconcileConfigTemplates(templates); err != nil {
+		t.Fatalf("ReconcileConfigTemplates() error = %v", err)
+	}
+
+	tmpl := srv.Find("extras", "config-templates", "name", "Access Switch")
+	if tmpl == nil {
+		t.Fatal("Config template Access Switch was not created")
+	}
+	if strings.HasSuffix(tmpl["template_code"].(string), "\n") {
+		t.Error("template_code keeps the trailing newline, expected it trimmed like NetBox does")
+	}
+
+	dr := NewDeviceReconciler(c)
+	device := &models.DeviceConfig{
+		Name:           "sw-01",
+		SiteSlug:       "berlin-dc",
+		RoleSlug:       "switch",
+		DeviceTypeSlug: "example-switch-48",
+		ConfigTemplate: "Access Switch",
+	}
+	if err := dr.reconcileDevice(device); err != nil {
+		t.Fatalf("reconcileDevice() error = %v", err)
+	}
+
+	created := srv.Find("dcim", "devices", "name", "sw-01")
+	if created == nil {
+		t.Fatal("Device sw-01 was not created")
+	}
+	if netboxtest.ID(created["config_template"]) != netboxtest.ID(tmpl) {
+		t.Errorf("sw-01 config_template = %v, expected %v", created["config_template"], tmpl["id"])
+	}
+
+	device.ConfigTemplate = "Missing"
+	if err := dr.reconcileDevice(device); err == nil {
+		t.Error("reconcileDevice() expected error for unknown config template")
+	}
+}
@@ -0,0 +1,165 @@
+package reconciler
+
+import (
+	"fmt"
+
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// FHRPReconciler handles first-hop redundancy groups (ipam/fhrp-groups) and their
+// interface assignments (ipam/fhrp-group-assignments)
+type FHRPReconciler struct {
+	client *client.NetBoxClient
+	logger *utils.Logger
+}
+
+// NewFHRPReconciler creates a new FHRP group reconciler
+func NewFHRPReconciler(c *client.NetBoxClient) *FHRPReconciler {
+	return &FHRPReconciler{
+		client: c,
+		logger: c.Logger(),
+	}
+}
+
+// ReconcileFHRPGroups reconciles FHRP groups and assigns them to device interfaces.
+// Assigned devices are looked up at their inventory site, so they must be in devices;
+// assignments of devices outside this run's inventory (e.g. another --site) are skipped.
+func (fr *FHRPReconciler) ReconcileFHRPGroups(groups []*models.FHRPGroup, devices []*models.DeviceConfig) error {
+	fr.logger.Info("Reconciling %d FHRP groups...", len(groups))
+
+	sites := make(map[string]string, len(devices))
+	for _, device := range devices {
+		sites[device.Name] = device.SiteSlug
+	}
+
+	for _, group := range groups {
+		if err := fr.reconcileGroup(group, sites); err != nil {
+			if fr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile FHRP group %s: %w", describeFHRPGroup(group), err)
+		}
+	}
+
+	return nil
+}
+
+// describeFHRPGroup names a group in messages, e.g. "vrrp2 10 (gw-berlin)"
+func describeFHRPGroup(group *models.FHRPGroup) string {
+	if group.Name == "" {
+		return fmt.Sprintf("%s %d", group.Protocol, group.GroupID)
+	}
+	return fmt.Sprintf("%s %d (%s)", group.Protocol, group.GroupID, group.Name)
+}
+
+// reconcileGroup creates or updates one group and its assignments
+func (fr *FHRPReconciler) reconcileGroup(group *models.FHRPGroup, sites map[string]string) error {
+	payload := map[string]interface{}{
+		"protocol": group.Protocol,
+		"group_id": group.GroupID,
+	}
+
+	if group.Name != "" {
+		payload["name"] = group.Name
+	}
+	if group.AuthType != "" {
+		payload["auth_type"] = group.AuthType
+	}
+	if group.AuthKey != "" {
+		payload["auth_key"] = group.AuthKey
+	}
+	if group.Description != "" {
+		payload["description"] = group.Description
+	}
+	setTags(fr.client, payload, group.Tags, "FHRP group "+describeFHRPGroup(group))
+
+	lookup := map[string]interface{}{
+		"protocol": group.Protocol,
+		"group_id": group.GroupID,
+	}
+	if group.Name != "" {
+		lookup["name"] = group.Name
+	}
+	groupObj, err := fr.client.Apply("ipam", "fhrp-groups", lookup, payload)
+	if err != nil {
+		return err
+	}
+
+	groupID := utils.GetIDFromObject(groupObj)
+	if groupID == 0 {
+		fr.logger.Debug("FHRP group %s created in dry-run mode, skipping assignments", describeFHRPGroup(group))
+		return nil
+	}
+
+	for _, assignment := range group.Assignments {
+		if err := fr.reconcileAssignment(groupID, assignment, sites); err != nil {
+			return fmt.Errorf("assignment to %s[%s]: %w", assignment.Device, assignment.Interface, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileAssignment binds the group to a device interface. Assignments are matched by
+// group and interface, so a changed priority updates the existing assignment.
+func (fr *FHRPReconciler) reconcileAssignment(groupID int, assignment models.FHRPGroupAssignment, sites map[string]string) error {
+	siteSlug, ok := sites[assignment.Device]
+	if !ok {
+		fr.logger.Debug("  Device %s is not in this run's inventory, skipping its FHRP assignment", assignment.Device)
+		return nil
+	}
+	siteID, ok := fr.client.Cache().GetGlobalID("sites", siteSlug)
+	if !ok {
+		return fmt.Errorf("site %s not found", siteSlug)
+	}
+
+	devices, err := fr.client.Filter("dcim", "devices", map[string]interface{}{
+		"name":    assignment.Device,
+		"site_id": siteID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to look up device: %w", err)
+	}
+	var interfaces []client.Object
+	if len(devices) > 0 {
+		interfaces, err = fr.client.Filter("dcim", "interfaces", map[string]interface{}{
+			"device_id": utils.GetIDFromObject(devices[0]),
+			"name":      assignment.Interface,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to look up interface: %w", err)
+		}
+	}
+	if len(interfaces) == 0 {
+		if fr.client.IsDryRun() {
+			return nil // Device or interface would be created in this run
+		}
+		return fmt.Errorf("interface not found")
+	}
+	interfaceID := utils.GetIDFromObject(interfaces[0])
+
+	priority := constants.DefaultFHRPPriority
+	if assignment.Priority != nil {
+		priority = *assignment.Priority
+	}
+	if priority < 0 || priority > 255 {
+		return fmt.Errorf("priority %d out of range 0-255", priority)
+	}
+
+	lookup := map[string]interface{}{
+		"group_id":       groupID,
+		"interface_type": constants.TerminationInterface,
+		"interface_id":   interfaceID,
+	}
+	payload := map[string]interface{}{
+		"group":          groupID,
+		"interface_type": constants.TerminationInterface,
+		"interface_id":   interfaceID,
+		"priority":       priority,
+	}
+	_, err = fr.client.Apply("ipam", "fhrp-group-assignments", lookup, payload)
+	return err
+}
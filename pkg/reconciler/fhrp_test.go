@@ -0,0 +1,72 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+)
+
+// TestReconcileFHRPGroupAssignsInterface tests creating a VRRP group and assigning it to an
+// interface, keyed on group and interface so a second run changes nothing
+func TestReconcileFHRPGroupAssignsInterface(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	sw := srv.Add("dcim", "devices", map[string]interface{}{"name": "sw-01", "site": site["id"]})
+	vlan10 := srv.Add("dcim", "interfaces", map[string]interface{}{"name": "Vlan10", "device": map[string]interface{}{"id": sw["id"]}})
+
+	priority := 200
+	groups := []*models.FHRPGroup{{
+		Name:        "gw-berlin",
+		Protocol:    "vrrp3",
+		GroupID:     10,
+		AuthType:    "md5",
+		AuthKey:     "secret",
+		Assignments: []models.FHRPGroupAssignment{{Device: "sw-01", Interface: "Vlan10", Priority: &priority}},
+	}}
+	devices := []*models.DeviceConfig{{Name: "sw-01", SiteSlug: "berlin-dc"}}
+
+	fr := NewFHRPReconciler(c)
+	if err := fr.ReconcileFHRPGroups(groups, devices); err != nil {
+		t.Fatalf("ReconcileFHRPGroups() error = %v", err)
+	}
+
+	group := srv.Find("ipam", "fhrp-groups", "name", "gw-berlin")
+	if group == nil {
+		t.Fatal("FHRP group gw-berlin was not created")
+	}
+	if group["protocol"] != "vrrp3" || netboxtest.ID(group["group_id"]) != 10 || group["auth_type"] != "md5" {
+		t.Errorf("FHRP group = %v, expected vrrp3 group 10 with md5 auth", group)
+	}
+
+	assignments := srv.Objects("ipam", "fhrp-group-assignments")
+	if len(assignments) != 1 {
+		t.Fatalf("Got %d FHRP group assignments, expected 1", len(assignments))
+	}
+	assignment := assignments[0]
+	if netboxtest.ID(assignment["group"]) != netboxtest.ID(group) ||
+		assignment["interface_type"] != "dcim.interface" ||
+		netboxtest.ID(assignment["interface_id"]) != netboxtest.ID(vlan10) ||
+		netboxtest.ID(assignment["priority"]) != 200 {
+		t.Errorf("FHRP group assignment = %v, expected gw-berlin on Vlan10 with priority 200", assignment)
+	}
+	if _, tagged := assignment["tags"]; tagged {
+		t.Errorf("FHRP group assignment has tags %v, expected none (assignments have no tags)", assignment["tags"])
+	}
+
+	// Second run must not touch anything
+	srv.ResetRequests()
+	if err := fr.ReconcileFHRPGroups(groups, devices); err != nil {
+		t.Fatalf("ReconcileFHRPGroups() second run error = %v", err)
+	}
+	if n := srv.CountRequests("POST", ""); n != 0 {
+		t.Errorf("Second run sent %d POST requests, expected none", n)
+	}
+	if n := srv.CountRequests("PATCH", ""); n != 0 {
+		t.Errorf("Second run sent %d PATCH requests, expected none", n)
+	}
+}
@@ -2,6 +2,7 @@ package reconciler
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/braunma/netbox-gitops-controller/pkg/client"
 	"github.com/braunma/netbox-gitops-controller/pkg/models"
@@ -12,13 +13,17 @@ import (
 type FoundationReconciler struct {
 	client *client.NetBoxClient
 	logger *utils.Logger
+
+	// roleGroupColors maps role group slugs to their color, inherited by roles without one
+	roleGroupColors map[string]string
 }
 
 // NewFoundationReconciler creates a new foundation reconciler
 func NewFoundationReconciler(c *client.NetBoxClient) *FoundationReconciler {
 	return &FoundationReconciler{
-		client: c,
-		logger: c.Logger(),
+		client:          c,
+		logger:          c.Logger(),
+		roleGroupColors: make(map[string]string),
 	}
 }
 
@@ -39,18 +44,31 @@ func (fr *FoundationReconciler) ReconcileSites(sites []*models.Site) error {
 		if site.TimeZone != "" {
 			payload["time_zone"] = site.TimeZone
 		}
-		if site.Description != "" {
-			payload["description"] = site.Description
+		// Present-but-empty clears the field, absent leaves NetBox untouched
+		if site.Description != nil {
+			payload["description"] = *site.Description
 		}
-		if site.Comments != "" {
-			payload["comments"] = site.Comments
+		if site.Comments != nil {
+			payload["comments"] = *site.Comments
 		}
 
 		lookup := map[string]interface{}{"slug": site.Slug}
-		_, err := fr.client.Apply("dcim", "sites", lookup, payload)
+		siteObj, err := fr.client.Apply("dcim", "sites", lookup, payload)
 		if err != nil {
+			if fr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile site %s: %w", site.Name, err)
 		}
+
+		if len(site.Contacts) > 0 {
+			if err := reconcileContactAssignments(fr.client, "dcim.site", utils.GetIDFromObject(siteObj), site.Contacts); err != nil {
+				if fr.client.SkipTimeout(err) {
+					continue
+				}
+				return fmt.Errorf("failed to reconcile contacts for site %s: %w", site.Name, err)
+			}
+		}
 	}
 
 	return nil
@@ -60,6 +78,11 @@ func (fr *FoundationReconciler) ReconcileSites(sites []*models.Site) error {
 func (fr *FoundationReconciler) ReconcileRacks(racks []*models.Rack) error {
 	fr.logger.Info("Reconciling %d racks...", len(racks))
 
+	// Rack asset tags are globally unique in NetBox, catch conflicts before the opaque 400
+	if err := fr.checkRackAssetTags(racks); err != nil {
+		return err
+	}
+
 	for _, rack := range racks {
 		// Get site ID using LIVE lookup (not cache) - matches Python dcim.py lines 26-30
 		// This is critical because the site might have been just created and not in cache yet
@@ -99,6 +122,12 @@ func (fr *FoundationReconciler) ReconcileRacks(racks []*models.Rack) error {
 		if rack.Description != "" {
 			payload["description"] = rack.Description
 		}
+		if rack.FacilityID != nil {
+			payload["facility_id"] = *rack.FacilityID
+		}
+		if rack.AssetTag != nil {
+			payload["asset_tag"] = assetTagValue(*rack.AssetTag)
+		}
 
 		lookup := map[string]interface{}{
 			"site_id": siteID,
@@ -107,6 +136,9 @@ func (fr *FoundationReconciler) ReconcileRacks(racks []*models.Rack) error {
 
 		_, err = fr.client.Apply("dcim", "racks", lookup, payload)
 		if err != nil {
+			if fr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile rack %s: %w", rack.Name, err)
 		}
 	}
@@ -114,22 +146,153 @@ func (fr *FoundationReconciler) ReconcileRacks(racks []*models.Rack) error {
 	return nil
 }
 
+// checkRackAssetTags fails if a rack asset tag is invalid, used by two racks in YAML,
+// or already held by a NetBox rack that is not declared in YAML
+func (fr *FoundationReconciler) checkRackAssetTags(racks []*models.Rack) error {
+	owners := make(map[string][]string)
+	var tags []string
+	declared := make(map[string]bool, len(racks))
+
+	var conflicts []string
+	for _, rack := range racks {
+		owner := fmt.Sprintf("rack %s at %s", rack.Name, rack.SiteSlug)
+		declared[rack.SiteSlug+"/"+rack.Name] = true
+		if err := validateAssetTag(rack.AssetTag); err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("%s: %v", owner, err))
+		}
+		if rack.AssetTag == nil || *rack.AssetTag == "" {
+			continue
+		}
+		if _, seen := owners[*rack.AssetTag]; !seen {
+			tags = append(tags, *rack.AssetTag)
+		}
+		owners[*rack.AssetTag] = append(owners[*rack.AssetTag], owner)
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("invalid rack asset tags:\n  %s", strings.Join(conflicts, "\n  "))
+	}
+
+	for _, tag := range tags {
+		if len(owners[tag]) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("asset tag %q is used by %s", tag, strings.Join(owners[tag], ", ")))
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("duplicate rack asset tags:\n  %s", strings.Join(conflicts, "\n  "))
+	}
+
+	// Asset tags already held by racks outside the definitions
+	holders, err := assetTagHolders(fr.client, "dcim", "racks", tags)
+	if err != nil {
+		return err
+	}
+	for _, holder := range holders {
+		tag, _ := holder["asset_tag"].(string)
+		name, _ := holder["name"].(string)
+		site := nestedString(holder, "site", "slug")
+		if declared[site+"/"+name] {
+			continue
+		}
+		managed := "unmanaged"
+		if fr.client.Tags().IsManaged(holder, fr.client.ManagedTagID()) {
+			managed = "managed"
+		}
+		conflicts = append(conflicts, fmt.Sprintf("asset tag %q (%s) is already held by %s rack %s at %s (ID: %d)",
+			tag, strings.Join(owners[tag], ", "), managed, name, site, utils.GetIDFromObject(holder)))
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("rack asset tags already in use:\n  %s", strings.Join(conflicts, "\n  "))
+	}
+
+	return nil
+}
+
+// ReconcileRoleGroups reconciles role group definitions.
+// NetBox models role groups as parent device roles, so groups share the roles endpoint.
+func (fr *FoundationReconciler) ReconcileRoleGroups(groups []*models.RoleGroup) error {
+	fr.logger.Info("Reconciling %d role groups...", len(groups))
+
+	for _, group := range groups {
+		payload := map[string]interface{}{
+			"name": group.Name,
+			"slug": group.Slug,
+		}
+
+		if color := utils.NormalizeColor(group.Color); color != "" {
+			payload["color"] = color
+			fr.roleGroupColors[group.Slug] = color
+		} else {
+			fr.logger.Warning("Role group %s has invalid color %q, leaving color unchanged", group.Name, group.Color)
+		}
+		if group.Description != "" {
+			payload["description"] = group.Description
+		}
+
+		lookup := map[string]interface{}{"slug": group.Slug}
+		groupObj, err := fr.client.Apply("dcim", "device-roles", lookup, payload)
+		if err != nil {
+			if fr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile role group %s: %w", group.Name, err)
+		}
+
+		// Make groups created in this run resolvable for their roles, including the
+		// negative IDs of --simulate
+		if groupID := utils.GetIDFromObject(groupObj); groupID != 0 {
+			fr.client.Cache().Set("roles", group.Slug, groupID)
+		}
+	}
+
+	return nil
+}
+
 // ReconcileRoles reconciles role definitions
 func (fr *FoundationReconciler) ReconcileRoles(roles []*models.Role) error {
 	fr.logger.Info("Reconciling %d roles...", len(roles))
 
 	for _, role := range roles {
 		payload := map[string]interface{}{
-			"name":        role.Name,
-			"slug":        role.Slug,
-			"color":       utils.NormalizeColor(role.Color),
-			"vm_role":     role.VMRole,
-			"description": role.Description,
+			"name":    role.Name,
+			"slug":    role.Slug,
+			"vm_role": role.VMRole,
+		}
+
+		roleColor := role.Color
+		if role.Group != "" {
+			groupID, ok := fr.client.Cache().GetGlobalID("roles", role.Group)
+			switch {
+			case !ok && fr.client.IsDryRun():
+				fr.logger.Warning("Role group %s of role %s not found (created in dry-run mode), planning the role without it", role.Group, role.Name)
+			case !ok:
+				return fmt.Errorf("role group %s not found for role %s", role.Group, role.Name)
+			default:
+				payload["parent"] = groupID
+			}
+
+			if roleColor == "" {
+				roleColor = fr.roleGroupColors[role.Group]
+			}
+		}
+
+		// NetBox stores colors as lowercase 6-char hex, normalize so "#FF0000" doesn't PATCH every run
+		if color := utils.NormalizeColor(roleColor); color != "" {
+			payload["color"] = color
+		} else {
+			fr.logger.Warning("Role %s has invalid color %q, leaving color unchanged", role.Name, role.Color)
+		}
+
+		// Omit empty description so a manually-set description isn't blanked
+		if role.Description != "" {
+			payload["description"] = role.Description
 		}
 
 		lookup := map[string]interface{}{"slug": role.Slug}
 		_, err := fr.client.Apply("dcim", "device-roles", lookup, payload)
 		if err != nil {
+			if fr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile role %s: %w", role.Name, err)
 		}
 	}
@@ -137,6 +300,40 @@ func (fr *FoundationReconciler) ReconcileRoles(roles []*models.Role) error {
 	return nil
 }
 
+// ReconcileInventoryItemRoles reconciles inventory item role definitions
+func (fr *FoundationReconciler) ReconcileInventoryItemRoles(roles []*models.InventoryItemRole) error {
+	fr.logger.Info("Reconciling %d inventory item roles...", len(roles))
+
+	for _, role := range roles {
+		payload := map[string]interface{}{
+			"name":  role.Name,
+			"slug":  role.Slug,
+			"color": utils.NormalizeColor(role.Color),
+		}
+
+		// Omit empty description so a manually-set description isn't blanked
+		if role.Description != "" {
+			payload["description"] = role.Description
+		}
+
+		lookup := map[string]interface{}{"slug": role.Slug}
+		roleObj, err := fr.client.Apply("dcim", "inventory-item-roles", lookup, payload)
+		if err != nil {
+			if fr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile inventory item role %s: %w", role.Name, err)
+		}
+
+		// Make roles created in this run resolvable for inventory item templates
+		if roleID := utils.GetIDFromObject(roleObj); roleID != 0 {
+			fr.client.Cache().Set("inventory_item_roles", role.Slug, roleID)
+		}
+	}
+
+	return nil
+}
+
 // ReconcileTags reconciles tag definitions
 func (fr *FoundationReconciler) ReconcileTags(tags []*models.Tag) error {
 	fr.logger.Info("Reconciling %d tags...", len(tags))
@@ -152,9 +349,78 @@ func (fr *FoundationReconciler) ReconcileTags(tags []*models.Tag) error {
 		lookup := map[string]interface{}{"slug": tag.Slug}
 		_, err := fr.client.Apply("extras", "tags", lookup, payload)
 		if err != nil {
+			if fr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile tag %s: %w", tag.Name, err)
 		}
 	}
 
 	return nil
 }
+
+// ReconcileConfigContexts reconciles config context definitions.
+// Config contexts can't carry the managed tag: their tags are assignment criteria.
+func (fr *FoundationReconciler) ReconcileConfigContexts(contexts []*models.ConfigContext) error {
+	fr.logger.Info("Reconciling %d config contexts...", len(contexts))
+
+	for _, cc := range contexts {
+		payload := map[string]interface{}{
+			"name": cc.Name,
+			"data": cc.Data,
+		}
+
+		if cc.Weight != 0 {
+			payload["weight"] = cc.Weight
+		}
+		if cc.Description != "" {
+			payload["description"] = cc.Description
+		}
+		if len(cc.Roles) > 0 {
+			payload["roles"] = resolveSlugs(fr.client, "roles", "dcim", "device-roles", cc.Roles, cc.Name)
+		}
+		if len(cc.Sites) > 0 {
+			payload["sites"] = resolveSlugs(fr.client, "sites", "dcim", "sites", cc.Sites, cc.Name)
+		}
+		if len(cc.Tags) > 0 {
+			payload["tags"] = cc.Tags
+		}
+
+		lookup := map[string]interface{}{"name": cc.Name}
+		if _, err := fr.client.Apply("extras", "config-contexts", lookup, payload); err != nil {
+			if fr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile config context %s: %w", cc.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// resolveSlugs resolves slugs to IDs using the cache, falling back to a live lookup
+// for objects created during this run. Unresolved slugs are logged and skipped.
+func resolveSlugs(c *client.NetBoxClient, resource, app, endpoint string, slugs []string, owner string) []int {
+	ids := []int{}
+	for _, slug := range slugs {
+		if id, ok := c.Cache().GetGlobalID(resource, slug); ok {
+			ids = append(ids, id)
+			continue
+		}
+
+		objs, err := c.Filter(app, endpoint, map[string]interface{}{"slug": slug})
+		if err != nil || len(objs) == 0 {
+			c.Logger().Warning("%s %s not found for %s, skipping", resource, slug, owner)
+			continue
+		}
+		ids = append(ids, utils.GetIDFromObject(objs[0]))
+	}
+	return ids
+}
+
+// setTags sets the tags of a component payload from their slugs; Apply adds the managed tag
+func setTags(c *client.NetBoxClient, payload map[string]interface{}, slugs []string, owner string) {
+	if len(slugs) > 0 {
+		payload["tags"] = resolveSlugs(c, "tags", "extras", "tags", slugs, owner)
+	}
+}
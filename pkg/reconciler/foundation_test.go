@@ -0,0 +1,294 @@
+package reconciler
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+)
+
+func TestReconcileRolesNoOpForExistingRole(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Add("dcim", "device-roles", map[string]interface{}{
+		"name":        "Server",
+		"slug":        "server",
+		"color":       "ff0000",
+		"vm_role":     false,
+		"description": "Set by hand in the UI",
+		"tags":        []interface{}{map[string]interface{}{"id": c.ManagedTagID(), "slug": constants.ManagedTagSlug}},
+	})
+
+	fr := NewFoundationReconciler(c)
+	roles := []*models.Role{{Name: "Server", Slug: "server", Color: "#FF0000"}}
+	if err := fr.ReconcileRoles(roles); err != nil {
+		t.Fatalf("ReconcileRoles() error = %v", err)
+	}
+
+	if got := srv.CountRequests("PATCH", "/api/dcim/device-roles/"); got != 0 {
+		t.Errorf("Unchanged role was updated %d times", got)
+	}
+	role := srv.Find("dcim", "device-roles", "slug", "server")
+	if role["description"] != "Set by hand in the UI" {
+		t.Errorf("Role description = %v, expected it to be left untouched", role["description"])
+	}
+}
+
+func TestReconcileRolesInRoleGroup(t *testing.T) {
+	c, srv := newTestClient(t)
+	fr := NewFoundationReconciler(c)
+
+	groups := []*models.RoleGroup{{Name: "Compute", Slug: "compute", Color: "#4CAF50"}}
+	if err := fr.ReconcileRoleGroups(groups); err != nil {
+		t.Fatalf("ReconcileRoleGroups() error = %v", err)
+	}
+	group := srv.Find("dcim", "device-roles", "slug", "compute")
+	if group == nil {
+		t.Fatal("Role group was not created")
+	}
+
+	roles := []*models.Role{
+		{Name: "Server", Slug: "server", Group: "compute"},
+		{Name: "Storage", Slug: "storage", Group: "compute", Color: "ff9800"},
+	}
+	if err := fr.ReconcileRoles(roles); err != nil {
+		t.Fatalf("ReconcileRoles() error = %v", err)
+	}
+
+	server := srv.Find("dcim", "device-roles", "slug", "server")
+	if netboxtest.ID(server["parent"]) != netboxtest.ID(group["id"]) {
+		t.Errorf("Role parent = %v, expected group %v", server["parent"], group["id"])
+	}
+	if server["color"] != "4caf50" {
+		t.Errorf("Role color = %v, expected inherited group color 4caf50", server["color"])
+	}
+	if storage := srv.Find("dcim", "device-roles", "slug", "storage"); storage["color"] != "ff9800" {
+		t.Errorf("Role color = %v, expected its own color ff9800", storage["color"])
+	}
+
+	unknown := []*models.Role{{Name: "Router", Slug: "router", Group: "networking", Color: "ff0000"}}
+	if err := fr.ReconcileRoles(unknown); err == nil {
+		t.Error("ReconcileRoles() expected error for unknown role group")
+	}
+}
+
+// TestReconcileRolesInNewRoleGroupDryRun tests that a role in a role group created in the same
+// run plans under --dry-run and --simulate instead of failing on the group
+func TestReconcileRolesInNewRoleGroupDryRun(t *testing.T) {
+	for _, simulate := range []bool{false, true} {
+		c, srv := newTestClient(t)
+		c.SetDryRun(true)
+		c.SetSimulate(simulate)
+		fr := NewFoundationReconciler(c)
+
+		if err := fr.ReconcileRoleGroups([]*models.RoleGroup{{Name: "Compute", Slug: "compute", Color: "4caf50"}}); err != nil {
+			t.Fatalf("ReconcileRoleGroups(simulate=%v) error = %v", simulate, err)
+		}
+		if err := fr.ReconcileRoles([]*models.Role{{Name: "Server", Slug: "server", Group: "compute"}}); err != nil {
+			t.Errorf("ReconcileRoles(simulate=%v) error = %v, expected the role planned", simulate, err)
+		}
+		if n := len(srv.Objects("dcim", "device-roles")); n != 0 {
+			t.Errorf("simulate=%v: dry-run created %d roles, expected none", simulate, n)
+		}
+	}
+}
+
+// TestReconcileConfigContextsDeepDiff tests that nested JSON data is compared deeply, not as a string
+func TestReconcileConfigContextsDeepDiff(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	srv.Add("dcim", "device-roles", map[string]interface{}{"name": "Switch", "slug": "switch"})
+
+	data, err := os.ReadFile("../../example/definitions/config_contexts/config_contexts.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	var contexts []*models.ConfigContext
+	if err := yaml.Unmarshal(data, &contexts); err != nil {
+		t.Fatalf("Failed to parse fixture: %v", err)
+	}
+
+	fr := NewFoundationReconciler(c)
+	if err := fr.ReconcileConfigContexts(contexts); err != nil {
+		t.Fatalf("ReconcileConfigContexts() error = %v", err)
+	}
+
+	berlin := srv.Find("extras", "config-contexts", "name", "Berlin Switches")
+	if berlin == nil {
+		t.Fatal("Config context Berlin Switches was not created")
+	}
+	if sites, _ := berlin["sites"].([]interface{}); len(sites) != 1 || netboxtest.ID(sites[0]) != netboxtest.ID(site["id"]) {
+		t.Errorf("Config context sites = %v, expected [%v]", berlin["sites"], site["id"])
+	}
+	if tags, _ := berlin["tags"].([]interface{}); len(tags) != 0 {
+		t.Errorf("Config context tags = %v, the managed tag must not be injected", berlin["tags"])
+	}
+
+	// Second run: data comes back from NetBox as JSON (float64 numbers) and must not diff
+	srv.ResetRequests()
+	if err := fr.ReconcileConfigContexts(contexts); err != nil {
+		t.Fatalf("ReconcileConfigContexts() second run error = %v", err)
+	}
+	if got := srv.CountRequests("PATCH", "/api/extras/config-contexts/"); got != 0 {
+		t.Errorf("Unchanged config contexts were updated %d times", got)
+	}
+
+	// Changing a nested value must be detected
+	stp := contexts[1].Data["spanning_tree"].(map[string]interface{})
+	stp["priority"] = 4096
+	if err := fr.ReconcileConfigContexts(contexts); err != nil {
+		t.Fatalf("ReconcileConfigContexts() third run error = %v", err)
+	}
+	if got := srv.CountRequests("PATCH", "/api/extras/config-contexts/"); got != 1 {
+		t.Errorf("Expected 1 update after changing nested data, got %d", got)
+	}
+}
+
+// TestReconcileSitesDescriptionPresence tests set, clear and leave-untouched semantics
+func TestReconcileSitesDescriptionPresence(t *testing.T) {
+	c, srv := newTestClient(t)
+	fr := NewFoundationReconciler(c)
+
+	site := srv.Add("dcim", "sites", map[string]interface{}{
+		"name":        "Berlin DC",
+		"slug":        "berlin-dc",
+		"status":      "active",
+		"description": "Set in the UI",
+		"tags":        []interface{}{map[string]interface{}{"id": c.ManagedTagID()}},
+	})
+
+	reconcile := func(t *testing.T, description *string) {
+		t.Helper()
+		sites := []*models.Site{{Name: "Berlin DC", Slug: "berlin-dc", Status: "active", Description: description}}
+		if err := fr.ReconcileSites(sites); err != nil {
+			t.Fatalf("ReconcileSites() error = %v", err)
+		}
+	}
+
+	t.Run("absent leaves untouched", func(t *testing.T) {
+		reconcile(t, nil)
+		if site["description"] != "Set in the UI" {
+			t.Errorf("description = %q, expected it to be left untouched", site["description"])
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		reconcile(t, strPtr("Primary data center"))
+		if site["description"] != "Primary data center" {
+			t.Errorf("description = %q, expected %q", site["description"], "Primary data center")
+		}
+	})
+
+	t.Run("empty clears", func(t *testing.T) {
+		reconcile(t, strPtr(""))
+		if site["description"] != "" {
+			t.Errorf("description = %q, expected it to be cleared", site["description"])
+		}
+
+		srv.ResetRequests()
+		reconcile(t, strPtr(""))
+		if got := srv.CountRequests("PATCH", "/api/dcim/sites/"); got != 0 {
+			t.Errorf("Cleared description was updated again %d times", got)
+		}
+	})
+}
+
+// TestReconcileSitesSkipsTimedOutSite tests that with --fail-fast=false a site that times out
+// is skipped and the sites after it are still reconciled
+func TestReconcileSitesSkipsTimedOutSite(t *testing.T) {
+	c, srv := newTestClient(t)
+	c.SetObjectTimeout(50*time.Millisecond, false)
+	fr := NewFoundationReconciler(c)
+
+	slow := srv.Add("dcim", "sites", map[string]interface{}{"name": "Slow DC", "slug": "slow-dc", "status": "planned"})
+	srv.Intercept("PATCH", fmt.Sprintf("/api/dcim/sites/%d/", netboxtest.ID(slow)), func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	})
+
+	sites := []*models.Site{
+		{Name: "Slow DC", Slug: "slow-dc", Status: "active"},
+		{Name: "Berlin DC", Slug: "berlin-dc", Status: "active"},
+	}
+	if err := fr.ReconcileSites(sites); err != nil {
+		t.Fatalf("ReconcileSites() error = %v, expected the timed-out site to be skipped", err)
+	}
+	if srv.Find("dcim", "sites", "slug", "berlin-dc") == nil {
+		t.Error("Site berlin-dc after the timed-out site was not created")
+	}
+	if got := c.TimedOutObjects(); len(got) != 1 || !strings.Contains(got[0], "slow-dc") {
+		t.Errorf("TimedOutObjects() = %v, expected the slow site", got)
+	}
+}
+
+// TestReconcileRacksFacilityIDAndAssetTag tests that facility ID and asset tag are passed
+// through and that asset tags held twice or by an undeclared rack fail before any write
+func TestReconcileRacksFacilityIDAndAssetTag(t *testing.T) {
+	c, srv := newTestClient(t)
+	fr := NewFoundationReconciler(c)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	strPtr := func(s string) *string { return &s }
+
+	racks := []*models.Rack{
+		{Name: "Rack A-01", SiteSlug: "berlin-dc", Status: "active", FacilityID: strPtr("BER1-R0101"), AssetTag: strPtr("RACK-0001")},
+		{Name: "Rack A-02", SiteSlug: "berlin-dc", Status: "active"},
+	}
+	if err := fr.ReconcileRacks(racks); err != nil {
+		t.Fatalf("ReconcileRacks() error = %v", err)
+	}
+
+	rack := srv.Find("dcim", "racks", "name", "Rack A-01")
+	if rack == nil {
+		t.Fatal("Rack A-01 was not created")
+	}
+	if rack["facility_id"] != "BER1-R0101" || rack["asset_tag"] != "RACK-0001" {
+		t.Errorf("Rack A-01 facility_id = %v, asset_tag = %v, expected BER1-R0101 and RACK-0001", rack["facility_id"], rack["asset_tag"])
+	}
+	if other := srv.Find("dcim", "racks", "name", "Rack A-02"); other == nil {
+		t.Fatal("Rack A-02 was not created")
+	} else if _, ok := other["asset_tag"]; ok {
+		t.Errorf("Rack A-02 asset_tag = %v, expected it to be left unset", other["asset_tag"])
+	}
+
+	t.Run("duplicate in YAML", func(t *testing.T) {
+		racks[1].AssetTag = strPtr("RACK-0001")
+		defer func() { racks[1].AssetTag = nil }()
+
+		srv.ResetRequests()
+		err := fr.ReconcileRacks(racks)
+		if err == nil || !strings.Contains(err.Error(), "Rack A-01") || !strings.Contains(err.Error(), "Rack A-02") {
+			t.Errorf("ReconcileRacks() error = %v, expected duplicate asset tag naming both racks", err)
+		}
+		if got := srv.CountRequests("PATCH", "/api/dcim/racks/"); got != 0 {
+			t.Errorf("Conflicting run still sent %d rack updates", got)
+		}
+	})
+
+	t.Run("held by undeclared rack", func(t *testing.T) {
+		srv.Add("dcim", "racks", map[string]interface{}{
+			"name":      "Old Rack",
+			"site":      map[string]interface{}{"id": site["id"], "slug": "berlin-dc"},
+			"asset_tag": "RACK-0002",
+		})
+		racks[1].AssetTag = strPtr("RACK-0002")
+		defer func() { racks[1].AssetTag = nil }()
+
+		srv.ResetRequests()
+		err := fr.ReconcileRacks(racks)
+		if err == nil || !strings.Contains(err.Error(), "unmanaged rack Old Rack") {
+			t.Errorf("ReconcileRacks() error = %v, expected asset tag held by Old Rack", err)
+		}
+		if got := srv.CountRequests("GET", "/api/dcim/racks/"); got != 1 {
+			t.Errorf("Got %d rack lookups, expected both asset tags in one", got)
+		}
+	})
+}
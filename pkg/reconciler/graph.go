@@ -0,0 +1,231 @@
+package reconciler
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ResourceDependency declares the resource types that must be reconciled before a resource type
+type ResourceDependency struct {
+	Name      string
+	DependsOn []string
+}
+
+// ResourceDependencies is the reconciliation dependency graph. Adding a resource type means
+// declaring its dependencies here and registering a runner for it in NewResourceGraph.
+var ResourceDependencies = []ResourceDependency{
+	{"tags", nil},
+	{"custom_field_choice_sets", nil},
+	{"custom_fields", []string{"custom_field_choice_sets"}},
+	{"contact_groups", nil},
+	{"contact_roles", nil},
+	{"contacts", []string{"contact_groups"}},
+	{"tenant_groups", nil},
+	{"tenants", []string{"tenant_groups"}},
+	{"role_groups", nil},
+	{"roles", []string{"role_groups"}},
+	{"inventory_item_roles", nil},
+	{"sites", []string{"contacts", "contact_roles"}},
+	{"racks", []string{"sites"}},
+	{"config_contexts", []string{"tags", "roles", "sites"}},
+	{"webhooks", nil},
+	{"export_templates", nil},
+	{"config_templates", []string{"tags"}},
+	{"providers", nil},
+	{"circuit_types", nil},
+	{"vrfs", nil},
+	{"ipam_roles", nil},
+	{"vlan_groups", []string{"sites", "racks"}},
+	{"vlans", []string{"sites", "vlan_groups", "ipam_roles"}},
+	{"prefixes", []string{"sites", "vrfs", "vlans", "ipam_roles"}},
+	{"vlan_translation_policies", []string{"tags"}},
+	{"module_types", nil},
+	{"device_types", []string{"module_types", "inventory_item_roles"}},
+	{"devices", []string{"tags", "custom_fields", "sites", "racks", "roles", "contacts", "contact_roles", "vrfs", "vlans", "prefixes", "module_types", "device_types", "config_templates"}},
+	{"virtual_chassis", []string{"devices"}},
+	{"fhrp_groups", []string{"tags", "devices"}},
+	{"circuits", []string{"tags", "tenants", "sites", "providers", "circuit_types", "devices"}},
+	{"cables", []string{"devices", "circuits"}}, // links may end on a circuit termination
+}
+
+// Graph runs named steps in dependency order, running independent steps in parallel
+type Graph struct {
+	names []string // insertion order, used to keep the execution order deterministic
+	deps  map[string][]string
+	run   map[string]func() error
+
+	onStart, onFinish func(name string)
+}
+
+// SetStepHooks sets functions called when a step starts and finishes (e.g., to report progress)
+func (g *Graph) SetStepHooks(onStart, onFinish func(name string)) {
+	g.onStart = onStart
+	g.onFinish = onFinish
+}
+
+// Len returns the number of steps
+func (g *Graph) Len() int {
+	return len(g.names)
+}
+
+// NewGraph creates an empty graph
+func NewGraph() *Graph {
+	return &Graph{
+		deps: make(map[string][]string),
+		run:  make(map[string]func() error),
+	}
+}
+
+// NewResourceGraph builds the graph of ResourceDependencies, with a runner for every resource type
+func NewResourceGraph(runners map[string]func() error) (*Graph, error) {
+	g := NewGraph()
+	for _, dep := range ResourceDependencies {
+		run, ok := runners[dep.Name]
+		if !ok {
+			return nil, fmt.Errorf("no runner registered for resource type %s", dep.Name)
+		}
+		g.Add(dep.Name, run, dep.DependsOn...)
+	}
+	if len(runners) != len(ResourceDependencies) {
+		return nil, fmt.Errorf("runners registered for undeclared resource types")
+	}
+	return g, nil
+}
+
+// Add registers a step that runs after all of its dependencies
+func (g *Graph) Add(name string, run func() error, dependsOn ...string) {
+	if _, exists := g.deps[name]; !exists {
+		g.names = append(g.names, name)
+	}
+	g.deps[name] = dependsOn
+	g.run[name] = run
+}
+
+// Levels groups the steps so that every step only depends on steps in earlier levels.
+// Steps within a level are independent of each other.
+func (g *Graph) Levels() ([][]string, error) {
+	for _, name := range g.names {
+		for _, dep := range g.deps[name] {
+			if _, ok := g.deps[dep]; !ok {
+				return nil, fmt.Errorf("step %s depends on unknown step %s", name, dep)
+			}
+		}
+	}
+
+	done := make(map[string]bool, len(g.names))
+	var levels [][]string
+
+	for len(done) < len(g.names) {
+		var level []string
+		for _, name := range g.names {
+			if done[name] || !g.ready(name, done) {
+				continue
+			}
+			level = append(level, name)
+		}
+
+		if len(level) == 0 {
+			var blocked []string
+			for _, name := range g.names {
+				if !done[name] {
+					blocked = append(blocked, name)
+				}
+			}
+			return nil, fmt.Errorf("dependency cycle between steps: %s", strings.Join(blocked, ", "))
+		}
+
+		for _, name := range level {
+			done[name] = true
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+// Order returns the steps in a topological order
+func (g *Graph) Order() ([]string, error) {
+	levels, err := g.Levels()
+	if err != nil {
+		return nil, err
+	}
+
+	var order []string
+	for _, level := range levels {
+		order = append(order, level...)
+	}
+	return order, nil
+}
+
+// Execute runs all steps in dependency order with at most concurrency steps at a time.
+// No new steps are started after the first failure.
+func (g *Graph) Execute(concurrency int) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	levels, err := g.Levels()
+	if err != nil {
+		return err
+	}
+
+	for _, level := range levels {
+		var (
+			wg       sync.WaitGroup
+			mu       sync.Mutex
+			firstErr error
+		)
+		sem := make(chan struct{}, concurrency)
+
+		for _, name := range level {
+			sem <- struct{}{}
+
+			mu.Lock()
+			failed := firstErr != nil
+			mu.Unlock()
+			if failed {
+				<-sem
+				break
+			}
+
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				if g.onStart != nil {
+					g.onStart(name)
+				}
+				if g.onFinish != nil {
+					defer g.onFinish(name)
+				}
+
+				if err := g.run[name](); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("%s: %w", name, err)
+					}
+					mu.Unlock()
+				}
+			}(name)
+		}
+
+		wg.Wait()
+		if firstErr != nil {
+			return firstErr
+		}
+	}
+
+	return nil
+}
+
+// ready reports whether all dependencies of a step are done
+func (g *Graph) ready(name string, done map[string]bool) bool {
+	for _, dep := range g.deps[name] {
+		if !done[dep] {
+			return false
+		}
+	}
+	return true
+}
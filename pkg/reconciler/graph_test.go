@@ -0,0 +1,183 @@
+package reconciler
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// TestResourceGraphOrderRespectsDependencies tests that every declared edge is honored
+func TestResourceGraphOrderRespectsDependencies(t *testing.T) {
+	runners := make(map[string]func() error)
+	for _, dep := range ResourceDependencies {
+		runners[dep.Name] = func() error { return nil }
+	}
+
+	graph, err := NewResourceGraph(runners)
+	if err != nil {
+		t.Fatalf("NewResourceGraph() error = %v", err)
+	}
+	order, err := graph.Order()
+	if err != nil {
+		t.Fatalf("Order() error = %v", err)
+	}
+
+	position := make(map[string]int, len(order))
+	for i, name := range order {
+		position[name] = i
+	}
+	if len(position) != len(ResourceDependencies) {
+		t.Fatalf("Order() returned %d steps, expected %d", len(position), len(ResourceDependencies))
+	}
+
+	for _, dep := range ResourceDependencies {
+		for _, before := range dep.DependsOn {
+			if position[before] >= position[dep.Name] {
+				t.Errorf("%s ordered before its dependency %s: %v", dep.Name, before, order)
+			}
+		}
+	}
+}
+
+// TestNewResourceGraphRequiresAllRunners tests that a missing runner is reported
+func TestNewResourceGraphRequiresAllRunners(t *testing.T) {
+	if _, err := NewResourceGraph(map[string]func() error{"sites": func() error { return nil }}); err == nil {
+		t.Error("NewResourceGraph() expected error for missing runners")
+	}
+}
+
+// TestGraphDetectsCycle tests that cyclic and unknown dependencies are rejected
+func TestGraphDetectsCycle(t *testing.T) {
+	noop := func() error { return nil }
+
+	g := NewGraph()
+	g.Add("a", noop, "b")
+	g.Add("b", noop, "a")
+	if _, err := g.Order(); err == nil {
+		t.Error("Order() expected error for cycle")
+	}
+
+	g = NewGraph()
+	g.Add("a", noop, "missing")
+	if _, err := g.Order(); err == nil {
+		t.Error("Order() expected error for unknown dependency")
+	}
+}
+
+// TestGraphExecuteRunsIndependentStepsInParallel tests parallelism and ordering during execution
+func TestGraphExecuteRunsIndependentStepsInParallel(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		finished = make(map[string]bool)
+		running  int32
+		peak     int32
+	)
+
+	step := func(name string, deps ...string) func() error {
+		return func() error {
+			mu.Lock()
+			for _, dep := range deps {
+				if !finished[dep] {
+					t.Errorf("%s started before %s finished", name, dep)
+				}
+			}
+			mu.Unlock()
+
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+
+			mu.Lock()
+			finished[name] = true
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	g := NewGraph()
+	g.Add("sites", step("sites"))
+	g.Add("vrfs", step("vrfs"))
+	g.Add("racks", step("racks", "sites"), "sites")
+	g.Add("vlans", step("vlans", "sites"), "sites")
+	g.Add("devices", step("devices", "racks", "vlans", "vrfs"), "racks", "vlans", "vrfs")
+
+	if err := g.Execute(4); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(finished) != 5 {
+		t.Errorf("Execute() ran %d steps, expected 5", len(finished))
+	}
+	if peak < 2 {
+		t.Errorf("Independent steps did not run in parallel (peak concurrency %d)", peak)
+	}
+}
+
+// TestGraphExecuteStopsOnError tests that dependents of a failed step never run
+func TestGraphExecuteStopsOnError(t *testing.T) {
+	ran := false
+
+	g := NewGraph()
+	g.Add("sites", func() error { return errors.New("boom") })
+	g.Add("racks", func() error { ran = true; return nil }, "sites")
+
+	if err := g.Execute(1); err == nil {
+		t.Error("Execute() expected error")
+	}
+	if ran {
+		t.Error("Dependent step ran after its dependency failed")
+	}
+}
+
+// TestGraphExecuteReportsProgress tests that a progress line receives phases and stats counts from a run
+func TestGraphExecuteReportsProgress(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Add("ipam", "vrfs", map[string]interface{}{
+		"name": "Management", "enforce_unique": false,
+		"tags": []interface{}{map[string]interface{}{"id": c.ManagedTagID()}},
+	})
+
+	var out bytes.Buffer
+	progress := utils.NewProgress(&out, 2)
+	c.Stats().SetObserver(progress.Record)
+
+	g := NewGraph()
+	g.Add("vrfs", func() error {
+		return NewNetworkReconciler(c).ReconcileVRFs([]*models.VRF{{Name: "Management"}, {Name: "Production"}})
+	})
+	g.Add("sites", func() error {
+		return NewFoundationReconciler(c).ReconcileSites([]*models.Site{{Name: "Berlin DC", Slug: "berlin-dc", Status: "active"}})
+	}, "vrfs")
+	g.SetStepHooks(progress.StartPhase, progress.FinishPhase)
+
+	if err := g.Execute(1); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	counts := progress.Counts()
+	if counts[client.ActionCreated] != 2 || counts[client.ActionUnchanged] != 1 {
+		t.Errorf("Progress counts = %v, expected 2 created and 1 unchanged", counts)
+	}
+	if progress.Done() != 2 {
+		t.Errorf("Progress done = %d, expected 2", progress.Done())
+	}
+	if line := progress.Line(); !strings.HasPrefix(line, "[2/2] - | 2 created, 0 updated, 1 unchanged") {
+		t.Errorf("Progress line = %q", line)
+	}
+	if !strings.Contains(out.String(), "[1/2] sites") {
+		t.Errorf("Progress output %q never showed the sites phase running", out.String())
+	}
+}
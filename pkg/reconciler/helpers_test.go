@@ -0,0 +1,33 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+)
+
+// newTestClient starts a fake NetBox server and returns a client connected to it
+func newTestClient(t *testing.T) (*client.NetBoxClient, *netboxtest.Server) {
+	t.Helper()
+
+	srv := netboxtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	c, err := client.NewClient(srv.URL, "test-token", false)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	return c, srv
+}
+
+// strPtr returns a pointer to s, for optional string fields
+func strPtr(s string) *string {
+	return &s
+}
+
+// boolPtr returns a pointer to b, for optional bool fields
+func boolPtr(b bool) *bool {
+	return &b
+}
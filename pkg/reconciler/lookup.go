@@ -0,0 +1,113 @@
+package reconciler
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LookupStrategiesFile is the lookup strategy file auto-discovered at the root of the data directory
+const LookupStrategiesFile = "lookups.yaml"
+
+// Lookup strategies select the fields a declared object is matched to its NetBox object by
+const (
+	// LookupNameSite matches by name within the object's site (names are unique per site)
+	LookupNameSite = "name+site"
+	// LookupName matches by name alone (names are unique across all sites)
+	LookupName = "name"
+	// lookupCustomFieldPrefix followed by a custom field name matches objects whose
+	// custom field holds the declared name (e.g. "cf:hostname")
+	lookupCustomFieldPrefix = "cf:"
+)
+
+// LookupStrategies maps resource types to the lookup strategy used to find their existing objects
+type LookupStrategies map[string]string
+
+// DefaultLookupStrategies returns the lookup strategy of every resource type that supports one
+func DefaultLookupStrategies() LookupStrategies {
+	return LookupStrategies{
+		"devices": LookupNameSite,
+	}
+}
+
+// LoadLookupStrategies reads a YAML lookup strategy file on top of the defaults.
+// Resource types the file omits keep their default strategy.
+//
+//	devices: name
+func LoadLookupStrategies(path string) (LookupStrategies, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lookup strategy file: %w", err)
+	}
+
+	var overrides map[string]string
+	if err := yaml.Unmarshal(content, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse lookup strategy file %s: %w", path, err)
+	}
+
+	strategies := DefaultLookupStrategies()
+	for resource, strategy := range overrides {
+		if _, ok := strategies[resource]; !ok {
+			return nil, fmt.Errorf("resource type %q in %s has no lookup strategy (valid: %s)", resource, path, strings.Join(strategies.Resources(), ", "))
+		}
+		if err := validateLookupStrategy(strategy); err != nil {
+			return nil, fmt.Errorf("invalid lookup strategy for %s in %s: %w", resource, path, err)
+		}
+		strategies[resource] = strategy
+	}
+
+	return strategies, nil
+}
+
+// Resources returns the resource types with a lookup strategy, sorted
+func (ls LookupStrategies) Resources() []string {
+	resources := make([]string, 0, len(ls))
+	for resource := range ls {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	return resources
+}
+
+// validateLookupStrategy fails for unknown strategies and custom field strategies without a field
+func validateLookupStrategy(strategy string) error {
+	switch {
+	case strategy == LookupNameSite, strategy == LookupName:
+		return nil
+	case strings.HasPrefix(strategy, lookupCustomFieldPrefix):
+		if strings.TrimPrefix(strategy, lookupCustomFieldPrefix) == "" {
+			return fmt.Errorf("%q is missing the custom field name (e.g. %shostname)", strategy, lookupCustomFieldPrefix)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown lookup strategy %q (valid: %s, %s, %s<field>)", strategy, LookupNameSite, LookupName, lookupCustomFieldPrefix)
+	}
+}
+
+// lookupFields builds the lookup of an object declared with a name in a site
+func lookupFields(strategy, name string, siteID int) map[string]interface{} {
+	switch {
+	case strategy == LookupName:
+		return map[string]interface{}{"name": name}
+	case strings.HasPrefix(strategy, lookupCustomFieldPrefix):
+		return map[string]interface{}{"cf_" + strings.TrimPrefix(strategy, lookupCustomFieldPrefix): name}
+	default:
+		return map[string]interface{}{
+			"name":    name,
+			"site_id": siteID,
+		}
+	}
+}
+
+// lookupCustomFields returns the custom fields an object must carry to be found again by a
+// custom field strategy on the next run, or nil for the other strategies
+func lookupCustomFields(strategy, name string) map[string]interface{} {
+	field, ok := strings.CutPrefix(strategy, lookupCustomFieldPrefix)
+	if !ok {
+		return nil
+	}
+	return map[string]interface{}{field: name}
+}
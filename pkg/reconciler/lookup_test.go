@@ -0,0 +1,58 @@
+package reconciler
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadLookupStrategies(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    LookupStrategies
+		wantErr bool
+	}{
+		{"name only", "devices: name\n", LookupStrategies{"devices": LookupName}, false},
+		{"custom field", "devices: cf:hostname\n", LookupStrategies{"devices": "cf:hostname"}, false},
+		{"empty file keeps defaults", "", DefaultLookupStrategies(), false},
+		{"unknown resource", "racks: name\n", nil, true},
+		{"unknown strategy", "devices: serial\n", nil, true},
+		{"custom field without name", "devices: \"cf:\"\n", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), LookupStrategiesFile)
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := LoadLookupStrategies(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadLookupStrategies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadLookupStrategies() = %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupFields(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     map[string]interface{}
+	}{
+		{LookupNameSite, map[string]interface{}{"name": "srv-01", "site_id": 3}},
+		{LookupName, map[string]interface{}{"name": "srv-01"}},
+		{"cf:hostname", map[string]interface{}{"cf_hostname": "srv-01"}},
+	}
+
+	for _, tt := range tests {
+		if got := lookupFields(tt.strategy, "srv-01", 3); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("lookupFields(%q) = %v, expected %v", tt.strategy, got, tt.want)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package reconciler
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
 	"github.com/braunma/netbox-gitops-controller/pkg/client"
 	"github.com/braunma/netbox-gitops-controller/pkg/models"
 	"github.com/braunma/netbox-gitops-controller/pkg/utils"
@@ -42,6 +45,9 @@ func (nr *NetworkReconciler) ReconcileVRFs(vrfs []*models.VRF) error {
 		lookup := map[string]interface{}{"name": vrf.Name}
 		_, err := nr.client.Apply("ipam", "vrfs", lookup, payload)
 		if err != nil {
+			if nr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile VRF %s: %w", vrf.Name, err)
 		}
 	}
@@ -49,6 +55,42 @@ func (nr *NetworkReconciler) ReconcileVRFs(vrfs []*models.VRF) error {
 	return nil
 }
 
+// ReconcileIPAMRoles reconciles IPAM role definitions (roles for prefixes and VLANs)
+func (nr *NetworkReconciler) ReconcileIPAMRoles(roles []*models.IPAMRole) error {
+	nr.logger.Info("Reconciling %d IPAM roles...", len(roles))
+
+	for _, role := range roles {
+		payload := map[string]interface{}{
+			"name": role.Name,
+			"slug": role.Slug,
+		}
+
+		if role.Weight > 0 {
+			payload["weight"] = role.Weight
+		}
+		if role.Description != "" {
+			payload["description"] = role.Description
+		}
+
+		lookup := map[string]interface{}{"slug": role.Slug}
+		roleObj, err := nr.client.Apply("ipam", "roles", lookup, payload)
+		if err != nil {
+			if nr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile IPAM role %s: %w", role.Name, err)
+		}
+
+		// Make roles created in this run resolvable for VLANs and prefixes
+		if roleID := utils.GetIDFromObject(roleObj); roleID > 0 {
+			nr.client.Cache().Set("ipam_roles", role.Slug, roleID)
+			nr.client.Cache().Set("ipam_roles", role.Name, roleID)
+		}
+	}
+
+	return nil
+}
+
 // ReconcileVLANGroups reconciles VLAN group definitions
 func (nr *NetworkReconciler) ReconcileVLANGroups(groups []*models.VLANGroup) error {
 	nr.logger.Info("Reconciling %d VLAN groups...", len(groups))
@@ -59,11 +101,13 @@ func (nr *NetworkReconciler) ReconcileVLANGroups(groups []*models.VLANGroup) err
 			"slug": group.Slug,
 		}
 
-		if group.SiteSlug != "" {
-			siteID, ok := nr.client.Cache().GetID("sites", group.SiteSlug)
-			if ok {
-				payload["site"] = siteID
-			}
+		scopeType, scopeID, err := nr.resolveVLANGroupScope(group)
+		if err != nil {
+			return fmt.Errorf("failed to reconcile VLAN group %s: %w", group.Name, err)
+		}
+		if scopeID > 0 {
+			payload["scope_type"] = scopeType
+			payload["scope_id"] = scopeID
 		}
 
 		if group.Description != "" {
@@ -77,78 +121,207 @@ func (nr *NetworkReconciler) ReconcileVLANGroups(groups []*models.VLANGroup) err
 		}
 
 		lookup := map[string]interface{}{"slug": group.Slug}
-		_, err := nr.client.Apply("ipam", "vlan-groups", lookup, payload)
+		groupObj, err := nr.client.Apply("ipam", "vlan-groups", lookup, payload)
 		if err != nil {
+			if nr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile VLAN group %s: %w", group.Name, err)
 		}
+
+		// Groups not scoped to a site are referenced by group-scoped VLANs later in this run
+		if groupID := utils.GetIDFromObject(groupObj); groupID > 0 && scopeType != "dcim.site" {
+			nr.client.Cache().Set("vlan_groups", group.Slug, groupID)
+		}
 	}
 
 	return nil
 }
 
+// vlanGroupScope is an object type a VLAN group can be scoped to
+type vlanGroupScope struct {
+	contentType   string
+	app, endpoint string
+	lookupField   string // Racks and clusters have no slug
+	withinSite    bool   // Looked up within site_slug, if set
+}
+
+// vlanGroupScopes maps the scope_type values of VLAN groups to their NetBox object types
+var vlanGroupScopes = map[string]vlanGroupScope{
+	"region":        {"dcim.region", "dcim", "regions", "slug", false},
+	"site_group":    {"dcim.sitegroup", "dcim", "site-groups", "slug", false},
+	"site":          {"dcim.site", "dcim", "sites", "slug", false},
+	"location":      {"dcim.location", "dcim", "locations", "slug", true},
+	"rack":          {"dcim.rack", "dcim", "racks", "name", true},
+	"cluster_group": {"virtualization.clustergroup", "virtualization", "cluster-groups", "slug", false},
+	"cluster":       {"virtualization.cluster", "virtualization", "clusters", "name", false},
+}
+
+// resolveVLANGroupScope returns the scope_type and scope_id of a VLAN group. A site_slug
+// without scope_type scopes the group to that site; with a location or rack scope it
+// narrows the lookup of the scope object. A scope ID of 0 means no scope (a global group,
+// or a scope object that does not exist yet in dry-run).
+func (nr *NetworkReconciler) resolveVLANGroupScope(group *models.VLANGroup) (string, int, error) {
+	scopeType, scopeSlug := group.ScopeType, group.ScopeSlug
+	if scopeType == "" {
+		if scopeSlug != "" {
+			return "", 0, fmt.Errorf("scope_slug %s requires a scope_type", scopeSlug)
+		}
+		if group.SiteSlug == "" {
+			return "", 0, nil
+		}
+		scopeType, scopeSlug = "site", group.SiteSlug
+	}
+
+	scope, ok := vlanGroupScopes[scopeType]
+	if !ok {
+		allowed := make([]string, 0, len(vlanGroupScopes))
+		for name := range vlanGroupScopes {
+			allowed = append(allowed, name)
+		}
+		sort.Strings(allowed)
+		return "", 0, fmt.Errorf("invalid scope_type %q, expected one of %s", scopeType, strings.Join(allowed, ", "))
+	}
+	if scopeSlug == "" {
+		return "", 0, fmt.Errorf("scope_type %s requires a scope_slug", scopeType)
+	}
+
+	filters := map[string]interface{}{scope.lookupField: scopeSlug}
+	if scope.withinSite && group.SiteSlug != "" {
+		if siteID, ok := nr.client.Cache().GetGlobalID("sites", group.SiteSlug); ok {
+			filters["site_id"] = siteID
+		}
+	}
+	objects, err := nr.client.Filter(scope.app, scope.endpoint, filters)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to look up %s %s: %w", scopeType, scopeSlug, err)
+	}
+	switch {
+	case len(objects) == 0 && nr.client.IsDryRun():
+		nr.logger.Warning("Scope %s %s of VLAN group %s not found, creating it without a scope", scopeType, scopeSlug, group.Name)
+		return scope.contentType, 0, nil
+	case len(objects) == 0:
+		return "", 0, fmt.Errorf("%s %s not found", scopeType, scopeSlug)
+	case len(objects) > 1:
+		return "", 0, fmt.Errorf("%s %s is ambiguous (%d matches), set site_slug to narrow it down", scopeType, scopeSlug, len(objects))
+	}
+	return scope.contentType, utils.GetIDFromObject(objects[0]), nil
+}
+
 // ReconcileVLANs reconciles VLAN definitions
 func (nr *NetworkReconciler) ReconcileVLANs(vlans []*models.VLAN) error {
 	nr.logger.Info("Reconciling %d VLANs...", len(vlans))
 
+	if err := validateQinQ(vlans); err != nil {
+		return err
+	}
+
+	// S-VLANs must exist before the C-VLANs that reference them
+	ordered := make([]*models.VLAN, 0, len(vlans))
 	for _, vlan := range vlans {
-		// Get site ID using LIVE lookup (not cache) - matches Python ipam.py pattern
-		sites, err := nr.client.Filter("dcim", "sites", map[string]interface{}{
-			"slug": vlan.SiteSlug,
-		})
-		if err != nil || len(sites) == 0 {
-			// Fallback: Try by name
-			sites, err = nr.client.Filter("dcim", "sites", map[string]interface{}{
-				"name": vlan.SiteSlug,
-			})
+		if vlan.QinQSVLAN == "" {
+			ordered = append(ordered, vlan)
 		}
+	}
+	for _, vlan := range vlans {
+		if vlan.QinQSVLAN != "" {
+			ordered = append(ordered, vlan)
+		}
+	}
 
-		if err != nil || len(sites) == 0 {
-			nr.logger.Warning("Site %s not found for VLAN %s, skipping", vlan.SiteSlug, vlan.Name)
-			continue
+	for _, vlan := range ordered {
+		// VLANs are unique per site, or per VLAN group for group-scoped VLANs
+		if vlan.SiteSlug == "" && vlan.GroupSlug == "" {
+			return fmt.Errorf("VLAN %s must have a site_slug or a group_slug", vlan.Name)
 		}
 
-		siteID := utils.GetIDFromObject(sites[0])
-		if siteID == 0 {
-			nr.logger.Warning("Site %s has invalid ID for VLAN %s, skipping", vlan.SiteSlug, vlan.Name)
-			continue
+		siteID := 0
+		if vlan.SiteSlug != "" {
+			// Get site ID using LIVE lookup (not cache) - matches Python ipam.py pattern
+			sites, err := nr.client.Filter("dcim", "sites", map[string]interface{}{
+				"slug": vlan.SiteSlug,
+			})
+			if err != nil || len(sites) == 0 {
+				// Fallback: Try by name
+				sites, err = nr.client.Filter("dcim", "sites", map[string]interface{}{
+					"name": vlan.SiteSlug,
+				})
+			}
+
+			if err != nil || len(sites) == 0 {
+				nr.logger.Warning("Site %s not found for VLAN %s, skipping", vlan.SiteSlug, vlan.Name)
+				continue
+			}
+
+			siteID = utils.GetIDFromObject(sites[0])
+			if siteID == 0 {
+				nr.logger.Warning("Site %s has invalid ID for VLAN %s, skipping", vlan.SiteSlug, vlan.Name)
+				continue
+			}
 		}
 
 		payload := map[string]interface{}{
 			"name":   vlan.Name,
 			"vid":    vlan.VID,
-			"site":   siteID,
 			"status": vlan.Status,
 		}
+		if siteID > 0 {
+			payload["site"] = siteID
+		}
 
+		groupID := 0
 		if vlan.GroupSlug != "" {
 			// VLAN groups can be site-specific OR global
 			// Try site-scoped lookup first (most common), then global fallback
-			groupID, ok := nr.client.Cache().GetSiteID("vlan_groups", siteID, vlan.GroupSlug)
+			id, ok := 0, false
+			if siteID > 0 {
+				id, ok = nr.client.Cache().GetSiteID("vlan_groups", siteID, vlan.GroupSlug)
+			}
 			if !ok {
 				// Fallback: try global VLAN group (no site)
-				groupID, ok = nr.client.Cache().GetGlobalID("vlan_groups", vlan.GroupSlug)
+				id, ok = nr.client.Cache().GetGlobalID("vlan_groups", vlan.GroupSlug)
 			}
 			if ok {
+				groupID = id
 				payload["group"] = groupID
+			} else if siteID == 0 {
+				// Group-scoped VLAN: without its group there is nothing to look it up by
+				nr.logger.Warning("VLAN group %s not found for VLAN %s, skipping", vlan.GroupSlug, vlan.Name)
+				continue
 			} else {
 				nr.logger.Warning("VLAN group %s not found for VLAN %s", vlan.GroupSlug, vlan.Name)
 			}
 		}
 
 		if vlan.Role != "" {
-			payload["role"] = vlan.Role
+			// NetBox expects the role as an ID, not a slug
+			if roleID, ok := nr.client.Cache().GetGlobalID("ipam_roles", vlan.Role); ok {
+				payload["role"] = roleID
+			} else {
+				nr.logger.Warning("IPAM role %s not found for VLAN %s", vlan.Role, vlan.Name)
+			}
 		}
 		if vlan.Description != "" {
 			payload["description"] = vlan.Description
 		}
+		if err := nr.setQinQ(payload, vlan, siteID, groupID); err != nil {
+			return err
+		}
 
 		lookup := map[string]interface{}{
-			"site_id": siteID,
-			"vid":     vlan.VID,
+			"vid": vlan.VID,
+		}
+		if siteID > 0 {
+			lookup["site_id"] = siteID
+		} else {
+			lookup["group_id"] = groupID
 		}
 
-		_, err = nr.client.Apply("ipam", "vlans", lookup, payload)
+		_, err := nr.client.Apply("ipam", "vlans", lookup, payload)
 		if err != nil {
+			if nr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile VLAN %s: %w", vlan.Name, err)
 		}
 	}
@@ -156,18 +329,96 @@ func (nr *NetworkReconciler) ReconcileVLANs(vlans []*models.VLAN) error {
 	return nil
 }
 
+// validateQinQ checks the Q-in-Q roles: only C-VLANs reference an S-VLAN
+func validateQinQ(vlans []*models.VLAN) error {
+	for _, vlan := range vlans {
+		switch vlan.QinQRole {
+		case "", "svlan", "cvlan":
+		default:
+			return fmt.Errorf("VLAN %s: invalid qinq_role %q (valid: svlan, cvlan)", vlan.Name, vlan.QinQRole)
+		}
+		if vlan.QinQSVLAN != "" && vlan.QinQRole != "cvlan" {
+			return fmt.Errorf("VLAN %s: qinq_svlan requires qinq_role cvlan", vlan.Name)
+		}
+	}
+	return nil
+}
+
+// setQinQ adds the Q-in-Q role and S-VLAN of a VLAN to its payload. NetBox before 4.1
+// rejects these fields, so they are left out there.
+func (nr *NetworkReconciler) setQinQ(payload map[string]interface{}, vlan *models.VLAN, siteID, groupID int) error {
+	if vlan.QinQRole == "" {
+		return nil
+	}
+	if !nr.client.AtLeastVersion(4, 1) {
+		nr.logger.Warning("Q-in-Q requires NetBox 4.1 or later, ignoring qinq_role of VLAN %s", vlan.Name)
+		return nil
+	}
+
+	payload["qinq_role"] = vlan.QinQRole
+	if vlan.QinQSVLAN == "" {
+		return nil
+	}
+
+	// The S-VLAN lives in the same site or, for group-scoped VLANs, the same group
+	filters := map[string]interface{}{"name": vlan.QinQSVLAN, "qinq_role": "svlan"}
+	if siteID > 0 {
+		filters["site_id"] = siteID
+	} else {
+		filters["group_id"] = groupID
+	}
+	svlans, err := nr.client.Filter("ipam", "vlans", filters)
+	if err != nil {
+		return fmt.Errorf("failed to look up S-VLAN %s for VLAN %s: %w", vlan.QinQSVLAN, vlan.Name, err)
+	}
+	if len(svlans) == 0 {
+		if nr.client.IsDryRun() {
+			nr.logger.Debug("S-VLAN %s for VLAN %s not found (created in dry-run mode)", vlan.QinQSVLAN, vlan.Name)
+			return nil
+		}
+		return fmt.Errorf("S-VLAN %s for VLAN %s not found (it needs qinq_role svlan)", vlan.QinQSVLAN, vlan.Name)
+	}
+	payload["qinq_svlan"] = utils.GetIDFromObject(svlans[0])
+	return nil
+}
+
 // ReconcilePrefixes reconciles prefix definitions
 func (nr *NetworkReconciler) ReconcilePrefixes(prefixes []*models.Prefix) error {
 	nr.logger.Info("Reconciling %d prefixes...", len(prefixes))
 
+	if err := validatePrefixAllocations(prefixes); err != nil {
+		return err
+	}
+
 	for _, prefix := range prefixes {
+		if prefix.Prefix == "" {
+			allocated, err := nr.allocatePrefix(prefix)
+			if err != nil {
+				return fmt.Errorf("failed to allocate prefix from %s: %w", prefix.ParentPrefix, err)
+			}
+			if allocated == "" {
+				continue // Would be allocated in this run (dry-run)
+			}
+			prefix.Prefix = allocated
+		}
+
 		payload := map[string]interface{}{
 			"prefix": prefix.Prefix,
 			"status": prefix.Status,
 			"is_pool": prefix.IsPool,
 		}
 
-		if prefix.SiteSlug != "" {
+		if prefix.LocationSlug != "" {
+			// A location scope implies its site
+			locationID, err := nr.resolveLocation(prefix)
+			if err != nil {
+				return err
+			}
+			if locationID > 0 {
+				payload["scope_type"] = "dcim.location"
+				payload["scope_id"] = locationID
+			}
+		} else if prefix.SiteSlug != "" {
 			siteID, ok := nr.client.Cache().GetID("sites", prefix.SiteSlug)
 			if ok {
 				payload["site"] = siteID
@@ -205,7 +456,12 @@ func (nr *NetworkReconciler) ReconcilePrefixes(prefixes []*models.Prefix) error
 		}
 
 		if prefix.Role != "" {
-			payload["role"] = prefix.Role
+			// NetBox expects the role as an ID, not a slug
+			if roleID, ok := nr.client.Cache().GetGlobalID("ipam_roles", prefix.Role); ok {
+				payload["role"] = roleID
+			} else {
+				nr.logger.Warning("IPAM role %s not found for prefix %s", prefix.Role, prefix.Prefix)
+			}
 		}
 		if prefix.Description != "" {
 			payload["description"] = prefix.Description
@@ -221,9 +477,131 @@ func (nr *NetworkReconciler) ReconcilePrefixes(prefixes []*models.Prefix) error
 
 		_, err := nr.client.Apply("ipam", "prefixes", lookup, payload)
 		if err != nil {
+			if nr.client.SkipTimeout(err) {
+				continue
+			}
 			return fmt.Errorf("failed to reconcile prefix %s: %w", prefix.Prefix, err)
 		}
 	}
 
 	return nil
 }
+
+// validatePrefixAllocations checks that allocated prefixes name a parent and length, and
+// that each allocation can be told apart from the others (by parent, length, VRF and description)
+func validatePrefixAllocations(prefixes []*models.Prefix) error {
+	seen := make(map[string]bool)
+	for _, prefix := range prefixes {
+		if prefix.Prefix != "" {
+			continue
+		}
+		if prefix.ParentPrefix == "" || prefix.NewPrefixLength <= 0 {
+			return fmt.Errorf("prefix without 'prefix' needs parent_prefix and new_prefix_length")
+		}
+
+		key := fmt.Sprintf("%s|%d|%s|%s", prefix.ParentPrefix, prefix.NewPrefixLength, prefix.VRFName, prefix.Description)
+		if seen[key] {
+			return fmt.Errorf("multiple /%d prefixes allocated from %s with description %q; give each a distinct description",
+				prefix.NewPrefixLength, prefix.ParentPrefix, prefix.Description)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// allocatePrefix returns the child prefix allocated for a definition, allocating the next
+// available one from the parent on the first run. Allocations are re-resolved on later runs
+// as the managed child of the parent with the same length and description.
+// Returns "" if the prefix would be allocated in dry-run mode.
+func (nr *NetworkReconciler) allocatePrefix(prefix *models.Prefix) (string, error) {
+	filters := map[string]interface{}{
+		"within": prefix.ParentPrefix,
+		"tag":    constants.ManagedTagSlug,
+	}
+	var vrfID int
+	if prefix.VRFName != "" {
+		if id, ok := nr.client.Cache().GetID("vrfs", prefix.VRFName); ok {
+			vrfID = id
+			filters["vrf_id"] = vrfID
+		}
+	}
+
+	children, err := nr.client.Filter("ipam", "prefixes", filters)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up allocated prefixes: %w", err)
+	}
+	for _, child := range children {
+		cidr, _ := child["prefix"].(string)
+		description, _ := child["description"].(string)
+		if description == prefix.Description && strings.HasSuffix(cidr, fmt.Sprintf("/%d", prefix.NewPrefixLength)) {
+			return cidr, nil
+		}
+	}
+
+	parentLookup := map[string]interface{}{"prefix": prefix.ParentPrefix}
+	if vrfID > 0 {
+		parentLookup["vrf_id"] = vrfID
+	}
+	parents, err := nr.client.Filter("ipam", "prefixes", parentLookup)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up parent prefix: %w", err)
+	}
+	if len(parents) == 0 {
+		if nr.client.IsDryRun() {
+			return "", nil // Parent would be created in this run
+		}
+		return "", fmt.Errorf("parent prefix %s not found", prefix.ParentPrefix)
+	}
+
+	request := map[string]interface{}{
+		"prefix_length": prefix.NewPrefixLength,
+	}
+	if prefix.Status != "" {
+		request["status"] = prefix.Status
+	}
+	if vrfID > 0 {
+		request["vrf"] = vrfID
+	}
+	if prefix.Description != "" {
+		request["description"] = prefix.Description
+	}
+	request = nr.client.Tags().InjectTag(request, nr.client.ManagedTagID())
+
+	parentID := utils.GetIDFromObject(parents[0])
+	nr.logger.Success("  ✓ Allocating /%d from %s", prefix.NewPrefixLength, prefix.ParentPrefix)
+	created, err := nr.client.Request("POST", fmt.Sprintf("/api/ipam/prefixes/%d/available-prefixes/", parentID), request)
+	if err != nil {
+		return "", err
+	}
+	if nr.client.IsDryRun() {
+		return "", nil
+	}
+
+	cidr, _ := created["prefix"].(string)
+	if cidr == "" {
+		return "", fmt.Errorf("no /%d prefix available in %s", prefix.NewPrefixLength, prefix.ParentPrefix)
+	}
+	nr.client.Stats().Record("prefixes", client.ActionCreated)
+	nr.logger.Info("    Allocated %s", cidr)
+	return cidr, nil
+}
+
+// resolveLocation looks up the ID of a prefix's location, within its site if one is set
+func (nr *NetworkReconciler) resolveLocation(prefix *models.Prefix) (int, error) {
+	filters := map[string]interface{}{"slug": prefix.LocationSlug}
+	if prefix.SiteSlug != "" {
+		if siteID, ok := nr.client.Cache().GetGlobalID("sites", prefix.SiteSlug); ok {
+			filters["site_id"] = siteID
+		}
+	}
+
+	locations, err := nr.client.Filter("dcim", "locations", filters)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up location %s: %w", prefix.LocationSlug, err)
+	}
+	if len(locations) == 0 {
+		nr.logger.Warning("Location %s not found for prefix %s", prefix.LocationSlug, prefix.Prefix)
+		return 0, nil
+	}
+	return utils.GetIDFromObject(locations[0]), nil
+}
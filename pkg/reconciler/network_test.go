@@ -0,0 +1,367 @@
+package reconciler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+)
+
+func TestReconcileVLANsResolvesIPAMRole(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	role := srv.Add("ipam", "roles", map[string]interface{}{"name": "Management", "slug": "management"})
+
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	nr := NewNetworkReconciler(c)
+	vlans := []*models.VLAN{
+		{Name: "Mgmt", VID: 100, SiteSlug: "berlin-dc", Status: "active", Role: "management"},
+		{Name: "Unknown", VID: 200, SiteSlug: "berlin-dc", Status: "active", Role: "does-not-exist"},
+	}
+	if err := nr.ReconcileVLANs(vlans); err != nil {
+		t.Fatalf("ReconcileVLANs() error = %v", err)
+	}
+
+	mgmt := srv.Find("ipam", "vlans", "name", "Mgmt")
+	if mgmt == nil {
+		t.Fatal("VLAN Mgmt was not created")
+	}
+	if got := netboxtest.ID(mgmt["role"]); got != netboxtest.ID(role) {
+		t.Errorf("VLAN Mgmt role = %v, expected role ID %v", got, role["id"])
+	}
+
+	unknown := srv.Find("ipam", "vlans", "name", "Unknown")
+	if unknown == nil {
+		t.Fatal("VLAN Unknown was not created")
+	}
+	if _, ok := unknown["role"]; ok {
+		t.Errorf("VLAN with unknown role should not send role, got %v", unknown["role"])
+	}
+}
+
+// statusHandler answers /api/status/ with a NetBox version
+func statusHandler(version string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"netbox-version": %q}`, version)
+	}
+}
+
+func TestReconcileVLANsQinQ(t *testing.T) {
+	vlans := func() []*models.VLAN {
+		return []*models.VLAN{
+			// Declared before its S-VLAN
+			{Name: "customer-a", VID: 100, SiteSlug: "berlin-dc", Status: "active", QinQRole: "cvlan", QinQSVLAN: "provider"},
+			{Name: "provider", VID: 1000, SiteSlug: "berlin-dc", Status: "active", QinQRole: "svlan"},
+		}
+	}
+
+	t.Run("NetBox 4.1", func(t *testing.T) {
+		c, srv := newTestClient(t)
+		srv.Intercept("GET", "/api/status/", statusHandler("4.1.3"))
+		srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+		nr := NewNetworkReconciler(c)
+
+		if err := nr.ReconcileVLANs(vlans()); err != nil {
+			t.Fatalf("ReconcileVLANs() error = %v", err)
+		}
+
+		svlan := srv.Find("ipam", "vlans", "name", "provider")
+		cvlan := srv.Find("ipam", "vlans", "name", "customer-a")
+		if svlan == nil || cvlan == nil {
+			t.Fatal("VLANs were not created")
+		}
+		if svlan["qinq_role"] != "svlan" || cvlan["qinq_role"] != "cvlan" {
+			t.Errorf("qinq_role = %v/%v, expected svlan/cvlan", svlan["qinq_role"], cvlan["qinq_role"])
+		}
+		if got := netboxtest.ID(cvlan["qinq_svlan"]); got != netboxtest.ID(svlan) {
+			t.Errorf("customer-a qinq_svlan = %v, expected provider ID %v", cvlan["qinq_svlan"], svlan["id"])
+		}
+	})
+
+	t.Run("NetBox 4.0", func(t *testing.T) {
+		c, srv := newTestClient(t)
+		srv.Intercept("GET", "/api/status/", statusHandler("4.0.11"))
+		srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+		nr := NewNetworkReconciler(c)
+
+		if err := nr.ReconcileVLANs(vlans()); err != nil {
+			t.Fatalf("ReconcileVLANs() error = %v", err)
+		}
+
+		for _, vlan := range srv.Objects("ipam", "vlans") {
+			if _, ok := vlan["qinq_role"]; ok {
+				t.Errorf("VLAN %v sent qinq_role to NetBox 4.0", vlan["name"])
+			}
+			if _, ok := vlan["qinq_svlan"]; ok {
+				t.Errorf("VLAN %v sent qinq_svlan to NetBox 4.0", vlan["name"])
+			}
+		}
+	})
+
+	t.Run("S-VLAN without cvlan role", func(t *testing.T) {
+		c, _ := newTestClient(t)
+		invalid := vlans()
+		invalid[0].QinQRole = "svlan"
+		if err := NewNetworkReconciler(c).ReconcileVLANs(invalid); err == nil {
+			t.Error("ReconcileVLANs() expected error for qinq_svlan on an S-VLAN")
+		}
+	})
+}
+
+func TestReconcilePrefixesResolvesIPAMRole(t *testing.T) {
+	c, srv := newTestClient(t)
+	nr := NewNetworkReconciler(c)
+
+	// Role created during this run must be resolvable without a cache reload
+	if err := nr.ReconcileIPAMRoles([]*models.IPAMRole{{Name: "Production", Slug: "production"}}); err != nil {
+		t.Fatalf("ReconcileIPAMRoles() error = %v", err)
+	}
+	role := srv.Find("ipam", "roles", "slug", "production")
+	if role == nil {
+		t.Fatal("IPAM role was not created")
+	}
+
+	prefixes := []*models.Prefix{
+		{Prefix: "10.0.0.0/24", Status: "active", Role: "production"},
+		{Prefix: "10.0.1.0/24", Status: "active", Role: "typo"},
+	}
+	if err := nr.ReconcilePrefixes(prefixes); err != nil {
+		t.Fatalf("ReconcilePrefixes() error = %v", err)
+	}
+
+	known := srv.Find("ipam", "prefixes", "prefix", "10.0.0.0/24")
+	if known == nil || netboxtest.ID(known["role"]) != netboxtest.ID(role) {
+		t.Errorf("Prefix role = %v, expected %v", known["role"], role["id"])
+	}
+
+	unknown := srv.Find("ipam", "prefixes", "prefix", "10.0.1.0/24")
+	if _, ok := unknown["role"]; ok {
+		t.Errorf("Prefix with unknown role should not send role, got %v", unknown["role"])
+	}
+}
+
+func TestReconcileSiteScopedVLANs(t *testing.T) {
+	c, srv := newTestClient(t)
+	berlin := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	munich := srv.Add("dcim", "sites", map[string]interface{}{"name": "Munich DC", "slug": "munich-dc"})
+	nr := NewNetworkReconciler(c)
+
+	// Same VID at two sites: two distinct VLANs
+	vlans := []*models.VLAN{
+		{Name: "Mgmt", VID: 100, SiteSlug: "berlin-dc", Status: "active"},
+		{Name: "Mgmt", VID: 100, SiteSlug: "munich-dc", Status: "active"},
+	}
+	for run := 1; run <= 2; run++ {
+		if err := nr.ReconcileVLANs(vlans); err != nil {
+			t.Fatalf("ReconcileVLANs() run %d error = %v", run, err)
+		}
+	}
+
+	created := srv.Objects("ipam", "vlans")
+	if len(created) != 2 {
+		t.Fatalf("Created %d VLANs, expected 2", len(created))
+	}
+	sites := map[int]bool{}
+	for _, vlan := range created {
+		sites[netboxtest.ID(vlan["site"])] = true
+	}
+	if !sites[netboxtest.ID(berlin)] || !sites[netboxtest.ID(munich)] {
+		t.Errorf("VLAN sites = %v, expected Berlin and Munich", sites)
+	}
+}
+
+func TestReconcileGroupScopedVLANs(t *testing.T) {
+	c, srv := newTestClient(t)
+	nr := NewNetworkReconciler(c)
+
+	groups := []*models.VLANGroup{
+		{Name: "Campus North", Slug: "campus-north"},
+		{Name: "Campus South", Slug: "campus-south"},
+	}
+	if err := nr.ReconcileVLANGroups(groups); err != nil {
+		t.Fatalf("ReconcileVLANGroups() error = %v", err)
+	}
+	north := srv.Find("ipam", "vlan-groups", "slug", "campus-north")
+
+	// Same VID in two groups, no site
+	vlans := []*models.VLAN{
+		{Name: "Voice", VID: 300, GroupSlug: "campus-north", Status: "active"},
+		{Name: "Voice", VID: 300, GroupSlug: "campus-south", Status: "active"},
+	}
+	for run := 1; run <= 2; run++ {
+		if err := nr.ReconcileVLANs(vlans); err != nil {
+			t.Fatalf("ReconcileVLANs() run %d error = %v", run, err)
+		}
+	}
+
+	created := srv.Objects("ipam", "vlans")
+	if len(created) != 2 {
+		t.Fatalf("Created %d VLANs, expected 2", len(created))
+	}
+	for _, vlan := range created {
+		if _, ok := vlan["site"]; ok {
+			t.Errorf("Group-scoped VLAN should not have a site, got %v", vlan["site"])
+		}
+	}
+	if netboxtest.ID(created[0]["group"]) != netboxtest.ID(north) {
+		t.Errorf("First VLAN group = %v, expected campus-north %v", created[0]["group"], north["id"])
+	}
+
+	// Neither site nor group
+	err := nr.ReconcileVLANs([]*models.VLAN{{Name: "Orphan", VID: 400, Status: "active"}})
+	if err == nil {
+		t.Error("ReconcileVLANs() expected error for VLAN without site or group")
+	}
+}
+
+func TestReconcileVLANGroupScopes(t *testing.T) {
+	t.Run("region", func(t *testing.T) {
+		c, srv := newTestClient(t)
+		emea := srv.Add("dcim", "regions", map[string]interface{}{"name": "EMEA", "slug": "emea"})
+		nr := NewNetworkReconciler(c)
+
+		groups := []*models.VLANGroup{{Name: "EMEA VLANs", Slug: "emea-vlans", ScopeType: "region", ScopeSlug: "emea"}}
+		if err := nr.ReconcileVLANGroups(groups); err != nil {
+			t.Fatalf("ReconcileVLANGroups() error = %v", err)
+		}
+
+		group := srv.Find("ipam", "vlan-groups", "slug", "emea-vlans")
+		if group == nil {
+			t.Fatal("VLAN group emea-vlans was not created")
+		}
+		if group["scope_type"] != "dcim.region" || netboxtest.ID(group["scope_id"]) != netboxtest.ID(emea) {
+			t.Errorf("VLAN group scope = %v/%v, expected dcim.region/%v", group["scope_type"], group["scope_id"], emea["id"])
+		}
+		// Referenced by group-scoped VLANs like a global group
+		if id, ok := c.Cache().GetGlobalID("vlan_groups", "emea-vlans"); !ok || id != netboxtest.ID(group) {
+			t.Errorf("Cache vlan_groups/emea-vlans = %d, %v, expected %v", id, ok, group["id"])
+		}
+	})
+
+	t.Run("rack", func(t *testing.T) {
+		c, srv := newTestClient(t)
+		berlin := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+		munich := srv.Add("dcim", "sites", map[string]interface{}{"name": "Munich DC", "slug": "munich-dc"})
+		if err := c.Cache().LoadGlobal(); err != nil {
+			t.Fatalf("LoadGlobal() error = %v", err)
+		}
+		srv.Add("dcim", "racks", map[string]interface{}{"name": "R01", "site": munich["id"]})
+		rack := srv.Add("dcim", "racks", map[string]interface{}{"name": "R01", "site": berlin["id"]})
+		nr := NewNetworkReconciler(c)
+
+		// Rack names are only unique within a site
+		ambiguous := []*models.VLANGroup{{Name: "Rack VLANs", Slug: "r01-vlans", ScopeType: "rack", ScopeSlug: "R01"}}
+		if err := nr.ReconcileVLANGroups(ambiguous); err == nil {
+			t.Error("ReconcileVLANGroups() expected error for a rack name at two sites")
+		}
+
+		groups := []*models.VLANGroup{{Name: "Rack VLANs", Slug: "r01-vlans", SiteSlug: "berlin-dc", ScopeType: "rack", ScopeSlug: "R01"}}
+		if err := nr.ReconcileVLANGroups(groups); err != nil {
+			t.Fatalf("ReconcileVLANGroups() error = %v", err)
+		}
+
+		group := srv.Find("ipam", "vlan-groups", "slug", "r01-vlans")
+		if group == nil {
+			t.Fatal("VLAN group r01-vlans was not created")
+		}
+		if group["scope_type"] != "dcim.rack" || netboxtest.ID(group["scope_id"]) != netboxtest.ID(rack) {
+			t.Errorf("VLAN group scope = %v/%v, expected dcim.rack/%v", group["scope_type"], group["scope_id"], rack["id"])
+		}
+	})
+
+	t.Run("invalid scope type", func(t *testing.T) {
+		c, _ := newTestClient(t)
+		groups := []*models.VLANGroup{{Name: "Pod VLANs", Slug: "pod-vlans", ScopeType: "pod", ScopeSlug: "pod-1"}}
+		err := NewNetworkReconciler(c).ReconcileVLANGroups(groups)
+		if err == nil || !strings.Contains(err.Error(), `invalid scope_type "pod"`) {
+			t.Errorf("ReconcileVLANGroups() error = %v, expected invalid scope_type", err)
+		}
+	})
+}
+
+func TestReconcilePrefixesAllocatesFromParent(t *testing.T) {
+	c, srv := newTestClient(t)
+	parent := srv.Add("ipam", "prefixes", map[string]interface{}{"prefix": "10.0.0.0/24", "status": "container"})
+
+	allocations := 0
+	srv.Intercept("POST", fmt.Sprintf("/api/ipam/prefixes/%d/available-prefixes/", netboxtest.ID(parent)),
+		func(w http.ResponseWriter, r *http.Request) {
+			allocations++
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			child := srv.Add("ipam", "prefixes", map[string]interface{}{
+				"prefix":      "10.0.0.0/26",
+				"description": body["description"],
+				"tags":        body["tags"],
+			})
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(child)
+		})
+
+	newPrefixes := func() []*models.Prefix {
+		return []*models.Prefix{{ParentPrefix: "10.0.0.0/24", NewPrefixLength: 26, Status: "active", Description: "servers"}}
+	}
+
+	nr := NewNetworkReconciler(c)
+	if err := nr.ReconcilePrefixes(newPrefixes()); err != nil {
+		t.Fatalf("ReconcilePrefixes() error = %v", err)
+	}
+	child := srv.Find("ipam", "prefixes", "prefix", "10.0.0.0/26")
+	if child == nil {
+		t.Fatal("Child prefix was not allocated")
+	}
+	if child["status"] != "active" {
+		t.Errorf("Allocated prefix status = %v, expected active", child["status"])
+	}
+
+	// Second run re-resolves the allocation instead of allocating again
+	if err := nr.ReconcilePrefixes(newPrefixes()); err != nil {
+		t.Fatalf("ReconcilePrefixes() second run error = %v", err)
+	}
+	if allocations != 1 {
+		t.Errorf("Allocations = %d, expected 1", allocations)
+	}
+}
+
+func TestReconcilePrefixesRejectsAmbiguousAllocations(t *testing.T) {
+	c, _ := newTestClient(t)
+	prefixes := []*models.Prefix{
+		{ParentPrefix: "10.0.0.0/24", NewPrefixLength: 26},
+		{ParentPrefix: "10.0.0.0/24", NewPrefixLength: 26},
+	}
+	if err := NewNetworkReconciler(c).ReconcilePrefixes(prefixes); err == nil {
+		t.Error("Expected error for indistinguishable allocations")
+	}
+	if err := NewNetworkReconciler(c).ReconcilePrefixes([]*models.Prefix{{ParentPrefix: "10.0.0.0/24"}}); err == nil {
+		t.Error("Expected error for allocation without new_prefix_length")
+	}
+}
+
+func TestReconcilePrefixesLocationScope(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	location := srv.Add("dcim", "locations", map[string]interface{}{"name": "Hall 1", "slug": "hall-1", "site": netboxtest.ID(site)})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	prefixes := []*models.Prefix{{Prefix: "10.1.0.0/24", Status: "active", SiteSlug: "berlin-dc", LocationSlug: "hall-1"}}
+	if err := NewNetworkReconciler(c).ReconcilePrefixes(prefixes); err != nil {
+		t.Fatalf("ReconcilePrefixes() error = %v", err)
+	}
+
+	prefix := srv.Find("ipam", "prefixes", "prefix", "10.1.0.0/24")
+	if prefix == nil {
+		t.Fatal("Prefix was not created")
+	}
+	if prefix["scope_type"] != "dcim.location" || netboxtest.ID(prefix["scope_id"]) != netboxtest.ID(location) {
+		t.Errorf("Prefix scope = %v/%v, expected dcim.location/%v", prefix["scope_type"], prefix["scope_id"], location["id"])
+	}
+}
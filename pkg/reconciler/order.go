@@ -0,0 +1,98 @@
+package reconciler
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OrderFile is the reconcile order file auto-discovered at the root of the data directory
+const OrderFile = "order.yaml"
+
+// LoadOrder reads a YAML list of the resource types to reconcile, in the order to reconcile them.
+// Resource types the list leaves out are not reconciled, e.g. [tags, sites, prefixes, vlans].
+func LoadOrder(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reconcile order file: %w", err)
+	}
+
+	var order []string
+	if err := yaml.Unmarshal(content, &order); err != nil {
+		return nil, fmt.Errorf("failed to parse reconcile order file %s: %w", path, err)
+	}
+	if len(order) == 0 {
+		return nil, fmt.Errorf("reconcile order file %s lists no resource types", path)
+	}
+	return order, nil
+}
+
+// CheckOrder validates an explicit step order. Unknown and duplicate steps are errors.
+// Steps ordered before one of their dependencies, or depending on a step the order leaves
+// out, are returned as warnings: the order is still run as given.
+func (g *Graph) CheckOrder(order []string) ([]string, error) {
+	position := make(map[string]int, len(order))
+	for i, name := range order {
+		if _, ok := g.deps[name]; !ok {
+			return nil, fmt.Errorf("unknown reconcile step %q", name)
+		}
+		if _, ok := position[name]; ok {
+			return nil, fmt.Errorf("reconcile step %s is listed twice", name)
+		}
+		position[name] = i
+	}
+
+	var warnings []string
+	for _, name := range order {
+		for _, dep := range g.deps[name] {
+			depPosition, listed := position[dep]
+			switch {
+			case !listed:
+				warnings = append(warnings, fmt.Sprintf("%s depends on %s, which is disabled", name, dep))
+			case depPosition > position[name]:
+				warnings = append(warnings, fmt.Sprintf("%s runs before its dependency %s", name, dep))
+			}
+		}
+	}
+	return warnings, nil
+}
+
+// Omitted returns the steps an explicit order leaves out, in graph order
+func (g *Graph) Omitted(order []string) []string {
+	listed := make(map[string]bool, len(order))
+	for _, name := range order {
+		listed[name] = true
+	}
+
+	var omitted []string
+	for _, name := range g.names {
+		if !listed[name] {
+			omitted = append(omitted, name)
+		}
+	}
+	return omitted
+}
+
+// ExecuteOrder runs the given steps one at a time in the given order, skipping the
+// steps it leaves out. No further steps are started after the first failure.
+func (g *Graph) ExecuteOrder(order []string) error {
+	for _, name := range order {
+		run, ok := g.run[name]
+		if !ok {
+			return fmt.Errorf("unknown reconcile step %q", name)
+		}
+
+		if g.onStart != nil {
+			g.onStart(name)
+		}
+		err := run()
+		if g.onFinish != nil {
+			g.onFinish(name)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package reconciler
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestExecuteReorderedConfig tests that a reconcile order file drives the step sequence
+func TestExecuteReorderedConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), OrderFile)
+	content := `
+- tags
+- sites
+- ipam_roles
+- prefixes
+- vlans
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := LoadOrder(path)
+	if err != nil {
+		t.Fatalf("LoadOrder() error = %v", err)
+	}
+
+	var ran []string
+	runners := make(map[string]func() error)
+	for _, dep := range ResourceDependencies {
+		name := dep.Name
+		runners[name] = func() error {
+			ran = append(ran, name)
+			return nil
+		}
+	}
+	graph, err := NewResourceGraph(runners)
+	if err != nil {
+		t.Fatalf("NewResourceGraph() error = %v", err)
+	}
+
+	warnings, err := graph.CheckOrder(order)
+	if err != nil {
+		t.Fatalf("CheckOrder() error = %v", err)
+	}
+	joined := strings.Join(warnings, "\n")
+	if !strings.Contains(joined, "prefixes runs before its dependency vlans") {
+		t.Errorf("CheckOrder() warnings = %v, expected prefixes before vlans to be reported", warnings)
+	}
+	if !strings.Contains(joined, "sites depends on contacts, which is disabled") {
+		t.Errorf("CheckOrder() warnings = %v, expected the disabled contacts dependency to be reported", warnings)
+	}
+
+	if err := graph.ExecuteOrder(order); err != nil {
+		t.Fatalf("ExecuteOrder() error = %v", err)
+	}
+	expected := []string{"tags", "sites", "ipam_roles", "prefixes", "vlans"}
+	if !reflect.DeepEqual(ran, expected) {
+		t.Errorf("ExecuteOrder() ran %v, expected %v", ran, expected)
+	}
+
+	omitted := graph.Omitted(order)
+	if len(omitted) != len(ResourceDependencies)-len(expected) {
+		t.Errorf("Omitted() = %v, expected every step but %v", omitted, expected)
+	}
+}
+
+// TestCheckOrderRejectsInvalidSteps tests that unknown and duplicate steps are errors
+func TestCheckOrderRejectsInvalidSteps(t *testing.T) {
+	noop := func() error { return nil }
+	g := NewGraph()
+	g.Add("sites", noop)
+	g.Add("racks", noop, "sites")
+
+	if _, err := g.CheckOrder([]string{"sites", "switches"}); err == nil {
+		t.Error("CheckOrder() expected error for unknown step")
+	}
+	if _, err := g.CheckOrder([]string{"sites", "racks", "sites"}); err == nil {
+		t.Error("CheckOrder() expected error for duplicate step")
+	}
+	if warnings, err := g.CheckOrder([]string{"sites", "racks"}); err != nil || len(warnings) != 0 {
+		t.Errorf("CheckOrder() = %v, %v, expected a valid order without warnings", warnings, err)
+	}
+}
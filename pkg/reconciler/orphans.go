@@ -0,0 +1,51 @@
+package reconciler
+
+import (
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// FindOrphans returns the managed objects of all prunable resource types that are no
+// longer declared in YAML, i.e. what --prune would delete with every scope. Nothing is deleted.
+// Resource types declared by skipped reconcile steps are left out, as their definitions were not loaded.
+func FindOrphans(c *client.NetBoxClient, desired *DesiredState, skippedSteps []string) ([]PruneCandidate, error) {
+	skipped := make(map[string]bool)
+	for _, resource := range SkippedPruneTypes(skippedSteps) {
+		skipped[resource] = true
+	}
+	var scope []string
+	for _, resource := range PruneResourceTypes() {
+		if !skipped[resource] {
+			scope = append(scope, resource)
+		}
+	}
+
+	pruner, err := NewPruner(c, scope)
+	if err != nil {
+		return nil, err
+	}
+	return pruner.Candidates(desired)
+}
+
+// PrintOrphanReport prints orphans grouped by resource type, even in summary-only mode
+func PrintOrphanReport(logger *utils.Logger, orphans []PruneCandidate) {
+	if len(orphans) == 0 {
+		logger.Summary("Orphans: none, every managed object is declared in YAML")
+		return
+	}
+
+	counts := make(map[string]int)
+	for _, orphan := range orphans {
+		counts[orphan.Resource]++
+	}
+
+	logger.Summary("Orphans: %d managed objects are not declared in YAML", len(orphans))
+	resource := ""
+	for _, orphan := range orphans {
+		if orphan.Resource != resource {
+			resource = orphan.Resource
+			logger.Summary("  %s (%d):", resource, counts[resource])
+		}
+		logger.Summary("    %s (ID: %d)", orphan.Key, orphan.ID)
+	}
+}
@@ -0,0 +1,327 @@
+package reconciler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// pruneTarget describes a resource type that can be pruned
+type pruneTarget struct {
+	resource string
+	// steps are the reconcile steps whose definitions declare objects of the resource type
+	steps    []string
+	app      string
+	endpoint string
+	// key builds the identity of a NetBox object, matching the keys added to DesiredState
+	key func(obj client.Object) string
+}
+
+// pruneTargets lists prunable resource types in deletion order (dependents first)
+var pruneTargets = []pruneTarget{
+	{"ip_addresses", []string{"devices"}, "ipam", "ip-addresses", func(o client.Object) string { return stringField(o, "address") }},
+	{"interfaces", []string{"devices"}, "dcim", "interfaces", componentKey},
+	{"front_ports", []string{"devices"}, "dcim", "front-ports", componentKey},
+	{"rear_ports", []string{"devices"}, "dcim", "rear-ports", componentKey},
+	{"devices", []string{"devices"}, "dcim", "devices", func(o client.Object) string { return stringField(o, "name") }},
+	{"device_types", []string{"device_types"}, "dcim", "device-types", slugKey},
+	{"module_types", []string{"module_types"}, "dcim", "module-types", slugKey},
+	{"prefixes", []string{"prefixes"}, "ipam", "prefixes", func(o client.Object) string {
+		return PrefixKey(stringField(o, "prefix"), nestedString(o, "vrf", "name"))
+	}},
+	{"vlans", []string{"vlans"}, "ipam", "vlans", func(o client.Object) string {
+		return VLANKey(nestedString(o, "site", "slug"), nestedString(o, "group", "slug"), utils.GetIDFromObject(o["vid"]))
+	}},
+	{"vlan_groups", []string{"vlan_groups"}, "ipam", "vlan-groups", slugKey},
+	{"vrfs", []string{"vrfs"}, "ipam", "vrfs", func(o client.Object) string { return stringField(o, "name") }},
+	{"ipam_roles", []string{"ipam_roles"}, "ipam", "roles", slugKey},
+	{"racks", []string{"racks"}, "dcim", "racks", func(o client.Object) string {
+		return nestedString(o, "site", "slug") + "/" + stringField(o, "name")
+	}},
+	{"sites", []string{"sites"}, "dcim", "sites", slugKey},
+	{"roles", []string{"role_groups", "roles"}, "dcim", "device-roles", slugKey},
+	{"contacts", []string{"contacts"}, "tenancy", "contacts", func(o client.Object) string { return stringField(o, "name") }},
+}
+
+// PruneResourceTypes returns the resource type names accepted by --prune-scope
+func PruneResourceTypes() []string {
+	names := make([]string, 0, len(pruneTargets))
+	for _, t := range pruneTargets {
+		names = append(names, t.resource)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SkippedPruneTypes returns the prunable resource types declared by one of the given
+// reconcile steps. When such a step is skipped, its desired objects are never loaded,
+// so every managed object of these types would look undeclared.
+func SkippedPruneTypes(skippedSteps []string) []string {
+	skipped := make(map[string]bool, len(skippedSteps))
+	for _, step := range skippedSteps {
+		skipped[step] = true
+	}
+
+	var types []string
+	for _, t := range pruneTargets {
+		for _, step := range t.steps {
+			if skipped[step] {
+				types = append(types, t.resource)
+				break
+			}
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// DesiredState records the identities of all objects declared in YAML, per resource type
+type DesiredState struct {
+	keys map[string]map[string]bool
+}
+
+// NewDesiredState creates an empty desired state
+func NewDesiredState() *DesiredState {
+	return &DesiredState{keys: make(map[string]map[string]bool)}
+}
+
+// Add records a desired object identity for a resource type
+func (ds *DesiredState) Add(resource, key string) {
+	if ds.keys[resource] == nil {
+		ds.keys[resource] = make(map[string]bool)
+	}
+	ds.keys[resource][key] = true
+}
+
+// Has reports whether an object identity is declared for a resource type
+func (ds *DesiredState) Has(resource, key string) bool {
+	return ds.keys[resource][key]
+}
+
+// ComponentKey builds the identity of a device component (interface, port)
+func ComponentKey(deviceName, name string) string {
+	return deviceName + "/" + name
+}
+
+// VLANKey builds the identity of a VLAN, scoped to its site or, without a site, to its group
+func VLANKey(siteSlug, groupSlug string, vid int) string {
+	if siteSlug == "" {
+		return fmt.Sprintf("group:%s/%d", groupSlug, vid)
+	}
+	return fmt.Sprintf("%s/%d", siteSlug, vid)
+}
+
+// PrefixKey builds the identity of a prefix within an optional VRF
+func PrefixKey(prefix, vrfName string) string {
+	if vrfName == "" {
+		return prefix
+	}
+	return prefix + "@" + vrfName
+}
+
+// Pruner deletes managed objects that are no longer declared in YAML.
+// Only resource types explicitly listed in the scope are ever considered.
+type Pruner struct {
+	client     *client.NetBoxClient
+	logger     *utils.Logger
+	scope      map[string]bool
+	confirm    PruneConfirmFunc
+	maxDeletes int
+}
+
+// NewPruner creates a pruner limited to the given resource types
+func NewPruner(c *client.NetBoxClient, scope []string) (*Pruner, error) {
+	valid := make(map[string]bool)
+	for _, t := range pruneTargets {
+		valid[t.resource] = true
+	}
+
+	scopeSet := make(map[string]bool)
+	for _, resource := range scope {
+		if !valid[resource] {
+			return nil, fmt.Errorf("unknown prune scope %q (valid: %s)", resource, strings.Join(PruneResourceTypes(), ", "))
+		}
+		scopeSet[resource] = true
+	}
+
+	return &Pruner{
+		client:     c,
+		logger:     c.Logger(),
+		scope:      scopeSet,
+		maxDeletes: constants.DefaultMaxDeletes,
+	}, nil
+}
+
+// CheckSkipped fails if the scope includes a resource type declared by a reconcile step
+// that is skipped, e.g. left out of order.yaml: all of its objects would be deleted
+func (p *Pruner) CheckSkipped(skippedSteps []string) error {
+	var blocked []string
+	for _, resource := range SkippedPruneTypes(skippedSteps) {
+		if p.scope[resource] {
+			blocked = append(blocked, resource)
+		}
+	}
+	if len(blocked) > 0 {
+		return fmt.Errorf("cannot prune %s: declared by reconcile steps that are disabled (%s)",
+			strings.Join(blocked, ", "), strings.Join(skippedSteps, ", "))
+	}
+	return nil
+}
+
+// SetMaxDeletes sets the largest number of candidates a prune may delete; above it, nothing is deleted
+func (p *Pruner) SetMaxDeletes(n int) {
+	p.maxDeletes = n
+}
+
+// SetConfirm sets a confirmation required before candidates are deleted (nil deletes without asking)
+func (p *Pruner) SetConfirm(confirm PruneConfirmFunc) {
+	p.confirm = confirm
+}
+
+// PruneCandidate is a managed object that is absent from the desired state
+type PruneCandidate struct {
+	Resource string
+	App      string
+	Endpoint string
+	ID       int
+	Key      string
+}
+
+// Candidates returns the managed objects of all scoped resource types that are not desired
+func (p *Pruner) Candidates(desired *DesiredState) ([]PruneCandidate, error) {
+	var candidates []PruneCandidate
+
+	for _, target := range pruneTargets {
+		if !p.scope[target.resource] {
+			continue
+		}
+
+		objects, err := p.client.FilterManaged(target.app, target.endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list managed %s: %w", target.resource, err)
+		}
+
+		for _, obj := range objects {
+			// Double-check the tag, never trust the filter alone before deleting
+			if !p.client.Tags().IsManaged(obj, p.client.ManagedTagID()) {
+				continue
+			}
+
+			key := target.key(obj)
+			if desired.Has(target.resource, key) {
+				continue
+			}
+
+			candidates = append(candidates, PruneCandidate{
+				Resource: target.resource,
+				App:      target.app,
+				Endpoint: target.endpoint,
+				ID:       utils.GetIDFromObject(obj),
+				Key:      key,
+			})
+		}
+	}
+
+	return candidates, nil
+}
+
+// Prune deletes all candidates of the scoped resource types
+func (p *Pruner) Prune(desired *DesiredState) error {
+	if len(p.scope) == 0 {
+		p.logger.Warning("--prune is set but no --prune-scope was given, nothing will be deleted")
+		return nil
+	}
+
+	candidates, err := p.Candidates(desired)
+	if err != nil {
+		return err
+	}
+
+	// A bad refactor of the YAML can make whole racks look undeclared, refuse mass deletions
+	if len(candidates) > p.maxDeletes {
+		p.logger.Error("Prune would delete %d objects, more than --max-deletes %d:", nil, len(candidates), p.maxDeletes)
+		for _, candidate := range candidates {
+			p.logger.Warning("  %s: %s (ID: %d)", candidate.Resource, candidate.Key, candidate.ID)
+		}
+		return fmt.Errorf("prune of %d objects exceeds --max-deletes %d, nothing deleted; check the YAML and raise --max-deletes deliberately if the deletions are intended",
+			len(candidates), p.maxDeletes)
+	}
+
+	// Ask before deleting anything for real
+	if len(candidates) > 0 && p.confirm != nil && !p.client.IsDryRun() {
+		ok, err := p.confirm(candidates)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("prune of %d objects was not confirmed, nothing deleted", len(candidates))
+		}
+	}
+
+	p.logger.Info("Pruning %d managed objects not present in YAML...", len(candidates))
+
+	for _, candidate := range candidates {
+		blocker, err := p.blockedBy(candidate)
+		if err != nil {
+			return err
+		}
+		if blocker != "" {
+			p.logger.Warning("  ⊘ Skipping %s: %s (ID: %d), %s", candidate.Resource, candidate.Key, candidate.ID, blocker)
+			continue
+		}
+
+		p.logger.Warning("  ✗ Deleting %s: %s (ID: %d)", candidate.Resource, candidate.Key, candidate.ID)
+		if err := p.client.Delete(candidate.App, candidate.Endpoint, candidate.ID); err != nil {
+			return fmt.Errorf("failed to delete %s %s: %w", candidate.Resource, candidate.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// blockedBy returns why a candidate cannot be deleted yet, or "" if nothing references it.
+// NetBox rejects such deletes with a bare 409, so they are skipped with a clear reason instead.
+func (p *Pruner) blockedBy(candidate PruneCandidate) (string, error) {
+	if candidate.Resource != "device_types" {
+		return "", nil
+	}
+
+	devices, err := p.client.Count("dcim", "devices", map[string]interface{}{"device_type_id": candidate.ID})
+	if err != nil {
+		return "", fmt.Errorf("failed to count devices of device type %s: %w", candidate.Key, err)
+	}
+	if devices == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("device type %s still has %d devices", candidate.Key, devices), nil
+}
+
+// slugKey returns the slug of an object
+func slugKey(obj client.Object) string {
+	return stringField(obj, "slug")
+}
+
+// componentKey returns the device/name identity of a device component
+func componentKey(obj client.Object) string {
+	return ComponentKey(nestedString(obj, "device", "name"), stringField(obj, "name"))
+}
+
+// stringField returns a string field of an object, or "" if absent
+func stringField(obj client.Object, field string) string {
+	s, _ := obj[field].(string)
+	return s
+}
+
+// nestedString returns a string field of a nested object reference, or "" if absent
+func nestedString(obj client.Object, field, key string) string {
+	nested, ok := obj[field].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	s, _ := nested[key].(string)
+	return s
+}
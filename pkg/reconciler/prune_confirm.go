@@ -0,0 +1,33 @@
+package reconciler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PruneConfirmFunc decides whether the given candidates may be deleted
+type PruneConfirmFunc func(candidates []PruneCandidate) (bool, error)
+
+// PromptPruneConfirm returns a confirmation that lists the candidates on out and
+// requires "yes" to be typed on in. Without an interactive in, pruning is refused.
+func PromptPruneConfirm(in io.Reader, out io.Writer, interactive bool) PruneConfirmFunc {
+	return func(candidates []PruneCandidate) (bool, error) {
+		if !interactive {
+			return false, fmt.Errorf("refusing to delete %d objects without confirmation: stdin is not a terminal (use --yes)", len(candidates))
+		}
+
+		fmt.Fprintf(out, "The following %d managed objects will be deleted:\n", len(candidates))
+		for _, candidate := range candidates {
+			fmt.Fprintf(out, "  - %s: %s (ID: %d)\n", candidate.Resource, candidate.Key, candidate.ID)
+		}
+		fmt.Fprint(out, "Type 'yes' to delete them: ")
+
+		answer, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return false, fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		return strings.TrimSpace(answer) == "yes", nil
+	}
+}
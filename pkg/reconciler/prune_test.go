@@ -0,0 +1,253 @@
+package reconciler
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/constants"
+)
+
+func TestPruneOnlyTouchesScopedResourceTypes(t *testing.T) {
+	c, srv := newTestClient(t)
+	managed := []interface{}{map[string]interface{}{"id": c.ManagedTagID(), "slug": constants.ManagedTagSlug}}
+
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Old DC", "slug": "old-dc", "tags": managed})
+	keptVLAN := srv.Add("ipam", "vlans", map[string]interface{}{
+		"name": "Mgmt", "vid": 100, "site": map[string]interface{}{"slug": "berlin-dc"}, "tags": managed,
+	})
+	staleVLAN := srv.Add("ipam", "vlans", map[string]interface{}{
+		"name": "Legacy", "vid": 999, "site": map[string]interface{}{"slug": "berlin-dc"}, "tags": managed,
+	})
+	unmanagedVLAN := srv.Add("ipam", "vlans", map[string]interface{}{
+		"name": "Manual", "vid": 500, "site": map[string]interface{}{"slug": "berlin-dc"},
+	})
+	iface := srv.Add("dcim", "interfaces", map[string]interface{}{
+		"name": "eth9", "device": map[string]interface{}{"name": "sw-01"}, "tags": managed,
+	})
+
+	desired := NewDesiredState()
+	desired.Add("vlans", VLANKey("berlin-dc", "", 100))
+
+	pruner, err := NewPruner(c, []string{"vlans"})
+	if err != nil {
+		t.Fatalf("NewPruner() error = %v", err)
+	}
+
+	candidates, err := pruner.Candidates(desired)
+	if err != nil {
+		t.Fatalf("Candidates() error = %v", err)
+	}
+	if len(candidates) != 1 || candidates[0].ID != staleVLAN["id"] {
+		t.Fatalf("Candidates() = %+v, expected only VLAN %v", candidates, staleVLAN["id"])
+	}
+
+	if err := pruner.Prune(desired); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+
+	if srv.Find("ipam", "vlans", "id", staleVLAN["id"]) != nil {
+		t.Error("Stale managed VLAN should have been deleted")
+	}
+	for _, obj := range []struct {
+		app, endpoint string
+		id            interface{}
+	}{
+		{"ipam", "vlans", keptVLAN["id"]},
+		{"ipam", "vlans", unmanagedVLAN["id"]},
+		{"dcim", "sites", site["id"]},
+		{"dcim", "interfaces", iface["id"]},
+	} {
+		if srv.Find(obj.app, obj.endpoint, "id", obj.id) == nil {
+			t.Errorf("%s/%s %v should not have been deleted", obj.app, obj.endpoint, obj.id)
+		}
+	}
+	if got := srv.CountRequests("GET", "/api/dcim/"); got != 0 {
+		t.Errorf("Out-of-scope dcim resources were listed %d times", got)
+	}
+}
+
+func TestPruneWithoutScopeIsNoOp(t *testing.T) {
+	c, srv := newTestClient(t)
+	managed := []interface{}{map[string]interface{}{"id": c.ManagedTagID(), "slug": constants.ManagedTagSlug}}
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Old DC", "slug": "old-dc", "tags": managed})
+
+	pruner, err := NewPruner(c, nil)
+	if err != nil {
+		t.Fatalf("NewPruner() error = %v", err)
+	}
+	if err := pruner.Prune(NewDesiredState()); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+
+	if got := srv.CountRequests("DELETE", "/api/"); got != 0 {
+		t.Errorf("Prune without scope issued %d DELETE requests", got)
+	}
+}
+
+func TestNewPrunerRejectsUnknownScope(t *testing.T) {
+	c, _ := newTestClient(t)
+	if _, err := NewPruner(c, []string{"vlans", "everything"}); err == nil {
+		t.Error("NewPruner() expected error for unknown scope")
+	}
+}
+
+func TestPruneConfirmation(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		interactive bool
+		wantDeleted bool
+	}{
+		{"yes", "yes\n", true, true},
+		{"no", "no\n", true, false},
+		{"empty answer", "", true, false},
+		{"not a terminal", "yes\n", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, srv := newTestClient(t)
+			managed := []interface{}{map[string]interface{}{"id": c.ManagedTagID(), "slug": constants.ManagedTagSlug}}
+			srv.Add("dcim", "sites", map[string]interface{}{"name": "Old DC", "slug": "old-dc", "tags": managed})
+
+			pruner, err := NewPruner(c, []string{"sites"})
+			if err != nil {
+				t.Fatalf("NewPruner() error = %v", err)
+			}
+			var out bytes.Buffer
+			pruner.SetConfirm(PromptPruneConfirm(strings.NewReader(tt.input), &out, tt.interactive))
+
+			err = pruner.Prune(NewDesiredState())
+			if tt.wantDeleted && err != nil {
+				t.Fatalf("Prune() error = %v", err)
+			}
+			if !tt.wantDeleted && err == nil {
+				t.Error("Prune() expected error when deletion is not confirmed")
+			}
+
+			deleted := srv.Find("dcim", "sites", "slug", "old-dc") == nil
+			if deleted != tt.wantDeleted {
+				t.Errorf("Site deleted = %v, expected %v", deleted, tt.wantDeleted)
+			}
+			if tt.interactive && !strings.Contains(out.String(), "sites: old-dc") {
+				t.Errorf("Prompt should list the candidates, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestPruneMaxDeletes(t *testing.T) {
+	c, srv := newTestClient(t)
+	managed := []interface{}{map[string]interface{}{"id": c.ManagedTagID(), "slug": constants.ManagedTagSlug}}
+	for _, slug := range []string{"old-dc-1", "old-dc-2", "old-dc-3"} {
+		srv.Add("dcim", "sites", map[string]interface{}{"name": slug, "slug": slug, "tags": managed})
+	}
+
+	pruner, err := NewPruner(c, []string{"sites"})
+	if err != nil {
+		t.Fatalf("NewPruner() error = %v", err)
+	}
+	var out bytes.Buffer
+	c.Logger().SetOutput(&out, &out)
+	pruner.SetMaxDeletes(2)
+
+	err = pruner.Prune(NewDesiredState())
+	if err == nil || !strings.Contains(err.Error(), "--max-deletes") {
+		t.Fatalf("Prune() error = %v, expected --max-deletes error", err)
+	}
+	if got := srv.CountRequests("DELETE", "/api/"); got != 0 {
+		t.Errorf("Prune() sent %d DELETE requests above --max-deletes, expected 0", got)
+	}
+	for _, slug := range []string{"old-dc-1", "old-dc-2", "old-dc-3"} {
+		if !strings.Contains(out.String(), "sites: "+slug) {
+			t.Errorf("Output should list candidate %s, got:\n%s", slug, out.String())
+		}
+	}
+
+	pruner.SetMaxDeletes(3)
+	if err := pruner.Prune(NewDesiredState()); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+	if got := len(srv.Objects("dcim", "sites")); got != 0 {
+		t.Errorf("Expected all 3 sites deleted at --max-deletes 3, %d left", got)
+	}
+}
+
+func TestPruneSkipsDeviceTypeInUse(t *testing.T) {
+	c, srv := newTestClient(t)
+	managed := []interface{}{map[string]interface{}{"id": c.ManagedTagID(), "slug": constants.ManagedTagSlug}}
+	inUse := srv.Add("dcim", "device-types", map[string]interface{}{"model": "R740", "slug": "r740", "tags": managed})
+	unused := srv.Add("dcim", "device-types", map[string]interface{}{"model": "R640", "slug": "r640", "tags": managed})
+	for _, name := range []string{"srv-01", "srv-02"} {
+		srv.Add("dcim", "devices", map[string]interface{}{"name": name, "device_type": map[string]interface{}{"id": inUse["id"]}})
+	}
+
+	pruner, err := NewPruner(c, []string{"device_types"})
+	if err != nil {
+		t.Fatalf("NewPruner() error = %v", err)
+	}
+	var out bytes.Buffer
+	c.Logger().SetOutput(&out, &out)
+
+	if err := pruner.Prune(NewDesiredState()); err != nil {
+		t.Fatalf("Prune() error = %v", err)
+	}
+
+	if srv.Find("dcim", "device-types", "id", unused["id"]) != nil {
+		t.Error("Unused device type r640 should have been deleted")
+	}
+	if srv.Find("dcim", "device-types", "id", inUse["id"]) == nil {
+		t.Error("Device type r740 with devices should not have been deleted")
+	}
+	if got := srv.CountRequests("DELETE", "/api/dcim/device-types/"); got != 1 {
+		t.Errorf("Prune() sent %d device type DELETE requests, expected 1", got)
+	}
+	if !strings.Contains(out.String(), "device type r740 still has 2 devices") {
+		t.Errorf("Output should explain the skipped device type, got:\n%s", out.String())
+	}
+	for _, r := range srv.Requests() {
+		if r.Method == "GET" && r.Path == "/api/dcim/devices/" && r.Query.Get("limit") != "1" {
+			t.Errorf("Devices of a device type should be counted with limit=1, got %s", r.Query.Encode())
+		}
+	}
+}
+
+func TestFindOrphans(t *testing.T) {
+	c, srv := newTestClient(t)
+	managed := []interface{}{map[string]interface{}{"id": c.ManagedTagID(), "slug": constants.ManagedTagSlug}}
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc", "tags": managed})
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Old DC", "slug": "old-dc", "tags": managed})
+	srv.Add("dcim", "sites", map[string]interface{}{"name": "Manual DC", "slug": "manual-dc"})
+	srv.Add("ipam", "vrfs", map[string]interface{}{"name": "legacy", "tags": managed})
+
+	desired := NewDesiredState()
+	desired.Add("sites", "berlin-dc")
+
+	orphans, err := FindOrphans(c, desired, nil)
+	if err != nil {
+		t.Fatalf("FindOrphans() error = %v", err)
+	}
+
+	found := make(map[string]bool)
+	for _, orphan := range orphans {
+		found[orphan.Resource+":"+orphan.Key] = true
+	}
+	if len(orphans) != 2 || !found["sites:old-dc"] || !found["vrfs:legacy"] {
+		t.Errorf("FindOrphans() = %+v, expected sites:old-dc and vrfs:legacy", orphans)
+	}
+
+	var out bytes.Buffer
+	c.Logger().SetOutput(&out, &out)
+	PrintOrphanReport(c.Logger(), orphans)
+	if !strings.Contains(out.String(), "old-dc") || !strings.Contains(out.String(), "legacy") {
+		t.Errorf("Orphan report is missing objects:\n%s", out.String())
+	}
+
+	if got := srv.CountRequests("DELETE", "/api/"); got != 0 {
+		t.Errorf("FindOrphans() sent %d DELETE requests, expected none", got)
+	}
+	if srv.Find("dcim", "sites", "slug", "old-dc") == nil {
+		t.Error("Orphan site was deleted")
+	}
+}
@@ -0,0 +1,288 @@
+package reconciler
+
+import (
+	"fmt"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// TenancyReconciler handles tenancy resources (contacts, contact groups, contact roles)
+type TenancyReconciler struct {
+	client *client.NetBoxClient
+	logger *utils.Logger
+}
+
+// NewTenancyReconciler creates a new tenancy reconciler
+func NewTenancyReconciler(c *client.NetBoxClient) *TenancyReconciler {
+	return &TenancyReconciler{
+		client: c,
+		logger: c.Logger(),
+	}
+}
+
+// ReconcileContactGroups reconciles contact group definitions
+// Groups are processed in order, so parents must be listed before their children
+func (tr *TenancyReconciler) ReconcileContactGroups(groups []*models.ContactGroup) error {
+	tr.logger.Info("Reconciling %d contact groups...", len(groups))
+
+	for _, group := range groups {
+		payload := map[string]interface{}{
+			"name": group.Name,
+			"slug": group.Slug,
+		}
+
+		if group.Parent != "" {
+			parentID, ok := tr.client.Cache().GetGlobalID("contact_groups", group.Parent)
+			switch {
+			case !ok && tr.client.IsDryRun():
+				tr.logger.Warning("Parent group %s of group %s not found (created in dry-run mode), planning the group without it", group.Parent, group.Name)
+			case !ok:
+				return fmt.Errorf("parent contact group %s not found for %s", group.Parent, group.Name)
+			default:
+				payload["parent"] = parentID
+			}
+		}
+		if group.Description != "" {
+			payload["description"] = group.Description
+		}
+
+		lookup := map[string]interface{}{"slug": group.Slug}
+		groupObj, err := tr.client.Apply("tenancy", "contact-groups", lookup, payload)
+		if err != nil {
+			if tr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile contact group %s: %w", group.Name, err)
+		}
+
+		if groupID := utils.GetIDFromObject(groupObj); groupID != 0 {
+			tr.client.Cache().Set("contact_groups", group.Slug, groupID)
+			tr.client.Cache().Set("contact_groups", group.Name, groupID)
+		}
+	}
+
+	return nil
+}
+
+// ReconcileTenantGroups reconciles tenant group definitions
+// Groups are processed in order, so parents must be listed before their children
+func (tr *TenancyReconciler) ReconcileTenantGroups(groups []*models.TenantGroup) error {
+	tr.logger.Info("Reconciling %d tenant groups...", len(groups))
+
+	for _, group := range groups {
+		payload := map[string]interface{}{
+			"name": group.Name,
+			"slug": group.Slug,
+		}
+
+		if group.Parent != "" {
+			parentID, ok := tr.client.Cache().GetGlobalID("tenant_groups", group.Parent)
+			switch {
+			case !ok && tr.client.IsDryRun():
+				tr.logger.Warning("Parent group %s of group %s not found (created in dry-run mode), planning the group without it", group.Parent, group.Name)
+			case !ok:
+				return fmt.Errorf("parent tenant group %s not found for %s", group.Parent, group.Name)
+			default:
+				payload["parent"] = parentID
+			}
+		}
+		if group.Description != "" {
+			payload["description"] = group.Description
+		}
+
+		lookup := map[string]interface{}{"slug": group.Slug}
+		groupObj, err := tr.client.Apply("tenancy", "tenant-groups", lookup, payload)
+		if err != nil {
+			if tr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile tenant group %s: %w", group.Name, err)
+		}
+
+		if groupID := utils.GetIDFromObject(groupObj); groupID != 0 {
+			tr.client.Cache().Set("tenant_groups", group.Slug, groupID)
+			tr.client.Cache().Set("tenant_groups", group.Name, groupID)
+		}
+	}
+
+	return nil
+}
+
+// ReconcileTenants reconciles tenant definitions
+func (tr *TenancyReconciler) ReconcileTenants(tenants []*models.Tenant) error {
+	tr.logger.Info("Reconciling %d tenants...", len(tenants))
+
+	for _, tenant := range tenants {
+		payload := map[string]interface{}{
+			"name": tenant.Name,
+			"slug": tenant.Slug,
+		}
+
+		if tenant.Group != "" {
+			groupID, ok := tr.client.Cache().GetGlobalID("tenant_groups", tenant.Group)
+			switch {
+			case !ok && tr.client.IsDryRun():
+				tr.logger.Warning("Tenant group %s of tenant %s not found (created in dry-run mode), planning the tenant without it", tenant.Group, tenant.Name)
+			case !ok:
+				return fmt.Errorf("tenant group %s not found for tenant %s", tenant.Group, tenant.Name)
+			default:
+				payload["group"] = groupID
+			}
+		}
+		if tenant.Description != "" {
+			payload["description"] = tenant.Description
+		}
+		if tenant.Comments != "" {
+			payload["comments"] = tenant.Comments
+		}
+
+		lookup := map[string]interface{}{"slug": tenant.Slug}
+		tenantObj, err := tr.client.Apply("tenancy", "tenants", lookup, payload)
+		if err != nil {
+			if tr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile tenant %s: %w", tenant.Name, err)
+		}
+
+		if tenantID := utils.GetIDFromObject(tenantObj); tenantID != 0 {
+			tr.client.Cache().Set("tenants", tenant.Slug, tenantID)
+			tr.client.Cache().Set("tenants", tenant.Name, tenantID)
+		}
+	}
+
+	return nil
+}
+
+// ReconcileContactRoles reconciles contact role definitions
+func (tr *TenancyReconciler) ReconcileContactRoles(roles []*models.ContactRole) error {
+	tr.logger.Info("Reconciling %d contact roles...", len(roles))
+
+	for _, role := range roles {
+		payload := map[string]interface{}{
+			"name": role.Name,
+			"slug": role.Slug,
+		}
+
+		if role.Description != "" {
+			payload["description"] = role.Description
+		}
+
+		lookup := map[string]interface{}{"slug": role.Slug}
+		roleObj, err := tr.client.Apply("tenancy", "contact-roles", lookup, payload)
+		if err != nil {
+			if tr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile contact role %s: %w", role.Name, err)
+		}
+
+		if roleID := utils.GetIDFromObject(roleObj); roleID > 0 {
+			tr.client.Cache().Set("contact_roles", role.Slug, roleID)
+			tr.client.Cache().Set("contact_roles", role.Name, roleID)
+		}
+	}
+
+	return nil
+}
+
+// ReconcileContacts reconciles contact definitions
+func (tr *TenancyReconciler) ReconcileContacts(contacts []*models.Contact) error {
+	tr.logger.Info("Reconciling %d contacts...", len(contacts))
+
+	for _, contact := range contacts {
+		payload := map[string]interface{}{
+			"name": contact.Name,
+		}
+
+		if contact.Group != "" {
+			groupID, ok := tr.client.Cache().GetGlobalID("contact_groups", contact.Group)
+			if ok {
+				payload["group"] = groupID
+			} else {
+				tr.logger.Warning("Contact group %s not found for contact %s", contact.Group, contact.Name)
+			}
+		}
+		if contact.Title != "" {
+			payload["title"] = contact.Title
+		}
+		if contact.Phone != "" {
+			payload["phone"] = contact.Phone
+		}
+		if contact.Email != "" {
+			payload["email"] = contact.Email
+		}
+		if contact.Address != "" {
+			payload["address"] = contact.Address
+		}
+		if contact.Description != "" {
+			payload["description"] = contact.Description
+		}
+		if contact.Comments != "" {
+			payload["comments"] = contact.Comments
+		}
+
+		lookup := map[string]interface{}{"name": contact.Name}
+		contactObj, err := tr.client.Apply("tenancy", "contacts", lookup, payload)
+		if err != nil {
+			if tr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile contact %s: %w", contact.Name, err)
+		}
+
+		if contactID := utils.GetIDFromObject(contactObj); contactID > 0 {
+			tr.client.Cache().Set("contacts", contact.Name, contactID)
+		}
+	}
+
+	return nil
+}
+
+// reconcileContactAssignments assigns contacts to an object (e.g., "dcim.site", "dcim.device")
+// Assignments are looked up by object+contact+role, so repeated runs stay idempotent
+func reconcileContactAssignments(c *client.NetBoxClient, objectType string, objectID int, contacts []models.ContactAssignment) error {
+	if objectID == 0 {
+		return nil // Object was created in dry-run mode
+	}
+
+	for _, assignment := range contacts {
+		contactID, ok := c.Cache().GetGlobalID("contacts", assignment.Name)
+		if !ok {
+			c.Logger().Warning("Contact %s not found, skipping assignment to %s %d", assignment.Name, objectType, objectID)
+			continue
+		}
+
+		roleID, ok := c.Cache().GetGlobalID("contact_roles", assignment.Role)
+		if !ok {
+			c.Logger().Warning("Contact role %s not found, skipping assignment of %s", assignment.Role, assignment.Name)
+			continue
+		}
+
+		payload := map[string]interface{}{
+			"object_type": objectType,
+			"object_id":   objectID,
+			"contact":     contactID,
+			"role":        roleID,
+		}
+
+		if assignment.Priority != "" {
+			payload["priority"] = assignment.Priority
+		}
+
+		lookup := map[string]interface{}{
+			"object_type": objectType,
+			"object_id":   objectID,
+			"contact_id":  contactID,
+			"role_id":     roleID,
+		}
+
+		if _, err := c.Apply("tenancy", "contact-assignments", lookup, payload); err != nil {
+			return fmt.Errorf("failed to assign contact %s: %w", assignment.Name, err)
+		}
+	}
+
+	return nil
+}
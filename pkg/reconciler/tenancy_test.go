@@ -0,0 +1,153 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+)
+
+func TestReconcileSiteContactAssignment(t *testing.T) {
+	c, srv := newTestClient(t)
+	tr := NewTenancyReconciler(c)
+	fr := NewFoundationReconciler(c)
+
+	if err := tr.ReconcileContactRoles([]*models.ContactRole{{Name: "Operations", Slug: "operations"}}); err != nil {
+		t.Fatalf("ReconcileContactRoles() error = %v", err)
+	}
+	if err := tr.ReconcileContacts([]*models.Contact{{Name: "DC Ops", Email: "ops@example.com"}}); err != nil {
+		t.Fatalf("ReconcileContacts() error = %v", err)
+	}
+
+	sites := []*models.Site{{
+		Name:     "Berlin DC",
+		Slug:     "berlin-dc",
+		Status:   "active",
+		Contacts: []models.ContactAssignment{{Name: "DC Ops", Role: "operations"}},
+	}}
+
+	// Run twice: the second run must find the existing assignment
+	for run := 1; run <= 2; run++ {
+		if err := fr.ReconcileSites(sites); err != nil {
+			t.Fatalf("ReconcileSites() run %d error = %v", run, err)
+		}
+	}
+
+	assignments := srv.Objects("tenancy", "contact-assignments")
+	if len(assignments) != 1 {
+		t.Fatalf("Expected 1 contact assignment, got %d", len(assignments))
+	}
+
+	site := srv.Find("dcim", "sites", "slug", "berlin-dc")
+	contact := srv.Find("tenancy", "contacts", "name", "DC Ops")
+	role := srv.Find("tenancy", "contact-roles", "slug", "operations")
+
+	a := assignments[0]
+	if a["object_type"] != "dcim.site" {
+		t.Errorf("object_type = %v, expected dcim.site", a["object_type"])
+	}
+	if netboxtest.ID(a["object_id"]) != netboxtest.ID(site) {
+		t.Errorf("object_id = %v, expected %v", a["object_id"], site["id"])
+	}
+	if netboxtest.ID(a["contact"]) != netboxtest.ID(contact) {
+		t.Errorf("contact = %v, expected %v", a["contact"], contact["id"])
+	}
+	if netboxtest.ID(a["role"]) != netboxtest.ID(role) {
+		t.Errorf("role = %v, expected %v", a["role"], role["id"])
+	}
+}
+
+func TestReconcileContactAssignmentUnknownContact(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	err := reconcileContactAssignments(c, "dcim.site", 1, []models.ContactAssignment{{Name: "Nobody", Role: "owner"}})
+	if err != nil {
+		t.Fatalf("reconcileContactAssignments() error = %v", err)
+	}
+
+	if n := len(srv.Objects("tenancy", "contact-assignments")); n != 0 {
+		t.Errorf("Expected no assignments for unknown contact, got %d", n)
+	}
+}
+
+// TestReconcileNestedContactGroupDryRun tests that a contact group in a parent created in the
+// same run plans under --dry-run and --simulate instead of failing
+func TestReconcileNestedContactGroupDryRun(t *testing.T) {
+	for _, simulate := range []bool{false, true} {
+		c, srv := newTestClient(t)
+		c.SetDryRun(true)
+		c.SetSimulate(simulate)
+		tr := NewTenancyReconciler(c)
+
+		groups := []*models.ContactGroup{
+			{Name: "Operations", Slug: "operations"},
+			{Name: "NOC", Slug: "noc", Parent: "operations"},
+		}
+		if err := tr.ReconcileContactGroups(groups); err != nil {
+			t.Errorf("ReconcileContactGroups(simulate=%v) error = %v, expected the groups planned", simulate, err)
+		}
+		if n := len(srv.Objects("tenancy", "contact-groups")); n != 0 {
+			t.Errorf("simulate=%v: dry-run created %d contact groups, expected none", simulate, n)
+		}
+	}
+}
+
+func TestReconcileNestedTenantGroup(t *testing.T) {
+	c, srv := newTestClient(t)
+	tr := NewTenancyReconciler(c)
+
+	groups := []*models.TenantGroup{
+		{Name: "Customers", Slug: "customers"},
+		{Name: "Enterprise Customers", Slug: "enterprise-customers", Parent: "customers"},
+	}
+	if err := tr.ReconcileTenantGroups(groups); err != nil {
+		t.Fatalf("ReconcileTenantGroups() error = %v", err)
+	}
+	if err := tr.ReconcileTenants([]*models.Tenant{{Name: "ACME Corp", Slug: "acme-corp", Group: "enterprise-customers"}}); err != nil {
+		t.Fatalf("ReconcileTenants() error = %v", err)
+	}
+
+	parent := srv.Find("tenancy", "tenant-groups", "slug", "customers")
+	child := srv.Find("tenancy", "tenant-groups", "slug", "enterprise-customers")
+	if parent == nil || child == nil {
+		t.Fatal("Tenant groups were not created")
+	}
+	if netboxtest.ID(child["parent"]) != netboxtest.ID(parent) {
+		t.Errorf("Child group parent = %v, expected %v", child["parent"], parent["id"])
+	}
+
+	tenant := srv.Find("tenancy", "tenants", "slug", "acme-corp")
+	if tenant == nil || netboxtest.ID(tenant["group"]) != netboxtest.ID(child) {
+		t.Errorf("Tenant group = %v, expected %v", tenant["group"], child["id"])
+	}
+
+	unknown := []*models.TenantGroup{{Name: "Orphans", Slug: "orphans", Parent: "does-not-exist"}}
+	if err := tr.ReconcileTenantGroups(unknown); err == nil {
+		t.Error("ReconcileTenantGroups() expected error for unknown parent")
+	}
+}
+
+// TestReconcileNestedTenantGroupDryRun tests that tenant groups and tenants referencing groups
+// created in the same run plan under --dry-run and --simulate instead of failing
+func TestReconcileNestedTenantGroupDryRun(t *testing.T) {
+	for _, simulate := range []bool{false, true} {
+		c, srv := newTestClient(t)
+		c.SetDryRun(true)
+		c.SetSimulate(simulate)
+		tr := NewTenancyReconciler(c)
+
+		groups := []*models.TenantGroup{
+			{Name: "Customers", Slug: "customers"},
+			{Name: "Enterprise Customers", Slug: "enterprise-customers", Parent: "customers"},
+		}
+		if err := tr.ReconcileTenantGroups(groups); err != nil {
+			t.Errorf("ReconcileTenantGroups(simulate=%v) error = %v, expected the groups planned", simulate, err)
+		}
+		if err := tr.ReconcileTenants([]*models.Tenant{{Name: "ACME Corp", Slug: "acme-corp", Group: "enterprise-customers"}}); err != nil {
+			t.Errorf("ReconcileTenants(simulate=%v) error = %v, expected the tenant planned", simulate, err)
+		}
+		if n := len(srv.Objects("tenancy", "tenant-groups")) + len(srv.Objects("tenancy", "tenants")); n != 0 {
+			t.Errorf("simulate=%v: dry-run created %d objects, expected none", simulate, n)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package reconciler
+
+import (
+	"fmt"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/client"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// VirtualChassisReconciler handles virtual chassis (device stacks)
+type VirtualChassisReconciler struct {
+	client *client.NetBoxClient
+	logger *utils.Logger
+}
+
+// NewVirtualChassisReconciler creates a new virtual chassis reconciler
+func NewVirtualChassisReconciler(c *client.NetBoxClient) *VirtualChassisReconciler {
+	return &VirtualChassisReconciler{
+		client: c,
+		logger: c.Logger(),
+	}
+}
+
+// ReconcileVirtualChassis reconciles virtual chassis and their members.
+// Member devices must already exist: each chassis is created, its members are
+// assigned, and only then is the master set (NetBox requires the master to be a member).
+func (vr *VirtualChassisReconciler) ReconcileVirtualChassis(chassis []*models.VirtualChassis, devices []*models.DeviceConfig) error {
+	vr.logger.Info("Reconciling %d virtual chassis...", len(chassis))
+
+	declared := make(map[string]bool, len(chassis))
+	for _, vc := range chassis {
+		declared[vc.Name] = true
+	}
+
+	members := make(map[string][]*models.DeviceConfig)
+	for _, device := range devices {
+		if device.VirtualChassis == "" {
+			continue
+		}
+		if !declared[device.VirtualChassis] {
+			vr.logger.Warning("Virtual chassis %s not defined for device %s, skipping", device.VirtualChassis, device.Name)
+			continue
+		}
+		if device.VCPosition <= 0 {
+			return fmt.Errorf("device %s is a member of virtual chassis %s but has no vc_position", device.Name, device.VirtualChassis)
+		}
+		members[device.VirtualChassis] = append(members[device.VirtualChassis], device)
+	}
+
+	for _, vc := range chassis {
+		if err := vr.reconcileChassis(vc, members[vc.Name]); err != nil {
+			if vr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile virtual chassis %s: %w", vc.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileChassis creates or updates one chassis, assigns its members and sets the master
+func (vr *VirtualChassisReconciler) reconcileChassis(vc *models.VirtualChassis, members []*models.DeviceConfig) error {
+	payload := map[string]interface{}{
+		"name": vc.Name,
+	}
+
+	if vc.Domain != "" {
+		payload["domain"] = vc.Domain
+	}
+	if vc.Description != "" {
+		payload["description"] = vc.Description
+	}
+
+	lookup := map[string]interface{}{"name": vc.Name}
+	vcObj, err := vr.client.Apply("dcim", "virtual-chassis", lookup, payload)
+	if err != nil {
+		return err
+	}
+
+	vcID := utils.GetIDFromObject(vcObj)
+	if vcID == 0 {
+		vr.logger.Debug("Virtual chassis %s created in dry-run mode, skipping members", vc.Name)
+		return nil
+	}
+
+	memberIDs := make(map[string]int, len(members))
+	for _, member := range members {
+		deviceID, err := vr.assignMember(vcID, member)
+		if err != nil {
+			return err
+		}
+		memberIDs[member.Name] = deviceID
+	}
+
+	if vc.Master == "" {
+		return nil
+	}
+
+	masterID, ok := memberIDs[vc.Master]
+	if !ok {
+		return fmt.Errorf("master %s is not a member", vc.Master)
+	}
+	if masterID == 0 || utils.GetIDFromObject(vcObj["master"]) == masterID {
+		return nil
+	}
+
+	vr.logger.Info("  ⟳ Setting master of virtual chassis %s to %s", vc.Name, vc.Master)
+	if err := vr.client.Update("dcim", "virtual-chassis", vcID, map[string]interface{}{"master": masterID}); err != nil {
+		return fmt.Errorf("failed to set master %s: %w", vc.Master, err)
+	}
+
+	return nil
+}
+
+// assignMember places a device into the chassis at its position and returns the device ID.
+// Only the membership fields are patched, so the device's other fields and tags are untouched.
+func (vr *VirtualChassisReconciler) assignMember(vcID int, member *models.DeviceConfig) (int, error) {
+	siteID, ok := vr.client.Cache().GetGlobalID("sites", member.SiteSlug)
+	if !ok {
+		return 0, fmt.Errorf("site %s not found for member %s", member.SiteSlug, member.Name)
+	}
+
+	devices, err := vr.client.Filter("dcim", "devices", map[string]interface{}{
+		"name":    member.Name,
+		"site_id": siteID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up member %s: %w", member.Name, err)
+	}
+	if len(devices) == 0 {
+		if vr.client.IsDryRun() {
+			return 0, nil // Device would be created in this run
+		}
+		return 0, fmt.Errorf("member device %s not found", member.Name)
+	}
+
+	device := devices[0]
+	deviceID := utils.GetIDFromObject(device)
+
+	desired := map[string]int{
+		"virtual_chassis": vcID,
+		"vc_position":     member.VCPosition,
+	}
+	if member.VCPriority > 0 {
+		desired["vc_priority"] = member.VCPriority
+	}
+
+	changes := make(map[string]interface{})
+	for field, value := range desired {
+		if utils.GetIDFromObject(device[field]) != value {
+			changes[field] = value
+		}
+	}
+
+	if len(changes) > 0 {
+		vr.logger.Info("  ⟳ Assigning %s to virtual chassis position %d", member.Name, member.VCPosition)
+		if err := vr.client.Update("dcim", "devices", deviceID, changes); err != nil {
+			return 0, fmt.Errorf("failed to assign member %s: %w", member.Name, err)
+		}
+	}
+
+	return deviceID, nil
+}
@@ -0,0 +1,81 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+)
+
+// TestReconcileVirtualChassisTwoMemberStack tests member assignment and master selection
+func TestReconcileVirtualChassisTwoMemberStack(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+
+	sw1 := srv.Add("dcim", "devices", map[string]interface{}{"name": "sw-01", "site": site["id"]})
+	sw2 := srv.Add("dcim", "devices", map[string]interface{}{"name": "sw-02", "site": site["id"]})
+
+	chassis := []*models.VirtualChassis{{Name: "stack-01", Domain: "stack-01.example.com", Master: "sw-01"}}
+	devices := []*models.DeviceConfig{
+		{Name: "sw-01", SiteSlug: "berlin-dc", VirtualChassis: "stack-01", VCPosition: 1, VCPriority: 255},
+		{Name: "sw-02", SiteSlug: "berlin-dc", VirtualChassis: "stack-01", VCPosition: 2},
+		{Name: "srv-01", SiteSlug: "berlin-dc"},
+	}
+
+	vr := NewVirtualChassisReconciler(c)
+	if err := vr.ReconcileVirtualChassis(chassis, devices); err != nil {
+		t.Fatalf("ReconcileVirtualChassis() error = %v", err)
+	}
+
+	vc := srv.Find("dcim", "virtual-chassis", "name", "stack-01")
+	if vc == nil {
+		t.Fatal("Virtual chassis stack-01 was not created")
+	}
+	if netboxtest.ID(vc["master"]) != netboxtest.ID(sw1["id"]) {
+		t.Errorf("Virtual chassis master = %v, expected sw-01 (ID %v)", vc["master"], sw1["id"])
+	}
+
+	for _, tc := range []struct {
+		device   map[string]interface{}
+		position int
+	}{{sw1, 1}, {sw2, 2}} {
+		if netboxtest.ID(tc.device["virtual_chassis"]) != netboxtest.ID(vc["id"]) {
+			t.Errorf("Device %s virtual_chassis = %v, expected %v", tc.device["name"], tc.device["virtual_chassis"], vc["id"])
+		}
+		if netboxtest.ID(tc.device["vc_position"]) != tc.position {
+			t.Errorf("Device %s vc_position = %v, expected %d", tc.device["name"], tc.device["vc_position"], tc.position)
+		}
+	}
+	if netboxtest.ID(sw1["vc_priority"]) != 255 {
+		t.Errorf("Device sw-01 vc_priority = %v, expected 255", sw1["vc_priority"])
+	}
+
+	// Second run must not touch anything
+	srv.ResetRequests()
+	if err := vr.ReconcileVirtualChassis(chassis, devices); err != nil {
+		t.Fatalf("ReconcileVirtualChassis() second run error = %v", err)
+	}
+	if got := srv.CountRequests("PATCH", "/api/dcim/"); got != 0 {
+		t.Errorf("Unchanged virtual chassis caused %d updates", got)
+	}
+}
+
+// TestReconcileVirtualChassisMasterMustBeMember tests that a non-member master is rejected
+func TestReconcileVirtualChassisMasterMustBeMember(t *testing.T) {
+	c, srv := newTestClient(t)
+	site := srv.Add("dcim", "sites", map[string]interface{}{"name": "Berlin DC", "slug": "berlin-dc"})
+	if err := c.Cache().LoadGlobal(); err != nil {
+		t.Fatalf("LoadGlobal() error = %v", err)
+	}
+	srv.Add("dcim", "devices", map[string]interface{}{"name": "sw-01", "site": site["id"]})
+
+	chassis := []*models.VirtualChassis{{Name: "stack-01", Master: "sw-99"}}
+	devices := []*models.DeviceConfig{{Name: "sw-01", SiteSlug: "berlin-dc", VirtualChassis: "stack-01", VCPosition: 1}}
+
+	if err := NewVirtualChassisReconciler(c).ReconcileVirtualChassis(chassis, devices); err == nil {
+		t.Error("ReconcileVirtualChassis() expected error for master that is not a member")
+	}
+}
@@ -0,0 +1,84 @@
+package reconciler
+
+import (
+	"fmt"
+
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+	"github.com/braunma/netbox-gitops-controller/pkg/utils"
+)
+
+// ReconcileVLANTranslationPolicies reconciles VLAN translation policies and then their rules.
+// NetBox added VLAN translation in 4.2; on older versions the policies are skipped with a warning.
+func (nr *NetworkReconciler) ReconcileVLANTranslationPolicies(policies []*models.VLANTranslationPolicy) error {
+	nr.logger.Info("Reconciling %d VLAN translation policies...", len(policies))
+	if len(policies) == 0 {
+		return nil
+	}
+	if !nr.client.AtLeastVersion(4, 2) {
+		nr.logger.Warning("VLAN translation requires NetBox 4.2 or later, skipping %d policies", len(policies))
+		return nil
+	}
+
+	for _, policy := range policies {
+		payload := map[string]interface{}{
+			"name": policy.Name,
+		}
+		if policy.Description != "" {
+			payload["description"] = policy.Description
+		}
+		setTags(nr.client, payload, policy.Tags, "VLAN translation policy "+policy.Name)
+
+		lookup := map[string]interface{}{"name": policy.Name}
+		policyObj, err := nr.client.Apply("ipam", "vlan-translation-policies", lookup, payload)
+		if err != nil {
+			if nr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile VLAN translation policy %s: %w", policy.Name, err)
+		}
+
+		policyID := utils.GetIDFromObject(policyObj)
+		if policyID == 0 {
+			nr.logger.Debug("VLAN translation policy %s created in dry-run mode, skipping rules", policy.Name)
+			continue
+		}
+		if err := nr.reconcileVLANTranslationRules(policyID, policy); err != nil {
+			if nr.client.SkipTimeout(err) {
+				continue
+			}
+			return fmt.Errorf("failed to reconcile VLAN translation policy %s: %w", policy.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// reconcileVLANTranslationRules reconciles the rules of a policy, matched by policy and both VIDs
+func (nr *NetworkReconciler) reconcileVLANTranslationRules(policyID int, policy *models.VLANTranslationPolicy) error {
+	for _, rule := range policy.Rules {
+		for _, vid := range []int{rule.LocalVID, rule.RemoteVID} {
+			if vid < 1 || vid > 4094 {
+				return fmt.Errorf("rule %d → %d: VID %d out of range 1-4094", rule.LocalVID, rule.RemoteVID, vid)
+			}
+		}
+
+		payload := map[string]interface{}{
+			"policy":     policyID,
+			"local_vid":  rule.LocalVID,
+			"remote_vid": rule.RemoteVID,
+		}
+		if rule.Description != "" {
+			payload["description"] = rule.Description
+		}
+
+		lookup := map[string]interface{}{
+			"policy_id":  policyID,
+			"local_vid":  rule.LocalVID,
+			"remote_vid": rule.RemoteVID,
+		}
+		if _, err := nr.client.Apply("ipam", "vlan-translation-rules", lookup, payload); err != nil {
+			return fmt.Errorf("rule %d → %d: %w", rule.LocalVID, rule.RemoteVID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/braunma/netbox-gitops-controller/internal/netboxtest"
+	"github.com/braunma/netbox-gitops-controller/pkg/models"
+)
+
+// TestReconcileVLANTranslationPolicy tests creating a policy with two rules, keyed on
+// policy and both VIDs so a second run changes nothing
+func TestReconcileVLANTranslationPolicy(t *testing.T) {
+	policies := []*models.VLANTranslationPolicy{{
+		Name: "customer-a",
+		Rules: []models.VLANTranslationRule{
+			{LocalVID: 100, RemoteVID: 2100},
+			{LocalVID: 200, RemoteVID: 2200, Description: "Storage"},
+		},
+	}}
+
+	t.Run("NetBox 4.1", func(t *testing.T) {
+		c, srv := newTestClient(t)
+		srv.Intercept("GET", "/api/status/", statusHandler("4.1.3"))
+
+		if err := NewNetworkReconciler(c).ReconcileVLANTranslationPolicies(policies); err != nil {
+			t.Fatalf("ReconcileVLANTranslationPolicies() error = %v", err)
+		}
+		if got := srv.Objects("ipam", "vlan-translation-policies"); len(got) != 0 {
+			t.Errorf("Got %d VLAN translation policies on NetBox 4.1, expected none", len(got))
+		}
+	})
+
+	t.Run("NetBox 4.2", func(t *testing.T) {
+		c, srv := newTestClient(t)
+		srv.Intercept("GET", "/api/status/", statusHandler("4.2.0"))
+		nr := NewNetworkReconciler(c)
+
+		if err := nr.ReconcileVLANTranslationPolicies(policies); err != nil {
+			t.Fatalf("ReconcileVLANTranslationPolicies() error = %v", err)
+		}
+
+		policy := srv.Find("ipam", "vlan-translation-policies", "name", "customer-a")
+		if policy == nil {
+			t.Fatal("VLAN translation policy customer-a was not created")
+		}
+		rules := srv.Objects("ipam", "vlan-translation-rules")
+		if len(rules) != 2 {
+			t.Fatalf("Got %d VLAN translation rules, expected 2", len(rules))
+		}
+		for _, rule := range rules {
+			if netboxtest.ID(rule["policy"]) != netboxtest.ID(policy) {
+				t.Errorf("VLAN translation rule %v does not belong to policy %v", rule, policy["id"])
+			}
+		}
+		storage := srv.Find("ipam", "vlan-translation-rules", "local_vid", 200)
+		if storage == nil || netboxtest.ID(storage["remote_vid"]) != 2200 || storage["description"] != "Storage" {
+			t.Errorf("VLAN translation rule 200 = %v, expected 200 → 2200 (Storage)", storage)
+		}
+
+		srv.ResetRequests()
+		if err := NewNetworkReconciler(c).ReconcileVLANTranslationPolicies(policies); err != nil {
+			t.Fatalf("second ReconcileVLANTranslationPolicies() error = %v", err)
+		}
+		if n := srv.CountRequests("POST", "") + srv.CountRequests("PATCH", ""); n != 0 {
+			t.Errorf("Second run sent %d writes, expected none", n)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// bufferedLine is a log line held back by BufferOutput, with the writer it was destined for
+type bufferedLine struct {
+	w    io.Writer
+	text string
+}
+
+// heldOutput collects all log output while buffering is enabled
+var (
+	heldOutputMu sync.Mutex
+	heldOutput   []bufferedLine
+	buffering    bool
+)
+
+// BufferOutput holds back the log output of all loggers until FlushOutput or DiscardOutput
+// (used by --quiet-no-change to decide at the end of a run whether to print anything)
+func BufferOutput() {
+	heldOutputMu.Lock()
+	defer heldOutputMu.Unlock()
+
+	buffering = true
+	heldOutput = nil
+}
+
+// FlushOutput writes the held-back output, in order, to the writers it was destined for
+// and stops buffering
+func FlushOutput() {
+	heldOutputMu.Lock()
+	lines := heldOutput
+	buffering = false
+	heldOutput = nil
+	heldOutputMu.Unlock()
+
+	for _, line := range lines {
+		io.WriteString(line.w, line.text)
+	}
+}
+
+// DiscardOutput drops the held-back output and stops buffering
+func DiscardOutput() {
+	heldOutputMu.Lock()
+	defer heldOutputMu.Unlock()
+
+	buffering = false
+	heldOutput = nil
+}
+
+// holdLine buffers a formatted log line if buffering is enabled and reports whether it did
+func holdLine(w io.Writer, format string, args ...interface{}) bool {
+	heldOutputMu.Lock()
+	defer heldOutputMu.Unlock()
+
+	if !buffering {
+		return false
+	}
+	heldOutput = append(heldOutput, bufferedLine{w: w, text: fmt.Sprintf(format, args...)})
+	return true
+}
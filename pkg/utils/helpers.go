@@ -7,16 +7,22 @@ import (
 	"time"
 )
 
-// Slugify converts a string to a URL-safe slug
+// Slugify converts a string to a URL-safe slug the way NetBox does:
+// lowercase, every run of non-alphanumeric characters becomes a single hyphen,
+// and leading/trailing hyphens are trimmed (e.g., "Site (DE)!" becomes "site-de")
 func Slugify(s string) string {
-	s = strings.ToLower(s)
-	s = strings.ReplaceAll(s, " ", "-")
-	// Remove any characters that aren't alphanumeric or hyphens
 	var result strings.Builder
-	for _, char := range s {
-		if (char >= 'a' && char <= 'z') || (char >= '0' && char <= '9') || char == '-' {
+	pendingHyphen := false
+	for _, char := range strings.ToLower(s) {
+		if (char >= 'a' && char <= 'z') || (char >= '0' && char <= '9') {
+			if pendingHyphen && result.Len() > 0 {
+				result.WriteRune('-')
+			}
+			pendingHyphen = false
 			result.WriteRune(char)
+			continue
 		}
+		pendingHyphen = true
 	}
 	return result.String()
 }
@@ -31,15 +31,40 @@ func TestSlugify(t *testing.T) {
 			expected: "hello-world-test",
 		},
 		{
-			name:     "special characters removed",
+			name:     "special character run becomes one hyphen",
 			input:    "test@#$%123",
-			expected: "test123",
+			expected: "test-123",
 		},
 		{
 			name:     "hyphens preserved",
 			input:    "test-case-one",
 			expected: "test-case-one",
 		},
+		{
+			name:     "parentheses",
+			input:    "Site (DE)",
+			expected: "site-de",
+		},
+		{
+			name:     "multiple spaces",
+			input:    "Rack   Row  A",
+			expected: "rack-row-a",
+		},
+		{
+			name:     "trailing punctuation",
+			input:    "Site (DE)!",
+			expected: "site-de",
+		},
+		{
+			name:     "leading punctuation and repeated hyphens",
+			input:    "--Juniper -- Networks--",
+			expected: "juniper-networks",
+		},
+		{
+			name:     "underscores and dots",
+			input:    "Dell_EMC v2.1",
+			expected: "dell-emc-v2-1",
+		},
 	}
 
 	for _, tt := range tests {
@@ -1,57 +1,131 @@
 package utils
 
 import (
-	"fmt"
-	"github.com/fatih/color"
+	"io"
 	"os"
+
+	"github.com/fatih/color"
 )
 
 // Logger provides structured logging for the application
 type Logger struct {
 	dryRun bool
+	out    io.Writer
+	errOut io.Writer
 }
 
+// summaryOnly suppresses per-object output process-wide, see ConfigureSummaryOnly
+var summaryOnly bool
+
 // NewLogger creates a new logger instance
 func NewLogger(dryRun bool) *Logger {
-	return &Logger{dryRun: dryRun}
+	return &Logger{dryRun: dryRun, out: os.Stdout, errOut: os.Stderr}
+}
+
+// SetOutput redirects regular and error output (e.g., to capture logs in tests)
+func (l *Logger) SetOutput(out, errOut io.Writer) {
+	l.out = out
+	l.errOut = errOut
+}
+
+// stdout returns the regular output writer (a nil Logger writes to os.Stdout)
+func (l *Logger) stdout() io.Writer {
+	if l == nil || l.out == nil {
+		return os.Stdout
+	}
+	return l.out
+}
+
+// stderr returns the error output writer (a nil Logger writes to os.Stderr)
+func (l *Logger) stderr() io.Writer {
+	if l == nil || l.errOut == nil {
+		return os.Stderr
+	}
+	return l.errOut
+}
+
+// ConfigureColor disables colored output when requested with --no-color, when the
+// NO_COLOR environment variable is set (https://no-color.org), or when stdout is not a terminal
+func ConfigureColor(noColor bool) {
+	if noColor || os.Getenv("NO_COLOR") != "" || !IsTerminal(os.Stdout) {
+		color.NoColor = true
+	}
+}
+
+// ConfigureSummaryOnly enables --summary-only mode: Success, Info, Debug and DryRun
+// messages are suppressed, while warnings, errors and Summary lines are still printed
+func ConfigureSummaryOnly(enabled bool) {
+	summaryOnly = enabled
+}
+
+// SummaryOnly reports whether --summary-only mode is enabled
+func SummaryOnly() bool {
+	return summaryOnly
+}
+
+// IsTerminal reports whether f is an interactive terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
 }
 
 // Success logs a success message in green
 func (l *Logger) Success(msg string, args ...interface{}) {
+	if summaryOnly {
+		return
+	}
 	green := color.New(color.FgGreen).SprintFunc()
-	fmt.Fprintf(os.Stdout, green("✓ "+msg)+"\n", args...)
+	writeLog(l.stdout(), green("✓ "+msg)+"\n", args...)
 }
 
 // Info logs an informational message in cyan
 func (l *Logger) Info(msg string, args ...interface{}) {
+	if summaryOnly {
+		return
+	}
 	cyan := color.New(color.FgCyan).SprintFunc()
-	fmt.Fprintf(os.Stdout, cyan(msg)+"\n", args...)
+	writeLog(l.stdout(), cyan(msg)+"\n", args...)
 }
 
 // Warning logs a warning message in yellow
 func (l *Logger) Warning(msg string, args ...interface{}) {
 	yellow := color.New(color.FgYellow).SprintFunc()
-	fmt.Fprintf(os.Stdout, yellow("⚠ "+msg)+"\n", args...)
+	writeLog(l.stdout(), yellow("⚠ "+msg)+"\n", args...)
 }
 
 // Error logs an error message in red
 func (l *Logger) Error(msg string, err error, args ...interface{}) {
 	red := color.New(color.FgRed).SprintFunc()
 	if err != nil {
-		fmt.Fprintf(os.Stderr, red("✗ "+msg+": %v")+"\n", append(args, err)...)
+		writeLog(l.stderr(), red("✗ "+msg+": %v")+"\n", append(args, err)...)
 	} else {
-		fmt.Fprintf(os.Stderr, red("✗ "+msg)+"\n", args...)
+		writeLog(l.stderr(), red("✗ "+msg)+"\n", args...)
 	}
 }
 
 // Debug logs a debug message in dim/gray
 func (l *Logger) Debug(msg string, args ...interface{}) {
+	if summaryOnly {
+		return
+	}
 	dim := color.New(color.Faint).SprintFunc()
-	fmt.Fprintf(os.Stdout, dim(msg)+"\n", args...)
+	writeLog(l.stdout(), dim(msg)+"\n", args...)
 }
 
 // DryRun logs a dry-run action in yellow
 func (l *Logger) DryRun(action string, msg string, args ...interface{}) {
+	if summaryOnly {
+		return
+	}
 	yellow := color.New(color.FgYellow).SprintFunc()
-	fmt.Fprintf(os.Stdout, yellow("[DRY-RUN] %s: "+msg)+"\n", append([]interface{}{action}, args...)...)
+	writeLog(l.stdout(), yellow("[DRY-RUN] %s: "+msg)+"\n", append([]interface{}{action}, args...)...)
+}
+
+// Summary logs a line of the final run summary in bold, even in summary-only mode
+func (l *Logger) Summary(msg string, args ...interface{}) {
+	bold := color.New(color.Bold).SprintFunc()
+	writeLog(l.stdout(), bold(msg)+"\n", args...)
 }
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestConfigureColorNoColor(t *testing.T) {
+	original := color.NoColor
+	t.Cleanup(func() { color.NoColor = original })
+
+	logAll := func() string {
+		var buf bytes.Buffer
+		logger := NewLogger(true)
+		logger.SetOutput(&buf, &buf)
+
+		logger.Success("created %s", "site")
+		logger.Info("info")
+		logger.Warning("warning")
+		logger.Error("failed", errors.New("boom"))
+		logger.Debug("debug")
+		logger.DryRun("POST", "/api/dcim/sites/")
+		return buf.String()
+	}
+
+	// Sanity check: with colors forced on, escapes are emitted
+	color.NoColor = false
+	if out := logAll(); !strings.Contains(out, "\x1b[") {
+		t.Fatalf("Expected ANSI escapes with color enabled, got %q", out)
+	}
+
+	ConfigureColor(true)
+	out := logAll()
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("Output contains ANSI escapes with --no-color: %q", out)
+	}
+	if !strings.Contains(out, "✓ created site") || !strings.Contains(out, "✗ failed: boom") {
+		t.Errorf("Unexpected output: %q", out)
+	}
+}
+
+func TestConfigureColorNoColorEnv(t *testing.T) {
+	original := color.NoColor
+	t.Cleanup(func() { color.NoColor = original })
+
+	color.NoColor = false
+	t.Setenv("NO_COLOR", "1")
+	ConfigureColor(false)
+	if !color.NoColor {
+		t.Error("NO_COLOR should disable color output")
+	}
+}
@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// activeProgress is the progress line that log output is printed above, see SetActiveProgress
+var (
+	activeProgressMu sync.Mutex
+	activeProgress   *Progress
+)
+
+// SetActiveProgress makes log lines clear and redraw p so they don't interleave with it (nil to detach)
+func SetActiveProgress(p *Progress) {
+	activeProgressMu.Lock()
+	defer activeProgressMu.Unlock()
+	activeProgress = p
+}
+
+// writeLog writes a log line, keeping the active progress line below it (or holds it back, see BufferOutput)
+func writeLog(w io.Writer, format string, args ...interface{}) {
+	if holdLine(w, format, args...) {
+		return
+	}
+
+	activeProgressMu.Lock()
+	p := activeProgress
+	activeProgressMu.Unlock()
+
+	if p == nil {
+		fmt.Fprintf(w, format, args...)
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+	fmt.Fprintf(w, format, args...)
+	p.draw()
+}
+
+// Progress renders a single, continuously updated status line with the running phases,
+// the number of finished phases, an ETA and the actions recorded so far
+type Progress struct {
+	mu      sync.Mutex
+	out     io.Writer
+	total   int
+	done    int
+	running []string
+	counts  map[string]int
+	start   time.Time
+	shown   bool
+	now     func() time.Time
+}
+
+// NewProgress creates a progress line for a run of total phases, written to out (a terminal)
+func NewProgress(out io.Writer, total int) *Progress {
+	return &Progress{
+		out:    out,
+		total:  total,
+		counts: make(map[string]int),
+		start:  time.Now(),
+		now:    time.Now,
+	}
+}
+
+// StartPhase marks a phase as running
+func (p *Progress) StartPhase(name string) {
+	p.update(func() {
+		p.running = append(p.running, name)
+		sort.Strings(p.running)
+	})
+}
+
+// FinishPhase marks a running phase as done
+func (p *Progress) FinishPhase(name string) {
+	p.update(func() {
+		for i, running := range p.running {
+			if running == name {
+				p.running = append(p.running[:i], p.running[i+1:]...)
+				break
+			}
+		}
+		p.done++
+	})
+}
+
+// Record counts an action (client.ActionCreated, ...) on an object, matching the stats collector's observer signature
+func (p *Progress) Record(resource, action string) {
+	p.update(func() { p.counts[action]++ })
+}
+
+// Counts returns the number of recorded actions by action
+func (p *Progress) Counts() map[string]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	counts := make(map[string]int, len(p.counts))
+	for action, n := range p.counts {
+		counts[action] = n
+	}
+	return counts
+}
+
+// Done returns the number of finished phases
+func (p *Progress) Done() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.done
+}
+
+// Stop removes the progress line
+func (p *Progress) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clear()
+}
+
+// Line renders the progress line, e.g. "[3/20] devices | 12 created, 4 updated, 230 unchanged | ETA 1m10s"
+func (p *Progress) Line() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.line()
+}
+
+func (p *Progress) update(change func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	change()
+	p.clear()
+	p.draw()
+}
+
+func (p *Progress) line() string {
+	phase := strings.Join(p.running, ", ")
+	if phase == "" {
+		phase = "-"
+	}
+
+	line := fmt.Sprintf("[%d/%d] %s | %d created, %d updated, %d unchanged",
+		p.done, p.total, phase, p.counts["created"], p.counts["updated"], p.counts["unchanged"])
+
+	if p.done > 0 && p.done < p.total {
+		elapsed := p.now().Sub(p.start)
+		eta := elapsed / time.Duration(p.done) * time.Duration(p.total-p.done)
+		line += fmt.Sprintf(" | ETA %s", eta.Round(time.Second))
+	}
+	return line
+}
+
+// clear erases the progress line; callers hold p.mu
+func (p *Progress) clear() {
+	if p.shown {
+		fmt.Fprint(p.out, "\r\033[K")
+		p.shown = false
+	}
+}
+
+// draw prints the progress line without a newline; callers hold p.mu
+func (p *Progress) draw() {
+	fmt.Fprint(p.out, p.line())
+	p.shown = true
+}
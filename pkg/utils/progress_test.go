@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestProgressKeepsLogLinesAbove tests that log lines clear the progress line and redraw it below
+func TestProgressKeepsLogLinesAbove(t *testing.T) {
+	var out bytes.Buffer
+	progress := NewProgress(&out, 4)
+	SetActiveProgress(progress)
+	defer SetActiveProgress(nil)
+
+	progress.StartPhase("sites")
+	logger := NewLogger(false)
+	logger.SetOutput(&out, &out)
+	logger.Warning("Site %s has no region", "berlin-dc")
+
+	expected := "[0/4] sites | 0 created, 0 updated, 0 unchanged" +
+		"\r\033[K" + "⚠ Site berlin-dc has no region\n" +
+		"[0/4] sites | 0 created, 0 updated, 0 unchanged"
+	if got := out.String(); !strings.Contains(got, expected) {
+		t.Errorf("Output = %q, expected log line between cleared and redrawn progress", got)
+	}
+
+	out.Reset()
+	progress.Stop()
+	if out.String() != "\r\033[K" {
+		t.Errorf("Stop() output = %q, expected the line to be cleared", out.String())
+	}
+}
+
+func TestProgressETA(t *testing.T) {
+	progress := NewProgress(&bytes.Buffer{}, 4)
+	now := progress.start
+	progress.now = func() time.Time { return now }
+
+	progress.StartPhase("sites")
+	progress.FinishPhase("sites")
+	now = now.Add(10 * time.Second)
+
+	if line := progress.Line(); !strings.HasSuffix(line, "ETA 30s") {
+		t.Errorf("Line() = %q, expected ETA 30s for 1 of 4 phases done after 10s", line)
+	}
+}
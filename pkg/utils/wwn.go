@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalizeWWN converts a World Wide Name to NetBox's stored form: 8 uppercase,
+// colon-separated hex octets (e.g. "50:01:43:80:12:34:56:78").
+// Colons, dashes, dots and no separators are accepted on input.
+func NormalizeWWN(input string) (string, error) {
+	hex := strings.NewReplacer(":", "", "-", "", ".", "").Replace(strings.TrimSpace(input))
+	if len(hex) != 16 {
+		return "", fmt.Errorf("invalid WWN %q: expected 8 octets (16 hex digits)", input)
+	}
+
+	octets := make([]string, 0, 8)
+	for i := 0; i < len(hex); i += 2 {
+		octet := strings.ToUpper(hex[i : i+2])
+		for _, ch := range octet {
+			if !strings.ContainsRune("0123456789ABCDEF", ch) {
+				return "", fmt.Errorf("invalid WWN %q: %q is not a hex digit", input, ch)
+			}
+		}
+		octets = append(octets, octet)
+	}
+
+	return strings.Join(octets, ":"), nil
+}
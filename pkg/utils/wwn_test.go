@@ -0,0 +1,60 @@
+package utils
+
+import "testing"
+
+func TestNormalizeWWN(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "colon-separated lowercase",
+			input:    "50:01:43:80:12:34:56:ab",
+			expected: "50:01:43:80:12:34:56:AB",
+		},
+		{
+			name:     "no separators",
+			input:    "500143801234abcd",
+			expected: "50:01:43:80:12:34:AB:CD",
+		},
+		{
+			name:     "dash-separated",
+			input:    "50-01-43-80-12-34-56-78",
+			expected: "50:01:43:80:12:34:56:78",
+		},
+		{
+			name:     "surrounding whitespace",
+			input:    " 50:01:43:80:12:34:56:78 ",
+			expected: "50:01:43:80:12:34:56:78",
+		},
+		{
+			name:    "too short (MAC address)",
+			input:   "00:11:22:33:44:55",
+			wantErr: true,
+		},
+		{
+			name:    "too long",
+			input:   "50:01:43:80:12:34:56:78:9a",
+			wantErr: true,
+		},
+		{
+			name:    "non-hex digit",
+			input:   "50:01:43:80:12:34:56:zz",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NormalizeWWN(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeWWN(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("NormalizeWWN(%q) = %q, expected %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}